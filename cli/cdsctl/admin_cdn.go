@@ -18,6 +18,7 @@ func adminCdn() *cobra.Command {
 	return cli.NewCommand(adminCdnCmd, nil, []*cobra.Command{
 		adminCdnCache(),
 		adminCdnItem(),
+		adminCdnQuota(),
 		adminCdnUnit(),
 		cli.NewListCommand(adminCdnStatusCmd, adminCdnStatusRun, nil),
 		cli.NewCommand(adminCdnMigFromCDSCmd, adminCdnMigFromCDS, nil),
@@ -129,10 +130,21 @@ var adminCdnItemSizeProjectCmd = cli.Command{
 	Ctx: []cli.Arg{
 		{Name: _ProjectKey},
 	},
+	Flags: []cli.Flag{
+		{
+			Type:  cli.FlagBool,
+			Name:  "units",
+			Usage: "Display the size used on each storage unit",
+		},
+	},
 }
 
 func adminCdnItemSizeProjectRun(v cli.Values) error {
-	btes, err := client.ServiceCallGET(sdk.TypeCDN, "/size/item/project/"+v.GetString(_ProjectKey))
+	path := "/size/item/project/" + v.GetString(_ProjectKey)
+	if v.GetBool("units") {
+		path += "?units=true"
+	}
+	btes, err := client.ServiceCallGET(sdk.TypeCDN, path)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var adminCdnQuotaCmd = cli.Command{
+	Name:    "quota",
+	Aliases: []string{"quotas"},
+	Short:   "Manage CDS CDN project quotas",
+}
+
+func adminCdnQuota() *cobra.Command {
+	return cli.NewCommand(adminCdnQuotaCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminCdnQuotaListCmd, adminCdnQuotaList, nil),
+		cli.NewCommand(adminCdnQuotaSetCmd, adminCdnQuotaSet, nil),
+		cli.NewDeleteCommand(adminCdnQuotaDeleteCmd, adminCdnQuotaDelete, nil),
+	})
+}
+
+var adminCdnQuotaListCmd = cli.Command{
+	Name:    "list",
+	Short:   "List quotas with the size used by each project",
+	Example: "cdsctl admin cdn quota list --project MYPROJ",
+	Flags: []cli.Flag{
+		{
+			Name:  "project",
+			Usage: "Only display the quotas of the given project",
+		},
+	},
+}
+
+func adminCdnQuotaList(v cli.Values) (cli.ListResult, error) {
+	path := "/quota"
+	if v.GetString("project") != "" {
+		path += "/" + url.PathEscape(v.GetString("project"))
+	}
+	btes, err := client.ServiceCallGET(sdk.TypeCDN, path)
+	if err != nil {
+		return nil, err
+	}
+	var usages []sdk.CDNQuotaUsage
+	if err := sdk.JSONUnmarshal(btes, &usages); err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(usages), nil
+}
+
+var adminCdnQuotaSetCmd = cli.Command{
+	Name:    "set",
+	Short:   "Create or update the quota of a project, limits are in bytes and 0 means no limit",
+	Example: "cdsctl admin cdn quota set MYPROJ --type run-result-v2 --soft 8000000000 --hard 10000000000",
+	Args: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "type",
+			Usage: "Item type limited by the quota, all item types if empty",
+		},
+		{
+			Name:    "soft",
+			Usage:   "Size that triggers a notification",
+			Default: "0",
+		},
+		{
+			Name:    "hard",
+			Usage:   "Size over which uploads are refused",
+			Default: "0",
+		},
+	},
+}
+
+func adminCdnQuotaSet(v cli.Values) error {
+	soft, err := v.GetInt64("soft")
+	if err != nil {
+		return err
+	}
+	hard, err := v.GetInt64("hard")
+	if err != nil {
+		return err
+	}
+	q := sdk.CDNQuota{
+		ProjectKey: v.GetString(_ProjectKey),
+		ItemType:   sdk.CDNItemType(v.GetString("type")),
+		SoftLimit:  soft,
+		HardLimit:  hard,
+	}
+	if err := q.IsValid(); err != nil {
+		return err
+	}
+	btes, err := json.Marshal(q)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := client.ServiceCallPOST(sdk.TypeCDN, "/quota", btes); err != nil {
+		return err
+	}
+	return nil
+}
+
+var adminCdnQuotaDeleteCmd = cli.Command{
+	Name:    "delete",
+	Short:   "Delete the quota of a project",
+	Example: "cdsctl admin cdn quota delete MYPROJ --type run-result-v2",
+	Args: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "type",
+			Usage: "Item type of the quota, the quota for all item types if empty",
+		},
+	},
+}
+
+func adminCdnQuotaDelete(v cli.Values) error {
+	path := fmt.Sprintf("/quota/%s?type=%s", url.PathEscape(v.GetString(_ProjectKey)), url.QueryEscape(v.GetString("type")))
+	return client.ServiceCallDELETE(sdk.TypeCDN, path)
+}
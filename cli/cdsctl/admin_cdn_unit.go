@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 
 	"github.com/spf13/cobra"
 
@@ -19,6 +21,8 @@ func adminCdnUnit() *cobra.Command {
 		cli.NewCommand(adminCdnUnitItemDeleteCmd, adminCdnItemUnitDelete, nil),
 		cli.NewCommand(adminCdnUnitDeleteCmd, adminCdnUnitDelete, nil),
 		cli.NewListCommand(adminCdnUnitListCmd, adminCdnUnitList, nil),
+		cli.NewCommand(adminCdnUnitVerifyCmd, adminCdnUnitVerify, nil),
+		cli.NewGetCommand(adminCdnUnitVerifyReportCmd, adminCdnUnitVerifyReport, nil),
 	})
 }
 
@@ -77,3 +81,61 @@ func adminCdnUnitDelete(v cli.Values) error {
 	}
 	return nil
 }
+
+var adminCdnUnitVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "verify the integrity of the items stored on the given storage unit",
+	Long: `Read the items stored on the storage unit and compare their content with their hash.
+Without --repair, damaged items are only reported. With --repair, they are synchronized again from a healthy unit.
+Use "cdsctl admin cdn unit verify-report" to display the result.`,
+	Example: "cdsctl admin cdn unit verify <unit_id> --sample 1000 --repair",
+	Args: []cli.Arg{
+		{
+			Name: "unit_id",
+		},
+	},
+	Flags: []cli.Flag{
+		{Name: "sample", Usage: "Number of random items to verify, all the items are verified if not set"},
+		{Name: "repair", Type: cli.FlagBool, Default: "false", Usage: "Synchronize again the missing or corrupted items"},
+	},
+}
+
+func adminCdnUnitVerify(v cli.Values) error {
+	query := url.Values{}
+	if sample := v.GetString("sample"); sample != "" {
+		if _, err := strconv.ParseInt(sample, 10, 64); err != nil {
+			return cli.NewError("invalid sample size %q", sample)
+		}
+		query.Set("sample", sample)
+	}
+	query.Set("repair", strconv.FormatBool(v.GetBool("repair")))
+	path := fmt.Sprintf("/admin/backend/%s/verify?%s", v.GetString("unit_id"), query.Encode())
+	if _, err := client.ServiceCallPOST(sdk.TypeCDN, path, nil); err != nil {
+		return err
+	}
+	fmt.Println("Verification started")
+	return nil
+}
+
+var adminCdnUnitVerifyReportCmd = cli.Command{
+	Name:    "verify-report",
+	Short:   "display the last integrity verification report of the given storage unit",
+	Example: "cdsctl admin cdn unit verify-report <unit_id>",
+	Args: []cli.Arg{
+		{
+			Name: "unit_id",
+		},
+	},
+}
+
+func adminCdnUnitVerifyReport(v cli.Values) (interface{}, error) {
+	bts, err := client.ServiceCallGET(sdk.TypeCDN, fmt.Sprintf("/admin/backend/%s/verify", v.GetString("unit_id")))
+	if err != nil {
+		return nil, err
+	}
+	var report sdk.CDNUnitVerifyReport
+	if err := sdk.JSONUnmarshal(bts, &report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
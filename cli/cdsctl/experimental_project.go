@@ -24,6 +24,7 @@ func experimentalProject() *cobra.Command {
 		projectConcurrency(),
 		projectWebHooks(),
 		projectRetention(),
+		projectLogRedaction(),
 	})
 }
 
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/rockbears/yaml"
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var projectLogRedactionCmd = cli.Command{
+	Name:    "log-redaction",
+	Aliases: []string{""},
+	Short:   "Manage CDS project log redaction rules",
+	Long: `Log redaction rules are applied by the workers and the CDN on job logs, in addition to the known secrets.
+
+A rule matches values with a regular expression (pattern), with a minimal Shannon entropy (min_entropy) or with both:
+
+	rules:
+	- name: aws-access-key
+	  pattern: AKIA[0-9A-Z]{16}
+	- name: password-arg
+	  pattern: --password[= ](\S+)
+	- name: tokens
+	  min_entropy: 4.5
+	  min_length: 32
+`,
+}
+
+func projectLogRedaction() *cobra.Command {
+	return cli.NewCommand(projectLogRedactionCmd, nil, []*cobra.Command{
+		cli.NewCommand(projectLogRedactionImportCmd, projectLogRedactionImportFunc, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(projectLogRedactionShowCmd, projectLogRedactionShowFunc, nil, withAllCommandModifiers()...),
+	})
+}
+
+var projectLogRedactionImportCmd = cli.Command{
+	Name:    "import",
+	Aliases: []string{""},
+
+	Short: "Update the project log redaction rules",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "filename"},
+	},
+}
+
+func projectLogRedactionImportFunc(v cli.Values) error {
+	btes, err := os.ReadFile(v.GetString("filename"))
+	if err != nil {
+		return cli.WrapError(err, "unable to open file %s", v.GetString("filename"))
+	}
+
+	var content sdk.ProjectLogRedaction
+	if err := yaml.Unmarshal(btes, &content); err != nil {
+		return cli.WrapError(err, "unable to parse file %s", v.GetString("filename"))
+	}
+	if err := content.Rules.IsValid(); err != nil {
+		return err
+	}
+
+	return client.ProjectLogRedactionImport(context.Background(), v.GetString(_ProjectKey), sdk.ProjectLogRedaction{Rules: content.Rules})
+}
+
+var projectLogRedactionShowCmd = cli.Command{
+	Name:    "show",
+	Aliases: []string{"get"},
+
+	Short: "Retrieve the current project log redaction rules",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func projectLogRedactionShowFunc(v cli.Values) (interface{}, error) {
+	return client.ProjectLogRedactionGet(context.Background(), v.GetString(_ProjectKey))
+}
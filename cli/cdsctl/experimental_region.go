@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/spf13/cobra"
@@ -20,6 +21,7 @@ func experimentalRegion() *cobra.Command {
 		cli.NewGetCommand(regionGetCmd, regionGetFunc, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(regionListCmd, regionListFunc, nil, withAllCommandModifiers()...),
 		cli.NewDeleteCommand(regionDeleteCmd, regionDeleteFunc, nil, withAllCommandModifiers()...),
+		experimentalRegionWeight(),
 	})
 }
 
@@ -98,3 +100,78 @@ func regionDeleteFunc(v cli.Values) error {
 	}
 	return err
 }
+
+var experimentalRegionWeightCmd = cli.Command{
+	Name:  "weight",
+	Short: "Manage the fair-share weight of projects on a region",
+}
+
+func experimentalRegionWeight() *cobra.Command {
+	return cli.NewCommand(experimentalRegionWeightCmd, nil, []*cobra.Command{
+		cli.NewListCommand(regionWeightListCmd, regionWeightListFunc, nil, withAllCommandModifiers()...),
+		cli.NewCommand(regionWeightSetCmd, regionWeightSetFunc, nil, withAllCommandModifiers()...),
+		cli.NewDeleteCommand(regionWeightDeleteCmd, regionWeightDeleteFunc, nil, withAllCommandModifiers()...),
+	})
+}
+
+var regionWeightListCmd = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Short:   "List the weights of projects on a region",
+	Example: "cdsctl experimental region weight list <region_identifier>",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "regionIdentifier"},
+	},
+}
+
+func regionWeightListFunc(v cli.Values) (cli.ListResult, error) {
+	weights, err := client.RegionProjectWeightList(context.Background(), v.GetString("regionIdentifier"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(weights), nil
+}
+
+var regionWeightSetCmd = cli.Command{
+	Name:    "set",
+	Short:   "Set the weight of a project on a region",
+	Example: "cdsctl experimental region weight set <region_identifier> <project_key> <weight>",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "regionIdentifier"},
+		{Name: "projectKey"},
+		{Name: "weight"},
+	},
+}
+
+func regionWeightSetFunc(v cli.Values) error {
+	weight, err := strconv.ParseInt(v.GetString("weight"), 10, 64)
+	if err != nil {
+		return cli.NewError("invalid weight %q: %v", v.GetString("weight"), err)
+	}
+	return client.RegionProjectWeightSet(context.Background(), v.GetString("regionIdentifier"), sdk.RegionProjectWeight{
+		ProjectKey: v.GetString("projectKey"),
+		Weight:     weight,
+	})
+}
+
+var regionWeightDeleteCmd = cli.Command{
+	Name:    "delete",
+	Aliases: []string{"remove", "rm"},
+	Short:   "Remove the weight of a project on a region",
+	Example: "cdsctl experimental region weight delete <region_identifier> <project_key>",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "regionIdentifier"},
+		{Name: "projectKey"},
+	},
+}
+
+func regionWeightDeleteFunc(v cli.Values) error {
+	err := client.RegionProjectWeightDelete(context.Background(), v.GetString("regionIdentifier"), v.GetString("projectKey"))
+	if v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil
+	}
+	return err
+}
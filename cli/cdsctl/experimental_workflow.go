@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,11 +27,13 @@ func experimentalWorkflow() *cobra.Command {
 		cli.NewGetCommand(workflowRunCmd, workflowRunFunc, nil, withAllCommandModifiers()...),
 		cli.NewDeleteCommand(workflowV2RunDeleteCmd, workflowV2RunDeleteFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRestartCmd, workflowRestartFunc, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowDebugCmd, workflowDebugFunc, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowRunHistoryCmd, workflowRunHistoryFunc, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowRunInfosListCmd, workflowRunInfosListFunc, nil, withAllCommandModifiers()...),
 		cli.NewGetCommand(workflowRunStatusCmd, workflowRunStatusFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunStopCmd, workflowRunStopFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowLintCmd, workflowLintFunc, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowExecCmd, workflowExecFunc, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(workflowRunSearchCmd, workflowRunSearchFunc, nil, withAllCommandModifiers()...),
 		experimentalWorkflowRunLogs(),
 		experimentalWorkflowJob(),
@@ -412,7 +415,7 @@ var workflowRunCmd = cli.Command{
 		{Name: "repo_identifier"},
 		{Name: "workflow_name"},
 	},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		{
 			Name: "branch",
 		},
@@ -437,7 +440,118 @@ var workflowRunCmd = cli.Command{
 		{
 			Name: "inputs-file",
 		},
-	},
+		{
+			Name:      "input",
+			Type:      cli.FlagArray,
+			Usage:     "Workflow input declared in on.manual.inputs (key=value). Repeat the key or give a JSON array (key='[\"a\",\"b\"]') for a multiple choice input",
+			ShortHand: "i",
+		},
+	}, workflowDebugFlags...),
+}
+
+// workflowInputsFromFlags reads the workflow inputs given with --input key=value. A key given several times,
+// or a value that is a JSON array, sets the values of a multiple choice input.
+func workflowInputsFromFlags(values []string) (map[string]interface{}, error) {
+	inputs := make(map[string]interface{})
+	for _, in := range values {
+		k, value, ok := strings.Cut(in, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid input %q, expected key=value", in)
+		}
+		var v interface{} = value
+		if strings.HasPrefix(value, "[") {
+			var array []interface{}
+			if err := json.Unmarshal([]byte(value), &array); err != nil {
+				return nil, fmt.Errorf("invalid input %q, expected a JSON array: %v", in, err)
+			}
+			v = array
+		}
+		previous, has := inputs[k]
+		if !has {
+			inputs[k] = v
+			continue
+		}
+		previousValues, isArray := previous.([]interface{})
+		if !isArray {
+			previousValues = []interface{}{previous}
+		}
+		if array, isArray := v.([]interface{}); isArray {
+			inputs[k] = append(previousValues, array...)
+		} else {
+			inputs[k] = append(previousValues, v)
+		}
+	}
+	return inputs, nil
+}
+
+// workflowRunManualInputs reads workflow inputs given with --input and, when interactive, asks for the
+// ones declared in on.manual.inputs that are still missing.
+func workflowRunManualInputs(v cli.Values, projKey, vcsId, repoId, wkfName string) (map[string]interface{}, error) {
+	inputs, err := workflowInputsFromFlags(v.GetStringArray("input"))
+	if err != nil {
+		return nil, err
+	}
+
+	if v.GetBool("no-interactive") {
+		return inputs, nil
+	}
+
+	var mods []cdsclient.RequestModifier
+	if v.GetString("workflow-branch") != "" {
+		mods = append(mods, cdsclient.WithQueryParameter("branch", v.GetString("workflow-branch")))
+	} else if v.GetString("workflow-tag") != "" {
+		mods = append(mods, cdsclient.WithQueryParameter("tag", v.GetString("workflow-tag")))
+	}
+	e, err := client.EntityGet(context.Background(), projKey, vcsId, repoId, sdk.EntityTypeWorkflow, wkfName, mods...)
+	if err != nil {
+		return nil, err
+	}
+	var wk sdk.V2Workflow
+	if err := yaml.Unmarshal([]byte(e.Data), &wk); err != nil {
+		return nil, fmt.Errorf("unable to read workflow %s: %v", wkfName, err)
+	}
+	if wk.On == nil || wk.On.Manual == nil {
+		return inputs, nil
+	}
+
+	keys := make([]string, 0, len(wk.On.Manual.Inputs))
+	for k := range wk.On.Manual.Inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, has := inputs[k]; has {
+			continue
+		}
+		def := wk.On.Manual.Inputs[k]
+		msg := k
+		if def.Description != "" {
+			msg = fmt.Sprintf("%s (%s)", k, def.Description)
+		}
+		if def.Options != nil && len(def.Options.Values) > 0 {
+			opts := make([]string, 0, len(def.Options.Values))
+			for _, o := range def.Options.Values {
+				opts = append(opts, fmt.Sprintf("%v", o))
+			}
+			if def.Options.Multiple {
+				selected := make([]interface{}, 0, len(opts))
+				for _, i := range cli.AskSelect(msg, opts...) {
+					selected = append(selected, def.Options.Values[i])
+				}
+				inputs[k] = selected
+				continue
+			}
+			inputs[k] = opts[cli.AskChoice(msg, opts...)]
+			continue
+		}
+		if def.Default != nil {
+			msg = fmt.Sprintf("%s [%v]", msg, def.Default)
+		}
+		if value := cli.AskValue(msg); value != "" {
+			inputs[k] = value
+		}
+	}
+	return inputs, nil
 }
 
 func workflowRunFunc(v cli.Values) (interface{}, error) {
@@ -487,6 +601,17 @@ func workflowRunFunc(v cli.Values) (interface{}, error) {
 		payload.JobInputs = inputs
 	}
 
+	inputs, err := workflowRunManualInputs(v, projKey, vcsId, repoId, wkfName)
+	if err != nil {
+		return nil, err
+	}
+	payload.Inputs = inputs
+
+	payload.Debug, err = workflowDebugFromFlags(v)
+	if err != nil {
+		return nil, err
+	}
+
 	runResp, err := client.WorkflowV2Run(context.Background(), projKey, vcsId, repoId, wkfName, payload)
 	if err != nil {
 		return nil, err
@@ -531,29 +656,71 @@ func workflowRunFunc(v cli.Values) (interface{}, error) {
 }
 
 var workflowRestartCmd = cli.Command{
-	Name:    "restart",
-	Short:   "Restart workflow failed jobs",
-	Example: "cdsctl workflow restart <proj_key> <workflow_run_id>",
-	Ctx:     []cli.Arg{},
+	Name:  "restart",
+	Short: "Restart workflow failed jobs",
+	Long: `Restart a terminated workflow run. Successful jobs are kept with their outputs and results.
+
+By default, or with --failed-only, failed and stopped jobs are restarted with the jobs that depend on them.
+With --job, the given job is restarted with all the jobs that depend on it.
+With --debug or --debug-step, the restarted jobs are paused on failure or before the given steps, see 'cdsctl experimental workflow debug'.`,
+	Example: `cdsctl workflow restart <proj_key> <workflow_run_id> --failed-only
+cdsctl workflow restart <proj_key> <workflow_run_id> --job <job_id>`,
+	Ctx: []cli.Arg{},
 	Args: []cli.Arg{
 		{Name: "proj_key"},
 		{Name: "workflow_run_id"},
 	},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		{
 			Name: "inputs",
 		},
 		{
 			Name: "inputs-file",
 		},
-	},
+		{
+			Name:  "failed-only",
+			Type:  cli.FlagBool,
+			Usage: "Restart only failed and stopped jobs and the jobs that depend on them, this is the default",
+		},
+		{
+			Name:  "job",
+			Usage: "Restart the given job and all the jobs that depend on it",
+		},
+	}, workflowDebugFlags...),
+}
+
+// workflowRestartCheckFlags checks that only one restart mode is requested
+func workflowRestartCheckFlags(v cli.Values) error {
+	if v.GetBool("failed-only") && v.GetString("job") != "" {
+		return fmt.Errorf("--failed-only and --job cannot be used together")
+	}
+	if v.GetString("job") != "" || v.GetBool("failed-only") {
+		if v.GetString("inputs") != "" || v.GetString("inputs-file") != "" {
+			return fmt.Errorf("inputs cannot be used with --failed-only or --job")
+		}
+	}
+	return nil
 }
 
 func workflowRestartFunc(v cli.Values) error {
 	projKey := v.GetString("proj_key")
 	workflowRunID := v.GetString("workflow_run_id")
+	if err := workflowRestartCheckFlags(v); err != nil {
+		return err
+	}
+	debug, err := workflowDebugFromFlags(v)
+	if err != nil {
+		return err
+	}
+	if debug != nil && (v.GetString("inputs") != "" || v.GetString("inputs-file") != "") {
+		return fmt.Errorf("inputs cannot be used with debug mode")
+	}
 	if v.GetString("inputs") == "" && v.GetString("inputs-file") == "" {
-		run, err := client.WorkflowV2Restart(context.Background(), projKey, workflowRunID)
+		mods := workflowDebugQueryParameters(debug)
+		if v.GetString("job") != "" {
+			mods = append(mods, cdsclient.WithQueryParameter("job", v.GetString("job")))
+		}
+		run, err := client.WorkflowV2Restart(context.Background(), projKey, workflowRunID, mods...)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+var workflowDebugFlags = []cli.Flag{
+	{
+		Name:  "debug",
+		Type:  cli.FlagBool,
+		Usage: "Pause the job on the first failed step",
+	},
+	{
+		Name:  "debug-step",
+		Type:  cli.FlagSlice,
+		Usage: "Pause the job before the given step (step id or step-<index>)",
+	},
+	{
+		Name:  "debug-job",
+		Type:  cli.FlagSlice,
+		Usage: "Enable debug mode only on the given jobs",
+	},
+	{
+		Name:  "debug-timeout",
+		Usage: fmt.Sprintf("Duration of a pause in minutes (default %d)", sdk.V2WorkflowRunDebugDefaultTimeoutMinutes),
+	},
+}
+
+// workflowDebugFromFlags returns the debug mode requested with the debug flags, or nil if it is not requested
+func workflowDebugFromFlags(v cli.Values) (*sdk.V2WorkflowRunDebug, error) {
+	if !v.GetBool("debug") && len(v.GetStringSlice("debug-step")) == 0 {
+		if len(v.GetStringSlice("debug-job")) > 0 || v.GetString("debug-timeout") != "" {
+			return nil, fmt.Errorf("--debug or --debug-step is required to enable debug mode")
+		}
+		return nil, nil
+	}
+	debug := &sdk.V2WorkflowRunDebug{
+		Jobs:        v.GetStringSlice("debug-job"),
+		OnFailure:   v.GetBool("debug"),
+		BeforeSteps: v.GetStringSlice("debug-step"),
+	}
+	if v.GetString("debug-timeout") != "" {
+		timeout, err := v.GetInt64("debug-timeout")
+		if err != nil {
+			return nil, fmt.Errorf("invalid debug timeout: %v", err)
+		}
+		debug.TimeoutMinutes = timeout
+	}
+	return debug, nil
+}
+
+// workflowDebugQueryParameters returns the query parameters of a restart in debug mode
+func workflowDebugQueryParameters(debug *sdk.V2WorkflowRunDebug) []cdsclient.RequestModifier {
+	if debug == nil {
+		return nil
+	}
+	mods := []cdsclient.RequestModifier{cdsclient.WithQueryParameter("debug", strconv.FormatBool(debug.OnFailure))}
+	for _, s := range debug.BeforeSteps {
+		mods = append(mods, cdsclient.WithQueryParameter("debug-step", s))
+	}
+	for _, j := range debug.Jobs {
+		mods = append(mods, cdsclient.WithQueryParameter("debug-job", j))
+	}
+	if debug.TimeoutMinutes > 0 {
+		mods = append(mods, cdsclient.WithQueryParameter("debug-timeout", strconv.FormatInt(debug.TimeoutMinutes, 10)))
+	}
+	return mods
+}
+
+var workflowDebugCmd = cli.Command{
+	Name:  "debug",
+	Short: "Open a debug shell on the worker of a paused job",
+	Long: `Open an interactive shell in the workspace of a job paused in debug mode.
+
+Start a run with --debug or --debug-step to pause its jobs. The session ends at the end of the pause.
+Inside the shell, run 'worker debug continue' to resume the job.`,
+	Example: "cdsctl experimental workflow debug <proj_key> <workflow_run_id> <job_run_id>",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "proj_key"},
+		{Name: "workflow_run_id"},
+		{Name: "job_run_id"},
+	},
+}
+
+func workflowDebugFunc(v cli.Values) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Ctrl+C closes the session
+	chanSignal := make(chan os.Signal, 1)
+	signal.Notify(chanSignal, os.Interrupt)
+	defer signal.Stop(chanSignal)
+
+	msgToSend := make(chan json.RawMessage)
+	msgReceived := make(chan json.RawMessage)
+	errs := make(chan error)
+	send := func(msg sdk.V2DebugShellMessage) {
+		bts, _ := json.Marshal(msg)
+		select {
+		case msgToSend <- bts:
+		case <-ctx.Done():
+		}
+	}
+
+	goRoutines := sdk.NewGoRoutines(ctx)
+	chanDone := make(chan error, 1)
+	goRoutines.Exec(ctx, "WorkflowV2RunJobDebugShell", func(ctx context.Context) {
+		chanDone <- client.WorkflowV2RunJobDebugShell(ctx, goRoutines, v.GetString("proj_key"), v.GetString("workflow_run_id"), v.GetString("job_run_id"), msgToSend, msgReceived, errs)
+	})
+	goRoutines.Exec(ctx, "WorkflowV2RunJobDebugShellStdin", func(ctx context.Context) {
+		reader := bufio.NewReader(os.Stdin)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				send(sdk.V2DebugShellMessage{Type: sdk.V2DebugShellMessageTypeInput, Data: line})
+			}
+			if err != nil {
+				send(sdk.V2DebugShellMessage{Type: sdk.V2DebugShellMessageTypeClose})
+				return
+			}
+		}
+	})
+
+	for {
+		select {
+		case <-chanSignal:
+			send(sdk.V2DebugShellMessage{Type: sdk.V2DebugShellMessageTypeClose})
+			return nil
+		case err := <-chanDone:
+			if err != nil && ctx.Err() == nil {
+				return fmt.Errorf("debug session closed: %v", err)
+			}
+			return nil
+		case err := <-errs:
+			return err
+		case m := <-msgReceived:
+			var msg sdk.V2DebugShellMessage
+			if err := json.Unmarshal(m, &msg); err != nil {
+				continue
+			}
+			switch msg.Type {
+			case sdk.V2DebugShellMessageTypeOutput:
+				_, _ = os.Stdout.Write(msg.Data)
+			case sdk.V2DebugShellMessageTypeClose:
+				fmt.Println("Debug session closed")
+				return nil
+			}
+		}
+	}
+}
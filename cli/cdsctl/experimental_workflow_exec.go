@@ -0,0 +1,1009 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	repo "github.com/fsamin/go-repo"
+	"github.com/rockbears/yaml"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+const (
+	workflowExecSourceDir = "/cds/source"
+	workflowExecJobDir    = "/cds/job"
+	workflowExecBinary    = "/usr/local/bin/worker"
+)
+
+var workflowExecCmd = cli.Command{
+	Name:  "exec",
+	Short: "Run a workflow locally",
+	Long: `Run the jobs of a workflow file on the local host, without starting a workflow run on the CDS API.
+
+Jobs are run one by one in the order of their dependencies, with the command 'worker exec' of the CDS worker binary. They
+run in a local shell, or in a docker container of their worker model with --docker. The worker binary is given with
+--worker-binary, or found in the PATH, or downloaded from the CDS API. With --docker, the worker binary for linux is
+mounted in the containers. The workspace of each job starts with the content of the
+working copy given with --source, checkout steps are skipped. The git context is computed from the working copy.
+
+Variable sets are loaded from the project given with --project, their items can be overridden with --var and --secret.
+Secret values are never returned by the CDS API, they must be given with --secret.
+
+Action plugins, including the script plugin that runs the 'run' steps, are downloaded from the CDS API. Actions stored
+in the working copy under .cds/actions are read locally, the other ones are loaded from the CDS API.
+
+Jobs calling a reusable workflow, job templates and integrations are not supported. A job with a matrix strategy is run
+once per combination of the values given with --matrix, a key can be given several times.`,
+	Example: `cdsctl experimental workflow exec .cds/workflows/build.yml
+cdsctl experimental workflow exec .cds/workflows/build.yml --job build --var my-varset.registry=localhost:5000 --secret my-varset.token=xxx
+cdsctl experimental workflow exec .cds/workflows/build.yml --docker --project MYPROJ
+cdsctl experimental workflow exec .cds/workflows/build.yml --job build --matrix os=linux --matrix os=windows --matrix arch=amd64`,
+	Ctx: []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "workflow_file"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "job",
+			Type:  cli.FlagSlice,
+			Usage: "Run only the given jobs",
+		},
+		{
+			Name:  "var",
+			Type:  cli.FlagArray,
+			Usage: "Variable set item (varset.item=value)",
+		},
+		{
+			Name:  "secret",
+			Type:  cli.FlagArray,
+			Usage: "Secret variable set item (varset.item=value), its value is hidden in the logs",
+		},
+		{
+			Name:      "input",
+			Type:      cli.FlagArray,
+			Usage:     "Workflow input declared in on.manual.inputs (key=value). Repeat the key or give a JSON array (key='[\"a\",\"b\"]') for a multiple choice input",
+			ShortHand: "i",
+		},
+		{
+			Name:  "matrix",
+			Type:  cli.FlagArray,
+			Usage: "Matrix value used by the jobs with a matrix strategy (key=value), a key can be given several times",
+		},
+		{
+			Name:    "source",
+			Default: ".",
+			Usage:   "Working copy copied in the workspace of the jobs",
+		},
+		{
+			Name:  "workdir",
+			Usage: "Directory where the jobs workspaces and the plugins are stored (default: a temporary directory)",
+		},
+		{
+			Name:  "project",
+			Usage: "Project key used to load variable sets and actions from the CDS API",
+		},
+		{
+			Name:  "vcs",
+			Usage: "VCS server name of the working copy",
+		},
+		{
+			Name:  "repository",
+			Usage: "Repository name of the working copy (default: computed from the git remote)",
+		},
+		{
+			Name:  "docker",
+			Type:  cli.FlagBool,
+			Usage: "Run each job in a docker container of its worker model",
+		},
+		{
+			Name:  "image",
+			Usage: "Docker image used by all the jobs instead of the image of their worker model",
+		},
+		{
+			Name:  "worker-binary",
+			Usage: "Path of the CDS worker binary used to run the jobs (default: found in the PATH or downloaded from the CDS API)",
+		},
+	},
+}
+
+// workflowExec contains the data shared by the jobs of a workflow run locally
+type workflowExec struct {
+	v              cli.Values
+	workflow       sdk.V2Workflow
+	source         string
+	workdir        string
+	projKey        string
+	vcsName        string
+	repoName       string
+	ref            string
+	runContext     sdk.WorkflowRunContext
+	inputs         map[string]interface{}
+	vars           map[string]interface{}
+	sensitiveDatas []string
+	actions        map[string]sdk.V2Action
+	matrix         map[string][]string
+	jobs           sdk.JobsResultContext
+}
+
+func workflowExecFunc(v cli.Values) error {
+	ctx := context.Background()
+
+	bts, err := os.ReadFile(v.GetString("workflow_file"))
+	if err != nil {
+		return err
+	}
+	var wf sdk.V2Workflow
+	if err := yaml.Unmarshal(bts, &wf); err != nil {
+		return cli.WrapError(err, "unable to read workflow %s", v.GetString("workflow_file"))
+	}
+	if wf.From != "" {
+		return cli.NewError("workflow templates are not supported by a local run")
+	}
+
+	source, err := filepath.Abs(v.GetString("source"))
+	if err != nil {
+		return err
+	}
+	workdir := v.GetString("workdir")
+	if workdir == "" {
+		workdir, err = os.MkdirTemp("", "cdsctl-exec-")
+		if err != nil {
+			return err
+		}
+	}
+	workdir, err = filepath.Abs(workdir)
+	if err != nil {
+		return err
+	}
+
+	e := &workflowExec{
+		v:        v,
+		workflow: wf,
+		source:   source,
+		workdir:  workdir,
+		projKey:  v.GetString("project"),
+		vcsName:  v.GetString("vcs"),
+		repoName: v.GetString("repository"),
+		actions:  make(map[string]sdk.V2Action),
+		matrix:   make(map[string][]string),
+		jobs:     sdk.JobsResultContext{},
+	}
+	if err := e.init(ctx); err != nil {
+		return err
+	}
+
+	jobIDs, err := workflowExecJobsOrder(wf, v.GetStringSlice("job"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workspaces of the jobs are created in %s\n", workdir)
+	hasErrors := false
+	for _, jobID := range jobIDs {
+		status, err := e.runJob(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Job %s: %s\n", jobID, status)
+		if status == sdk.V2WorkflowRunJobStatusFail && !wf.Jobs[jobID].ContinueOnError {
+			hasErrors = true
+		}
+	}
+	if hasErrors {
+		cli.OSExit(1)
+	}
+	return nil
+}
+
+// init computes the run context from the working copy, and the inputs, vars and matrix from the flags
+func (e *workflowExec) init(ctx context.Context) error {
+	git := sdk.GitContext{
+		Server: e.vcsName,
+	}
+	r, err := repo.New(ctx, e.source)
+	if err == nil {
+		if e.repoName == "" {
+			e.repoName, _ = r.Name(ctx)
+		}
+		git.RepositoryURL, _ = r.FetchURL(ctx)
+		if branch, _ := r.CurrentBranch(ctx); branch != "" && branch != "HEAD" {
+			git.Ref = sdk.GitRefBranchPrefix + branch
+			git.RefName = branch
+			git.RefType = "branch"
+		}
+		commit, err := r.LatestCommit(ctx, repo.CommitOption{DisableDiffDetail: true})
+		if err != nil {
+			return cli.WrapError(err, "unable to get latest commit")
+		}
+		git.Sha = commit.LongHash
+		if len(commit.LongHash) >= 7 {
+			git.ShaShort = commit.LongHash[:7]
+		}
+		git.CommitMessage = commit.Subject
+		git.Author = commit.Author
+		git.AuthorEmail = commit.AuthorEmail
+	} else {
+		fmt.Printf("%s is not a git repository, the git context is empty\n", e.source)
+	}
+	git.Repository = e.repoName
+	e.ref = git.Ref
+
+	actor := ""
+	if u, err := user.Current(); err == nil {
+		actor = u.Username
+	}
+	e.runContext = sdk.WorkflowRunContext{
+		CDS: sdk.CDSContext{
+			EventName:          sdk.WorkflowHookEventNameManual,
+			ProjectKey:         e.projKey,
+			RunID:              sdk.UUID(),
+			RunNumber:          1,
+			RunAttempt:         1,
+			Workflow:           e.workflow.Name,
+			WorkflowRef:        git.Ref,
+			WorkflowSha:        git.Sha,
+			WorkflowVCSServer:  e.vcsName,
+			WorkflowRepository: e.repoName,
+			TriggeringActor:    actor,
+		},
+		Git: git,
+		Env: make(map[string]string),
+	}
+	for k, v := range e.workflow.Env {
+		e.runContext.Env[k] = v
+	}
+
+	inputs, err := workflowInputsFromFlags(e.v.GetStringArray("input"))
+	if err != nil {
+		return err
+	}
+	e.inputs = inputs
+	if e.workflow.On != nil && e.workflow.On.Manual != nil {
+		e.inputs, err = e.workflow.On.Manual.ComputeInputs(inputs)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, m := range e.v.GetStringArray("matrix") {
+		k, value, ok := strings.Cut(m, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid matrix value %q, expected key=value", m)
+		}
+		e.matrix[k] = append(e.matrix[k], value)
+	}
+
+	return e.initVars(ctx)
+}
+
+// initVars loads the variable sets used by the workflow from the CDS API, then overrides their items with the flags
+func (e *workflowExec) initVars(ctx context.Context) error {
+	e.vars = make(map[string]interface{})
+	varsetItems := func(name string) map[string]interface{} {
+		if _, has := e.vars[name]; !has {
+			e.vars[name] = make(map[string]interface{})
+		}
+		return e.vars[name].(map[string]interface{})
+	}
+
+	if e.projKey != "" {
+		varsets := append([]string{}, e.workflow.VariableSets...)
+		for _, j := range e.workflow.Jobs {
+			varsets = append(varsets, j.VariableSets...)
+		}
+		sort.Strings(varsets)
+		for i, name := range varsets {
+			if i > 0 && varsets[i-1] == name {
+				continue
+			}
+			vs, err := client.ProjectVariableSetShow(ctx, e.projKey, name)
+			if err != nil {
+				return cli.WrapError(err, "unable to load variable set %s", name)
+			}
+			items := varsetItems(name)
+			for _, item := range vs.Items {
+				if item.Type == sdk.ProjectVariableTypeSecret {
+					fmt.Printf("Secret %s.%s is not loaded, use --secret to set it\n", name, item.Name)
+					continue
+				}
+				items[item.Name] = workflowExecVarValue(item.Value)
+			}
+		}
+	}
+
+	setItems := func(flag string, secret bool) error {
+		for _, in := range e.v.GetStringArray(flag) {
+			k, value, ok := strings.Cut(in, "=")
+			varset, item, okItem := strings.Cut(k, ".")
+			if !ok || !okItem || varset == "" || item == "" {
+				return fmt.Errorf("invalid %s %q, expected varset.item=value", flag, in)
+			}
+			varsetItems(varset)[item] = workflowExecVarValue(value)
+			if secret {
+				e.sensitiveDatas = append(e.sensitiveDatas, strings.Split(value, "\n")...)
+				e.sensitiveDatas = append(e.sensitiveDatas, sdk.OneLineValue(value))
+			}
+		}
+		return nil
+	}
+	if err := setItems("var", false); err != nil {
+		return err
+	}
+	return setItems("secret", true)
+}
+
+// workflowExecVarValue returns the value of a variable set item, JSON objects and arrays are decoded
+func workflowExecVarValue(value string) interface{} {
+	if (strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")) || (strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]")) {
+		var jsonValue interface{}
+		if err := json.Unmarshal([]byte(value), &jsonValue); err == nil {
+			return jsonValue
+		}
+	}
+	return value
+}
+
+// workflowExecJobsOrder returns the jobs of the workflow sorted by dependencies. If jobs are given, only them are returned.
+func workflowExecJobsOrder(wf sdk.V2Workflow, selected []string) ([]string, error) {
+	for _, j := range selected {
+		if _, has := wf.Jobs[j]; !has {
+			return nil, fmt.Errorf("job %s not found in workflow %s", j, wf.Name)
+		}
+	}
+
+	ids := make([]string, 0, len(wf.Jobs))
+	for id := range wf.Jobs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	done := make(map[string]bool, len(ids))
+	order := make([]string, 0, len(ids))
+	for len(order) < len(ids) {
+		progress := false
+		for _, id := range ids {
+			if done[id] {
+				continue
+			}
+			ready := true
+			for _, d := range workflowExecJobDependencies(wf, id) {
+				if !done[d] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				continue
+			}
+			done[id] = true
+			order = append(order, id)
+			progress = true
+		}
+		if !progress {
+			return nil, fmt.Errorf("unable to compute the jobs order of workflow %s: circular dependencies", wf.Name)
+		}
+	}
+
+	if len(selected) == 0 {
+		return order, nil
+	}
+	filtered := make([]string, 0, len(selected))
+	for _, id := range order {
+		if sdk.IsInArray(id, selected) {
+			filtered = append(filtered, id)
+		}
+	}
+	return filtered, nil
+}
+
+// workflowExecJobDependencies returns the jobs needed by a job and the jobs of the stages needed by its stage
+func workflowExecJobDependencies(wf sdk.V2Workflow, jobID string) []string {
+	jobDef := wf.Jobs[jobID]
+	deps := append([]string{}, jobDef.Needs...)
+	if jobDef.Stage == "" {
+		return deps
+	}
+	for _, s := range wf.Stages[jobDef.Stage].Needs {
+		for id, j := range wf.Jobs {
+			if j.Stage == s {
+				deps = append(deps, id)
+			}
+		}
+	}
+	return deps
+}
+
+// runJob runs a job if its condition is true, and stores its result in the jobs context
+func (e *workflowExec) runJob(ctx context.Context, jobID string) (sdk.V2WorkflowRunJobStatus, error) {
+	jobDef := e.workflow.Jobs[jobID]
+	if jobDef.Uses != "" || jobDef.From != "" {
+		return "", cli.NewError("job %s: reusable workflows and job templates are not supported by a local run", jobID)
+	}
+	if len(jobDef.Integrations) > 0 || len(e.workflow.Integrations) > 0 {
+		fmt.Printf("Job %s: integrations are not available on a local run\n", jobID)
+	}
+
+	if !jobDef.Strategy.HasMatrix() {
+		jobResult, err := e.runJobVariant(ctx, jobID, map[string]string{})
+		if err != nil {
+			return "", err
+		}
+		e.jobs[jobID] = jobResult
+		return jobResult.Result, nil
+	}
+
+	// Like on the API, the job fails if one of its variants fails and is skipped if all its variants are skipped
+	permutations := workflowExecMatrixPermutations(e.matrix)
+	jobResult := sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSkipped, Outputs: sdk.JobResultOutput{}}
+	for _, m := range permutations {
+		variantResult, err := e.runJobVariant(ctx, jobID, m)
+		if err != nil {
+			return "", err
+		}
+		if len(permutations) > 1 {
+			fmt.Printf("Job %s %v: %s\n", jobID, m, variantResult.Result)
+		}
+		workflowExecMergeVariantResult(&jobResult, variantResult)
+	}
+	e.jobs[jobID] = jobResult
+	return jobResult.Result, nil
+}
+
+// workflowExecMergeVariantResult merges the result of a matrix variant in the result of its job
+func workflowExecMergeVariantResult(jobResult *sdk.JobResultContext, variantResult sdk.JobResultContext) {
+	switch {
+	case variantResult.Result == sdk.V2WorkflowRunJobStatusFail:
+		jobResult.Result = sdk.V2WorkflowRunJobStatusFail
+	case variantResult.Result == sdk.V2WorkflowRunJobStatusSuccess && jobResult.Result == sdk.V2WorkflowRunJobStatusSkipped:
+		jobResult.Result = sdk.V2WorkflowRunJobStatusSuccess
+	}
+	for k, v := range variantResult.Outputs {
+		jobResult.Outputs[k] = v
+	}
+	for k, v := range variantResult.JobRunResults {
+		if jobResult.JobRunResults == nil {
+			jobResult.JobRunResults = sdk.JobRunResults{}
+		}
+		jobResult.JobRunResults[k] = v
+	}
+}
+
+// workflowExecMatrixPermutations returns all the combinations of the matrix values, sorted by key
+func workflowExecMatrixPermutations(matrix map[string][]string) []map[string]string {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	permutations := []map[string]string{{}}
+	for _, k := range keys {
+		next := make([]map[string]string, 0, len(permutations)*len(matrix[k]))
+		for _, p := range permutations {
+			for _, v := range matrix[k] {
+				m := make(map[string]string, len(p)+1)
+				for pk, pv := range p {
+					m[pk] = pv
+				}
+				m[k] = v
+				next = append(next, m)
+			}
+		}
+		permutations = next
+	}
+	return permutations
+}
+
+// runJobVariant runs a job with the given matrix values
+func (e *workflowExec) runJobVariant(ctx context.Context, jobID string, matrix map[string]string) (sdk.JobResultContext, error) {
+	jobDef := e.workflow.Jobs[jobID]
+
+	contexts := sdk.WorkflowRunJobsContext{
+		WorkflowRunContext: e.runContext,
+		Inputs:             e.inputs,
+		Jobs:               sdk.JobsResultContext{},
+		Needs:              sdk.NeedsContext{},
+		Matrix:             matrix,
+		Vars:               e.vars,
+		Integrations:       &sdk.JobIntegrationsContexts{},
+	}
+	contexts.CDS.Job = jobID
+	contexts.CDS.Stage = jobDef.Stage
+	contexts.Env = make(map[string]string)
+	for k, v := range e.runContext.Env {
+		contexts.Env[k] = v
+	}
+	for k, v := range jobDef.Env {
+		contexts.Env[k] = v
+	}
+	for k, v := range e.jobs {
+		contexts.Jobs[k] = v
+	}
+	for _, n := range jobDef.Needs {
+		j, has := e.jobs[n]
+		if !has {
+			continue
+		}
+		needContext := sdk.NeedContext{
+			Result:  j.Result,
+			Outputs: j.Outputs,
+		}
+		if j.Result == sdk.V2WorkflowRunJobStatusFail && e.workflow.Jobs[n].ContinueOnError {
+			needContext.Result = sdk.V2WorkflowRunJobStatusSuccess
+		}
+		contexts.Needs[n] = needContext
+	}
+	canRun, err := e.checkJobCondition(ctx, jobID, contexts)
+	if err != nil {
+		return sdk.JobResultContext{}, cli.WrapError(err, "job %s", jobID)
+	}
+	if !canRun {
+		return sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSkipped, Outputs: sdk.JobResultOutput{}}, nil
+	}
+
+	if err := e.resolveActions(ctx, jobDef.Steps); err != nil {
+		return sdk.JobResultContext{}, cli.WrapError(err, "job %s", jobID)
+	}
+
+	now := time.Now()
+	job := sdk.V2TakeJobResponse{
+		RunJob: sdk.V2WorkflowRunJob{
+			ID:            sdk.UUID(),
+			JobID:         jobID,
+			WorkflowRunID: e.runContext.CDS.RunID,
+			ProjectKey:    e.projKey,
+			VCSServer:     e.vcsName,
+			Repository:    e.repoName,
+			WorkflowName:  e.workflow.Name,
+			RunNumber:     e.runContext.CDS.RunNumber,
+			RunAttempt:    e.runContext.CDS.RunAttempt,
+			Status:        sdk.V2WorkflowRunJobStatusBuilding,
+			Queued:        now,
+			Started:       &now,
+			Job:           jobDef,
+			StepsStatus:   sdk.JobStepsStatus{},
+			Region:        jobDef.Region,
+			Matrix:        contexts.Matrix,
+		},
+		AsCodeActions:  e.actions,
+		Contexts:       contexts,
+		SensitiveDatas: e.sensitiveDatas,
+	}
+
+	var res *sdk.V2LocalRunJobResult
+	if e.v.GetBool("docker") {
+		res, err = e.runJobInDocker(ctx, job)
+	} else {
+		res, err = e.runJobLocally(ctx, job)
+	}
+	if err != nil {
+		return sdk.JobResultContext{}, cli.WrapError(err, "job %s", jobID)
+	}
+
+	jobResult := sdk.JobResultContext{
+		Result:  res.Result.Status,
+		Outputs: sdk.JobResultOutput{},
+	}
+	for i := range res.RunResults {
+		r := &res.RunResults[i]
+		switch r.Type {
+		case sdk.V2WorkflowRunResultTypeVariable, sdk.V2WorkflowRunResultVariableDetailType:
+			x, err := sdk.GetConcreteDetail[*sdk.V2WorkflowRunResultVariableDetail](r)
+			if err != nil {
+				return sdk.JobResultContext{}, cli.WrapError(err, "job %s: unable to read result %s", jobID, r.Name())
+			}
+			jobResult.Outputs[x.Name] = x.Value
+		default:
+			if jobResult.JobRunResults == nil {
+				jobResult.JobRunResults = sdk.JobRunResults{}
+			}
+			jobResult.JobRunResults[r.Name()], _ = r.GetDetailLightForContext()
+		}
+	}
+	if res.Result.Error != "" {
+		fmt.Printf("Job %s: %s\n", jobID, res.Result.Error)
+	}
+	return jobResult, nil
+}
+
+// checkJobCondition checks the gate and the condition of a job. Jobs of a stage are skipped if a job of the stages it needs failed.
+func (e *workflowExec) checkJobCondition(ctx context.Context, jobID string, contexts sdk.WorkflowRunJobsContext) (bool, error) {
+	jobDef := e.workflow.Jobs[jobID]
+	for _, d := range workflowExecJobDependencies(e.workflow, jobID) {
+		if sdk.IsInArray(d, jobDef.Needs) {
+			continue
+		}
+		if j, has := e.jobs[d]; has && j.Result == sdk.V2WorkflowRunJobStatusFail && !e.workflow.Jobs[d].ContinueOnError {
+			return false, nil
+		}
+	}
+
+	// A local run is a manual run: gates are opened with their default inputs
+	if jobDef.Gate != "" {
+		gate := e.workflow.Gates[jobDef.Gate]
+		contexts.Gate = make(map[string]interface{})
+		for k, v := range gate.Inputs {
+			contexts.Gate[k] = v.Default
+		}
+		contexts.Gate["manual"] = true
+		ok, err := workflowExecCondition(ctx, gate.If, contexts)
+		if err != nil || !ok {
+			return ok, err
+		}
+		if jobDef.If == "" {
+			return true, nil
+		}
+	}
+	return workflowExecCondition(ctx, jobDef.If, contexts)
+}
+
+func workflowExecCondition(ctx context.Context, condition string, contexts sdk.WorkflowRunJobsContext) (bool, error) {
+	if condition == "" {
+		condition = "${{success()}}"
+	}
+	if !strings.HasPrefix(condition, "${{") {
+		condition = fmt.Sprintf("${{ %s }}", condition)
+	}
+	bts, err := json.Marshal(contexts)
+	if err != nil {
+		return false, err
+	}
+	var mapContexts map[string]interface{}
+	if err := json.Unmarshal(bts, &mapContexts); err != nil {
+		return false, err
+	}
+	ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
+	result, err := ap.InterpolateToBool(ctx, condition)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse statement %s into a boolean: %v", condition, err)
+	}
+	return result, nil
+}
+
+// resolveActions replaces the actions used by the steps with their complete name, and stores their definition.
+// Actions of the working copy are read locally, the other ones are loaded from the CDS API.
+func (e *workflowExec) resolveActions(ctx context.Context, steps []sdk.ActionStep) error {
+	for i := range steps {
+		step := &steps[i]
+		if step.Uses == "" {
+			continue
+		}
+		if _, has := e.actions[strings.TrimPrefix(step.Uses, "actions/")]; has {
+			continue
+		}
+
+		var act sdk.V2Action
+		var completeName string
+		switch {
+		case strings.HasPrefix(step.Uses, ".cds/actions/"):
+			bts, err := os.ReadFile(filepath.Join(e.source, step.Uses))
+			if err != nil {
+				return fmt.Errorf("unable to read action %s: %v", step.Uses, err)
+			}
+			if err := yaml.Unmarshal(bts, &act); err != nil {
+				return fmt.Errorf("unable to read action %s: %v", step.Uses, err)
+			}
+			completeName = fmt.Sprintf("%s/%s/%s/%s@%s", e.projKey, e.vcsName, e.repoName, act.Name, e.ref)
+		case strings.HasPrefix(step.Uses, "actions/"):
+			actionName, ref, _ := strings.Cut(strings.TrimPrefix(step.Uses, "actions/"), "@")
+			path := strings.Split(actionName, "/")
+			projKey, vcsName, repoName := e.projKey, e.vcsName, e.repoName
+			switch len(path) {
+			case 1:
+				// Action plugin
+				continue
+			case 3:
+				repoName = path[0] + "/" + path[1]
+			case 4:
+				vcsName = path[0]
+				repoName = path[1] + "/" + path[2]
+			case 5:
+				projKey = path[0]
+				vcsName = path[1]
+				repoName = path[2] + "/" + path[3]
+			default:
+				return fmt.Errorf("unable to parse the action %s", step.Uses)
+			}
+			name := path[len(path)-1]
+			if ref == "" {
+				ref = e.ref
+			}
+			var err error
+			act, err = e.loadAction(ctx, projKey, vcsName, repoName, name, ref)
+			if err != nil {
+				return err
+			}
+			completeName = fmt.Sprintf("%s/%s/%s/%s@%s", projKey, vcsName, repoName, name, ref)
+		default:
+			return fmt.Errorf("unable to parse the action %s", step.Uses)
+		}
+
+		step.Uses = "actions/" + completeName
+		e.actions[completeName] = act
+		if err := e.resolveActions(ctx, act.Runs.Steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadAction reads an action of the working copy, or loads it from the CDS API if it's stored in another repository
+func (e *workflowExec) loadAction(ctx context.Context, projKey, vcsName, repoName, name, ref string) (sdk.V2Action, error) {
+	var act sdk.V2Action
+	if projKey == e.projKey && vcsName == e.vcsName && repoName == e.repoName && ref == e.ref {
+		files, err := filepath.Glob(filepath.Join(e.source, ".cds", "actions", "*.y*ml"))
+		if err != nil {
+			return act, err
+		}
+		for _, f := range files {
+			bts, err := os.ReadFile(f)
+			if err != nil {
+				return act, err
+			}
+			if err := yaml.Unmarshal(bts, &act); err == nil && act.Name == name {
+				return act, nil
+			}
+		}
+	}
+
+	if projKey == "" || vcsName == "" {
+		return act, fmt.Errorf("unable to find action %s/%s/%s/%s: --project and --vcs are required to load it", projKey, vcsName, repoName, name)
+	}
+	var mods []cdsclient.RequestModifier
+	switch {
+	case strings.HasPrefix(ref, sdk.GitRefBranchPrefix) || strings.HasPrefix(ref, sdk.GitRefTagPrefix):
+		mods = append(mods, cdsclient.WithQueryParameter("ref", ref))
+	case ref != "":
+		mods = append(mods, cdsclient.WithQueryParameter("branch", ref))
+	}
+	ent, err := client.EntityGet(ctx, projKey, vcsName, repoName, sdk.EntityTypeAction, name, mods...)
+	if err != nil {
+		return act, cli.WrapError(err, "unable to load action %s/%s/%s/%s", projKey, vcsName, repoName, name)
+	}
+	if err := yaml.Unmarshal([]byte(ent.Data), &act); err != nil {
+		return act, fmt.Errorf("unable to read action %s: %v", name, err)
+	}
+	return act, nil
+}
+
+// workerBinary returns the path of the worker binary for the given os: the binary given with --worker-binary, the
+// worker found in the PATH, or the worker downloaded from the CDS API in the work directory
+func (e *workflowExec) workerBinary(goos string) (string, error) {
+	if bin := e.v.GetString("worker-binary"); bin != "" {
+		return filepath.Abs(bin)
+	}
+	if goos == sdk.GOOS {
+		if bin, err := exec.LookPath("worker"); err == nil {
+			return filepath.Abs(bin)
+		}
+	}
+
+	bin := filepath.Join(e.workdir, "bin", sdk.BinaryFilename("worker", goos, sdk.GOARCH, ""))
+	if _, err := os.Stat(bin); err == nil {
+		return bin, nil
+	}
+	urlBinary := client.DownloadURLFromAPI("worker", goos, sdk.GOARCH, "")
+	fmt.Printf("Downloading worker binary from %s\n", urlBinary)
+	resp, err := http.Get(urlBinary)
+	if err != nil {
+		return "", cli.WrapError(err, "unable to download the worker binary, use --worker-binary")
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode != http.StatusOK {
+		return "", cli.NewError("unable to download the worker binary from %s: http code %d, use --worker-binary", urlBinary, resp.StatusCode)
+	}
+	if err := sdk.CheckContentTypeBinary(resp); err != nil {
+		return "", cli.NewError("unable to download the worker binary from %s: %v, use --worker-binary", urlBinary, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(bin), os.FileMode(0755)); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(bin, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0755))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close() // nolint
+		_ = os.Remove(bin)
+		return "", err
+	}
+	return bin, f.Close()
+}
+
+// workerEnv returns the environment variables used by the worker to download the action plugins from the CDS API
+func workerEnv() map[string]string {
+	envs := make(map[string]string)
+	if cfg != nil {
+		envs["CDS_API_URL"] = cfg.Host
+		envs["CDS_SESSION_TOKEN"] = cfg.SessionToken
+		if cfg.InsecureSkipVerifyTLS {
+			envs["CDS_INSECURE"] = "true"
+		}
+	}
+	return envs
+}
+
+// workerLogLevel returns the log level of the worker engine, its logs are displayed only in verbose mode
+func workerLogLevel() string {
+	if cli.Verbose {
+		return "debug"
+	}
+	return "error"
+}
+
+// prepareJobDirectory writes the job in the directory exchanged with the worker exec command
+func (e *workflowExec) prepareJobDirectory(job sdk.V2TakeJobResponse) (string, error) {
+	jobDir := filepath.Join(e.workdir, job.RunJob.JobID)
+	if err := os.MkdirAll(jobDir, os.FileMode(0755)); err != nil {
+		return "", err
+	}
+	bts, err := json.Marshal(job)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(jobDir, sdk.V2LocalRunJobFile), bts, os.FileMode(0600)); err != nil {
+		return "", err
+	}
+	_ = os.Remove(filepath.Join(jobDir, sdk.V2LocalRunResultFile))
+	return jobDir, nil
+}
+
+// runWorkerExec runs the given command and reads the result written by the worker exec command in the job directory
+func runWorkerExec(cmd *exec.Cmd, jobDir string) (*sdk.V2LocalRunJobResult, error) {
+	resultFile := filepath.Join(jobDir, sdk.V2LocalRunResultFile)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if _, errStat := os.Stat(resultFile); errStat != nil {
+			return nil, fmt.Errorf("unable to run the worker: %v", err)
+		}
+	}
+	bts, err := os.ReadFile(resultFile)
+	if err != nil {
+		return nil, err
+	}
+	var res sdk.V2LocalRunJobResult
+	if err := json.Unmarshal(bts, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// runJobLocally runs worker exec on the local host, the workspaces of the jobs are created in the work directory
+func (e *workflowExec) runJobLocally(ctx context.Context, job sdk.V2TakeJobResponse) (*sdk.V2LocalRunJobResult, error) {
+	bin, err := e.workerBinary(sdk.GOOS)
+	if err != nil {
+		return nil, err
+	}
+	jobDir, err := e.prepareJobDirectory(job)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, bin, "exec", jobDir, "--source", e.source, "--basedir", e.workdir, "--log-level", workerLogLevel())
+	cmd.Env = os.Environ()
+	for k, v := range workerEnv() {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	return runWorkerExec(cmd, jobDir)
+}
+
+// runJobInDocker runs worker exec in a container, the job and its result are exchanged through a mounted directory
+func (e *workflowExec) runJobInDocker(ctx context.Context, job sdk.V2TakeJobResponse) (*sdk.V2LocalRunJobResult, error) {
+	image, envs, err := e.jobImage(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	bin, err := e.workerBinary("linux")
+	if err != nil {
+		return nil, err
+	}
+	jobDir, err := e.prepareJobDirectory(job)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "--rm",
+		"-v", bin + ":" + workflowExecBinary + ":ro",
+		"-v", e.source + ":" + workflowExecSourceDir + ":ro",
+		"-v", jobDir + ":" + workflowExecJobDir,
+	}
+	for k, v := range workerEnv() {
+		envs[k] = v
+	}
+	for k, v := range envs {
+		args = append(args, "-e", k+"="+v)
+	}
+	args = append(args, image, workflowExecBinary, "exec", workflowExecJobDir, "--source", workflowExecSourceDir, "--log-level", workerLogLevel())
+
+	fmt.Printf("Job %s: starting container %s\n", job.RunJob.JobID, image)
+	return runWorkerExec(exec.CommandContext(ctx, "docker", args...), jobDir)
+}
+
+// jobImage returns the docker image and the environment variables of the worker model used by a job
+func (e *workflowExec) jobImage(ctx context.Context, job sdk.V2TakeJobResponse) (string, map[string]string, error) {
+	envs := make(map[string]string)
+	if image := e.v.GetString("image"); image != "" {
+		return image, envs, nil
+	}
+
+	modelName := job.RunJob.Job.RunsOn.Model
+	if strings.Contains(modelName, "${{") {
+		bts, _ := json.Marshal(job.Contexts)
+		var mapContexts map[string]interface{}
+		if err := json.Unmarshal(bts, &mapContexts); err != nil {
+			return "", nil, err
+		}
+		var err error
+		modelName, err = sdk.NewActionParser(mapContexts, sdk.DefaultFuncs).InterpolateToString(ctx, modelName)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to interpolate worker model %s: %v", job.RunJob.Job.RunsOn.Model, err)
+		}
+	}
+
+	var wm sdk.V2WorkerModel
+	if strings.HasPrefix(modelName, ".cds/worker-models/") {
+		bts, err := os.ReadFile(filepath.Join(e.source, modelName))
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to read worker model %s: %v", modelName, err)
+		}
+		if err := yaml.Unmarshal(bts, &wm); err != nil {
+			return "", nil, fmt.Errorf("unable to read worker model %s: %v", modelName, err)
+		}
+	} else {
+		name, ref, _ := strings.Cut(modelName, "@")
+		path := strings.Split(name, "/")
+		projKey, vcsName, repoName := e.projKey, e.vcsName, e.repoName
+		switch len(path) {
+		case 3:
+			repoName = path[0] + "/" + path[1]
+		case 4:
+			vcsName = path[0]
+			repoName = path[1] + "/" + path[2]
+		case 5:
+			projKey = path[0]
+			vcsName = path[1]
+			repoName = path[2] + "/" + path[3]
+		default:
+			return "", nil, fmt.Errorf("unable to find the image of worker model %q, use --image", modelName)
+		}
+		if projKey == "" || vcsName == "" {
+			return "", nil, fmt.Errorf("unable to load worker model %s: --project and --vcs are required to load it, or use --image", modelName)
+		}
+		var mods []cdsclient.RequestModifier
+		if ref != "" {
+			mods = append(mods, cdsclient.WithQueryParameter("branch", ref))
+		}
+		ent, err := client.EntityGet(ctx, projKey, vcsName, repoName, sdk.EntityTypeWorkerModel, path[len(path)-1], mods...)
+		if err != nil {
+			return "", nil, cli.WrapError(err, "unable to load worker model %s", modelName)
+		}
+		if err := yaml.Unmarshal([]byte(ent.Data), &wm); err != nil {
+			return "", nil, fmt.Errorf("unable to read worker model %s: %v", modelName, err)
+		}
+	}
+
+	if wm.Type != sdk.WorkerModelTypeDocker {
+		return "", nil, fmt.Errorf("worker model %s is not a docker model, use --image", modelName)
+	}
+	var spec sdk.V2WorkerModelDockerSpec
+	if err := json.Unmarshal(wm.Spec, &spec); err != nil {
+		return "", nil, fmt.Errorf("unable to read worker model %s: %v", modelName, err)
+	}
+	for k, v := range spec.Envs {
+		envs[k] = v
+	}
+	return spec.Image, envs, nil
+}
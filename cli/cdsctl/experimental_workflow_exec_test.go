@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowExecMatrixPermutations(t *testing.T) {
+	// Without --matrix, a matrix job runs once with an empty matrix
+	require.Equal(t, []map[string]string{{}}, workflowExecMatrixPermutations(map[string][]string{}))
+
+	require.Equal(t, []map[string]string{
+		{"arch": "amd64", "os": "linux"},
+		{"arch": "amd64", "os": "windows"},
+		{"arch": "arm64", "os": "linux"},
+		{"arch": "arm64", "os": "windows"},
+	}, workflowExecMatrixPermutations(map[string][]string{
+		"os":   {"linux", "windows"},
+		"arch": {"amd64", "arm64"},
+	}))
+}
+
+func TestWorkflowExecMergeVariantResult(t *testing.T) {
+	jobResult := sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSkipped, Outputs: sdk.JobResultOutput{}}
+
+	workflowExecMergeVariantResult(&jobResult, sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSkipped})
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSkipped, jobResult.Result)
+
+	workflowExecMergeVariantResult(&jobResult, sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSuccess, Outputs: sdk.JobResultOutput{"version": "1.0.0"}})
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSuccess, jobResult.Result)
+
+	workflowExecMergeVariantResult(&jobResult, sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusFail})
+	require.Equal(t, sdk.V2WorkflowRunJobStatusFail, jobResult.Result)
+
+	workflowExecMergeVariantResult(&jobResult, sdk.JobResultContext{Result: sdk.V2WorkflowRunJobStatusSuccess})
+	require.Equal(t, sdk.V2WorkflowRunJobStatusFail, jobResult.Result)
+	require.Equal(t, "1.0.0", jobResult.Outputs["version"])
+}
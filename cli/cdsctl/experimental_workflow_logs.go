@@ -6,11 +6,13 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/ovh/cds/cli"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
 )
 
 var experimentalWorkflowRunJobsCmd = cli.Command{
@@ -22,6 +24,8 @@ var experimentalWorkflowRunJobsCmd = cli.Command{
 func experimentalWorkflowRunLogs() *cobra.Command {
 	return cli.NewCommand(experimentalWorkflowRunJobsCmd, nil, []*cobra.Command{
 		cli.NewCommand(workflowRunJobLogsDownloadCmd, workflowRunJobLogsDownloadFunc, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowRunJobLogsSearchCmd, workflowRunJobLogsSearchFunc, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowRunJobLogsFollowCmd, workflowRunJobLogsFollowFunc, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -107,6 +111,176 @@ func workflowRunJobLogsDownloadFunc(v cli.Values) error {
 	return nil
 }
 
+var workflowRunJobLogsSearchCmd = cli.Command{
+	Name:  "search",
+	Short: "Search a text or a regex in the job step logs of a project",
+	Long: `Search a text or a regex in the job step logs of a project, latest logs first.
+
+A search returns at most --limit lines (1000 max) and scans the logs of the latest 500 job steps matching the filters.
+When a limit is reached, a warning is displayed: use --workflow, --run or --job to search in older logs.`,
+	Example: "cdsctl experimental workflow logs search <proj_key> OOMKilled --workflow my-workflow",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "proj_key"},
+		{Name: "pattern"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "workflow",
+			Usage: "Filter on workflow name",
+		},
+		{
+			Name:  "run",
+			Usage: "Filter on workflow run ID",
+		},
+		{
+			Name:  "job",
+			Usage: "Filter on job run ID",
+		},
+		{
+			Name:  "regex",
+			Usage: "Use the pattern as a regular expression",
+			Type:  cli.FlagBool,
+		},
+		{
+			Name:  "limit",
+			Usage: "Maximum count of lines returned",
+		},
+	},
+}
+
+type workflowRunJobLogsSearchLine struct {
+	Workflow  string `cli:"workflow"`
+	RunNumber int64  `cli:"run_number"`
+	Job       string `cli:"job"`
+	Step      string `cli:"step"`
+	Line      int64  `cli:"line"`
+	Value     string `cli:"value"`
+}
+
+func workflowRunJobLogsSearchFunc(v cli.Values) (cli.ListResult, error) {
+	mods := []cdsclient.RequestModifier{cdsclient.WithQueryParameter("regex", fmt.Sprintf("%t", v.GetBool("regex")))}
+	for _, f := range []string{"workflow", "run", "job", "limit"} {
+		if v.GetString(f) != "" {
+			mods = append(mods, cdsclient.WithQueryParameter(f, v.GetString(f)))
+		}
+	}
+
+	search, err := client.WorkflowV2LogsSearch(context.Background(), v.GetString("proj_key"), v.GetString("pattern"), mods...)
+	if err != nil {
+		return nil, err
+	}
+	if search.Truncated {
+		fmt.Fprintln(os.Stderr, "Search limits reached, older lines can match: add filters to narrow the search")
+	}
+
+	res := make([]workflowRunJobLogsSearchLine, 0, len(search.Lines))
+	for _, l := range search.Lines {
+		res = append(res, workflowRunJobLogsSearchLine{
+			Workflow:  l.APIRef.WorkflowName,
+			RunNumber: l.APIRef.RunNumber,
+			Job:       l.APIRef.RunJobName,
+			Step:      l.APIRef.StepName,
+			Line:      l.Number,
+			Value:     l.Value,
+		})
+	}
+	return cli.AsListResult(res), nil
+}
+
+var workflowRunJobLogsFollowCmd = cli.Command{
+	Name:    "follow",
+	Aliases: []string{"tail"},
+	Short:   "Stream the job step logs of a workflow run until it ends, the command fails if the run is not successful",
+	Example: "cdsctl experimental workflow logs follow <proj_key> <workflow_run_id> [job]",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "proj_key"},
+		{Name: "workflow_run_id"},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "job"},
+	},
+}
+
+// workflowRunLogsFollowGracePeriod is the maximum time to wait for the last lines once the run is terminated
+const workflowRunLogsFollowGracePeriod = 30 * time.Second
+
+var workflowRunLogsFollowColors = []func(format string, a ...interface{}) string{cli.Cyan, cli.Yellow, cli.Green, cli.Magenta, cli.Blue}
+
+func workflowRunJobLogsFollowFunc(v cli.Values) error {
+	projKey := v.GetString("proj_key")
+	workflowRunID := v.GetString("workflow_run_id")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mods []cdsclient.RequestModifier
+	if v.GetString("job") != "" {
+		mods = append(mods, cdsclient.WithQueryParameter("job", v.GetString("job")))
+	}
+
+	events := make(chan sdk.CDNLogFollowEvent)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- client.WorkflowV2LogsFollow(ctx, projKey, workflowRunID, events, mods...)
+	}()
+
+	statusTicker := time.NewTicker(2 * time.Second)
+	defer statusTicker.Stop()
+
+	var run *sdk.V2WorkflowRun
+	var terminated time.Time
+	incoming := -1
+	prefixColors := make(map[string]func(format string, a ...interface{}) string)
+	var prefixWidth int
+	for {
+		select {
+		case err := <-streamErr:
+			if err != nil {
+				return err
+			}
+			return cli.NewError("log stream of workflow run %s was closed", workflowRunID)
+		case e := <-events:
+			if e.Status != nil {
+				incoming = e.Status.Incoming
+			}
+			if e.Line == nil {
+				continue
+			}
+			prefix := e.Line.APIRef.RunJobName + "/" + e.Line.APIRef.StepName
+			color, has := prefixColors[prefix]
+			if !has {
+				color = workflowRunLogsFollowColors[len(prefixColors)%len(workflowRunLogsFollowColors)]
+				prefixColors[prefix] = color
+			}
+			if len(prefix) > prefixWidth {
+				prefixWidth = len(prefix)
+			}
+			fmt.Printf("%s %s\n", color("%-*s |", prefixWidth, prefix), e.Line.Value)
+		case <-statusTicker.C:
+			var err error
+			run, err = client.WorkflowV2RunStatus(ctx, projKey, workflowRunID)
+			if err != nil {
+				return err
+			}
+			if run.Status.IsTerminated() && terminated.IsZero() {
+				terminated = time.Now()
+			}
+		}
+
+		// Once the run is terminated, wait for all the logs to be received
+		if !terminated.IsZero() && (incoming == 0 || time.Since(terminated) > workflowRunLogsFollowGracePeriod) {
+			break
+		}
+	}
+
+	if run.Status != sdk.V2WorkflowRunStatusSuccess {
+		return cli.NewError("workflow run %s ended with status %s", workflowRunID, run.Status)
+	}
+	return nil
+}
+
 func getFileName(rj sdk.V2WorkflowRunJob, name string) string {
 	return fmt.Sprintf("%s-%d-%d-%s-%s", rj.WorkflowName, rj.RunNumber, rj.RunAttempt, rj.JobID, name)
 }
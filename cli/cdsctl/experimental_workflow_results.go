@@ -25,9 +25,73 @@ func experimentalWorkflowResult() *cobra.Command {
 	return cli.NewCommand(experimentalWorkflowResultCmd, nil, []*cobra.Command{
 		cli.NewListCommand(workflowV2RunResultListCmd, workflowV2RunResultListFunc, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowV2RunResultDownloadCmd, workflowV2RunResultDownloadFunc, nil, withAllCommandModifiers()...),
+		experimentalWorkflowResultShare(),
 	})
 }
 
+var experimentalWorkflowResultShareCmd = cli.Command{
+	Name:  "share",
+	Short: "Manage public download links of a run result",
+}
+
+func experimentalWorkflowResultShare() *cobra.Command {
+	return cli.NewCommand(experimentalWorkflowResultShareCmd, nil, []*cobra.Command{
+		cli.NewGetCommand(workflowV2RunResultShareCreateCmd, workflowV2RunResultShareCreateFunc, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(workflowV2RunResultShareListCmd, workflowV2RunResultShareListFunc, nil, withAllCommandModifiers()...),
+	})
+}
+
+var workflowV2RunResultShareCreateCmd = cli.Command{
+	Name:    "create",
+	Aliases: []string{"add"},
+	Short:   "Create a public download link of a run result stored on CDN",
+	Long:    "The link can be used without CDS account until it expires. A single use link can only be downloaded once.",
+	Example: "cdsctl experimental workflow results share create <project_key> <run_identifier> <result_id> --ttl 2h --single-use",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "proj_key"},
+		{Name: "run_identifier"},
+		{Name: "result_id"},
+	},
+	Flags: []cli.Flag{
+		{Name: "ttl", Usage: "Validity of the link (ex: 30m, 12h), default to 24h"},
+		{Name: "single-use", Type: cli.FlagBool, Default: "false", Usage: "The link can only be downloaded once"},
+	},
+}
+
+func workflowV2RunResultShareCreateFunc(v cli.Values) (interface{}, error) {
+	req := sdk.V2WorkflowRunResultShareRequest{
+		TTL:       v.GetString("ttl"),
+		SingleUse: v.GetBool("single-use"),
+	}
+	share, err := client.WorkflowV2RunResultShare(context.Background(), v.GetString("proj_key"), v.GetString("run_identifier"), v.GetString("result_id"), req)
+	if err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+var workflowV2RunResultShareListCmd = cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Short:   "List the public download links of a run result",
+	Example: "cdsctl experimental workflow results share list <project_key> <run_identifier> <result_id>",
+	Ctx:     []cli.Arg{},
+	Args: []cli.Arg{
+		{Name: "proj_key"},
+		{Name: "run_identifier"},
+		{Name: "result_id"},
+	},
+}
+
+func workflowV2RunResultShareListFunc(v cli.Values) (cli.ListResult, error) {
+	shares, err := client.WorkflowV2RunResultShareList(context.Background(), v.GetString("proj_key"), v.GetString("run_identifier"), v.GetString("result_id"))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(shares), nil
+}
+
 var workflowV2RunResultDownloadCmd = cli.Command{
 	Name:    "download",
 	Aliases: []string{"dl", "get"},
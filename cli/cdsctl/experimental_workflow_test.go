@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/cli"
+)
+
+func TestWorkflowRestartCheckFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  cli.Values
+		wantErr string
+	}{
+		{
+			name:   "failed jobs by default",
+			values: cli.Values{},
+		},
+		{
+			name:   "failed only",
+			values: cli.Values{"failed-only": {"true"}},
+		},
+		{
+			name:   "job and its dependents",
+			values: cli.Values{"failed-only": {"false"}, "job": {"build"}},
+		},
+		{
+			name:    "failed only and job",
+			values:  cli.Values{"failed-only": {"true"}, "job": {"build"}},
+			wantErr: "--failed-only and --job cannot be used together",
+		},
+		{
+			name:    "failed only and inputs",
+			values:  cli.Values{"failed-only": {"true"}, "inputs": {`{"deploy":{"env":"prod"}}`}},
+			wantErr: "inputs cannot be used with --failed-only or --job",
+		},
+		{
+			name:    "job and inputs file",
+			values:  cli.Values{"job": {"build"}, "inputs-file": {"inputs.yml"}},
+			wantErr: "inputs cannot be used with --failed-only or --job",
+		},
+		{
+			name:   "gated jobs with inputs",
+			values: cli.Values{"inputs": {`{"deploy":{"env":"prod"}}`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := workflowRestartCheckFlags(tt.values)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWorkflowInputsFromFlags(t *testing.T) {
+	inputs, err := workflowInputsFromFlags([]string{"env=prod", "targets=eu", "targets=us", `regions=["gra","sbg"]`, "regions=bhs", "query=a=b"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"env":     "prod",
+		"targets": []interface{}{"eu", "us"},
+		"regions": []interface{}{"gra", "sbg", "bhs"},
+		"query":   "a=b",
+	}, inputs)
+
+	_, err = workflowInputsFromFlags([]string{"env"})
+	require.Error(t, err)
+
+	_, err = workflowInputsFromFlags([]string{"regions=[gra"})
+	require.Error(t, err)
+}
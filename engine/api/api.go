@@ -992,6 +992,9 @@ func (a *API) Serve(ctx context.Context) error {
 	a.GoRoutines.RunWithRestart(ctx, "api.StopDeadJobs", func(ctx context.Context) {
 		a.StopDeadJobs(ctx)
 	})
+	a.GoRoutines.RunWithRestart(ctx, "api.StopTimedOutJobs", func(ctx context.Context) {
+		a.StopTimedOutJobs(ctx)
+	})
 	a.GoRoutines.RunWithRestart(ctx, "api.StopUnStartedJobs", func(ctx context.Context) {
 		a.StopUnstartedJobs(ctx)
 	})
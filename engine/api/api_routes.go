@@ -456,6 +456,7 @@ func (api *API) InitRouter() {
 	r.Handle("/v2/hooks/workflows", Scope(sdk.AuthConsumerScopeHooks), r.POSTv2(api.postRetrieveWorkflowToTriggerHandler))
 	r.Handle("/v2/hooks/workflows/hook/{hookID}", Scope(sdk.AuthConsumerScopeHooks), r.GETv2(api.getV2WorkflowHookHandler))
 	r.Handle("/v2/hooks/workflows/hooks/schedulers", Scope(sdk.AuthConsumerScopeHooks), r.GETv2(api.getV2AllSchedulerHooksHandler))
+	r.Handle("/v2/hooks/workflows/hooks/messagebus", Scope(sdk.AuthConsumerScopeHooks), r.GETv2(api.getV2AllMessageBusHooksHandler))
 	r.Handle("/v2/hooks/event/signKey", Scope(sdk.AuthConsumerScopeHooks), r.POSTv2(api.postHookEventRetrieveSignKeyHandler))
 	r.Handle("/v2/hooks/event/signKey/{uuid}", Scope(sdk.AuthConsumerScopeHooks), r.GETv2(api.getRetrieveSignKeyOperationHandler))
 	r.Handle("/v2/hooks/event/user", Scope(sdk.AuthConsumerScopeHooks), r.POSTv2(api.postRetrieveEventUserHandler))
@@ -475,6 +476,8 @@ func (api *API) InitRouter() {
 
 	r.Handle("/v2/region", Scope(sdk.AuthConsumerScopeAdmin), r.POSTv2(api.postRegionHandler), r.GETv2(api.getRegionsHandler))
 	r.Handle("/v2/region/{regionIdentifier}", Scope(sdk.AuthConsumerScopeAdmin), r.GETv2(api.getRegionHandler), r.DELETEv2(api.deleteRegionHandler))
+	r.Handle("/v2/region/{regionIdentifier}/weight", Scope(sdk.AuthConsumerScopeAdmin), r.GETv2(api.getRegionProjectWeightsHandler), r.POSTv2(api.postRegionProjectWeightHandler))
+	r.Handle("/v2/region/{regionIdentifier}/weight/{projectKey}", Scope(sdk.AuthConsumerScopeAdmin), r.DELETEv2(api.deleteRegionProjectWeightHandler))
 
 	r.Handle("/v2/migrate/project/{projectKey}/variableset/item", Scope(sdk.AuthConsumerScopeProject), r.POSTv2(api.postMigrateProjectVariableHandler))
 	r.Handle("/v2/migrate/project/{projectKey}/variableset/application", Scope(sdk.AuthConsumerScopeProject), r.POSTv2(api.postMigrateApplicationVariableToVariableSetHandler))
@@ -499,7 +502,12 @@ func (api *API) InitRouter() {
 	r.Handle("/v2/project/{projectKey}/keys/{name}/enable", Scope(sdk.AuthConsumerScopeProject), r.POSTv2(api.postEnableKeyInProjectV2Handler))
 
 	r.Handle("/v2/project/{projectKey}/type/{type}/access", Scope(sdk.AuthConsumerScopeService), r.GETv2(api.getProjectV2AccessHandler))
+	r.Handle("/v2/project/{projectKey}/cdn/log/redaction", Scope(sdk.AuthConsumerScopeService), r.GETv2(api.getProjectCDNLogRedactionHandler), r.POSTv2(api.postProjectCDNLogRedactionHandler))
+	r.Handle("/v2/project/{projectKey}/cdn/quota/event", Scope(sdk.AuthConsumerScopeService), r.POSTv2(api.postProjectV2CDNQuotaEventHandler))
+	r.Handle("/v2/project/{projectKey}/cdn/share/{shareID}/download", Scope(sdk.AuthConsumerScopeService), r.POSTv2(api.postProjectCDNRunResultShareDownloadHandler))
+	r.Handle("/v2/project/{projectKey}/cdn/share/{shareID}/download/{downloadID}/failed", Scope(sdk.AuthConsumerScopeService), r.POSTv2(api.postProjectCDNRunResultShareDownloadFailedHandler))
 
+	r.Handle("/v2/project/{projectKey}/log/redaction", Scope(sdk.AuthConsumerScopeProject), r.GETv2(api.getProjectLogRedactionHandler), r.PUTv2(api.putProjectLogRedactionHandler))
 	r.Handle("/v2/project/{projectKey}/notification", Scope(sdk.AuthConsumerScopeProject), r.GETv2(api.getProjectNotifsHandler), r.POSTv2(api.postProjectNotificationHandler))
 	r.Handle("/v2/project/{projectKey}/notification/{notification}", Scope(sdk.AuthConsumerScopeProject), r.GETv2(api.getProjectNotificationHandler), r.PUTv2(api.putProjectNotificationHandler), r.DELETEv2(api.deleteProjectNotificationHandler))
 
@@ -542,9 +550,11 @@ func (api *API) InitRouter() {
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/stop", Scope(sdk.AuthConsumerScopeRun), r.POSTv2(api.postStopWorkflowRunHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobsV2Handler), r.POSTv2(api.postStartJobWorkflowRunHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/result", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunResultsV2Handler))
+	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/result/{resultID}/share", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunResultSharesHandler), r.POSTv2(api.postWorkflowRunResultShareHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobRunID}", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobRunID}/retry", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobRetryHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobRunID}/infos", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobInfosHandler))
+	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobRunID}/debug", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobDebugShellHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobIdentifier}/run", Scope(sdk.AuthConsumerScopeRun), r.POSTv2(api.postRunJobHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobIdentifier}/stop", Scope(sdk.AuthConsumerScopeRun), r.POSTv2(api.postStopJobHandler))
 	r.Handle("/v2/project/{projectKey}/run/{workflowRunID}/job/{jobRunID}/logs/links", Scope(sdk.AuthConsumerScopeRun), r.GETv2(api.getWorkflowRunJobLogsLinksV2Handler))
@@ -558,6 +568,8 @@ func (api *API) InitRouter() {
 
 	r.Handle("/v2/queue/{regionName}/job/{runJobID}", Scope(sdk.AuthConsumerScopeRunExecution), r.GETv2(api.getJobRunQueueInfoHandler))
 	r.Handle("/v2/queue/{regionName}/job/{runJobID}/info", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTv2(api.postJobRunInfoHandler))
+	r.Handle("/v2/queue/{regionName}/job/{runJobID}/debug", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTv2(api.postJobRunDebugBreakpointHandler), r.DELETEv2(api.deleteJobRunDebugBreakpointHandler))
+	r.Handle("/v2/queue/{regionName}/job/{runJobID}/debug/ws", Scope(sdk.AuthConsumerScopeRunExecution), r.GETv2(api.getJobRunDebugWebsocketHandler))
 	r.Handle("/v2/queue/{regionName}/job/{runJobID}/key/{keyName}", Scope(sdk.AuthConsumerScopeRunExecution), r.GETv2(api.getJobRunProjectV2KeyHandler))
 	r.Handle("/v2/queue/{regionName}/job/{runJobID}/runinfo", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTv2(api.postRunInfoHandler))
 	r.Handle("/v2/queue/{regionName}/job/{runJobID}/step", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTv2(api.postJobRunStepHandler))
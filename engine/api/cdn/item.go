@@ -13,9 +13,10 @@ import (
 )
 
 const (
-	ParamRunID      = "runid"
-	ParamProjectKey = "projectkey"
-	ParamCacheTag   = "cachetag"
+	ParamRunID          = "runid"
+	ParamProjectKey     = "projectkey"
+	ParamCacheTag       = "cachetag"
+	ParamCacheTagPrefix = "cachetagprefix"
 )
 
 func ListItems(ctx context.Context, db gorp.SqlExecutor, itemtype sdk.CDNItemType, params map[string]string) (sdk.CDNItemLinks, error) {
@@ -35,7 +35,7 @@ type EntityFinder struct {
 	workerModelCache      map[string]sdk.EntityWithObject
 	localTemplatesCache   map[string]sdk.EntityWithObject
 	templatesCache        map[string]sdk.EntityWithObject
-	localWorkflowCache    map[string]sdk.V2Workflow
+	localWorkflowCache    map[string]sdk.EntityWithObject
 	workflowCache         map[string]sdk.V2Workflow
 	plugins               map[string]sdk.GRPCPlugin
 	libraryProject        string
@@ -57,7 +57,7 @@ func NewEntityFinder(ctx context.Context, db *gorp.DbMap, pkey, currentRef, curr
 		templatesCache:        make(map[string]sdk.EntityWithObject),
 		localTemplatesCache:   make(map[string]sdk.EntityWithObject),
 		repoCache:             make(map[string]sdk.ProjectRepository),
-		localWorkflowCache:    make(map[string]sdk.V2Workflow),
+		localWorkflowCache:    make(map[string]sdk.EntityWithObject),
 		workflowCache:         make(map[string]sdk.V2Workflow),
 		vcsServerCache:        make(map[string]sdk.VCSProject),
 		repoDefaultRefCache:   make(map[string]string),
@@ -488,6 +488,43 @@ func (ef *EntityFinder) searchWorkflowTemplate(ctx context.Context, db gorp.SqlE
 
 }
 
+func (ef *EntityFinder) searchWorkflow(ctx context.Context, db gorp.SqlExecutor, store cache.Store, name string) (*sdk.EntityWithObject, string, error) {
+	if strings.HasPrefix(name, ".cds/workflows/") {
+		// Find workflow from path
+		localEntity, has := ef.localWorkflowCache[name]
+		if !has {
+			wEntity, err := entity.LoadEntityByPathAndRefAndCommit(ctx, db, ef.currentRepo.ID, name, ef.currentRef, ef.currentSha)
+			if err != nil {
+				return nil, fmt.Sprintf("Unable to find workflow %s in repository %s", name, ef.currentRepo.Name), nil
+			}
+			if err := yaml.Unmarshal([]byte(wEntity.Data), &localEntity.Workflow); err != nil {
+				return nil, "", sdk.NewErrorFrom(sdk.ErrInvalidData, "unable to read workflow %s: %v", name, err)
+			}
+			if !ef.initiator.IsAdminWithMFA {
+				can, err := ef.checkEntityReadPermission(ctx, db, wEntity.ProjectKey)
+				if err != nil {
+					return nil, "", err
+				}
+				if !can {
+					return nil, fmt.Sprintf("user %s do not have the permission to access %s", ef.initiator.Username(), name), nil
+				}
+			}
+			localEntity.Entity = *wEntity
+			localEntity.CompleteName = fmt.Sprintf("%s/%s/%s/%s@%s", ef.currentProject, ef.currentVCS.Name, ef.currentRepo.Name, localEntity.Workflow.Name, ef.currentRef)
+			ef.localWorkflowCache[name] = localEntity
+		}
+		return &localEntity, "", nil
+	}
+	entityWithObj, msg, err := ef.searchEntity(ctx, db, store, name, sdk.EntityTypeWorkflow)
+	if err != nil {
+		return nil, "", err
+	}
+	if msg != "" {
+		return nil, msg, nil
+	}
+	return entityWithObj, "", nil
+}
+
 func (ef *EntityFinder) checkEntityReadPermission(ctx context.Context, db gorp.SqlExecutor, projKey string) (bool, error) {
 	// Verify project read permission
 	if ef.initiator.IsUser() {
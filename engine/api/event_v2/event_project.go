@@ -43,3 +43,23 @@ func PublishProjectPurgeEvent(ctx context.Context, store cache.Store, eventType
 	}
 	publish(ctx, store, e)
 }
+
+func PublishProjectCDNQuotaEvent(ctx context.Context, store cache.Store, e sdk.CDNQuotaEvent) {
+	eventType := sdk.EventProjectCDNQuotaSoftLimitReached
+	if e.HardLimit {
+		eventType = sdk.EventProjectCDNQuotaHardLimitReached
+	}
+	bts, _ := json.Marshal(e)
+	evt := sdk.ProjectEvent{
+		GlobalEventV2: sdk.GlobalEventV2{
+			ID:        sdk.UUID(),
+			Type:      eventType,
+			Payload:   bts,
+			Timestamp: time.Now(),
+		},
+		ProjectEventV2: sdk.ProjectEventV2{
+			ProjectKey: e.Quota.ProjectKey,
+		},
+	}
+	publish(ctx, store, evt)
+}
@@ -0,0 +1,58 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func InsertLogRedaction(ctx context.Context, db gorpmapper.SqlExecutorWithTx, redaction *sdk.ProjectLogRedaction) error {
+	redaction.LastModified = time.Now()
+	redaction.ID = sdk.UUID()
+	dbData := dbProjectLogRedaction{ProjectLogRedaction: *redaction}
+	if err := gorpmapping.Insert(db, &dbData); err != nil {
+		return err
+	}
+	*redaction = dbData.ProjectLogRedaction
+	return nil
+}
+
+func UpdateLogRedaction(ctx context.Context, db gorpmapper.SqlExecutorWithTx, redaction *sdk.ProjectLogRedaction) error {
+	redaction.LastModified = time.Now()
+	dbData := dbProjectLogRedaction{ProjectLogRedaction: *redaction}
+	if err := gorpmapping.Update(db, &dbData); err != nil {
+		return err
+	}
+	*redaction = dbData.ProjectLogRedaction
+	return nil
+}
+
+func LoadLogRedactionByProjectKey(ctx context.Context, db gorp.SqlExecutor, projKey string) (*sdk.ProjectLogRedaction, error) {
+	query := gorpmapping.NewQuery(`SELECT project_log_redaction.* FROM project_log_redaction WHERE project_key = $1`).Args(projKey)
+	var res dbProjectLogRedaction
+	found, err := gorpmapping.Get(ctx, db, query, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &res.ProjectLogRedaction, nil
+}
+
+// LoadLogRedactionRulesByProjectKey returns the log redaction rules of the project, or nil if the project has no rules
+func LoadLogRedactionRulesByProjectKey(ctx context.Context, db gorp.SqlExecutor, projKey string) (sdk.LogRedactionRules, error) {
+	redaction, err := LoadLogRedactionByProjectKey(ctx, db, projKey)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return redaction.Rules, nil
+}
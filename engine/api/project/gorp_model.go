@@ -16,6 +16,10 @@ type dbProjectRunRetention struct {
 	sdk.ProjectRunRetention
 }
 
+type dbProjectLogRedaction struct {
+	sdk.ProjectLogRedaction
+}
+
 type dbProjectWebHook struct {
 	sdk.ProjectWebHook
 }
@@ -200,6 +204,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbProjectWebHook{}, "project_webhook", false, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectRunRetention{}, "project_run_retention", false, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectRunFilter{}, "project_run_filter", false, "id"))
+	gorpmapping.Register(gorpmapping.New(dbProjectLogRedaction{}, "project_log_redaction", false, "id"))
 
 }
 
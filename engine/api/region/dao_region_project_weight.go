@@ -0,0 +1,70 @@
+package region
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAllProjectWeights(ctx context.Context, db gorp.SqlExecutor, query gorpmapping.Query) ([]sdk.RegionProjectWeight, error) {
+	var res []dbRegionProjectWeight
+	if err := gorpmapping.GetAll(ctx, db, query, &res); err != nil {
+		return nil, err
+	}
+	weights := make([]sdk.RegionProjectWeight, 0, len(res))
+	for _, w := range res {
+		weights = append(weights, w.RegionProjectWeight)
+	}
+	return weights, nil
+}
+
+func InsertProjectWeight(ctx context.Context, db gorpmapper.SqlExecutorWithTx, w *sdk.RegionProjectWeight) error {
+	w.ID = sdk.UUID()
+	dbData := &dbRegionProjectWeight{RegionProjectWeight: *w}
+	if err := gorpmapping.Insert(db, dbData); err != nil {
+		return err
+	}
+	*w = dbData.RegionProjectWeight
+	return nil
+}
+
+func UpdateProjectWeight(ctx context.Context, db gorpmapper.SqlExecutorWithTx, w *sdk.RegionProjectWeight) error {
+	dbData := &dbRegionProjectWeight{RegionProjectWeight: *w}
+	return sdk.WrapError(gorpmapping.Update(db, dbData), "unable to update weight of project %s on region %s", w.ProjectKey, w.RegionID)
+}
+
+func DeleteProjectWeight(db gorpmapper.SqlExecutorWithTx, regionID, projectKey string) error {
+	_, err := db.Exec("DELETE FROM region_project_weight WHERE region_id = $1 AND project_key = $2", regionID, projectKey)
+	return sdk.WrapError(err, "cannot delete weight of project %s on region %s", projectKey, regionID)
+}
+
+func LoadProjectWeightsByRegionID(ctx context.Context, db gorp.SqlExecutor, regionID string) ([]sdk.RegionProjectWeight, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM region_project_weight WHERE region_id = $1 ORDER BY project_key`).Args(regionID)
+	return getAllProjectWeights(ctx, db, query)
+}
+
+func LoadProjectWeightsByRegionName(ctx context.Context, db gorp.SqlExecutor, regionName string) ([]sdk.RegionProjectWeight, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT region_project_weight.*
+    FROM region_project_weight
+    JOIN region ON region.id = region_project_weight.region_id
+    WHERE region.name = $1`).Args(regionName)
+	return getAllProjectWeights(ctx, db, query)
+}
+
+func LoadProjectWeight(ctx context.Context, db gorp.SqlExecutor, regionID, projectKey string) (*sdk.RegionProjectWeight, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM region_project_weight WHERE region_id = $1 AND project_key = $2`).Args(regionID, projectKey)
+	var res dbRegionProjectWeight
+	found, err := gorpmapping.Get(ctx, db, query, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &res.RegionProjectWeight, nil
+}
@@ -8,6 +8,7 @@ import (
 
 func init() {
 	gorpmapping.Register(gorpmapping.New(dbRegion{}, "region", false, "id"))
+	gorpmapping.Register(gorpmapping.New(dbRegionProjectWeight{}, "region_project_weight", false, "id"))
 }
 
 type dbRegion struct {
@@ -21,3 +22,7 @@ func (o dbRegion) Canonical() gorpmapper.CanonicalForms {
 		"{{.ID}}{{.Name}}",
 	}
 }
+
+type dbRegionProjectWeight struct {
+	sdk.RegionProjectWeight
+}
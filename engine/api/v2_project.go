@@ -267,3 +267,22 @@ func (api *API) getProjectV2AccessHandler() ([]service.RbacChecker, service.Hand
 			return service.WriteJSON(w, nil, http.StatusForbidden)
 		}
 }
+
+func (api *API) postProjectV2CDNQuotaEventHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.isCDNService),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			projectKey := vars["projectKey"]
+
+			var e sdk.CDNQuotaEvent
+			if err := service.UnmarshalBody(req, &e); err != nil {
+				return err
+			}
+			if e.Quota.ProjectKey != projectKey {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid quota project key")
+			}
+
+			event_v2.PublishProjectCDNQuotaEvent(ctx, api.Cache, e)
+			return service.WriteJSON(w, nil, http.StatusNoContent)
+		}
+}
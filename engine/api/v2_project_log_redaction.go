@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow_v2"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getProjectLogRedactionHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.projectRead),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+
+			proj, err := project.Load(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+
+			redaction, err := project.LoadLogRedactionByProjectKey(ctx, api.mustDB(), proj.Key)
+			if err != nil {
+				if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+					return err
+				}
+				redaction = &sdk.ProjectLogRedaction{ProjectKey: proj.Key, Rules: sdk.LogRedactionRules{}}
+			}
+			return service.WriteJSON(w, redaction, http.StatusOK)
+		}
+}
+
+func (api *API) putProjectLogRedactionHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.projectManage),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+
+			var projectLogRedaction sdk.ProjectLogRedaction
+			if err := service.UnmarshalBody(req, &projectLogRedaction); err != nil {
+				return err
+			}
+			if err := projectLogRedaction.Rules.IsValid(); err != nil {
+				return err
+			}
+			if projectLogRedaction.Rules == nil {
+				projectLogRedaction.Rules = sdk.LogRedactionRules{}
+			}
+
+			proj, err := project.Load(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			redactionDB, err := project.LoadLogRedactionByProjectKey(ctx, tx, proj.Key)
+			switch {
+			case err == nil:
+				redactionDB.Rules = projectLogRedaction.Rules
+				if err := project.UpdateLogRedaction(ctx, tx, redactionDB); err != nil {
+					return err
+				}
+			case sdk.ErrorIs(err, sdk.ErrNotFound):
+				redactionDB = &sdk.ProjectLogRedaction{ProjectKey: proj.Key, Rules: projectLogRedaction.Rules}
+				if err := project.InsertLogRedaction(ctx, tx, redactionDB); err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return service.WriteJSON(w, redactionDB, http.StatusOK)
+		}
+}
+
+// getProjectCDNLogRedactionHandler returns the log redaction rules of the project to the CDN
+func (api *API) getProjectCDNLogRedactionHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.isCDNService),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+
+			rules, err := project.LoadLogRedactionRulesByProjectKey(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+			if rules == nil {
+				rules = sdk.LogRedactionRules{}
+			}
+			return service.WriteJSON(w, rules, http.StatusOK)
+		}
+}
+
+// postProjectCDNLogRedactionHandler adds an info on the job run with the count of values redacted by the CDN
+func (api *API) postProjectCDNLogRedactionHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.isCDNService),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+
+			var report sdk.CDNLogRedactionReport
+			if err := service.UnmarshalBody(req, &report); err != nil {
+				return err
+			}
+			if report.Count <= 0 {
+				return service.WriteJSON(w, nil, http.StatusNoContent)
+			}
+
+			runJob, err := workflow_v2.LoadRunJobByID(ctx, api.mustDB(), report.RunJobID)
+			if err != nil {
+				return err
+			}
+			if runJob.ProjectKey != pKey {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid job run project key")
+			}
+
+			msg := fmt.Sprintf("%d values were redacted by the CDN from the job logs", report.Count)
+			switch {
+			case report.StepName != "":
+				msg = fmt.Sprintf("%d values were redacted by the CDN from the logs of step %q", report.Count, report.StepName)
+			case report.ServiceName != "":
+				msg = fmt.Sprintf("%d values were redacted by the CDN from the logs of service %q", report.Count, report.ServiceName)
+			}
+			info := sdk.V2WorkflowRunJobInfo{
+				Level:            sdk.WorkflowRunInfoLevelWarning,
+				IssuedAt:         time.Now(),
+				WorkflowRunJobID: runJob.ID,
+				WorkflowRunID:    runJob.WorkflowRunID,
+				Message:          msg,
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+			if err := workflow_v2.InsertRunJobInfo(ctx, tx, &info); err != nil {
+				return err
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return service.WriteJSON(w, nil, http.StatusNoContent)
+		}
+}
@@ -153,10 +153,105 @@ func (api *API) getJobsQueuedRegionalizedHandler() ([]service.RbacChecker, servi
 			if err != nil {
 				return err
 			}
+			jobs, err = api.sortQueuedJobsByRegionFairShare(ctx, jobs)
+			if err != nil {
+				return err
+			}
 			return service.WriteJSON(w, jobs, http.StatusOK)
 		}
 }
 
+// sortQueuedJobsByRegionFairShare orders the waiting jobs, already sorted by priority and queued date, with the fair share of their region.
+// Jobs of other statuses and jobs of other regions keep their position.
+func (api *API) sortQueuedJobsByRegionFairShare(ctx context.Context, jobs []sdk.V2WorkflowRunJob) ([]sdk.V2WorkflowRunJob, error) {
+	waitingJobsByRegion := make(map[string][]int)
+	for i := range jobs {
+		if jobs[i].Status == sdk.V2WorkflowRunJobStatusWaiting {
+			waitingJobsByRegion[jobs[i].Region] = append(waitingJobsByRegion[jobs[i].Region], i)
+		}
+	}
+	res := make([]sdk.V2WorkflowRunJob, len(jobs))
+	copy(res, jobs)
+	for regionName, indexes := range waitingJobsByRegion {
+		if len(indexes) < 2 {
+			continue
+		}
+		weights, err := region.LoadProjectWeightsByRegionName(ctx, api.mustDB(), regionName)
+		if err != nil {
+			return nil, err
+		}
+		runningJobs, err := workflow_v2.CountBuildingRunJobsByRegionGroupByProject(ctx, api.mustDB(), regionName)
+		if err != nil {
+			return nil, err
+		}
+		regionJobs := make([]sdk.V2WorkflowRunJob, 0, len(indexes))
+		for _, i := range indexes {
+			regionJobs = append(regionJobs, jobs[i])
+		}
+		for k, j := range sortQueuedJobsByFairShare(regionJobs, runningJobs, weights) {
+			res[indexes[k]] = j
+		}
+	}
+	return res, nil
+}
+
+// sortQueuedJobsByFairShare orders jobs already sorted by priority and queued date.
+// For a same priority, the region is shared between projects regarding their weights and their number of running jobs.
+func sortQueuedJobsByFairShare(jobs []sdk.V2WorkflowRunJob, runningJobs map[string]int64, weights []sdk.RegionProjectWeight) []sdk.V2WorkflowRunJob {
+	projectWeights := make(map[string]int64, len(weights))
+	for _, w := range weights {
+		projectWeights[w.ProjectKey] = w.Weight
+	}
+	getWeight := func(projectKey string) int64 {
+		if w, has := projectWeights[projectKey]; has && w > 0 {
+			return w
+		}
+		return sdk.RegionProjectDefaultWeight
+	}
+	running := make(map[string]int64, len(runningJobs))
+	for k, v := range runningJobs {
+		running[k] = v
+	}
+
+	res := make([]sdk.V2WorkflowRunJob, 0, len(jobs))
+	for i := 0; i < len(jobs); {
+		// Retrieve all jobs with the same priority, grouped by project and keeping the queued order
+		j := i
+		projectQueues := make(map[string][]sdk.V2WorkflowRunJob)
+		projectKeys := make([]string, 0)
+		for ; j < len(jobs) && jobs[j].Priority == jobs[i].Priority; j++ {
+			if _, has := projectQueues[jobs[j].ProjectKey]; !has {
+				projectKeys = append(projectKeys, jobs[j].ProjectKey)
+			}
+			projectQueues[jobs[j].ProjectKey] = append(projectQueues[jobs[j].ProjectKey], jobs[j])
+		}
+
+		for k := i; k < j; k++ {
+			// Pick the project with the lowest usage of its share, the oldest job wins on equality
+			var selected string
+			for _, pKey := range projectKeys {
+				if len(projectQueues[pKey]) == 0 {
+					continue
+				}
+				if selected == "" {
+					selected = pKey
+					continue
+				}
+				current := (running[pKey] + 1) * getWeight(selected)
+				best := (running[selected] + 1) * getWeight(pKey)
+				if current < best || (current == best && projectQueues[pKey][0].Queued.Before(projectQueues[selected][0].Queued)) {
+					selected = pKey
+				}
+			}
+			res = append(res, projectQueues[selected][0])
+			projectQueues[selected] = projectQueues[selected][1:]
+			running[selected]++
+		}
+		i = j
+	}
+	return res
+}
+
 func (api *API) getJobsQueuedHandler() ([]service.RbacChecker, service.Handler) {
 	return service.RBACNone(),
 		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
@@ -240,6 +335,10 @@ func (api *API) getJobsQueuedHandler() ([]service.RbacChecker, service.Handler)
 			if err != nil {
 				return err
 			}
+			jobs, err = api.sortQueuedJobsByRegionFairShare(ctx, jobs)
+			if err != nil {
+				return err
+			}
 
 			w.Header().Set("X-Total-Count", strconv.FormatInt(count, 10))
 			return service.WriteJSON(w, jobs, http.StatusOK)
@@ -496,10 +595,18 @@ func (api *API) getCacheLinkHandler() ([]service.RbacChecker, service.Handler) {
 				return err
 			}
 
-			itemsLinks, err := cdn.ListItems(ctx, api.mustDBWithCtx(ctx), sdk.CDNTypeItemWorkerCacheV2, map[string]string{
+			params := map[string]string{
 				cdn.ParamProjectKey: p.Key,
 				cdn.ParamCacheTag:   cacheKey,
-			})
+			}
+			// With prefix, the most recent cache with a key starting with the given one is returned
+			if service.FormBool(req, "prefix") {
+				params = map[string]string{
+					cdn.ParamProjectKey:     p.Key,
+					cdn.ParamCacheTagPrefix: cacheKey,
+				}
+			}
+			itemsLinks, err := cdn.ListItems(ctx, api.mustDBWithCtx(ctx), sdk.CDNTypeItemWorkerCacheV2, params)
 			if err != nil {
 				return err
 			}
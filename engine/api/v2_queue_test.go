@@ -264,6 +264,23 @@ func TestUserGetJobsQueuedHandler(t *testing.T) {
 	}
 	require.NoError(t, workflow_v2.InsertRunJob(ctx, db, &jobRun2))
 
+	// Queued after job1 but with a higher priority
+	jobRun3 := sdk.V2WorkflowRunJob{
+		ProjectKey:    proj.Key,
+		Status:        sdk.V2WorkflowRunJobStatusWaiting,
+		JobID:         "job3",
+		ModelType:     "docker",
+		ModelOSArch:   "linux/amd64",
+		Region:        "default",
+		Priority:      10,
+		WorkflowRunID: wr.ID,
+		Initiator: sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+	}
+	require.NoError(t, workflow_v2.InsertRunJob(ctx, db, &jobRun3))
+
 	reg := sdk.Region{Name: "default"}
 	require.NoError(t, region.Insert(context.TODO(), db, &reg))
 
@@ -280,8 +297,9 @@ func TestUserGetJobsQueuedHandler(t *testing.T) {
 	require.Equal(t, 200, w.Code)
 	var jobRunResponse []sdk.V2WorkflowRunJob
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &jobRunResponse))
-	require.Equal(t, 1, len(jobRunResponse))
-	require.Equal(t, "job1", jobRunResponse[0].JobID)
+	require.Equal(t, 2, len(jobRunResponse))
+	require.Equal(t, "job3", jobRunResponse[0].JobID)
+	require.Equal(t, "job1", jobRunResponse[1].JobID)
 }
 
 func TestGetJobsRegionalizedQueuedHandler(t *testing.T) {
@@ -582,3 +600,42 @@ hatcheries:
 	require.Len(t, info, 1)
 	require.Equal(t, infoToSend.Message, info[0].Message)
 }
+
+func TestSortQueuedJobsByFairShare(t *testing.T) {
+	now := time.Now()
+	newJob := func(id, projectKey string, priority int64, queuedDelay int) sdk.V2WorkflowRunJob {
+		return sdk.V2WorkflowRunJob{
+			ID:         id,
+			ProjectKey: projectKey,
+			Priority:   priority,
+			Queued:     now.Add(time.Duration(queuedDelay) * time.Second),
+		}
+	}
+
+	// Jobs are loaded sorted by priority and queued date
+	jobs := []sdk.V2WorkflowRunJob{
+		newJob("release", "PROJB", 10, 5),
+		newJob("pr1", "PROJA", 0, 0),
+		newJob("pr2", "PROJA", 0, 1),
+		newJob("pr3", "PROJA", 0, 2),
+		newJob("pr4", "PROJA", 0, 3),
+		newJob("build1", "PROJB", 0, 4),
+		newJob("build2", "PROJC", 0, 6),
+	}
+
+	// Without running jobs nor weights, projects are interleaved
+	res := sortQueuedJobsByFairShare(jobs, nil, nil)
+	ids := make([]string, 0, len(res))
+	for _, j := range res {
+		ids = append(ids, j.ID)
+	}
+	require.Equal(t, []string{"release", "pr1", "build2", "pr2", "build1", "pr3", "pr4"}, ids)
+
+	// PROJA has a weight of 3 and already 1 running job
+	res = sortQueuedJobsByFairShare(jobs, map[string]int64{"PROJA": 1}, []sdk.RegionProjectWeight{{ProjectKey: "PROJA", Weight: 3}})
+	ids = make([]string, 0, len(res))
+	for _, j := range res {
+		ids = append(ids, j.ID)
+	}
+	require.Equal(t, []string{"release", "pr1", "pr2", "build2", "pr3", "pr4", "build1"}, ids)
+}
@@ -112,6 +112,11 @@ func (api *API) postV2WorkerTakeJobHandler() ([]service.RbacChecker, service.Han
 			vss = append(vss, *vsDB)
 		}
 
+		logRedactionRules, err := project.LoadLogRedactionRulesByProjectKey(ctx, api.mustDB(), projWithSecrets.Key)
+		if err != nil {
+			return err
+		}
+
 		tx, err := api.mustDB().Begin()
 		if err != nil {
 			return sdk.WithStack(err)
@@ -147,6 +152,13 @@ func (api *API) postV2WorkerTakeJobHandler() ([]service.RbacChecker, service.Han
 		jobRun.Status = sdk.V2WorkflowRunJobStatusBuilding
 		jobRun.Started = &now
 		jobRun.WorkerName = wrkWithSecret.Name
+
+		// In debug mode, the job timeout is extended with the time the job can be paused
+		var debug *sdk.V2WorkflowRunDebug
+		if run.RunEvent.Debug.IsEnabledForJob(jobRun.JobID) {
+			debug = run.RunEvent.Debug
+			jobRun.TimeoutMinutes = int64((jobRun.Job.GetTimeout() + debug.MaxPauseDuration()) / time.Minute)
+		}
 		if err := workflow_v2.UpdateJobRun(ctx, tx, jobRun); err != nil {
 			return err
 		}
@@ -167,11 +179,13 @@ func (api *API) postV2WorkerTakeJobHandler() ([]service.RbacChecker, service.Han
 		}
 
 		takeResponse := sdk.V2TakeJobResponse{
-			RunJob:         *jobRun,
-			AsCodeActions:  run.WorkflowData.Actions,
-			SigningKey:     base64.StdEncoding.EncodeToString(workerKey),
-			Contexts:       *contexts,
-			SensitiveDatas: sensitiveDatas,
+			RunJob:            *jobRun,
+			AsCodeActions:     run.WorkflowData.Actions,
+			SigningKey:        base64.StdEncoding.EncodeToString(workerKey),
+			Contexts:          *contexts,
+			SensitiveDatas:    sensitiveDatas,
+			LogRedactionRules: logRedactionRules,
+			Debug:             debug,
 		}
 
 		event_v2.PublishRunJobEvent(ctx, api.Cache, sdk.EventRunJobBuilding, *run, *jobRun)
@@ -197,6 +211,7 @@ func computeRunJobContext(ctx context.Context, db gorpmapper.SqlExecutorWithTx,
 	contexts.Git = run.Contexts.Git
 	contexts.Gate = jobRun.GateInputs
 	contexts.Matrix = jobRun.Matrix
+	contexts.Inputs = buildJobInputsContext(run, jobRun.Job)
 
 	sensitiveDatas := sdk.StringSlice{}
 
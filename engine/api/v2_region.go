@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/event_v2"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/rbac"
 	"github.com/ovh/cds/engine/api/region"
 	"github.com/ovh/cds/engine/service"
@@ -183,3 +184,104 @@ func (api *API) deleteRegionHandler() ([]service.RbacChecker, service.Handler) {
 			return nil
 		}
 }
+
+func (api *API) getRegionProjectWeightsHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.regionRead),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			regionIdentifier := vars["regionIdentifier"]
+
+			reg, err := api.getRegionByIdentifier(ctx, regionIdentifier)
+			if err != nil {
+				return err
+			}
+			weights, err := region.LoadProjectWeightsByRegionID(ctx, api.mustDB(), reg.ID)
+			if err != nil {
+				return err
+			}
+			return service.WriteMarshal(w, req, weights, http.StatusOK)
+		}
+}
+
+func (api *API) postRegionProjectWeightHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.globalRegionManage),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			regionIdentifier := vars["regionIdentifier"]
+
+			var weight sdk.RegionProjectWeight
+			if err := service.UnmarshalBody(req, &weight); err != nil {
+				return err
+			}
+			if weight.Weight < 1 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "weight must be greater than 0")
+			}
+
+			reg, err := api.getRegionByIdentifier(ctx, regionIdentifier)
+			if err != nil {
+				return err
+			}
+			exist, err := project.Exist(api.mustDB(), weight.ProjectKey)
+			if err != nil {
+				return err
+			}
+			if !exist {
+				return sdk.NewErrorFrom(sdk.ErrNotFound, "project %s not found", weight.ProjectKey)
+			}
+			weight.RegionID = reg.ID
+
+			existingWeight, err := region.LoadProjectWeight(ctx, api.mustDB(), reg.ID, weight.ProjectKey)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			if existingWeight != nil {
+				weight.ID = existingWeight.ID
+				if err := region.UpdateProjectWeight(ctx, tx, &weight); err != nil {
+					return err
+				}
+			} else {
+				if err := region.InsertProjectWeight(ctx, tx, &weight); err != nil {
+					return err
+				}
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return service.WriteMarshal(w, req, weight, http.StatusOK)
+		}
+}
+
+func (api *API) deleteRegionProjectWeightHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.globalRegionManage),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			regionIdentifier := vars["regionIdentifier"]
+			projectKey := vars["projectKey"]
+
+			reg, err := api.getRegionByIdentifier(ctx, regionIdentifier)
+			if err != nil {
+				return err
+			}
+			if _, err := region.LoadProjectWeight(ctx, api.mustDB(), reg.ID, projectKey); err != nil {
+				return err
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			if err := region.DeleteProjectWeight(tx, reg.ID, projectKey); err != nil {
+				return err
+			}
+			return sdk.WithStack(tx.Commit())
+		}
+}
@@ -1135,6 +1135,60 @@ func prepareWorkflowHooks(ctx context.Context, db gorpmapper.SqlExecutorWithTx,
 		}
 	}
 
+	// Prepare kafka and rabbitmq hooks
+	// default branch && latest commit
+	if e.Ref == defaultBranch.ID && e.Commit == defaultBranch.LatestCommit && e.Workflow.On != nil {
+		destVCS := workflowDefVCSName
+		destRepo := workflowDefRepositoryName
+		if e.Workflow.Repository != nil {
+			destVCS = e.Workflow.Repository.VCSServer
+			destRepo = e.Workflow.Repository.Name
+		}
+
+		for _, k := range e.Workflow.On.Kafka {
+			wh := sdk.V2WorkflowHook{
+				VCSName:        workflowDefVCSName,
+				EntityID:       e.ID,
+				ProjectKey:     e.ProjectKey,
+				Type:           sdk.WorkflowHookTypeKafka,
+				Ref:            e.Ref,
+				Commit:         e.Commit,
+				WorkflowName:   e.Name,
+				RepositoryName: workflowDefRepositoryName,
+				Data: sdk.V2WorkflowHookData{
+					VCSServer:      destVCS,
+					RepositoryName: destRepo,
+					Integration:    k.Integration,
+					Topic:          k.Topic,
+					MessageFilter:  k.Filter,
+				},
+				Head: e.Head,
+			}
+			whs = append(whs, wh)
+		}
+		for _, r := range e.Workflow.On.RabbitMQ {
+			wh := sdk.V2WorkflowHook{
+				VCSName:        workflowDefVCSName,
+				EntityID:       e.ID,
+				ProjectKey:     e.ProjectKey,
+				Type:           sdk.WorkflowHookTypeRabbitMQ,
+				Ref:            e.Ref,
+				Commit:         e.Commit,
+				WorkflowName:   e.Name,
+				RepositoryName: workflowDefRepositoryName,
+				Data: sdk.V2WorkflowHookData{
+					VCSServer:      destVCS,
+					RepositoryName: destRepo,
+					Integration:    r.Integration,
+					Queue:          r.Queue,
+					MessageFilter:  r.Filter,
+				},
+				Head: e.Head,
+			}
+			whs = append(whs, wh)
+		}
+	}
+
 	// Prepare workflow_run hook
 	// default branch && latest commit
 	if e.Ref == defaultBranch.ID && e.Commit == defaultBranch.LatestCommit && e.Workflow.On != nil {
@@ -1197,15 +1251,17 @@ func manageWorkflowHooks(ctx context.Context, db gorpmapper.SqlExecutorWithTx, c
 			}
 		}
 
-		// Remove previous hooks workflow_run
-		whs, err = workflow_v2.LoadHookByWorkflowAndType(ctx, db, e.ProjectKey, workflowDefVCSName, workflowDefRepositoryName, e.Name, sdk.WorkflowHookTypeWorkflowRun)
-		if err != nil {
-			return nil, err
-		}
-		for _, wh := range whs {
-			if err := workflow_v2.DeleteWorkflowHookByID(ctx, db, wh.ID); err != nil {
+		// Remove previous hooks workflow_run, kafka and rabbitmq
+		for _, hookType := range []string{sdk.WorkflowHookTypeWorkflowRun, sdk.WorkflowHookTypeKafka, sdk.WorkflowHookTypeRabbitMQ} {
+			whs, err = workflow_v2.LoadHookByWorkflowAndType(ctx, db, e.ProjectKey, workflowDefVCSName, workflowDefRepositoryName, e.Name, hookType)
+			if err != nil {
 				return nil, err
 			}
+			for _, wh := range whs {
+				if err := workflow_v2.DeleteWorkflowHookByID(ctx, db, wh.ID); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 
@@ -1751,6 +1807,43 @@ func Lint[T sdk.Lintable](ctx context.Context, db *gorp.DbMap, store cache.Store
 					}
 				}
 
+				// Group and inputs are computed by CDS on the jobs of a reusable workflow
+				if j.Group != "" || len(j.Inputs) > 0 {
+					err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: group and inputs are computed by CDS and cannot be set", x.Name, jobID))
+				}
+
+				// The jobs of a reusable workflow run with their own conditions and gates
+				if j.Uses != "" && (j.If != "" || j.Gate != "") {
+					err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: if and gate cannot be set on a job calling a reusable workflow", x.Name, jobID))
+				}
+
+				// Check if reusable workflow exists
+				if j.Uses != "" {
+					entWorkflow, msg, errSearch := ef.searchWorkflow(ctx, db, store, j.Uses)
+					if errSearch != nil {
+						err = append(err, errSearch)
+					}
+					if msg != "" {
+						err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: %s", x.Name, jobID, msg))
+					}
+					if entWorkflow != nil {
+						if entWorkflow.Workflow.On == nil || entWorkflow.Workflow.On.WorkflowCall == nil {
+							err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: workflow %s cannot be called, on.workflow-call is not defined", x.Name, jobID, j.Uses))
+						} else {
+							for k := range j.With {
+								if _, has := entWorkflow.Workflow.On.WorkflowCall.Inputs[k]; !has {
+									err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: input %q is not defined by workflow %s", x.Name, jobID, k, j.Uses))
+								}
+							}
+							for k, v := range entWorkflow.Workflow.On.WorkflowCall.Inputs {
+								if _, has := j.With[k]; !has && v.Default == nil {
+									err = append(err, sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s job %s: required input %q of workflow %s is missing", x.Name, jobID, k, j.Uses))
+								}
+							}
+						}
+					}
+				}
+
 				// Check concurrency
 				if j.Concurrency != "" {
 					found := false
@@ -1832,6 +1925,20 @@ func Lint[T sdk.Lintable](ctx context.Context, db *gorp.DbMap, store cache.Store
 					}
 				}
 			}
+
+			// Check integrations used by message bus hooks
+			if x.On != nil {
+				for _, k := range x.On.Kafka {
+					if errI := checkWorkflowHookIntegration(ctx, db, ownerProjectKey, x.Name, k.Integration, sdk.KafkaIntegrationModel); errI != nil {
+						err = append(err, errI)
+					}
+				}
+				for _, r := range x.On.RabbitMQ {
+					if errI := checkWorkflowHookIntegration(ctx, db, ownerProjectKey, x.Name, r.Integration, sdk.RabbitMQIntegrationModel); errI != nil {
+						err = append(err, errI)
+					}
+				}
+			}
 		}
 	}
 
@@ -1842,6 +1949,25 @@ func Lint[T sdk.Lintable](ctx context.Context, db *gorp.DbMap, store cache.Store
 	return nil
 }
 
+// checkWorkflowHookIntegration checks that the integration used by a kafka or rabbitmq hook exists and has the expected model.
+func checkWorkflowHookIntegration(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName, integrationName, modelName string) error {
+	if integrationName == "" {
+		return nil
+	}
+	pi, err := integration.LoadProjectIntegrationByName(ctx, db, projectKey, integrationName)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s: integration %s doesn't exist", workflowName, integrationName)
+		}
+		log.ErrorWithStackTrace(ctx, err)
+		return sdk.NewErrorFrom(sdk.ErrUnknownError, "workflow %s: unable to check if integration %s exists", workflowName, integrationName)
+	}
+	if pi.Model.Name != modelName {
+		return sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s: integration %s is not a %s integration", workflowName, integrationName, modelName)
+	}
+	return nil
+}
+
 func ReadEntityFile[T sdk.Lintable](ctx context.Context, api *API, directory, fileName string, content []byte, out *[]T, t string, analysis sdk.ProjectRepositoryAnalysis, ef *EntityFinder) ([]sdk.EntityWithObject, []error) {
 	namePattern, err := regexp.Compile(sdk.EntityNamePattern)
 	if err != nil {
@@ -181,7 +181,7 @@ func (c *websocketV2ClientData) eventPostCheck(ctx context.Context, db gorp.SqlE
 	}
 
 	// Post check to return only runs according query filters
-	if event.Type == sdk.EventRunCrafted || event.Type == sdk.EventRunBuilding || event.Type == sdk.EventRunEnded || event.Type == sdk.EventRunRestart {
+	if event.Type == sdk.EventRunCrafted || event.Type == sdk.EventRunBuilding || event.Type == sdk.EventRunEnded || event.Type == sdk.EventRunRestart || event.Type == sdk.EventRunPartialRestart {
 		filter := c.filters.GetFirstByType(sdk.WebsocketV2FilterTypeProjectRuns)
 		if filter == nil {
 			return false, nil
@@ -349,7 +349,7 @@ func (a *API) websocketV2ComputeEventKeys(event sdk.FullEventV2) []string {
 			Type:       sdk.WebsocketV2FilterTypeProject,
 			ProjectKey: event.ProjectKey,
 		}.Key())
-	case sdk.EventRunCrafted, sdk.EventRunBuilding, sdk.EventRunEnded, sdk.EventRunRestart:
+	case sdk.EventRunCrafted, sdk.EventRunBuilding, sdk.EventRunEnded, sdk.EventRunRestart, sdk.EventRunPartialRestart:
 		keys = append(keys, sdk.WebsocketV2Filter{
 			Type:       sdk.WebsocketV2FilterTypeProjectRuns,
 			ProjectKey: event.ProjectKey,
@@ -1124,7 +1124,7 @@ func (api *API) postStartJobWorkflowRunHandler() ([]service.RbacChecker, service
 			}
 			defer tx.Rollback() // nolint
 
-			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep); err != nil {
+			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep, nil); err != nil {
 				return err
 			}
 
@@ -1202,42 +1202,81 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 			}
 
 			runJobsMap := make(map[string]sdk.V2WorkflowRunJob)
-			failedRunJob := make(map[string]sdk.V2WorkflowRunJob)
 			for _, rj := range runJobs {
 				runJobsMap[rj.ID] = rj
-				if rj.Status == sdk.V2WorkflowRunJobStatusFail || rj.Status == sdk.V2WorkflowRunJobStatusStopped {
-					failedRunJob[rj.ID] = rj
-				}
-			}
-			if len(failedRunJob) == 0 {
-				return sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow doesn't contains failed or stopped jobs")
 			}
 
-			runJobsToKeep := workflow_v2.RetrieveJobToKeep(ctx, wr.WorkflowData.Workflow, runJobsMap, failedRunJob)
-
-			// Only retrieve parent failed job to restart
-			runJobToRestart := make(map[string]sdk.V2WorkflowRunJob)
-			for _, rj := range failedRunJob {
-				if _, has := runJobsToKeep[rj.JobID]; has {
-					continue
+			// With a job, restart it and all the jobs that depend on it. Otherwise restart only failed and stopped jobs.
+			var runJobToRestart map[string]sdk.V2WorkflowRunJob
+			var runJobsToKeep map[string]sdk.V2WorkflowRunJob
+			restartMessage := u.GetFullname() + " restarted all failed and stopped jobs"
+			restartEvent := sdk.EventRunRestart
+			if jobID := req.FormValue("job"); jobID != "" {
+				if _, has := wr.WorkflowData.Workflow.Jobs[jobID]; !has {
+					return sdk.NewErrorFrom(sdk.ErrNotFound, "job %s not found in workflow", jobID)
+				}
+				// Restart all matrix variants of the job
+				runJobsFromJob := make(map[string]sdk.V2WorkflowRunJob)
+				for _, rj := range runJobs {
+					if rj.JobID == jobID && rj.Status.IsTerminated() {
+						runJobsFromJob[rj.ID] = rj
+					}
+				}
+				if len(runJobsFromJob) == 0 {
+					return sdk.NewErrorFrom(sdk.ErrInvalidData, "job %s cannot be restarted", jobID)
+				}
+				runJobsToKeep = workflow_v2.RetrieveJobToKeep(ctx, wr.WorkflowData.Workflow, runJobsMap, runJobsFromJob)
+				runJobToRestart = runJobsFromJob
+				restartMessage = fmt.Sprintf("%s restarted job %s and all the jobs that depend on it", u.GetFullname(), jobID)
+				restartEvent = sdk.EventRunPartialRestart
+			} else {
+				failedRunJob := make(map[string]sdk.V2WorkflowRunJob)
+				for _, rj := range runJobs {
+					if rj.Status == sdk.V2WorkflowRunJobStatusFail || rj.Status == sdk.V2WorkflowRunJobStatusStopped {
+						failedRunJob[rj.ID] = rj
+					}
 				}
+				if len(failedRunJob) == 0 {
+					return sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow doesn't contains failed or stopped jobs")
+				}
+
+				runJobsToKeep = workflow_v2.RetrieveJobToKeep(ctx, wr.WorkflowData.Workflow, runJobsMap, failedRunJob)
 
-				// Retrieve parents
-				parentJobs := sdk.WorkflowJobParents(wr.WorkflowData.Workflow, rj.JobID)
-				// Check if there is a parent in the failed job list
-				keepJob := true
-				for _, rjParent := range failedRunJob {
-					if rjParent.JobID == rj.JobID {
+				// Only retrieve parent failed job to restart
+				runJobToRestart = make(map[string]sdk.V2WorkflowRunJob)
+				for _, rj := range failedRunJob {
+					if _, has := runJobsToKeep[rj.JobID]; has {
 						continue
 					}
-					if slices.Contains(parentJobs, rjParent.JobID) {
-						keepJob = false
-						break
+
+					// Retrieve parents
+					parentJobs := sdk.WorkflowJobParents(wr.WorkflowData.Workflow, rj.JobID)
+					// Check if there is a parent in the failed job list
+					keepJob := true
+					for _, rjParent := range failedRunJob {
+						if rjParent.JobID == rj.JobID {
+							continue
+						}
+						if slices.Contains(parentJobs, rjParent.JobID) {
+							keepJob = false
+							break
+						}
+					}
+					if keepJob {
+						runJobToRestart[rj.ID] = rj
 					}
 				}
-				if keepJob {
-					runJobToRestart[rj.JobID] = rj
+			}
+
+			debug, err := parseWorkflowRunDebugQuery(req)
+			if err != nil {
+				return err
+			}
+			if debug != nil {
+				if err := checkWorkflowRunDebug(wr.WorkflowData.Workflow, debug); err != nil {
+					return err
 				}
+				restartMessage += " in debug mode"
 			}
 
 			tx, err := api.mustDB().Begin()
@@ -1246,7 +1285,7 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 			}
 			defer tx.Rollback() // nolint
 
-			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep); err != nil {
+			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep, debug); err != nil {
 				return err
 			}
 
@@ -1254,7 +1293,7 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 				WorkflowRunID: wr.ID,
 				IssuedAt:      time.Now(),
 				Level:         sdk.WorkflowRunInfoLevelInfo,
-				Message:       u.GetFullname() + " restarted all failed and stopped jobs",
+				Message:       restartMessage,
 			}
 			if err := workflow_v2.InsertRunInfo(ctx, tx, &runInfo); err != nil {
 				return err
@@ -1262,9 +1301,22 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 
 			// For each job to restart that has a gate, reuse the previous GateInputs as-is
 			// (they are already complete) and just ensure manual=true.
+			// Gated jobs that depend on the restarted jobs are also triggered again if they were triggered before.
+			for _, rj := range runJobs {
+				if _, has := runJobsToKeep[rj.ID]; has || rj.Job.Gate == "" || len(rj.GateInputs) == 0 {
+					continue
+				}
+				runJobToRestart[rj.ID] = rj
+			}
 			updateRun := false
+			// Run jobs are keyed by id, the gate event is sent once for all the matrix variants of a job
+			gateJobIDs := make(map[string]struct{})
 			for _, rj := range runJobToRestart {
+				if _, has := gateJobIDs[rj.JobID]; has {
+					continue
+				}
 				if rj.Job.Gate != "" {
+					gateJobIDs[rj.JobID] = struct{}{}
 					updateRun = true
 					inputs := make(map[string]interface{})
 					for k, v := range rj.GateInputs {
@@ -1311,7 +1363,7 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 			}
 			initiator.User = usr.Initiator()
 
-			event_v2.PublishRunEvent(ctx, api.Cache, sdk.EventRunRestart, *wr, runJobsMap, runResults, &initiator)
+			event_v2.PublishRunEvent(ctx, api.Cache, restartEvent, *wr, runJobsMap, runResults, &initiator)
 
 			// Then continue the workflow
 			api.EnqueueWorkflowRun(ctx, wr.ID, initiator, wr.WorkflowName, wr.RunNumber)
@@ -1319,8 +1371,10 @@ func (api *API) postRestartWorkflowRunHandler() ([]service.RbacChecker, service.
 		}
 }
 
-func (api *API) restartWorkflowRun(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, wr *sdk.V2WorkflowRun, runJobsToKeep map[string]sdk.V2WorkflowRunJob) error {
+// restartWorkflowRun starts a new attempt of the workflow run. The debug mode only applies on the new attempt.
+func (api *API) restartWorkflowRun(ctx context.Context, tx gorpmapper.SqlExecutorWithTx, wr *sdk.V2WorkflowRun, runJobsToKeep map[string]sdk.V2WorkflowRunJob, debug *sdk.V2WorkflowRunDebug) error {
 	wr.RunAttempt++
+	wr.RunEvent.Debug = debug
 	wr.Status = sdk.V2WorkflowRunStatusBuilding
 	wr.Contexts.CDS.RunAttempt = wr.RunAttempt
 
@@ -1505,6 +1559,7 @@ func (api *API) postRunJobHandler() ([]service.RbacChecker, service.Handler) {
 
 			runJobsContexts, _ := computeExistingRunJobContexts(ctx, runJobs, runResults)
 			jobContext := buildContextForJob(ctx, wr.WorkflowData.Workflow, runJobsContexts, wr.Contexts, stages, jobToRuns[0].JobID)
+			jobContext.Inputs = buildJobInputsContext(*wr, wr.WorkflowData.Workflow.Jobs[jobToRuns[0].JobID])
 			initiator := sdk.V2Initiator{
 				UserID:         u.AuthConsumerUser.AuthentifiedUser.ID,
 				User:           u.AuthConsumerUser.AuthentifiedUser.Initiator(),
@@ -1535,7 +1590,7 @@ func (api *API) postRunJobHandler() ([]service.RbacChecker, service.Handler) {
 			}
 			defer tx.Rollback() // nolint
 
-			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep); err != nil {
+			if err := api.restartWorkflowRun(ctx, tx, wr, runJobsToKeep, nil); err != nil {
 				return err
 			}
 			wr.RunJobEvent = append(wr.RunJobEvent, sdk.V2WorkflowRunJobEvent{
@@ -1665,12 +1720,24 @@ func (api *API) postWorkflowRunV2Handler() ([]service.RbacChecker, service.Handl
 				}
 			}
 
-			// Check job inputs regarding workflow definition
-			if runRequest.JobInputs != nil {
-				var wk sdk.V2Workflow
-				if err := yaml.Unmarshal([]byte(workflowEntity.Data), &wk); err != nil {
+			var wk sdk.V2Workflow
+			if err := yaml.Unmarshal([]byte(workflowEntity.Data), &wk); err != nil {
+				return err
+			}
+
+			// Check workflow inputs regarding workflow definition, and set default values
+			if wk.On != nil && wk.On.Manual != nil {
+				inputs, err := wk.On.Manual.ComputeInputs(runRequest.Inputs)
+				if err != nil {
 					return err
 				}
+				runRequest.Inputs = inputs
+			} else if len(runRequest.Inputs) > 0 && wk.From == "" {
+				return sdk.NewErrorFrom(sdk.ErrInvalidData, "workflow %s doesn't declare any input", workflowName)
+			}
+
+			// Check job inputs regarding workflow definition
+			if runRequest.JobInputs != nil {
 				for jobID, inputs := range runRequest.JobInputs {
 					if err := sdk.CheckJobInputWithGate(wk, jobID, inputs); err != nil {
 						return err
@@ -1689,6 +1756,13 @@ func (api *API) postWorkflowRunV2Handler() ([]service.RbacChecker, service.Handl
 				}
 			}
 
+			// Check debug mode
+			if runRequest.Debug != nil {
+				if err := checkWorkflowRunDebug(wk, runRequest.Debug); err != nil {
+					return err
+				}
+			}
+
 			hookRequest := sdk.HookManualWorkflowRun{
 				UserRequest:    runRequest,
 				Project:        proj.Key,
@@ -1749,6 +1823,10 @@ func (api *API) startWorkflowV2(ctx context.Context, proj sdk.Project, vcsProjec
 		WebHookID:          runRequest.WebhookID,
 		RepositoryOrigin:   repoOrigin,
 		HookEventID:        runRequest.HookEventID,
+		Inputs:             runRequest.Inputs,
+		Integration:        runRequest.Integration,
+		Topic:              runRequest.Topic,
+		Debug:              runRequest.Debug,
 	}
 
 	var msg string
@@ -1767,6 +1845,10 @@ func (api *API) startWorkflowV2(ctx context.Context, proj sdk.Project, vcsProjec
 		msg = fmt.Sprintf("Workflow was triggered by the workflow-run hook on workflow %s", runEvent.WorkflowRun)
 	case sdk.WorkflowHookTypeWebhook:
 		msg = fmt.Sprintf("Workflow was triggered by webhook %s", runEvent.WebHookID)
+	case sdk.WorkflowHookTypeKafka:
+		msg = fmt.Sprintf("Workflow was triggered by a message on kafka topic %s from integration %s", runEvent.Topic, runEvent.Integration)
+	case sdk.WorkflowHookTypeRabbitMQ:
+		msg = fmt.Sprintf("Workflow was triggered by a message on rabbitMQ queue %s from integration %s", runEvent.Topic, runEvent.Integration)
 	default:
 		return nil, sdk.WrapError(sdk.ErrNotImplemented, "event %s not implemented", runEvent.HookType)
 	}
@@ -329,6 +329,20 @@ func (api *API) craftWorkflowRunV2(ctx context.Context, id string) error {
 		return stopRun(ctx, api.mustDB(), api.Cache, run, nil, msgs...)
 	}
 
+	// Check inputs of a manual run, the workflow definition can come from a workflow template
+	if run.RunEvent.HookType == sdk.WorkflowHookTypeManual && run.WorkflowData.Workflow.On != nil && run.WorkflowData.Workflow.On.Manual != nil {
+		inputs, err := run.WorkflowData.Workflow.On.Manual.ComputeInputs(run.RunEvent.Inputs)
+		if err != nil {
+			return stopRun(ctx, api.mustDB(), api.Cache, run, nil, sdk.V2WorkflowRunInfo{
+				WorkflowRunID: run.ID,
+				IssuedAt:      time.Now(),
+				Level:         sdk.WorkflowRunInfoLevelError,
+				Message:       fmt.Sprintf("unable to compute workflow inputs: %v", err),
+			})
+		}
+		run.RunEvent.Inputs = inputs
+	}
+
 	// Reload integration regarding jobs
 	integrations, infos, err := wref.checkIntegrations(ctx, api.mustDB(), run.WorkflowData.Workflow.Jobs)
 	if err != nil {
@@ -1452,3 +1466,22 @@ func (wref *WorkflowRunEntityFinder) checkWorkflowTemplate(ctx context.Context,
 	}
 	return *e, nil, nil
 }
+
+func (wref *WorkflowRunEntityFinder) checkReusableWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, workflowName string) (sdk.EntityWithObject, *sdk.V2WorkflowRunInfo, error) {
+	ctx, next := telemetry.Span(ctx, "wref.checkReusableWorkflow", trace.StringAttribute(telemetry.TagWorkflow, workflowName))
+	defer next()
+
+	e, msg, err := wref.ef.searchWorkflow(ctx, db, store, workflowName)
+	if err != nil {
+		return sdk.EntityWithObject{}, nil, err
+	}
+	if msg != "" {
+		runMsg := sdk.V2WorkflowRunInfo{
+			WorkflowRunID: wref.run.ID,
+			Level:         sdk.WorkflowRunInfoLevelError,
+			Message:       msg,
+		}
+		return sdk.EntityWithObject{}, &runMsg, nil
+	}
+	return *e, nil, nil
+}
@@ -0,0 +1,395 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	gorillaws "github.com/gorilla/websocket"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow_v2"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/engine/websocket"
+	"github.com/ovh/cds/sdk"
+)
+
+/*
+During a debug pause, the worker opens a websocket on the API. When a user opens a debug shell on another websocket,
+messages are relayed between both websockets through the cache pubsub, so the worker and the user can be connected on
+different API instances.
+*/
+
+func debugBreakpointKey(runJobID string) string {
+	return cache.Key("api:v2:debug:breakpoint", runJobID)
+}
+
+// debugPausedKey stores the time a job has been paused, in seconds
+func debugPausedKey(runJobID string) string {
+	return cache.Key("api:v2:debug:paused", runJobID)
+}
+
+func debugWorkerChannel(runJobID string) string {
+	return cache.Key("api:v2:debug:worker", runJobID)
+}
+
+func debugSessionChannel(runJobID, sessionID string) string {
+	return cache.Key("api:v2:debug:session", runJobID, sessionID)
+}
+
+// checkWorkflowRunDebug checks the debug mode requested on a manual run or a restart
+func checkWorkflowRunDebug(wk sdk.V2Workflow, debug *sdk.V2WorkflowRunDebug) error {
+	if err := debug.Check(); err != nil {
+		return err
+	}
+	// Jobs of a workflow template are unknown before the run is crafted
+	if wk.From != "" {
+		return nil
+	}
+	for _, jobID := range debug.Jobs {
+		if _, has := wk.Jobs[jobID]; !has {
+			return sdk.NewErrorFrom(sdk.ErrInvalidData, "job %s not found in workflow", jobID)
+		}
+	}
+	return nil
+}
+
+// parseWorkflowRunDebugQuery returns the debug mode given on a restart request, or nil if it is not requested
+func parseWorkflowRunDebugQuery(req *http.Request) (*sdk.V2WorkflowRunDebug, error) {
+	onFailure := service.FormBool(req, "debug")
+	beforeSteps := FormStringSlice(req, "debug-step")
+	if !onFailure && len(beforeSteps) == 0 {
+		return nil, nil
+	}
+	debug := &sdk.V2WorkflowRunDebug{
+		Jobs:        FormStringSlice(req, "debug-job"),
+		OnFailure:   onFailure,
+		BeforeSteps: beforeSteps,
+	}
+	if timeout := req.FormValue("debug-timeout"); timeout != "" {
+		t, err := strconv.ParseInt(timeout, 10, 64)
+		if err != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid debug timeout %q", timeout)
+		}
+		debug.TimeoutMinutes = t
+	}
+	return debug, nil
+}
+
+func (api *API) loadDebugBreakpoint(runJobID string) (*sdk.V2WorkflowRunJobBreakpoint, error) {
+	var bp sdk.V2WorkflowRunJobBreakpoint
+	found, err := api.Cache.Get(debugBreakpointKey(runJobID), &bp)
+	if err != nil {
+		return nil, err
+	}
+	if !found || bp.Until.Before(time.Now()) {
+		return nil, nil
+	}
+	return &bp, nil
+}
+
+func (api *API) publishDebugShellMessage(ctx context.Context, channel string, msg sdk.V2DebugShellMessage) error {
+	bts, err := json.Marshal(msg)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	return api.Cache.Publish(ctx, channel, string(bts))
+}
+
+// relayDebugShellChannel writes all the messages received on the pubsub channel to the websocket
+func (api *API) relayDebugShellChannel(ctx context.Context, c *gorillaws.Conn, pubSub cache.PubSub, onMessage func(sdk.V2DebugShellMessage)) {
+	for ctx.Err() == nil {
+		m, err := pubSub.GetMessage(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn(ctx, "unable to get debug shell message: %v", err)
+			}
+			continue
+		}
+		if m == "" {
+			continue
+		}
+		if err := c.WriteMessage(gorillaws.TextMessage, []byte(m)); err != nil {
+			log.Debug(ctx, "unable to write debug shell message: %v", err)
+			return
+		}
+		if onMessage != nil {
+			var msg sdk.V2DebugShellMessage
+			if err := sdk.JSONUnmarshal([]byte(m), &msg); err == nil {
+				onMessage(msg)
+			}
+		}
+	}
+}
+
+func (api *API) insertDebugRunJobInfo(ctx context.Context, runJob sdk.V2WorkflowRunJob, message string) error {
+	info := sdk.V2WorkflowRunJobInfo{
+		Level:            sdk.WorkflowRunInfoLevelInfo,
+		IssuedAt:         time.Now(),
+		WorkflowRunJobID: runJob.ID,
+		WorkflowRunID:    runJob.WorkflowRunID,
+		Message:          message,
+	}
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+	if err := workflow_v2.InsertRunJobInfo(ctx, tx, &info); err != nil {
+		return err
+	}
+	return sdk.WithStack(tx.Commit())
+}
+
+// postJobRunDebugBreakpointHandler is called by the worker when it pauses the job, and when the job is resumed
+func (api *API) postJobRunDebugBreakpointHandler() ([]service.RbacChecker, service.Handler) {
+	return []service.RbacChecker{api.jobRunUpdate, api.isWorker}, func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		jobRunID := vars["runJobID"]
+
+		var bp sdk.V2WorkflowRunJobBreakpoint
+		if err := service.UnmarshalBody(r, &bp); err != nil {
+			return err
+		}
+
+		runJob, err := workflow_v2.LoadRunJobByID(ctx, api.mustDB(), jobRunID)
+		if err != nil {
+			return err
+		}
+		if runJob.Status != sdk.V2WorkflowRunJobStatusBuilding {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "job %s is not building", runJob.JobID)
+		}
+
+		run, err := workflow_v2.LoadRunByID(ctx, api.mustDB(), runJob.WorkflowRunID)
+		if err != nil {
+			return err
+		}
+		if !run.RunEvent.Debug.IsEnabledForJob(runJob.JobID) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "debug mode is not enabled on job %s", runJob.JobID)
+		}
+		debug := run.RunEvent.Debug
+
+		duration := time.Until(bp.Until)
+		if duration <= 0 || duration > debug.PauseDuration() {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid breakpoint end %s", bp.Until)
+		}
+
+		// The total time the job is paused cannot exceed the extension of the job timeout
+		pausedKey := debugPausedKey(runJob.ID)
+		pausedTTL := int(runJob.TimeoutMinutes * 60)
+		pauseSeconds := int64(duration.Seconds())
+		paused, err := api.Cache.IncrByWithTTL(pausedKey, pauseSeconds, pausedTTL)
+		if err != nil {
+			return err
+		}
+		if paused > int64(debug.MaxPauseDuration().Seconds()) {
+			if _, err := api.Cache.IncrByWithTTL(pausedKey, -pauseSeconds, pausedTTL); err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+			}
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "job %s has reached its maximum pause duration of %s", runJob.JobID, debug.MaxPauseDuration())
+		}
+
+		if err := api.Cache.SetWithDuration(debugBreakpointKey(runJob.ID), bp, duration); err != nil {
+			return err
+		}
+
+		return api.insertDebugRunJobInfo(ctx, *runJob, fmt.Sprintf("Job paused at step %s (%s) until %s. Open a debug shell with: cdsctl experimental workflow debug %s %s %s",
+			bp.StepName, bp.Reason, bp.Until.Format(time.RFC3339), runJob.ProjectKey, runJob.WorkflowRunID, runJob.ID))
+	}
+}
+
+func (api *API) deleteJobRunDebugBreakpointHandler() ([]service.RbacChecker, service.Handler) {
+	return []service.RbacChecker{api.jobRunUpdate, api.isWorker}, func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		jobRunID := vars["runJobID"]
+
+		runJob, err := workflow_v2.LoadRunJobByID(ctx, api.mustDB(), jobRunID)
+		if err != nil {
+			return err
+		}
+		// The remaining time of the pause can be used by the next breakpoints
+		bp, err := api.loadDebugBreakpoint(runJob.ID)
+		if err != nil {
+			return err
+		}
+		if bp != nil {
+			remaining := int64(time.Until(bp.Until).Seconds())
+			if _, err := api.Cache.IncrByWithTTL(debugPausedKey(runJob.ID), -remaining, int(runJob.TimeoutMinutes*60)); err != nil {
+				return err
+			}
+		}
+		if err := api.Cache.Delete(debugBreakpointKey(runJob.ID)); err != nil {
+			return err
+		}
+		return api.insertDebugRunJobInfo(ctx, *runJob, "Job resumed")
+	}
+}
+
+// getJobRunDebugWebsocketHandler is opened by the worker during a debug pause to receive the debug shell messages
+func (api *API) getJobRunDebugWebsocketHandler() ([]service.RbacChecker, service.Handler) {
+	return []service.RbacChecker{api.jobRunUpdate, api.isWorker}, func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		jobRunID := vars["runJobID"]
+
+		c, err := websocket.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			service.WriteError(ctx, w, r, sdk.NewErrorWithStack(err, sdk.ErrWebsocketUpgrade))
+			return nil
+		}
+		defer c.Close() // nolint
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		channel := debugWorkerChannel(jobRunID)
+		pubSub, err := api.Cache.Subscribe(channel)
+		if err != nil {
+			return sdk.WrapError(err, "unable to subscribe to %s", channel)
+		}
+		defer pubSub.Unsubscribe(context.Background(), channel) // nolint
+
+		api.GoRoutines.Exec(ctx, "debug-worker-"+jobRunID, func(ctx context.Context) {
+			api.relayDebugShellChannel(ctx, c, pubSub, nil)
+			cancel()
+		})
+
+		for ctx.Err() == nil {
+			_, m, err := c.ReadMessage()
+			if err != nil {
+				log.Debug(ctx, "debug websocket of job %s closed: %v", jobRunID, err)
+				return nil
+			}
+			var msg sdk.V2DebugShellMessage
+			if err := sdk.JSONUnmarshal(m, &msg); err != nil || msg.SessionID == "" {
+				log.Warn(ctx, "invalid debug shell message from worker of job %s", jobRunID)
+				continue
+			}
+			if err := api.publishDebugShellMessage(ctx, debugSessionChannel(jobRunID, msg.SessionID), msg); err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+			}
+		}
+		return nil
+	}
+}
+
+// getWorkflowRunJobDebugShellHandler opens an interactive shell on the worker of a paused job.
+// The session is audited in the job infos and is closed at the end of the pause.
+func (api *API) getWorkflowRunJobDebugShellHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.workflowTrigger),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+			workflowRunID := vars["workflowRunID"]
+			jobRunID := vars["jobRunID"]
+
+			u := getUserConsumer(ctx)
+			if u == nil {
+				return sdk.WithStack(sdk.ErrForbidden)
+			}
+
+			proj, err := project.Load(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+			wr, err := workflow_v2.LoadRunByProjectKeyAndID(ctx, api.mustDB(), proj.Key, workflowRunID)
+			if err != nil {
+				return err
+			}
+			runJob, err := workflow_v2.LoadRunJobByRunIDAndID(ctx, api.mustDB(), wr.ID, jobRunID)
+			if err != nil {
+				return err
+			}
+			if runJob.Status != sdk.V2WorkflowRunJobStatusBuilding {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "job %s is not building", runJob.JobID)
+			}
+			bp, err := api.loadDebugBreakpoint(runJob.ID)
+			if err != nil {
+				return err
+			}
+			if bp == nil {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "job %s is not paused", runJob.JobID)
+			}
+
+			c, err := websocket.Upgrader.Upgrade(w, req, nil)
+			if err != nil {
+				service.WriteError(ctx, w, req, sdk.NewErrorWithStack(err, sdk.ErrWebsocketUpgrade))
+				return nil
+			}
+			defer c.Close() // nolint
+
+			// The session cannot outlive the pause
+			ctx, cancel := context.WithDeadline(ctx, bp.Until)
+			defer cancel()
+
+			sessionID := sdk.UUID()
+			channel := debugSessionChannel(runJob.ID, sessionID)
+			pubSub, err := api.Cache.Subscribe(channel)
+			if err != nil {
+				return sdk.WrapError(err, "unable to subscribe to %s", channel)
+			}
+			defer pubSub.Unsubscribe(context.Background(), channel) // nolint
+
+			log.Info(ctx, "user %s opened debug session %s on job %s", u.GetUsername(), sessionID, runJob.ID)
+			if err := api.insertDebugRunJobInfo(ctx, *runJob, fmt.Sprintf("%s opened debug session %s", u.GetUsername(), sessionID)); err != nil {
+				return err
+			}
+			defer func() {
+				log.Info(ctx, "user %s closed debug session %s on job %s", u.GetUsername(), sessionID, runJob.ID)
+				if err := api.insertDebugRunJobInfo(context.Background(), *runJob, fmt.Sprintf("Debug session %s closed", sessionID)); err != nil {
+					log.ErrorWithStackTrace(ctx, err)
+				}
+				_ = api.publishDebugShellMessage(context.Background(), debugWorkerChannel(runJob.ID), sdk.V2DebugShellMessage{SessionID: sessionID, Type: sdk.V2DebugShellMessageTypeClose})
+			}()
+
+			if err := api.publishDebugShellMessage(ctx, debugWorkerChannel(runJob.ID), sdk.V2DebugShellMessage{
+				SessionID: sessionID,
+				Type:      sdk.V2DebugShellMessageTypeOpen,
+				Username:  u.GetUsername(),
+			}); err != nil {
+				return err
+			}
+
+			api.GoRoutines.Exec(ctx, "debug-session-"+sessionID, func(ctx context.Context) {
+				api.relayDebugShellChannel(ctx, c, pubSub, func(msg sdk.V2DebugShellMessage) {
+					if msg.Type == sdk.V2DebugShellMessageTypeClose {
+						cancel()
+					}
+				})
+			})
+			// Unblock the websocket read at the end of the session
+			api.GoRoutines.Exec(ctx, "debug-session-close-"+sessionID, func(ctx context.Context) {
+				<-ctx.Done()
+				_ = c.Close()
+			})
+
+			for ctx.Err() == nil {
+				_, m, err := c.ReadMessage()
+				if err != nil {
+					return nil
+				}
+				var msg sdk.V2DebugShellMessage
+				if err := sdk.JSONUnmarshal(m, &msg); err != nil {
+					continue
+				}
+				switch msg.Type {
+				case sdk.V2DebugShellMessageTypeInput, sdk.V2DebugShellMessageTypeContinue:
+				case sdk.V2DebugShellMessageTypeClose:
+					return nil
+				default:
+					continue
+				}
+				msg.SessionID = sessionID
+				msg.Username = u.GetUsername()
+				if err := api.publishDebugShellMessage(ctx, debugWorkerChannel(runJob.ID), msg); err != nil {
+					log.ErrorWithStackTrace(ctx, err)
+				}
+			}
+			return nil
+		}
+}
@@ -236,6 +236,8 @@ func (api *API) workflowRunV2Trigger(ctx context.Context, wrEnqueue sdk.V2Workfl
 			GateInputs:         rj.GateInputs,
 			Initiator:          rj.Initiator,
 			Concurrency:        rj.Concurrency,
+			TimeoutMinutes:     rj.TimeoutMinutes,
+			Priority:           rj.Priority,
 		}
 		rj.Status = sdk.V2WorkflowRunJobStatusFail
 
@@ -325,7 +327,7 @@ func (api *API) workflowRunV2Trigger(ctx context.Context, wrEnqueue sdk.V2Workfl
 	// Enqueue JOB
 	hasTemplatedJob := false
 	for _, j := range jobsToQueue {
-		if j.Job.From != "" {
+		if j.Job.From != "" || j.Job.Uses != "" {
 			hasTemplatedJob = true
 		}
 	}
@@ -447,6 +449,23 @@ func (api *API) workflowRunV2Trigger(ctx context.Context, wrEnqueue sdk.V2Workfl
 			now := time.Now()
 			rj.Ended = &now
 		}
+		rj.Priority = rj.ComputePriority(run.Concurrency)
+
+		// Compute outputs of a job calling a reusable workflow
+		var reusableWorkflowOutputs []sdk.V2WorkflowRunResult
+		if rj.Job.Uses != "" && rj.Status == sdk.V2WorkflowRunJobStatusSuccess && len(rj.Job.Outputs) > 0 {
+			reusableWorkflowOutputs, err = computeReusableWorkflowOutputs(ctx, *run, *rj, runJobsContexts)
+			if err != nil {
+				rj.Status = sdk.V2WorkflowRunJobStatusFail
+				runJobsInfos[rj.ID] = sdk.V2WorkflowRunJobInfo{
+					WorkflowRunID: run.ID,
+					IssuedAt:      time.Now(),
+					Level:         sdk.WorkflowRunInfoLevelError,
+					Message:       err.Error(),
+				}
+			}
+		}
+
 		if _, has := allreadyExistRunJobs[rj.ID]; !has {
 			if err := workflow_v2.InsertRunJob(ctx, tx, rj); err != nil {
 				return err
@@ -457,6 +476,13 @@ func (api *API) workflowRunV2Trigger(ctx context.Context, wrEnqueue sdk.V2Workfl
 			}
 		}
 
+		for i := range reusableWorkflowOutputs {
+			reusableWorkflowOutputs[i].WorkflowRunJobID = rj.ID
+			if err := workflow_v2.InsertRunResult(ctx, tx, &reusableWorkflowOutputs[i]); err != nil {
+				return err
+			}
+		}
+
 		if info, has := runJobsInfos[rj.ID]; has {
 			info.WorkflowRunJobID = rj.ID
 			if err := workflow_v2.InsertRunJobInfo(ctx, tx, &info); err != nil {
@@ -1478,6 +1504,7 @@ func prepareRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Store, proj
 				Git: run.Contexts.Git,
 				Env: envCtx,
 			},
+			Inputs:       buildJobInputsContext(*run, jobDef),
 			Jobs:         runJobsContexts,
 			Vars:         make(map[string]interface{}),
 			Needs:        sdk.NeedsContext{},
@@ -1530,7 +1557,8 @@ func prepareRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Store, proj
 		}
 
 		// Compute job matrix strategy
-		matrixPermutation, msInfo := generateMatrixPermutation(ctx, runJobContext, run, jobDef, jobToTrigger.Status)
+		isMatrixJob := jobDef.Strategy.HasMatrix()
+		matrixPermutation, msInfo := generateMatrixPermutation(ctx, runJobContext, run, &jobDef, jobToTrigger.Status)
 		if msInfo != nil {
 			return nil, nil, nil, []sdk.V2WorkflowRunInfo{*msInfo}, false, err
 		}
@@ -1554,12 +1582,13 @@ func prepareRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Store, proj
 				RunNumber:          run.RunNumber,
 				RunAttempt:         run.RunAttempt,
 				Initiator:          wrEnqueue.Initiator,
+				TimeoutMinutes:     jobDef.TimeoutMinutes,
 			}
-			if jobDef.From == "" && len(jobDef.Steps) == 0 && !jobToTrigger.Status.IsTerminated() {
+			if jobDef.From == "" && jobDef.Uses == "" && len(jobDef.Steps) == 0 && !jobToTrigger.Status.IsTerminated() {
 				runJob.Status = sdk.V2WorkflowRunJobStatusSuccess
 			}
 			// If the current job was a matrix, skip it
-			if jobDef.Strategy != nil && len(jobDef.Strategy.Matrix) > 0 {
+			if isMatrixJob {
 				runJob.Status = sdk.V2WorkflowRunJobStatusSkipped
 				runJobsInfo[runJob.ID] = sdk.V2WorkflowRunJobInfo{
 					WorkflowRunID:    runJob.WorkflowRunID,
@@ -1584,6 +1613,22 @@ func prepareRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Store, proj
 					if len(msgs) > 0 {
 						return nil, nil, nil, msgs, false, nil
 					}
+				} else if jobDef.Uses != "" {
+					if !isReusableWorkflowExpanded(run.WorkflowData.Workflow, jobID) {
+						// The jobs of the reusable workflow are added to the parent workflow, the current job will be run
+						// when all of them are terminated to compute its status and outputs
+						hasToUpdateRun = true
+						msgs, err := computeJobFromReusableWorkflow(ctx, db, store, wref, runJobContext, jobID, jobDef, run, projectVariableSets, defaultRegion)
+						if err != nil {
+							return nil, nil, nil, nil, hasToUpdateRun, err
+						}
+						if len(msgs) > 0 {
+							return nil, nil, nil, msgs, false, nil
+						}
+					} else {
+						runJob.Status = computeReusableWorkflowStatus(run.WorkflowData.Workflow, jobID, runJobsContexts)
+						runJobs = append(runJobs, runJob)
+					}
 				} else {
 					// If no template, interpolate job data
 					if jobDef.RunsOn.Model != "" {
@@ -1870,6 +1915,12 @@ loop:
 		}
 	}
 
+	return mergeEntityJobsInWorkflow(ctx, db, store, wref, templateEntity, run, newJobs, newGates, newAnnotations, newConcurrencies, allVariableSets, defaultRegion)
+}
+
+// mergeEntityJobsInWorkflow adds jobs coming from another entity (template or reusable workflow) into the run workflow,
+// with their gates, annotations and concurrencies. Actions and worker models are resolved from the entity repository.
+func mergeEntityJobsInWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, wref *WorkflowRunEntityFinder, templateEntity *sdk.EntityWithObject, run *sdk.V2WorkflowRun, newJobs map[string]sdk.V2Job, newGates map[string]sdk.V2JobGate, newAnnotations map[string]string, newConcurrencies []sdk.WorkflowConcurrency, allVariableSets []sdk.ProjectVariableSet, defaultRegion string) ([]sdk.V2WorkflowRunInfo, error) {
 	repoTemplate, err := repository.LoadRepositoryByID(ctx, db, templateEntity.ProjectRepositoryID)
 	if err != nil {
 		return nil, err
@@ -1966,6 +2017,240 @@ loop:
 	return nil, nil
 }
 
+func computeJobFromReusableWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, wref *WorkflowRunEntityFinder, runJobContext sdk.WorkflowRunJobsContext, jobID string, j sdk.V2Job, run *sdk.V2WorkflowRun, allVariableSets []sdk.ProjectVariableSet, defaultRegion string) ([]sdk.V2WorkflowRunInfo, error) {
+	ctx, end := telemetry.Span(ctx, "computeJobFromReusableWorkflow")
+	defer end()
+
+	var errorMsg = func(format string, args ...interface{}) []sdk.V2WorkflowRunInfo {
+		return []sdk.V2WorkflowRunInfo{{
+			WorkflowRunID: run.ID,
+			Level:         sdk.WorkflowRunInfoLevelError,
+			IssuedAt:      time.Now(),
+			Message:       fmt.Sprintf(format, args...),
+		}}
+	}
+
+	// Retrieve the called workflow
+	e, msg, err := wref.checkReusableWorkflow(ctx, db, store, j.Uses)
+	if err != nil {
+		return nil, err
+	}
+	if msg != nil {
+		msg.IssuedAt = time.Now()
+		return []sdk.V2WorkflowRunInfo{*msg}, nil
+	}
+	calledWorkflow := e.Workflow
+	if calledWorkflow.On == nil || calledWorkflow.On.WorkflowCall == nil {
+		return errorMsg("Job %s: workflow %s cannot be called, on.workflow-call is not defined", jobID, j.Uses), nil
+	}
+	if calledWorkflow.From != "" {
+		return errorMsg("Job %s: workflow %s is generated from a template and cannot be called", jobID, j.Uses), nil
+	}
+	for subJobID, subJob := range calledWorkflow.Jobs {
+		if subJob.Uses != "" {
+			return errorMsg("Job %s: workflow %s cannot call another workflow from job %s", jobID, j.Uses, subJobID), nil
+		}
+		if _, exist := run.WorkflowData.Workflow.Jobs[reusableWorkflowJobID(jobID, subJobID)]; exist {
+			return errorMsg("Job %s: job %s defined in workflow %s already exist in the parent workflow", jobID, reusableWorkflowJobID(jobID, subJobID), j.Uses), nil
+		}
+	}
+
+	// Interpolate and check inputs
+	bts, _ := json.Marshal(runJobContext)
+	var mapContexts map[string]interface{}
+	if err := json.Unmarshal(bts, &mapContexts); err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+		return errorMsg("Job %s: unable to build context to compute workflow inputs: %v", jobID, err), nil
+	}
+	ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
+	with := make(map[string]interface{}, len(j.With))
+	for k, v := range j.With {
+		if s, ok := v.(string); ok {
+			value, err := ap.Interpolate(ctx, s)
+			if err != nil {
+				return errorMsg("Job %s: unable to interpolate input %s: %v", jobID, k, err), nil
+			}
+			v = value
+		}
+		with[k] = v
+	}
+	inputs, err := calledWorkflow.On.WorkflowCall.ComputeInputs(with)
+	if err != nil {
+		return errorMsg("Job %s: invalid inputs for workflow %s: %v", jobID, j.Uses, sdk.ExtractHTTPError(err).Message), nil
+	}
+
+	// Jobs of the reusable workflow run in the stage of the calling job, their ids are prefixed by the calling job id
+	// so the same workflow can be called by several jobs
+	newJobs := make(map[string]sdk.V2Job, len(calledWorkflow.Jobs))
+	for subJobID, subJob := range calledWorkflow.Jobs {
+		subJob = subJob.Copy()
+		subJob.Group = jobID
+		for k, v := range inputs {
+			if _, has := subJob.Inputs[k]; !has {
+				subJob.Inputs[k] = v
+			}
+		}
+		subJob.Stage = j.Stage
+		for k, v := range calledWorkflow.Env {
+			if _, has := subJob.Env[k]; !has {
+				subJob.Env[k] = v
+			}
+		}
+		for _, integ := range calledWorkflow.Integrations {
+			if !slices.Contains(subJob.Integrations, integ) {
+				subJob.Integrations = append(subJob.Integrations, integ)
+			}
+		}
+		for _, vs := range calledWorkflow.VariableSets {
+			if !slices.Contains(subJob.VariableSets, vs) {
+				subJob.VariableSets = append(subJob.VariableSets, vs)
+			}
+		}
+		if len(subJob.Needs) == 0 {
+			subJob.Needs = append(subJob.Needs, j.Needs...)
+		} else {
+			for i := range subJob.Needs {
+				subJob.Needs[i] = reusableWorkflowJobID(jobID, subJob.Needs[i])
+			}
+		}
+		newJobs[reusableWorkflowJobID(jobID, subJobID)] = subJob
+	}
+
+	// Retrieve final jobs, the calling job will wait for them
+	finalJobs := make([]string, 0)
+loop:
+	for subJobID := range newJobs {
+		for _, jobDef := range newJobs {
+			if slices.Contains(jobDef.Needs, subJobID) {
+				continue loop
+			}
+		}
+		finalJobs = append(finalJobs, subJobID)
+	}
+	slices.Sort(finalJobs)
+
+	msgs, err := mergeEntityJobsInWorkflow(ctx, db, store, wref, &e, run, newJobs, calledWorkflow.Gates, nil, calledWorkflow.Concurrencies, allVariableSets, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > 0 {
+		return msgs, nil
+	}
+
+	callingJob := run.WorkflowData.Workflow.Jobs[jobID]
+	callingJob.Needs = finalJobs
+	callingJob.If = "${{ always() }}"
+	callingJob.Gate = ""
+	callingJob.Inputs = inputs
+	callingJob.Outputs = make(map[string]sdk.ActionOutput, len(calledWorkflow.On.WorkflowCall.Outputs))
+	for k, o := range calledWorkflow.On.WorkflowCall.Outputs {
+		callingJob.Outputs[k] = sdk.ActionOutput{Description: o.Description, Value: o.Value}
+	}
+	run.WorkflowData.Workflow.Jobs[jobID] = callingJob
+
+	msgsLint := make([]sdk.V2WorkflowRunInfo, 0)
+	for _, e := range run.WorkflowData.Workflow.Lint() {
+		msgsLint = append(msgsLint, sdk.V2WorkflowRunInfo{
+			WorkflowRunID: run.ID,
+			Level:         sdk.WorkflowRunInfoLevelError,
+			IssuedAt:      time.Now(),
+			Message:       e.Error(),
+		})
+	}
+	return msgsLint, nil
+}
+
+// reusableWorkflowJobID returns the id in the run workflow of a job added by a reusable workflow
+func reusableWorkflowJobID(callingJobID, subJobID string) string {
+	return callingJobID + "." + subJobID
+}
+
+// reusableWorkflowJobsContext returns the contexts of the jobs added by a reusable workflow, keyed by their id in the reusable workflow
+func reusableWorkflowJobsContext(callingJobID string, runJobsContexts sdk.JobsResultContext) sdk.JobsResultContext {
+	jobsContext := sdk.JobsResultContext{}
+	for k, v := range runJobsContexts {
+		if subJobID, found := strings.CutPrefix(k, callingJobID+"."); found {
+			jobsContext[subJobID] = v
+		}
+	}
+	return jobsContext
+}
+
+// isReusableWorkflowExpanded returns true if the jobs of the reusable workflow called by the given job have been added to the run workflow
+func isReusableWorkflowExpanded(w sdk.V2Workflow, jobID string) bool {
+	for _, j := range w.Jobs {
+		if j.Group == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// computeReusableWorkflowStatus returns the status of a job calling a reusable workflow from the status of the reusable workflow jobs:
+// stopped if one of them has been stopped, then failed if one of them failed, success otherwise
+func computeReusableWorkflowStatus(w sdk.V2Workflow, jobID string, runJobsContexts sdk.JobsResultContext) sdk.V2WorkflowRunJobStatus {
+	status := sdk.V2WorkflowRunJobStatusSuccess
+	for subJobID, subJob := range w.Jobs {
+		if subJob.Group != jobID {
+			continue
+		}
+		jobCtx, has := runJobsContexts[subJobID]
+		if !has {
+			continue
+		}
+		switch {
+		case jobCtx.Result == sdk.V2WorkflowRunJobStatusStopped:
+			return sdk.V2WorkflowRunJobStatusStopped
+		case jobCtx.Result == sdk.V2WorkflowRunJobStatusFail && !subJob.ContinueOnError:
+			status = sdk.V2WorkflowRunJobStatusFail
+		}
+	}
+	return status
+}
+
+// computeReusableWorkflowOutputs interpolates the outputs declared by a reusable workflow and returns them as variable run results
+func computeReusableWorkflowOutputs(ctx context.Context, run sdk.V2WorkflowRun, rj sdk.V2WorkflowRunJob, runJobsContexts sdk.JobsResultContext) ([]sdk.V2WorkflowRunResult, error) {
+	outputsContext := sdk.WorkflowRunJobsContext{
+		WorkflowRunContext: run.Contexts,
+		Inputs:             rj.Job.Inputs,
+		Jobs:               reusableWorkflowJobsContext(rj.JobID, runJobsContexts),
+	}
+	bts, err := json.Marshal(outputsContext)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	var mapContexts map[string]interface{}
+	if err := json.Unmarshal(bts, &mapContexts); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
+
+	results := make([]sdk.V2WorkflowRunResult, 0, len(rj.Job.Outputs))
+	for name, o := range rj.Job.Outputs {
+		value, err := ap.InterpolateToString(ctx, o.Value)
+		if err != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrInvalidData, "unable to compute output %s of workflow %s: %v", name, rj.Job.Uses, err)
+		}
+		results = append(results, sdk.V2WorkflowRunResult{
+			ID:               sdk.UUID(),
+			WorkflowRunID:    run.ID,
+			WorkflowRunJobID: rj.ID,
+			RunAttempt:       rj.RunAttempt,
+			IssuedAt:         time.Now(),
+			Status:           sdk.StatusSuccess,
+			Type:             sdk.V2WorkflowRunResultTypeVariable,
+			Detail: sdk.V2WorkflowRunResultDetail{
+				Type: sdk.V2WorkflowRunResultVariableDetailType,
+				Data: sdk.V2WorkflowRunResultVariableDetail{
+					Name:  name,
+					Value: value,
+				},
+			},
+		})
+	}
+	return results, nil
+}
+
 func createTemplatedMatrixedJobs(ctx context.Context, db *gorp.DbMap, store cache.Store, wref *WorkflowRunEntityFinder, matrixPermutation []map[string]string, run *sdk.V2WorkflowRun, data prepareJobData) []sdk.V2WorkflowRunInfo {
 	newJobs := make(map[string]sdk.V2Job)
 	newStages := make(map[string]sdk.WorkflowStage)
@@ -2121,6 +2406,23 @@ func createMatrixedRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Stor
 
 	// Check permutation to trigger
 	permutations := searchPermutationToTrigger(ctx, matrixPermutation, data.existingRunJobs, data.jobID)
+
+	// Limit the number of permutations running at the same time, the next ones will be triggered when a run job ends
+	if strategy := data.jobToTrigger.Job.Strategy; strategy != nil && strategy.MaxParallel > 0 && !data.jobToTrigger.Status.IsTerminated() {
+		nbRunning := 0
+		for _, rj := range data.existingRunJobs {
+			if rj.JobID == data.jobID && !rj.Status.IsTerminated() {
+				nbRunning++
+			}
+		}
+		nbToTrigger := int(strategy.MaxParallel) - nbRunning
+		if nbToTrigger < 0 {
+			nbToTrigger = 0
+		}
+		if len(permutations) > nbToTrigger {
+			permutations = permutations[:nbToTrigger]
+		}
+	}
 	for _, m := range permutations {
 		permJobDef := data.jobToTrigger.Job.Copy()
 		runJob := sdk.V2WorkflowRunJob{
@@ -2141,6 +2443,7 @@ func createMatrixedRunJobs(ctx context.Context, db *gorp.DbMap, store cache.Stor
 			RunAttempt:         run.RunAttempt,
 			Matrix:             sdk.JobMatrix{},
 			Initiator:          data.wrEnqueue.Initiator,
+			TimeoutMinutes:     permJobDef.TimeoutMinutes,
 		}
 		if len(data.jobToTrigger.Job.Steps) == 0 && !data.jobToTrigger.Status.IsTerminated() {
 			runJob.Status = sdk.V2WorkflowRunJobStatusSuccess
@@ -2217,6 +2520,9 @@ func searchPermutationToTrigger(_ context.Context, permutations []map[string]str
 		// Search if it has been already trigger
 	runJobLoop:
 		for _, rj := range runJobsForJobID {
+			if len(rj.Matrix) != len(perm) {
+				continue
+			}
 			for k, v := range perm {
 				// If not the same permutation, check next run job
 				if rj.Matrix[k] != v {
@@ -2233,95 +2539,205 @@ func searchPermutationToTrigger(_ context.Context, permutations []map[string]str
 	return permutationToTrigger
 }
 
-func generateMatrixPermutation(ctx context.Context, rootJobContext sdk.WorkflowRunJobsContext, run *sdk.V2WorkflowRun, jobDef sdk.V2Job, status sdk.V2WorkflowRunJobStatus) ([]map[string]string, *sdk.V2WorkflowRunInfo) {
-	if status.IsTerminated() {
+func generateMatrixPermutation(ctx context.Context, rootJobContext sdk.WorkflowRunJobsContext, run *sdk.V2WorkflowRun, jobDef *sdk.V2Job, status sdk.V2WorkflowRunJobStatus) ([]map[string]string, *sdk.V2WorkflowRunInfo) {
+	if status.IsTerminated() || !jobDef.Strategy.HasMatrix() {
 		return make([]map[string]string, 0), nil
 	}
-	keys := make([]string, 0)
-	interpolatedMatrix := make(map[string][]string)
-	if jobDef.Strategy != nil && len(jobDef.Strategy.Matrix) > 0 {
-		bts, _ := json.Marshal(rootJobContext)
-		var mapContexts map[string]interface{}
-		_ = json.Unmarshal(bts, &mapContexts) // error cannot happen here
+	matrixInfo := func(format string, args ...interface{}) *sdk.V2WorkflowRunInfo {
+		return &sdk.V2WorkflowRunInfo{
+			WorkflowRunID: run.ID,
+			IssuedAt:      time.Now(),
+			Level:         sdk.WorkflowRunInfoLevelError,
+			Message:       fmt.Sprintf(format, args...),
+		}
+	}
 
-		ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
+	bts, _ := json.Marshal(rootJobContext)
+	var mapContexts map[string]interface{}
+	_ = json.Unmarshal(bts, &mapContexts) // error cannot happen here
 
-		for k, v := range jobDef.Strategy.Matrix {
-			keys = append(keys, k)
+	ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
 
-			matrixValues := make([]string, 0)
-			if slice, ok := v.([]interface{}); ok {
-				for _, sliceValue := range slice {
-					valueString, ok := sliceValue.(string)
-					if !ok {
-						msg := &sdk.V2WorkflowRunInfo{
-							WorkflowRunID: run.ID,
-							IssuedAt:      time.Now(),
-							Level:         sdk.WorkflowRunInfoLevelError,
-							Message:       fmt.Sprintf("matrix value %v is not a string", sliceValue),
-						}
-						return nil, msg
-					}
+	// Dynamic matrix: the whole matrix is computed at runtime, generally from the outputs of a needed job.
+	// The resolved matrix is set on a copy of the strategy to keep the expression in the workflow definition
+	if jobDef.Strategy.MatrixExpression != "" {
+		interpolatedValue, err := ap.Interpolate(ctx, jobDef.Strategy.MatrixExpression)
+		if err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+			return nil, matrixInfo("unable to interpolate matrix %s: %v", jobDef.Strategy.MatrixExpression, err)
+		}
+		resolvedMatrix, ok := interpolatedValue.(map[string]interface{})
+		if !ok {
+			return nil, matrixInfo("matrix %s must be computed as an object, got %T", jobDef.Strategy.MatrixExpression, interpolatedValue)
+		}
+		strategy := *jobDef.Strategy
+		strategy.Matrix = resolvedMatrix
+		strategy.MatrixExpression = ""
+		jobDef.Strategy = &strategy
+	}
 
-					interpolatedValue, err := ap.InterpolateToString(ctx, valueString)
+	interpolatedMatrix := make(map[string]interface{})
+	for k, v := range jobDef.Strategy.Matrix {
+		// Include and exclude entries are objects, only interpolate their values
+		if k == sdk.V2JobStrategyMatrixInclude || k == sdk.V2JobStrategyMatrixExclude {
+			entries, err := sdk.GetMatrixEntries(k, v)
+			if err != nil {
+				return nil, matrixInfo("%v", err)
+			}
+			interpolatedEntries := make([]interface{}, 0, len(entries))
+			for _, e := range entries {
+				interpolatedEntry := make(map[string]interface{}, len(e))
+				for entryKey, entryValue := range e {
+					interpolatedValue, err := ap.InterpolateToString(ctx, entryValue)
 					if err != nil {
 						log.ErrorWithStackTrace(ctx, err)
-						msg := &sdk.V2WorkflowRunInfo{
-							WorkflowRunID: run.ID,
-							IssuedAt:      time.Now(),
-							Level:         sdk.WorkflowRunInfoLevelError,
-							Message:       fmt.Sprintf("unable to interpolate matrix value %s: %v", valueString, err),
-						}
-						return nil, msg
+						return nil, matrixInfo("unable to interpolate matrix %s value %s: %v", k, entryValue, err)
 					}
-					matrixValues = append(matrixValues, interpolatedValue)
+					interpolatedEntry[entryKey] = interpolatedValue
 				}
-			} else if valueString, ok := v.(string); ok {
-				interpolatedValue, err := ap.Interpolate(ctx, valueString)
-				if err != nil {
-					log.ErrorWithStackTrace(ctx, err)
-					msg := &sdk.V2WorkflowRunInfo{
-						WorkflowRunID: run.ID,
-						IssuedAt:      time.Now(),
-						Level:         sdk.WorkflowRunInfoLevelError,
-						Message:       fmt.Sprintf("unable to interpolate %s: %v", valueString, err),
-					}
-					return nil, msg
-				}
-				interpoaltedSlice, ok := interpolatedValue.([]interface{})
+				interpolatedEntries = append(interpolatedEntries, interpolatedEntry)
+			}
+			interpolatedMatrix[k] = interpolatedEntries
+			continue
+		}
+
+		matrixValues := make([]string, 0)
+		if slice, ok := v.([]interface{}); ok {
+			for _, sliceValue := range slice {
+				valueString, ok := sliceValue.(string)
 				if !ok {
-					msg := &sdk.V2WorkflowRunInfo{
-						WorkflowRunID: run.ID,
-						IssuedAt:      time.Now(),
-						Level:         sdk.WorkflowRunInfoLevelError,
-						Message:       fmt.Sprintf("interpolated matrix is not a string slice, got %T", interpolatedValue),
+					// Values computed from a json can be numbers or booleans
+					switch sliceValue.(type) {
+					case map[string]interface{}, []interface{}, nil:
+						return nil, matrixInfo("matrix value %v is not a string", sliceValue)
 					}
-					return nil, msg
-				}
-				stringsValue := make([]string, 0, len(interpoaltedSlice))
-				for _, vle := range interpoaltedSlice {
-					stringsValue = append(stringsValue, fmt.Sprintf("%v", vle))
+					matrixValues = append(matrixValues, fmt.Sprintf("%v", sliceValue))
+					continue
 				}
-				matrixValues = stringsValue
-			} else {
-				msg := &sdk.V2WorkflowRunInfo{
-					WorkflowRunID: run.ID,
-					IssuedAt:      time.Now(),
-					Level:         sdk.WorkflowRunInfoLevelError,
-					Message:       fmt.Sprintf("unable to use matrix key %s of type %T", k, v),
+
+				interpolatedValue, err := ap.InterpolateToString(ctx, valueString)
+				if err != nil {
+					log.ErrorWithStackTrace(ctx, err)
+					return nil, matrixInfo("unable to interpolate matrix value %s: %v", valueString, err)
 				}
-				return nil, msg
+				matrixValues = append(matrixValues, interpolatedValue)
+			}
+		} else if valueString, ok := v.(string); ok {
+			interpolatedValue, err := ap.Interpolate(ctx, valueString)
+			if err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+				return nil, matrixInfo("unable to interpolate %s: %v", valueString, err)
+			}
+			interpoaltedSlice, ok := interpolatedValue.([]interface{})
+			if !ok {
+				return nil, matrixInfo("interpolated matrix is not a string slice, got %T", interpolatedValue)
 			}
-			interpolatedMatrix[k] = matrixValues
+			stringsValue := make([]string, 0, len(interpoaltedSlice))
+			for _, vle := range interpoaltedSlice {
+				stringsValue = append(stringsValue, fmt.Sprintf("%v", vle))
+			}
+			matrixValues = stringsValue
+		} else {
+			return nil, matrixInfo("unable to use matrix key %s of type %T", k, v)
+		}
+		interpolatedMatrix[k] = matrixValues
+	}
+
+	alls, err := computeMatrixPermutations(interpolatedMatrix)
+	if err != nil {
+		return nil, matrixInfo("%v", err)
+	}
+	for k := range interpolatedMatrix {
+		jobDef.Strategy.Matrix[k] = interpolatedMatrix[k]
+	}
 
+	return alls, nil
+}
+
+// computeMatrixPermutations returns the permutations of an interpolated matrix.
+// Permutations matching all the values of an exclude entry are removed. Then each include entry
+// extends the permutations matching its values, or is added as a new permutation if none matches.
+func computeMatrixPermutations(matrix map[string]interface{}) ([]map[string]string, error) {
+	keys := make([]string, 0, len(matrix))
+	values := make(map[string][]string, len(matrix))
+	var includes, excludes []map[string]string
+	for k, v := range matrix {
+		var err error
+		switch k {
+		case sdk.V2JobStrategyMatrixInclude:
+			includes, err = sdk.GetMatrixEntries(k, v)
+		case sdk.V2JobStrategyMatrixExclude:
+			excludes, err = sdk.GetMatrixEntries(k, v)
+		default:
+			keys = append(keys, k)
+			switch vs := v.(type) {
+			case []string:
+				values[k] = vs
+			case []interface{}:
+				for _, vi := range vs {
+					values[k] = append(values[k], fmt.Sprintf("%v", vi))
+				}
+			default:
+				err = fmt.Errorf("matrix key %s must be a list, got %T", k, v)
+			}
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
+	slices.Sort(keys)
 
 	alls := make([]map[string]string, 0)
-	if jobDef.Strategy != nil && len(interpolatedMatrix) > 0 {
-		generateMatrix(interpolatedMatrix, keys, 0, make(map[string]string), &alls)
-		for k := range interpolatedMatrix {
-			jobDef.Strategy.Matrix[k] = interpolatedMatrix[k]
+	if len(keys) > 0 {
+		generateMatrix(values, keys, 0, make(map[string]string), &alls)
+	}
+
+	matchEntry := func(perm map[string]string, entry map[string]string, onlyMatrixKeys bool) bool {
+		for k, v := range entry {
+			if _, isMatrixKey := values[k]; onlyMatrixKeys && !isMatrixKey {
+				continue
+			}
+			if permValue, has := perm[k]; !has || permValue != v {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(excludes) > 0 {
+		filtered := make([]map[string]string, 0, len(alls))
+	permLoop:
+		for _, perm := range alls {
+			for _, e := range excludes {
+				if matchEntry(perm, e, false) {
+					continue permLoop
+				}
+			}
+			filtered = append(filtered, perm)
+		}
+		alls = filtered
+	}
+
+	nbPermutations := len(alls)
+	for _, inc := range includes {
+		found := false
+		for _, perm := range alls[:nbPermutations] {
+			if !matchEntry(perm, inc, true) {
+				continue
+			}
+			found = true
+			for k, v := range inc {
+				if _, isMatrixKey := values[k]; !isMatrixKey {
+					perm[k] = v
+				}
+			}
+		}
+		if !found {
+			newPerm := make(map[string]string, len(inc))
+			for k, v := range inc {
+				newPerm[k] = v
+			}
+			alls = append(alls, newPerm)
 		}
 	}
 
@@ -2382,14 +2798,11 @@ func retrieveJobToQueue(ctx context.Context, db *gorp.DbMap, wrEnqueue sdk.V2Wor
 					continue
 				}
 
-				nbPermutations := 1
-				for _, v := range runJobMapItem.Job.Strategy.Matrix {
-					if vString, ok := v.([]string); ok {
-						nbPermutations *= len(vString)
-					} else if vInterface, ok := v.([]interface{}); ok {
-						nbPermutations *= len(vInterface)
-					}
+				permutations, err := computeMatrixPermutations(runJobMapItem.Job.Strategy.Matrix)
+				if err != nil {
+					return nil, nil, err
 				}
+				nbPermutations := len(permutations)
 				runPermutations := 0
 				for _, rj := range runJobs {
 					if rj.JobID == runJobMapItem.JobID {
@@ -2431,6 +2844,7 @@ func retrieveJobToQueue(ctx context.Context, db *gorp.DbMap, wrEnqueue sdk.V2Wor
 
 		// Build job context
 		jobContext := buildContextForJob(ctx, run.WorkflowData.Workflow, runJobsContexts, run.Contexts, stages, jobID)
+		jobContext.Inputs = buildJobInputsContext(*run, jobDef)
 
 		canBeQueued, infos, err := checkJob(ctx, db, wrEnqueue, *run, jobID, &jobDef, jobContext)
 		runInfos = append(runInfos, infos...)
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"strings"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/rockbears/log"
@@ -98,14 +99,12 @@ nextjob:
 				break
 			}
 		}
-		var nbPermutations = 1
-		for _, v := range jobDef.Strategy.Matrix {
-			if vString, ok := v.([]string); ok {
-				nbPermutations *= len(vString)
-			} else if vInterface, ok := v.([]interface{}); ok {
-				nbPermutations *= len(vInterface)
-			}
+		permutations, err := computeMatrixPermutations(jobDef.Strategy.Matrix)
+		if err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+			continue
 		}
+		nbPermutations := len(permutations)
 		// if there is still permutation to run, ignore this job context
 		if nbPermutations > len(matrixJobs[k]) {
 			continue
@@ -183,14 +182,38 @@ func buildContextForJob(ctx context.Context, workflow sdk.V2Workflow, runJobsCon
 		}
 	}
 
+	// Jobs added by a reusable workflow refer to each other with their id in the reusable workflow
+	if jobDef.Group != "" {
+		for k, v := range reusableWorkflowJobsContext(jobDef.Group, jobsContext) {
+			jobsContext[k] = v
+		}
+		for _, n := range jobNeeds {
+			if subJobID, found := strings.CutPrefix(n, jobDef.Group+"."); found {
+				if needContext, has := needsContext[n]; has {
+					needsContext[subJobID] = needContext
+				}
+			}
+		}
+	}
+
 	currentJobContext := sdk.WorkflowRunJobsContext{
 		WorkflowRunContext: runContext,
+		Inputs:             jobDef.Inputs,
 		Jobs:               jobsContext,
 		Needs:              needsContext,
 	}
 	return currentJobContext
 }
 
+// buildJobInputsContext returns the inputs of a job: the inputs of the reusable workflow that added the job,
+// or the workflow inputs given when the run was manually started.
+func buildJobInputsContext(run sdk.V2WorkflowRun, jobDef sdk.V2Job) map[string]interface{} {
+	if jobDef.Group != "" {
+		return jobDef.Inputs
+	}
+	return run.RunEvent.Inputs
+}
+
 func buildAncestorJobContext(ctx context.Context, jobID string, workflow sdk.V2Workflow, runJobsContext sdk.JobsResultContext, stages sdk.WorkflowRunStages, currentJobContext sdk.JobsResultContext) {
 	jobDef := workflow.Jobs[jobID]
 	if len(jobDef.Needs) == 0 && jobDef.Stage != "" {
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ovh/cds/engine/api/entity"
+	"github.com/ovh/cds/engine/api/organization"
+	"github.com/ovh/cds/engine/api/rbac"
+	"github.com/ovh/cds/engine/api/region"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow_v2"
+	"github.com/ovh/cds/sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowTrigger_ReusableWorkflow(t *testing.T) {
+	ctx := context.TODO()
+	api, db, _ := newTestAPI(t)
+
+	_, err := db.Exec("DELETE FROM rbac")
+	require.NoError(t, err)
+	_, err = db.Exec("DELETE FROM region")
+	require.NoError(t, err)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+
+	org, err := organization.LoadOrganizationByName(context.TODO(), db, "default")
+	require.NoError(t, err)
+
+	reg := sdk.Region{
+		Name: "build",
+	}
+	require.NoError(t, region.Insert(context.TODO(), db, &reg))
+	api.Config.Workflow.JobDefaultRegion = reg.Name
+
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+
+	rb := sdk.RBAC{
+		Name: sdk.RandomString(10),
+		Regions: []sdk.RBACRegion{
+			{
+				RegionID:            reg.ID,
+				AllUsers:            true,
+				RBACOrganizationIDs: []string{org.ID},
+				Role:                sdk.RegionRoleExecute,
+			},
+		},
+		RegionProjects: []sdk.RBACRegionProject{
+			{
+				Role:        sdk.RegionRoleExecute,
+				AllProjects: true,
+				RegionID:    reg.ID,
+			},
+		},
+	}
+	require.NoError(t, rbac.Insert(context.TODO(), db, &rb))
+
+	vcsServer := assets.InsertTestVCSProject(t, db, proj.ID, "github", "github")
+	repo := assets.InsertTestProjectRepository(t, db, proj.Key, vcsServer.ID, sdk.RandomString(10))
+
+	// Create reusable workflow
+	e := sdk.Entity{
+		ProjectKey:          proj.Key,
+		Type:                sdk.EntityTypeWorkflow,
+		FilePath:            ".cds/workflows/release.yml",
+		Name:                "release",
+		Commit:              "123456789",
+		Ref:                 "refs/heads/master",
+		ProjectRepositoryID: repo.ID,
+		UserID:              &admin.ID,
+		Data: `name: release
+on:
+  workflow-call:
+    inputs:
+      env:
+        type: string
+    outputs:
+      environment:
+        value: ${{ inputs.env }}
+jobs:
+  build:
+  deploy:
+    needs: [build]`,
+	}
+	require.NoError(t, entity.Insert(ctx, db, &e))
+
+	wr := sdk.V2WorkflowRun{
+		ProjectKey:   proj.Key,
+		VCSServerID:  vcsServer.ID,
+		VCSServer:    vcsServer.Name,
+		RepositoryID: repo.ID,
+		Repository:   repo.Name,
+		WorkflowName: sdk.RandomString(10),
+		WorkflowSha:  "123456789",
+		WorkflowRef:  "refs/heads/master",
+		RunAttempt:   1,
+		RunNumber:    1,
+		Started:      time.Now(),
+		LastModified: time.Now(),
+		Status:       sdk.V2WorkflowRunStatusBuilding,
+		RunEvent:     sdk.V2WorkflowRunEvent{},
+		WorkflowData: sdk.V2WorkflowRunData{Workflow: sdk.V2Workflow{
+			Name: "myworkflow",
+			Jobs: map[string]sdk.V2Job{
+				"release": {
+					Uses: ".cds/workflows/release.yml",
+					With: map[string]interface{}{
+						"env": "prod",
+					},
+				},
+				"notify": {
+					Needs: []string{"release"},
+				},
+			},
+		}},
+		Initiator: &sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+	}
+	require.NoError(t, workflow_v2.InsertRun(context.Background(), db, &wr))
+
+	trigger := func() {
+		require.NoError(t, api.workflowRunV2Trigger(context.Background(), sdk.V2WorkflowRunEnqueue{
+			RunID: wr.ID,
+			Initiator: sdk.V2Initiator{
+				UserID:         admin.ID,
+				User:           admin.Initiator(),
+				IsAdminWithMFA: true,
+			},
+		}))
+	}
+
+	// First trigger: jobs of the reusable workflow are added to the run
+	trigger()
+
+	runInfos, err := workflow_v2.LoadRunInfosByRunID(context.TODO(), db, wr.ID)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(runInfos))
+
+	wrAfter, err := workflow_v2.LoadRunByID(context.TODO(), db, wr.ID)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(wrAfter.WorkflowData.Workflow.Jobs)) // release / notify / release.build / release.deploy
+	require.Equal(t, "release", wrAfter.WorkflowData.Workflow.Jobs["release.build"].Group)
+	require.Equal(t, "prod", wrAfter.WorkflowData.Workflow.Jobs["release.build"].Inputs["env"])
+	require.Equal(t, "release", wrAfter.WorkflowData.Workflow.Jobs["release.deploy"].Group)
+	require.Equal(t, []string{"release.build"}, wrAfter.WorkflowData.Workflow.Jobs["release.deploy"].Needs)
+	require.Equal(t, []string{"release.deploy"}, wrAfter.WorkflowData.Workflow.Jobs["release"].Needs)
+	require.Equal(t, []string{"release"}, wrAfter.WorkflowData.Workflow.Jobs["notify"].Needs)
+
+	// build, then deploy, then release
+	trigger()
+	trigger()
+	trigger()
+
+	rjs, err := workflow_v2.LoadRunJobsByRunID(context.TODO(), db, wr.ID, wr.RunAttempt)
+	require.NoError(t, err)
+	var releaseRunJob *sdk.V2WorkflowRunJob
+	for i := range rjs {
+		t.Logf("RunJob: %s status: %s", rjs[i].JobID, rjs[i].Status)
+		if rjs[i].JobID == "release" {
+			releaseRunJob = &rjs[i]
+		}
+	}
+	require.NotNil(t, releaseRunJob)
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSuccess, releaseRunJob.Status)
+
+	// Outputs of the reusable workflow are available on the calling job
+	results, err := workflow_v2.LoadRunResultsByRunIDAttempt(context.TODO(), db, wr.ID, []string{releaseRunJob.ID}, wr.RunAttempt)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	output, err := sdk.GetConcreteDetail[*sdk.V2WorkflowRunResultVariableDetail](&results[0])
+	require.NoError(t, err)
+	require.Equal(t, "environment", output.Name)
+	require.Equal(t, "prod", output.Value)
+}
+
+func TestComputeReusableWorkflowStatus(t *testing.T) {
+	w := sdk.V2Workflow{
+		Jobs: map[string]sdk.V2Job{
+			"release":        {Uses: ".cds/workflows/release.yml"},
+			"release.build":  {Group: "release"},
+			"release.lint":   {Group: "release", ContinueOnError: true},
+			"release.deploy": {Group: "release"},
+			"other.build":    {Group: "other"},
+		},
+	}
+
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSuccess, computeReusableWorkflowStatus(w, "release", sdk.JobsResultContext{
+		"release.build":  {Result: sdk.V2WorkflowRunJobStatusSuccess},
+		"release.lint":   {Result: sdk.V2WorkflowRunJobStatusFail},
+		"release.deploy": {Result: sdk.V2WorkflowRunJobStatusSuccess},
+		"other.build":    {Result: sdk.V2WorkflowRunJobStatusFail},
+	}))
+	require.Equal(t, sdk.V2WorkflowRunJobStatusFail, computeReusableWorkflowStatus(w, "release", sdk.JobsResultContext{
+		"release.build":  {Result: sdk.V2WorkflowRunJobStatusFail},
+		"release.lint":   {Result: sdk.V2WorkflowRunJobStatusSuccess},
+		"release.deploy": {Result: sdk.V2WorkflowRunJobStatusSkipped},
+	}))
+	require.Equal(t, sdk.V2WorkflowRunJobStatusStopped, computeReusableWorkflowStatus(w, "release", sdk.JobsResultContext{
+		"release.build":  {Result: sdk.V2WorkflowRunJobStatusFail},
+		"release.lint":   {Result: sdk.V2WorkflowRunJobStatusSuccess},
+		"release.deploy": {Result: sdk.V2WorkflowRunJobStatusStopped},
+	}))
+}
@@ -351,6 +351,64 @@ func TestGenerateMatrix(t *testing.T) {
 	require.True(t, foo2bar2)
 }
 
+func TestComputeMatrixPermutations(t *testing.T) {
+	matrix := map[string]interface{}{
+		"os":   []interface{}{"linux", "windows"},
+		"arch": []string{"amd64", "arm64"},
+		"exclude": []interface{}{
+			map[string]interface{}{"os": "windows", "arch": "arm64"},
+		},
+		"include": []interface{}{
+			map[string]interface{}{"os": "linux", "tag": "latest"},
+			map[string]interface{}{"os": "darwin", "arch": "arm64"},
+		},
+	}
+	all, err := computeMatrixPermutations(matrix)
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	require.Contains(t, all, map[string]string{"os": "linux", "arch": "amd64", "tag": "latest"})
+	require.Contains(t, all, map[string]string{"os": "linux", "arch": "arm64", "tag": "latest"})
+	require.Contains(t, all, map[string]string{"os": "windows", "arch": "amd64"})
+	require.Contains(t, all, map[string]string{"os": "darwin", "arch": "arm64"})
+
+	_, err = computeMatrixPermutations(map[string]interface{}{"include": "linux"})
+	require.Error(t, err)
+}
+
+func TestGenerateMatrixPermutationFromNeedsOutputs(t *testing.T) {
+	run := &sdk.V2WorkflowRun{ID: sdk.UUID()}
+	jobDef := sdk.V2Job{
+		Strategy: &sdk.V2JobStrategy{
+			MatrixExpression: "${{ fromJSON(needs.discover.outputs.targets) }}",
+		},
+	}
+	jobContext := sdk.WorkflowRunJobsContext{
+		Needs: sdk.NeedsContext{
+			"discover": sdk.NeedContext{
+				Result: sdk.V2WorkflowRunJobStatusSuccess,
+				Outputs: sdk.JobResultOutput{
+					"targets": `{"service": ["api", "ui"], "include": [{"service": "ui", "node": 20}]}`,
+				},
+			},
+		},
+	}
+
+	all, info := generateMatrixPermutation(context.TODO(), jobContext, run, &jobDef, sdk.V2WorkflowRunJobStatusWaiting)
+	require.Nil(t, info)
+	require.Len(t, all, 2)
+	require.Contains(t, all, map[string]string{"service": "api"})
+	require.Contains(t, all, map[string]string{"service": "ui", "node": "20"})
+
+	// The resolved matrix is set on the job, not on the workflow definition
+	require.Empty(t, jobDef.Strategy.MatrixExpression)
+	require.Equal(t, []string{"api", "ui"}, jobDef.Strategy.Matrix["service"])
+
+	jobDef.Strategy = &sdk.V2JobStrategy{MatrixExpression: "${{ needs.discover.result }}"}
+	_, info = generateMatrixPermutation(context.TODO(), jobContext, run, &jobDef, sdk.V2WorkflowRunJobStatusWaiting)
+	require.NotNil(t, info)
+	require.Contains(t, info.Message, "must be computed as an object")
+}
+
 func TestWorkflowTrigger1Job(t *testing.T) {
 	api, db, _ := newTestAPI(t)
 
@@ -8,6 +8,7 @@ import (
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/event_v2"
+	"github.com/ovh/cds/engine/api/worker_v2"
 	"github.com/ovh/cds/engine/api/workflow_v2"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/sdk"
@@ -16,7 +17,11 @@ import (
 	"github.com/rockbears/log"
 )
 
-const jobLockKey = "jobs:lock"
+const (
+	jobLockKey = "jobs:lock"
+	// Delay let to the worker to send the result of a timed out job before the API stops it
+	jobTimeoutGraceDelay = 5 * time.Minute
+)
 
 func (api *API) CancelAbandonnedRunResults(ctx context.Context) {
 	tick := time.NewTicker(5 * time.Minute)
@@ -125,6 +130,31 @@ func (api *API) StopDeadJobs(ctx context.Context) {
 	}
 }
 
+func (api *API) StopTimedOutJobs(ctx context.Context) {
+	tickTimedOutJobs := time.NewTicker(1 * time.Minute)
+	defer tickTimedOutJobs.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "%v", ctx.Err())
+			}
+			return
+		case <-tickTimedOutJobs.C:
+			jobs, err := workflow_v2.LoadTimedOutRunJobs(ctx, api.mustDB(), sdk.V2JobDefaultTimeoutMinutes, jobTimeoutGraceDelay)
+			if err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+				continue
+			}
+			for i := range jobs {
+				if err := api.stopTimedOutJob(ctx, api.Cache, api.mustDB(), jobs[i].ID); err != nil {
+					log.ErrorWithStackTrace(ctx, err)
+				}
+			}
+		}
+	}
+}
+
 func (api *API) ReEnqueueScheduledJobs(ctx context.Context) {
 	tickScheduledJob := time.NewTicker(1 * time.Minute)
 	defer tickScheduledJob.Stop()
@@ -359,3 +389,94 @@ func (api *API) stopDeadJob(ctx context.Context, store cache.Store, db *gorp.DbM
 	api.manageEndConcurrency(runJob.ProjectKey, runJob.VCSServer, runJob.Repository, runJob.WorkflowName, runJob.WorkflowRunID, runJob.ID, runJob.Concurrency)
 	return nil
 }
+
+func (api *API) stopTimedOutJob(ctx context.Context, store cache.Store, db *gorp.DbMap, runJobID string) error {
+	ctx, next := telemetry.Span(ctx, "stopTimedOutJob")
+	defer next()
+
+	_, next = telemetry.Span(ctx, "stopTimedOutJob.lock")
+	lockKey := cache.Key(jobLockKey, runJobID)
+	b, err := store.Lock(lockKey, 1*time.Minute, 0, 1)
+	if err != nil {
+		next()
+		return err
+	}
+	if !b {
+		next()
+		return nil
+	}
+	next()
+	defer func() {
+		_ = store.Unlock(lockKey)
+	}()
+
+	runJob, err := workflow_v2.LoadRunJobByID(ctx, db, runJobID)
+	if err != nil {
+		return err
+	}
+	if runJob.Status != sdk.V2WorkflowRunJobStatusBuilding {
+		return nil
+	}
+
+	run, err := workflow_v2.LoadRunByID(ctx, db, runJob.WorkflowRunID)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, cdslog.WorkflowRunID, runJob.WorkflowRunID)
+	ctx = context.WithValue(ctx, cdslog.Workflow, runJob.WorkflowName)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint
+
+	log.Info(ctx, fmt.Sprintf("stopTimedOutJob: stopping job %s/%s (started %s ago) on workflow %s run %d", runJob.JobID, runJob.ID, time.Since(sdk.TimeSafe(runJob.Started)).String(), runJob.WorkflowName, runJob.RunNumber))
+	runJob.Status = sdk.V2WorkflowRunJobStatusFail
+
+	now := time.Now()
+	runJob.Ended = &now
+
+	if err := workflow_v2.UpdateJobRun(ctx, tx, runJob); err != nil {
+		return err
+	}
+
+	// Disable the worker, it will be killed by its hatchery
+	if runJob.WorkerID != "" {
+		wk, err := worker_v2.LoadByID(ctx, tx, runJob.WorkerID)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if wk != nil && wk.Status != sdk.StatusDisabled {
+			wk.Status = sdk.StatusDisabled
+			if err := worker_v2.Update(ctx, tx, wk); err != nil {
+				return err
+			}
+		}
+	}
+
+	info := sdk.V2WorkflowRunJobInfo{
+		Level:            sdk.WorkflowRunInfoLevelError,
+		WorkflowRunJobID: runJob.ID,
+		Message:          fmt.Sprintf("Job timed out after %s, worker %q has been disabled and will be stopped.", runJob.Job.GetTimeout(), runJob.WorkerName),
+		IssuedAt:         time.Now(),
+		WorkflowRunID:    runJob.WorkflowRunID,
+	}
+
+	if err := workflow_v2.InsertRunJobInfo(ctx, tx, &info); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	// Trigger workflow
+	event_v2.PublishRunJobEvent(ctx, api.Cache, sdk.EventRunJobEnded, *run, *runJob)
+	api.EnqueueWorkflowRun(ctx, runJob.WorkflowRunID, runJob.Initiator, runJob.WorkflowName, runJob.RunNumber)
+
+	// Trigger other workflow regarding concurrency
+	api.manageEndConcurrency(runJob.ProjectKey, runJob.VCSServer, runJob.Repository, runJob.WorkflowName, runJob.WorkflowRunID, runJob.ID, runJob.Concurrency)
+	return nil
+}
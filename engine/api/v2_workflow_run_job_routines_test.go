@@ -191,6 +191,95 @@ func TestStopDeadJobs(t *testing.T) {
 	require.Equal(t, sdk.V2WorkflowRunJobStatusStopped, rjDB.Status)
 }
 
+func TestStopTimedOutJobs(t *testing.T) {
+	ctx := context.TODO()
+	api, db, _ := newTestAPI(t)
+
+	db.Exec("DELETE FROM v2_worker")
+	db.Exec("DELETE FROM v2_workflow_run_job")
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	vcsServer := assets.InsertTestVCSProject(t, db, proj.ID, "github", "github")
+	repo := assets.InsertTestProjectRepository(t, db, proj.Key, vcsServer.ID, sdk.RandomString(10))
+	wr := sdk.V2WorkflowRun{
+		ProjectKey:   proj.Key,
+		VCSServerID:  vcsServer.ID,
+		VCSServer:    vcsServer.Name,
+		RepositoryID: repo.ID,
+		Repository:   repo.Name,
+		WorkflowName: sdk.RandomString(10),
+		WorkflowSha:  "123",
+		WorkflowRef:  "master",
+		RunAttempt:   0,
+		RunNumber:    1,
+		Started:      time.Now(),
+		LastModified: time.Now(),
+		Status:       sdk.V2WorkflowRunStatusBuilding,
+		Initiator: &sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+		RunEvent: sdk.V2WorkflowRunEvent{},
+		WorkflowData: sdk.V2WorkflowRunData{Workflow: sdk.V2Workflow{
+			Jobs: map[string]sdk.V2Job{
+				"job1": {},
+				"job2": {},
+			},
+		}},
+	}
+	require.NoError(t, workflow_v2.InsertRun(context.Background(), db, &wr))
+
+	nowMinus20Min := time.Now().Add(-20 * time.Minute)
+
+	// Job with a 10 minutes timeout started 20 minutes ago
+	wrj := sdk.V2WorkflowRunJob{
+		Job:           sdk.V2Job{TimeoutMinutes: 10},
+		WorkflowRunID: wr.ID,
+		Initiator: sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+		ProjectKey:     wr.ProjectKey,
+		JobID:          "job1",
+		Status:         sdk.V2WorkflowRunJobStatusBuilding,
+		Started:        &nowMinus20Min,
+		TimeoutMinutes: 10,
+	}
+	require.NoError(t, workflow_v2.InsertRunJob(context.TODO(), db, &wrj))
+
+	// Job with the default timeout started 20 minutes ago
+	wrj2 := sdk.V2WorkflowRunJob{
+		Job:           sdk.V2Job{},
+		WorkflowRunID: wr.ID,
+		Initiator: sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+		ProjectKey: wr.ProjectKey,
+		JobID:      "job2",
+		Status:     sdk.V2WorkflowRunJobStatusBuilding,
+		Started:    &nowMinus20Min,
+	}
+	require.NoError(t, workflow_v2.InsertRunJob(context.TODO(), db, &wrj2))
+
+	jobs, err := workflow_v2.LoadTimedOutRunJobs(ctx, db, sdk.V2JobDefaultTimeoutMinutes, jobTimeoutGraceDelay)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobs))
+	require.Equal(t, wrj.ID, jobs[0].ID)
+
+	require.NoError(t, api.stopTimedOutJob(ctx, api.Cache, db.DbMap, wrj.ID))
+
+	rjDB, err := workflow_v2.LoadRunJobByID(ctx, db, wrj.ID)
+	require.NoError(t, err)
+	require.Equal(t, sdk.V2WorkflowRunJobStatusFail, rjDB.Status)
+
+	infos, err := workflow_v2.LoadRunJobInfosByRunJobID(ctx, db, wrj.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(infos))
+	require.Contains(t, infos[0].Message, "timed out")
+}
+
 func TestStopWaitingJobs(t *testing.T) {
 	ctx := context.TODO()
 	api, db, _ := newTestAPI(t)
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow_v2"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+)
+
+// postWorkflowRunResultShareHandler creates a signed and expiring public download link of a run result stored on CDN
+func (api *API) postWorkflowRunResultShareHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.workflowTrigger),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+			workflowRunID := vars["workflowRunID"]
+			resultID := vars["resultID"]
+
+			u := getUserConsumer(ctx)
+			if u == nil {
+				return sdk.WithStack(sdk.ErrForbidden)
+			}
+
+			var shareRequest sdk.V2WorkflowRunResultShareRequest
+			if err := service.UnmarshalBody(req, &shareRequest); err != nil {
+				return err
+			}
+			duration, err := shareRequest.Duration()
+			if err != nil {
+				return err
+			}
+
+			proj, err := project.Load(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+			wr, err := workflow_v2.LoadRunByProjectKeyAndID(ctx, api.mustDB(), proj.Key, workflowRunID)
+			if err != nil {
+				return err
+			}
+			result, err := workflow_v2.LoadRunResult(ctx, api.mustDB(), wr.ID, resultID)
+			if err != nil {
+				return err
+			}
+			apiRefHash := result.ArtifactManagerMetadata.Get("cdn_api_ref_hash")
+			if apiRefHash == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "run result %s is not stored on CDN", result.Name())
+			}
+
+			httpURL, err := services.GetCDNPublicHTTPAdress(ctx, api.mustDB())
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			share := sdk.V2WorkflowRunResultShare{
+				ProjectKey:    proj.Key,
+				WorkflowRunID: wr.ID,
+				RunResultID:   result.ID,
+				CreatedBy:     u.GetUsername(),
+				Expires:       now.Add(duration),
+				SingleUse:     shareRequest.SingleUse,
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			if err := workflow_v2.InsertRunResultShare(ctx, tx, &share); err != nil {
+				return err
+			}
+
+			signature := cdn.Signature{
+				ProjectKey:    proj.Key,
+				WorkflowName:  wr.WorkflowName,
+				WorkflowRunID: wr.ID,
+				RunNumber:     wr.RunNumber,
+				Timestamp:     now.UnixNano(),
+				RunResultShare: &cdn.SignatureRunResultShare{
+					ShareID:     share.ID,
+					RunResultID: result.ID,
+					APIRefHash:  apiRefHash,
+				},
+			}
+			token, err := authentication.SignJWS(signature, now, duration)
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+
+			share.URL = httpURL + "/share/run-result/" + token
+			return service.WriteJSON(w, share, http.StatusOK)
+		}
+}
+
+func (api *API) getWorkflowRunResultSharesHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.projectRead),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+			workflowRunID := vars["workflowRunID"]
+			resultID := vars["resultID"]
+
+			proj, err := project.Load(ctx, api.mustDB(), pKey)
+			if err != nil {
+				return err
+			}
+			wr, err := workflow_v2.LoadRunByProjectKeyAndID(ctx, api.mustDB(), proj.Key, workflowRunID)
+			if err != nil {
+				return err
+			}
+			result, err := workflow_v2.LoadRunResult(ctx, api.mustDB(), wr.ID, resultID)
+			if err != nil {
+				return err
+			}
+
+			shares, err := workflow_v2.LoadRunResultSharesByRunResultID(ctx, api.mustDB(), result.ID)
+			if err != nil {
+				return err
+			}
+			for i := range shares {
+				shares[i].Downloads, err = workflow_v2.LoadRunResultShareDownloads(ctx, api.mustDB(), shares[i].ID)
+				if err != nil {
+					return err
+				}
+			}
+			return service.WriteJSON(w, shares, http.StatusOK)
+		}
+}
+
+// postProjectCDNRunResultShareDownloadHandler audits a download from a public link, and refuses it if the link can no more be used.
+// The audit is returned to the CDN that reports it as failed if the file could not be sent.
+func (api *API) postProjectCDNRunResultShareDownloadHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.isCDNService),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+			shareID := vars["shareID"]
+
+			var download sdk.V2WorkflowRunResultShareDownload
+			if err := service.UnmarshalBody(req, &download); err != nil {
+				return err
+			}
+
+			share, err := workflow_v2.LoadRunResultShareByID(ctx, api.mustDB(), pKey, shareID)
+			if err != nil {
+				return err
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			consumed, err := workflow_v2.ConsumeRunResultShare(ctx, tx, share.ID)
+			if err != nil {
+				return err
+			}
+			if !consumed {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "download link is expired or has already been used")
+			}
+
+			download.ShareID = share.ID
+			if err := workflow_v2.InsertRunResultShareDownload(ctx, tx, &download); err != nil {
+				return err
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return service.WriteJSON(w, download, http.StatusOK)
+		}
+}
+
+// postProjectCDNRunResultShareDownloadFailedHandler is called by the CDN when a file could not be sent from a public link,
+// the download is kept in the audit but is no more counted.
+func (api *API) postProjectCDNRunResultShareDownloadFailedHandler() ([]service.RbacChecker, service.Handler) {
+	return service.RBAC(api.isCDNService),
+		func(ctx context.Context, w http.ResponseWriter, req *http.Request) error {
+			vars := mux.Vars(req)
+			pKey := vars["projectKey"]
+			shareID := vars["shareID"]
+			downloadID := vars["downloadID"]
+
+			share, err := workflow_v2.LoadRunResultShareByID(ctx, api.mustDB(), pKey, shareID)
+			if err != nil {
+				return err
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			if err := workflow_v2.FailRunResultShareDownload(ctx, tx, share.ID, downloadID); err != nil {
+				return err
+			}
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+			return service.WriteJSON(w, nil, http.StatusNoContent)
+		}
+}
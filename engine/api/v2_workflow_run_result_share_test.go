@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow_v2"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func insertRunResultShareTestRun(t *testing.T, api *API, db gorpmapper.SqlExecutorWithTx, admin *sdk.AuthentifiedUser) (*sdk.V2WorkflowRun, *sdk.V2WorkflowRunResult) {
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	vcsServer := assets.InsertTestVCSProject(t, db, proj.ID, "github", "github")
+	repo := assets.InsertTestProjectRepository(t, db, proj.Key, vcsServer.ID, sdk.RandomString(10))
+
+	wr := sdk.V2WorkflowRun{
+		ProjectKey:   proj.Key,
+		VCSServerID:  vcsServer.ID,
+		VCSServer:    vcsServer.Name,
+		RepositoryID: repo.ID,
+		Repository:   repo.Name,
+		WorkflowName: sdk.RandomString(10),
+		WorkflowSha:  "123",
+		WorkflowRef:  "master",
+		RunAttempt:   0,
+		RunNumber:    1,
+		Started:      time.Now(),
+		LastModified: time.Now(),
+		Status:       sdk.V2WorkflowRunStatusSuccess,
+		Initiator: &sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+		RunEvent:     sdk.V2WorkflowRunEvent{},
+		WorkflowData: sdk.V2WorkflowRunData{Workflow: sdk.V2Workflow{}},
+	}
+	require.NoError(t, workflow_v2.InsertRun(context.TODO(), db, &wr))
+
+	rj := sdk.V2WorkflowRunJob{
+		Status:        sdk.V2WorkflowRunJobStatusSuccess,
+		WorkflowRunID: wr.ID,
+		ProjectKey:    proj.Key,
+		JobID:         "job1",
+		RunAttempt:    wr.RunAttempt,
+		Initiator:     *wr.Initiator,
+	}
+	require.NoError(t, workflow_v2.InsertRunJob(context.TODO(), db, &rj))
+
+	rr := sdk.V2WorkflowRunResult{
+		ID:                      sdk.UUID(),
+		WorkflowRunJobID:        rj.ID,
+		WorkflowRunID:           wr.ID,
+		IssuedAt:                time.Now(),
+		Status:                  sdk.V2WorkflowRunResultStatusCompleted,
+		Type:                    sdk.V2WorkflowRunResultTypeGeneric,
+		RunAttempt:              wr.RunAttempt,
+		ArtifactManagerMetadata: &sdk.V2WorkflowRunResultArtifactManagerMetadata{"cdn_api_ref_hash": sdk.RandomString(10)},
+		Detail: sdk.V2WorkflowRunResultDetail{
+			Type: "V2WorkflowRunResultGenericDetail",
+			Data: sdk.V2WorkflowRunResultGenericDetail{
+				Name: "foo.txt",
+			},
+		},
+	}
+	require.NoError(t, workflow_v2.InsertRunResult(context.TODO(), db, &rr))
+
+	return &wr, &rr
+}
+
+func TestPostWorkflowRunResultShareHandler(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, pwd := assets.InsertAdminUser(t, db)
+	wr, rr := insertRunResultShareTestRun(t, api, db, admin)
+
+	_, _, _ = assets.InitCDNService(t, db)
+
+	uri := api.Router.GetRouteV2(http.MethodPost, api.postWorkflowRunResultShareHandler, map[string]string{
+		"projectKey":    wr.ProjectKey,
+		"workflowRunID": wr.ID,
+		"resultID":      rr.ID,
+	})
+	test.NotEmpty(t, uri)
+
+	// Invalid ttl
+	req := assets.NewAuthentifiedRequest(t, admin, pwd, http.MethodPost, uri, sdk.V2WorkflowRunResultShareRequest{TTL: "1000h"})
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+
+	req = assets.NewAuthentifiedRequest(t, admin, pwd, http.MethodPost, uri, sdk.V2WorkflowRunResultShareRequest{TTL: "1h", SingleUse: true})
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	var share sdk.V2WorkflowRunResultShare
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &share))
+	require.True(t, strings.HasPrefix(share.URL, "http://cdn.net:8080/share/run-result/"))
+	require.True(t, share.SingleUse)
+	require.Equal(t, admin.Username, share.CreatedBy)
+	require.WithinDuration(t, time.Now().Add(time.Hour), share.Expires, time.Minute)
+
+	shareDB, err := workflow_v2.LoadRunResultShareByID(context.TODO(), db, wr.ProjectKey, share.ID)
+	require.NoError(t, err)
+	require.Equal(t, rr.ID, shareDB.RunResultID)
+	require.Equal(t, int64(0), shareDB.DownloadCount)
+}
+
+func TestPostWorkflowRunResultShareHandler_NotOnCDN(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, pwd := assets.InsertAdminUser(t, db)
+	wr, rr := insertRunResultShareTestRun(t, api, db, admin)
+	rr.ArtifactManagerMetadata = &sdk.V2WorkflowRunResultArtifactManagerMetadata{}
+	require.NoError(t, workflow_v2.UpdateRunResult(context.TODO(), db, rr))
+
+	uri := api.Router.GetRouteV2(http.MethodPost, api.postWorkflowRunResultShareHandler, map[string]string{
+		"projectKey":    wr.ProjectKey,
+		"workflowRunID": wr.ID,
+		"resultID":      rr.ID,
+	})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, admin, pwd, http.MethodPost, uri, sdk.V2WorkflowRunResultShareRequest{})
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 400, w.Code)
+}
+
+func TestPostProjectCDNRunResultShareDownloadHandler_SingleUse(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	wr, rr := insertRunResultShareTestRun(t, api, db, admin)
+	_, _, jwtCDN := assets.InitCDNService(t, db)
+
+	share := sdk.V2WorkflowRunResultShare{
+		ProjectKey:    wr.ProjectKey,
+		WorkflowRunID: wr.ID,
+		RunResultID:   rr.ID,
+		CreatedBy:     admin.Username,
+		Expires:       time.Now().Add(time.Hour),
+		SingleUse:     true,
+	}
+	require.NoError(t, workflow_v2.InsertRunResultShare(context.TODO(), db, &share))
+
+	uri := api.Router.GetRouteV2(http.MethodPost, api.postProjectCDNRunResultShareDownloadHandler, map[string]string{
+		"projectKey": wr.ProjectKey,
+		"shareID":    share.ID,
+	})
+	test.NotEmpty(t, uri)
+
+	download := sdk.V2WorkflowRunResultShareDownload{
+		RemoteAddr: "127.0.0.1",
+		UserAgent:  "curl/8.0",
+	}
+	req := assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uri, download)
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	var firstDownload sdk.V2WorkflowRunResultShareDownload
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &firstDownload))
+	require.NotEmpty(t, firstDownload.ID)
+	require.Equal(t, share.ID, firstDownload.ShareID)
+
+	// The link was used once, it is refused
+	req = assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uri, download)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 403, w.Code)
+
+	shareDB, err := workflow_v2.LoadRunResultShareByID(context.TODO(), db, wr.ProjectKey, share.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), shareDB.DownloadCount)
+	require.True(t, shareDB.IsExpired())
+
+	downloads, err := workflow_v2.LoadRunResultShareDownloads(context.TODO(), db, share.ID)
+	require.NoError(t, err)
+	require.Len(t, downloads, 1)
+	require.Equal(t, "127.0.0.1", downloads[0].RemoteAddr)
+	require.Equal(t, "curl/8.0", downloads[0].UserAgent)
+	require.False(t, downloads[0].Failed)
+
+	// The file could not be sent, the link can be used again
+	uriFailed := api.Router.GetRouteV2(http.MethodPost, api.postProjectCDNRunResultShareDownloadFailedHandler, map[string]string{
+		"projectKey": wr.ProjectKey,
+		"shareID":    share.ID,
+		"downloadID": firstDownload.ID,
+	})
+	test.NotEmpty(t, uriFailed)
+	req = assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uriFailed, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 204, w.Code)
+
+	// A download can only be reported as failed once
+	req = assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uriFailed, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 404, w.Code)
+
+	req = assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uri, download)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	downloads, err = workflow_v2.LoadRunResultShareDownloads(context.TODO(), db, share.ID)
+	require.NoError(t, err)
+	require.Len(t, downloads, 2)
+	var failed int
+	for _, d := range downloads {
+		if d.Failed {
+			require.Equal(t, firstDownload.ID, d.ID)
+			failed++
+		}
+	}
+	require.Equal(t, 1, failed)
+}
+
+func TestPostProjectCDNRunResultShareDownloadHandler_Expired(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, pwd := assets.InsertAdminUser(t, db)
+	wr, rr := insertRunResultShareTestRun(t, api, db, admin)
+	_, _, jwtCDN := assets.InitCDNService(t, db)
+
+	share := sdk.V2WorkflowRunResultShare{
+		ProjectKey:    wr.ProjectKey,
+		WorkflowRunID: wr.ID,
+		RunResultID:   rr.ID,
+		CreatedBy:     admin.Username,
+		Expires:       time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, workflow_v2.InsertRunResultShare(context.TODO(), db, &share))
+
+	uri := api.Router.GetRouteV2(http.MethodPost, api.postProjectCDNRunResultShareDownloadHandler, map[string]string{
+		"projectKey": wr.ProjectKey,
+		"shareID":    share.ID,
+	})
+	test.NotEmpty(t, uri)
+
+	// Only the CDN can audit a download
+	req := assets.NewAuthentifiedRequest(t, admin, pwd, http.MethodPost, uri, sdk.V2WorkflowRunResultShareDownload{})
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 403, w.Code)
+
+	req = assets.NewJWTAuthentifiedRequest(t, jwtCDN, http.MethodPost, uri, sdk.V2WorkflowRunResultShareDownload{RemoteAddr: "127.0.0.1"})
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 403, w.Code)
+
+	shareDB, err := workflow_v2.LoadRunResultShareByID(context.TODO(), db, wr.ProjectKey, share.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), shareDB.DownloadCount)
+
+	downloads, err := workflow_v2.LoadRunResultShareDownloads(context.TODO(), db, share.ID)
+	require.NoError(t, err)
+	require.Empty(t, downloads)
+}
@@ -1137,6 +1137,105 @@ func TestPutWorkflowRun(t *testing.T) {
 	require.Equal(t, int64(2), rJob3.RunAttempt)
 }
 
+func TestPostRestartWorkflowRun_JobAndDependents(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, pwd := assets.InsertAdminUser(t, db)
+	proj := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	vcsServer := assets.InsertTestVCSProject(t, db, proj.ID, "github", "github")
+	repo := assets.InsertTestProjectRepository(t, db, proj.Key, vcsServer.ID, sdk.RandomString(10))
+
+	wr := sdk.V2WorkflowRun{
+		ProjectKey:   proj.Key,
+		VCSServerID:  vcsServer.ID,
+		VCSServer:    vcsServer.Name,
+		RepositoryID: repo.ID,
+		Repository:   repo.Name,
+		WorkflowName: sdk.RandomString(10),
+		WorkflowSha:  "123",
+		WorkflowRef:  "master",
+		RunAttempt:   0,
+		RunNumber:    1,
+		Started:      time.Now(),
+		LastModified: time.Now(),
+		Status:       sdk.V2WorkflowRunStatusSuccess,
+		Initiator: &sdk.V2Initiator{
+			UserID: admin.ID,
+			User:   admin.Initiator(),
+		},
+		RunEvent: sdk.V2WorkflowRunEvent{},
+		WorkflowData: sdk.V2WorkflowRunData{Workflow: sdk.V2Workflow{
+			Jobs: map[string]sdk.V2Job{
+				"job1": {},
+				"job2": {
+					Needs: []string{"job1"},
+				},
+				"job3": {
+					Needs: []string{"job2"},
+				},
+				"job4": {
+					Needs: []string{"job1"},
+				},
+			},
+		}},
+	}
+	require.NoError(t, workflow_v2.InsertRun(context.Background(), db, &wr))
+
+	for _, jobID := range []string{"job1", "job2", "job2", "job3", "job4"} {
+		rj := sdk.V2WorkflowRunJob{
+			Status:        sdk.V2WorkflowRunJobStatusSuccess,
+			WorkflowRunID: wr.ID,
+			ProjectKey:    proj.Key,
+			JobID:         jobID,
+			RunAttempt:    wr.RunAttempt,
+			Initiator:     *wr.Initiator,
+		}
+		if jobID == "job2" {
+			// job2 runs with a matrix, all its variants must be restarted
+			rj.Matrix = sdk.JobMatrix{"os": sdk.RandomString(5)}
+		}
+		require.NoError(t, workflow_v2.InsertRunJob(context.TODO(), db, &rj))
+	}
+
+	// Mock CDN
+	s, _ := assets.InsertService(t, db, t.Name()+"_CDN", sdk.TypeCDN)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	servicesClients := mock_services.NewMockClient(ctrl)
+	services.NewClient = func(_ []sdk.Service) services.Client {
+		return servicesClients
+	}
+	defer func() {
+		_ = services.Delete(db, s)
+		services.NewClient = services.NewDefaultClient
+	}()
+
+	servicesClients.EXPECT().
+		DoJSONRequest(gomock.Any(), "POST", "/item/duplicate", gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+
+	uri := api.Router.GetRouteV2(http.MethodPost, api.postRestartWorkflowRunHandler, map[string]string{
+		"projectKey":    proj.Key,
+		"workflowRunID": wr.ID,
+	})
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, admin, pwd, http.MethodPost, uri+"?job=job2", nil)
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	wrDB, err := workflow_v2.LoadRunByID(context.TODO(), db, wr.ID)
+	require.NoError(t, err)
+	require.Equal(t, sdk.V2WorkflowRunStatusBuilding, wrDB.Status)
+	require.Equal(t, int64(1), wrDB.RunAttempt)
+
+	// All the variants of job2 and job3 are restarted, job1 and job4 are kept
+	runjobs, err := workflow_v2.LoadRunJobsByRunID(context.TODO(), db, wrDB.ID, wrDB.RunAttempt)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(runjobs))
+	keptJobs := []string{runjobs[0].JobID, runjobs[1].JobID}
+	require.ElementsMatch(t, []string{"job1", "job4"}, keptJobs)
+}
+
 func TestPostRestartWorkflowRun_BuildingRun(t *testing.T) {
 	api, db, _ := newTestAPI(t)
 
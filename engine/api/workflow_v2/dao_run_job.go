@@ -195,11 +195,33 @@ func LoadRunJobsByJobID(ctx context.Context, db gorp.SqlExecutor, wrID string, j
 func LoadQueuedRunJobByModelTypeAndRegionAndModelOSArch(ctx context.Context, db gorp.SqlExecutor, regionName string, modelType string, modelOSArch []string) ([]sdk.V2WorkflowRunJob, error) {
 	ctx, next := telemetry.Span(ctx, "workflow_v2.LoadQueuedRunJobByModelTypeAndRegion")
 	defer next()
-	query := gorpmapping.NewQuery("SELECT * from v2_workflow_run_job WHERE status = $1 AND model_type = $2 and region = $3 and model_osarch = ANY($4) ORDER BY queued").
+	query := gorpmapping.NewQuery("SELECT * from v2_workflow_run_job WHERE status = $1 AND model_type = $2 and region = $3 and model_osarch = ANY($4) ORDER BY priority DESC, queued").
 		Args(sdk.StatusWaiting, modelType, regionName, pq.StringArray(modelOSArch))
 	return getAllRunJobs(ctx, db, query)
 }
 
+// CountBuildingRunJobsByRegionGroupByProject returns the number of scheduled and building jobs of each project on the given region
+func CountBuildingRunJobsByRegionGroupByProject(ctx context.Context, db gorp.SqlExecutor, regionName string) (map[string]int64, error) {
+	ctx, next := telemetry.Span(ctx, "workflow_v2.CountBuildingRunJobsByRegionGroupByProject")
+	defer next()
+	var res []struct {
+		ProjectKey string `db:"project_key"`
+		Count      int64  `db:"count"`
+	}
+	if _, err := db.Select(&res, `
+    SELECT project_key, count(id) AS count
+    FROM v2_workflow_run_job
+    WHERE region = $1 AND status = ANY($2)
+    GROUP BY project_key`, regionName, pq.StringArray([]string{sdk.StatusScheduling, sdk.StatusBuilding})); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	counts := make(map[string]int64, len(res))
+	for _, r := range res {
+		counts[r.ProjectKey] = r.Count
+	}
+	return counts, nil
+}
+
 func LoadRunJobsByRunIDAndStatus(ctx context.Context, db gorp.SqlExecutor, runID string, status []string, runAttempt int64) ([]sdk.V2WorkflowRunJob, error) {
 	ctx, next := telemetry.Span(ctx, "workflow_v2.LoadRunJobsByRunIDAndStatus")
 	defer next()
@@ -256,6 +278,22 @@ func LoadDeadJobs(ctx context.Context, db gorp.SqlExecutor) ([]sdk.V2WorkflowRun
 	return getAllRunJobs(ctx, db, query)
 }
 
+// LoadTimedOutRunJobs returns building jobs that exceed their timeout, plus a grace delay let to the worker to send its result
+func LoadTimedOutRunJobs(ctx context.Context, db gorp.SqlExecutor, defaultTimeoutMinutes int64, graceDelay time.Duration) ([]sdk.V2WorkflowRunJob, error) {
+	ctx, next := telemetry.Span(ctx, "workflow_v2.LoadTimedOutRunJobs")
+	defer next()
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM v2_workflow_run_job
+    WHERE status = $1
+      AND started IS NOT NULL
+      AND now() - started > (CASE WHEN timeout_minutes > 0 THEN timeout_minutes ELSE $2 END) * INTERVAL '1' MINUTE + $3 * INTERVAL '1' SECOND
+    ORDER BY started
+    LIMIT 100
+  `).Args(sdk.StatusBuilding, defaultTimeoutMinutes, int64(graceDelay.Seconds()))
+	return getAllRunJobs(ctx, db, query)
+}
+
 func CountRunJobsByProjectStatusAndRegions(ctx context.Context, db gorp.SqlExecutor, pkeys []string, statusFilter []sdk.V2WorkflowRunJobStatus, regionsFilter []string) (int64, error) {
 	var statusStrings []string
 	for _, v := range statusFilter {
@@ -289,7 +327,7 @@ func LoadRunJobsByProjectStatusAndRegions(ctx context.Context, db gorp.SqlExecut
 		  v2_workflow_run_job.status = ANY($2)
 			AND
 		  (array_length($3::text[], 1) IS NULL OR v2_workflow_run_job.region = ANY($3))
-		ORDER BY queued ASC
+		ORDER BY priority DESC, queued ASC
 		OFFSET $4 LIMIT $5
   `).Args(pq.StringArray(pkeys), pq.StringArray(statusStrings), pq.StringArray(regionsFilter), offset, limit)
 	return getAllRunJobs(ctx, db, query)
@@ -0,0 +1,115 @@
+package workflow_v2
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func InsertRunResultShare(ctx context.Context, db gorp.SqlExecutor, share *sdk.V2WorkflowRunResultShare) error {
+	share.ID = sdk.UUID()
+	share.Created = time.Now()
+	entity := dbV2WorkflowRunResultShare{*share}
+	if err := gorpmapping.Insert(db, &entity); err != nil {
+		return err
+	}
+	*share = entity.V2WorkflowRunResultShare
+	return nil
+}
+
+func LoadRunResultShareByID(ctx context.Context, db gorp.SqlExecutor, projectKey, id string) (*sdk.V2WorkflowRunResultShare, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM v2_workflow_run_result_share WHERE project_key = $1 AND id = $2`).Args(projectKey, id)
+	var res dbV2WorkflowRunResultShare
+	found, err := gorpmapping.Get(ctx, db, query, &res)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &res.V2WorkflowRunResultShare, nil
+}
+
+func LoadRunResultSharesByRunResultID(ctx context.Context, db gorp.SqlExecutor, runResultID string) ([]sdk.V2WorkflowRunResultShare, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM v2_workflow_run_result_share WHERE run_result_id = $1 ORDER BY created DESC`).Args(runResultID)
+	var dbShares []dbV2WorkflowRunResultShare
+	if err := gorpmapping.GetAll(ctx, db, query, &dbShares); err != nil {
+		return nil, err
+	}
+	shares := make([]sdk.V2WorkflowRunResultShare, 0, len(dbShares))
+	for i := range dbShares {
+		shares = append(shares, dbShares[i].V2WorkflowRunResultShare)
+	}
+	return shares, nil
+}
+
+// ConsumeRunResultShare counts a download on the given link.
+// It returns false if the link is expired or if it is a single use link that was already used.
+func ConsumeRunResultShare(ctx context.Context, db gorp.SqlExecutor, id string) (bool, error) {
+	res, err := db.Exec(`
+		UPDATE v2_workflow_run_result_share
+		SET download_count = download_count + 1
+		WHERE id = $1 AND expires > now() AND (NOT single_use OR download_count = 0)`, id)
+	if err != nil {
+		return false, sdk.WithStack(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, sdk.WithStack(err)
+	}
+	return n == 1, nil
+}
+
+// FailRunResultShareDownload marks a download as failed and gives the download back to the link,
+// so that a single use link can be used again when the file was not sent.
+func FailRunResultShareDownload(ctx context.Context, db gorp.SqlExecutor, shareID, downloadID string) error {
+	res, err := db.Exec(`
+		UPDATE v2_workflow_run_result_share_download
+		SET failed = true
+		WHERE id = $1 AND share_id = $2 AND NOT failed`, downloadID, shareID)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if n != 1 {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	if _, err := db.Exec(`
+		UPDATE v2_workflow_run_result_share
+		SET download_count = download_count - 1
+		WHERE id = $1 AND download_count > 0`, shareID); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
+
+func InsertRunResultShareDownload(ctx context.Context, db gorp.SqlExecutor, download *sdk.V2WorkflowRunResultShareDownload) error {
+	download.ID = sdk.UUID()
+	download.Downloaded = time.Now()
+	entity := dbV2WorkflowRunResultShareDownload{*download}
+	if err := gorpmapping.Insert(db, &entity); err != nil {
+		return err
+	}
+	*download = entity.V2WorkflowRunResultShareDownload
+	return nil
+}
+
+func LoadRunResultShareDownloads(ctx context.Context, db gorp.SqlExecutor, shareID string) ([]sdk.V2WorkflowRunResultShareDownload, error) {
+	query := gorpmapping.NewQuery(`SELECT * FROM v2_workflow_run_result_share_download WHERE share_id = $1 ORDER BY downloaded DESC`).Args(shareID)
+	var dbDownloads []dbV2WorkflowRunResultShareDownload
+	if err := gorpmapping.GetAll(ctx, db, query, &dbDownloads); err != nil {
+		return nil, err
+	}
+	downloads := make([]sdk.V2WorkflowRunResultShareDownload, 0, len(dbDownloads))
+	for i := range dbDownloads {
+		downloads = append(downloads, dbDownloads[i].V2WorkflowRunResultShareDownload)
+	}
+	return downloads, nil
+}
@@ -223,6 +223,13 @@ func LoadAllSchedulerHooks(ctx context.Context, db gorp.SqlExecutor) ([]sdk.V2Wo
 	return getAllHooks(ctx, db, q)
 }
 
+// LoadAllMessageBusHooks returns the kafka and rabbitMQ hooks of the workflows on their default branch.
+func LoadAllMessageBusHooks(ctx context.Context, db gorp.SqlExecutor) ([]sdk.V2WorkflowHook, error) {
+	q := gorpmapping.NewQuery(`SELECT * FROM v2_workflow_hook WHERE type = ANY($1) AND head = true`).
+		Args(pq.StringArray([]string{sdk.WorkflowHookTypeKafka, sdk.WorkflowHookTypeRabbitMQ}))
+	return getAllHooks(ctx, db, q)
+}
+
 func LoadDistinctSchedulerWorkflowKeysByProjectKey(ctx context.Context, db gorp.SqlExecutor, projectKey string) ([]sdk.V2WorkflowHookShort, error) {
 	var rows []struct {
 		VCSName        string `db:"vcs_name"`
@@ -58,6 +58,14 @@ type dbV2WorkflowRunResult struct {
 	sdk.V2WorkflowRunResult
 }
 
+type dbV2WorkflowRunResultShare struct {
+	sdk.V2WorkflowRunResultShare
+}
+
+type dbV2WorkflowRunResultShareDownload struct {
+	sdk.V2WorkflowRunResultShareDownload
+}
+
 type dbV2WorkflowVersion struct {
 	sdk.V2WorkflowVersion
 }
@@ -70,4 +78,6 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbWorkflowHook{}, "v2_workflow_hook", false, "id"))
 	gorpmapping.Register(gorpmapping.New(dbV2WorkflowRunResult{}, "v2_workflow_run_result", false, "id"))
 	gorpmapping.Register(gorpmapping.New(dbV2WorkflowVersion{}, "v2_workflow_version", false, "id"))
+	gorpmapping.Register(gorpmapping.New(dbV2WorkflowRunResultShare{}, "v2_workflow_run_result_share", false, "id"))
+	gorpmapping.Register(gorpmapping.New(dbV2WorkflowRunResultShareDownload{}, "v2_workflow_run_result_share_download", false, "id"))
 }
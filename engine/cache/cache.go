@@ -30,6 +30,7 @@ type Store interface {
 	SetWithTTL(key string, value interface{}, ttl int) error
 	SetWithDuration(key string, value interface{}, duration time.Duration) error
 	UpdateTTL(key string, ttl int) error
+	IncrByWithTTL(key string, value int64, ttl int) (int64, error)
 	Delete(key string) error
 	DeleteAll(key string) error
 	Exist(key string) (bool, error)
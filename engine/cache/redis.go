@@ -183,6 +183,23 @@ func (s *RedisStore) UpdateTTL(key string, ttl int) error {
 	return nil
 }
 
+// IncrByWithTTL atomically increments the integer value of a key and updates its ttl, it returns the new value
+func (s *RedisStore) IncrByWithTTL(key string, value int64, ttl int) (int64, error) {
+	if s.Client == nil {
+		return 0, sdk.WithStack(fmt.Errorf("redis> cannot get redis client"))
+	}
+
+	var incr *redis.IntCmd
+	if _, err := s.Client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(context.Background(), key, value)
+		pipe.Expire(context.Background(), key, time.Duration(ttl)*time.Second)
+		return nil
+	}); err != nil {
+		return 0, sdk.WrapError(err, "redis>IncrByWithTTL> incr error %s", key)
+	}
+	return incr.Val(), nil
+}
+
 // Set a value in redis
 func (s *RedisStore) Set(key string, value interface{}) error {
 	return s.SetWithTTL(key, value, s.ttl)
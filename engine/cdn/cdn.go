@@ -13,7 +13,10 @@ import (
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/cdn/item"
 	"github.com/ovh/cds/engine/cdn/lru"
+	"github.com/ovh/cds/engine/cdn/quota"
 	"github.com/ovh/cds/engine/cdn/storage"
+	_ "github.com/ovh/cds/engine/cdn/storage/azureblob"
+	_ "github.com/ovh/cds/engine/cdn/storage/gcs"
 	_ "github.com/ovh/cds/engine/cdn/storage/local"
 	_ "github.com/ovh/cds/engine/cdn/storage/nfs"
 	_ "github.com/ovh/cds/engine/cdn/storage/redis"
@@ -87,6 +90,13 @@ func (s *Service) ApplyConfiguration(config interface{}) error {
 	if s.Cfg.Metrics.Frequency <= 0 {
 		s.Cfg.Metrics.Frequency = 30
 	}
+	if len(s.Cfg.Log.Redaction) > 0 {
+		var err error
+		s.logRedaction, err = sdk.NewBlur(nil, s.Cfg.Log.Redaction...)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -104,6 +114,12 @@ func (s *Service) CheckConfiguration(config interface{}) error {
 	if sConfig.Name == "" {
 		return fmt.Errorf("please enter a name in your CDN configuration")
 	}
+	if sConfig.OTLP.Enabled && sConfig.OTLP.Protocol != otlpProtocolGRPC && sConfig.OTLP.Protocol != otlpProtocolHTTP {
+		return fmt.Errorf("invalid otlp protocol %q, must be %q or %q", sConfig.OTLP.Protocol, otlpProtocolGRPC, otlpProtocolHTTP)
+	}
+	if err := sdk.LogRedactionRules(sConfig.Log.Redaction).IsValid(); err != nil {
+		return fmt.Errorf("invalid log redaction rules: %v", err)
+	}
 
 	return nil
 }
@@ -138,6 +154,7 @@ func (s *Service) Start(ctx context.Context) error {
 	// Init dao packages
 	item.InitDBMapping(s.Mapper)
 	storage.InitDBMapping(s.Mapper)
+	quota.InitDBMapping(s.Mapper)
 
 	log.Info(ctx, "Initializing lru connection...")
 	s.LogCache, err = lru.NewRedisLRU(s.mustDBWithCtx(ctx), s.Cfg.Cache.LruSize, s.Cfg.Cache.Redis)
@@ -154,6 +171,10 @@ func (s *Service) Start(ctx context.Context) error {
 
 	s.Units.Start(ctx, s.GoRoutines)
 
+	if err := s.initOTLPLogExporter(ctx); err != nil {
+		return err
+	}
+
 	s.GoRoutines.Run(ctx, "service.cdn-gc-items", func(ctx context.Context) {
 		s.itemsGC(ctx)
 	})
@@ -175,6 +196,12 @@ func (s *Service) Start(ctx context.Context) error {
 		s.workerCacheExpiredPurge(ctx)
 	})
 
+	if s.Cfg.IntegrityCheck.Enable {
+		s.GoRoutines.Run(ctx, "service.cdn-integrity-check", func(ctx context.Context) {
+			s.itemIntegrityCheck(ctx)
+		})
+	}
+
 	return nil
 }
 
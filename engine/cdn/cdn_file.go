@@ -13,6 +13,8 @@ import (
 	"os"
 	"sort"
 
+	"github.com/rockbears/log"
+
 	"github.com/ovh/cds/engine/cdn/item"
 	"github.com/ovh/cds/engine/cdn/storage"
 	"github.com/ovh/cds/engine/gorpmapper"
@@ -24,24 +26,28 @@ type StoreFileOptions struct {
 	DisableApiRunResult bool
 }
 
-func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.ReadCloser, storeFileOptions StoreFileOptions) (*sdk.CDNItem, error) {
-	var itemType sdk.CDNItemType
+func fileItemType(sig cdn.Signature) (sdk.CDNItemType, error) {
 	switch {
 	case sig.Worker.RunResultName != "":
-		itemType = sdk.CDNTypeItemRunResultV2
+		return sdk.CDNTypeItemRunResultV2, nil
 	case sig.Worker.FileName != "":
-		itemType = sdk.CDNTypeItemRunResult
+		return sdk.CDNTypeItemRunResult, nil
 	case sig.Worker.CacheTag != "":
 		if sig.RunJobID != "" {
-			itemType = sdk.CDNTypeItemWorkerCacheV2
-		} else {
-			itemType = sdk.CDNTypeItemWorkerCache
+			return sdk.CDNTypeItemWorkerCacheV2, nil
 		}
-
+		return sdk.CDNTypeItemWorkerCache, nil
 	default:
-		return nil, sdk.WrapError(sdk.ErrWrongRequest, "invalid item type")
+		return "", sdk.WrapError(sdk.ErrWrongRequest, "invalid item type")
+	}
+}
+
+// newFileItem checks that the file described by the signature can be uploaded and returns the item to create
+func (s *Service) newFileItem(ctx context.Context, sig cdn.Signature, storeFileOptions StoreFileOptions) (*sdk.CDNItem, error) {
+	itemType, err := fileItemType(sig)
+	if err != nil {
+		return nil, err
 	}
-	bufferUnit := s.Units.FileBuffer()
 
 	// Item and ItemUnit creation
 	apiRef, err := sdk.NewCDNApiRef(itemType, sig)
@@ -62,6 +68,10 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 		return nil, err
 	}
 
+	if err := s.checkQuotas(ctx, sig.ProjectKey, itemType); err != nil {
+		return nil, err
+	}
+
 	it := &sdk.CDNItem{
 		APIRef:     apiRef,
 		Type:       itemType,
@@ -108,6 +118,16 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 		}
 	}
 
+	return it, nil
+}
+
+func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.ReadCloser, storeFileOptions StoreFileOptions) (*sdk.CDNItem, error) {
+	it, err := s.newFileItem(ctx, sig, storeFileOptions)
+	if err != nil {
+		return nil, err
+	}
+	bufferUnit := s.Units.FileBuffer()
+
 	iu, err := s.Units.NewItemUnit(ctx, bufferUnit, it)
 	if err != nil {
 		return nil, err
@@ -150,22 +170,100 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 	it.Size = sizeWriter.Size
 	it.Status = sdk.CDNStatusItemCompleted
 
+	if err := s.saveFileItem(ctx, sig, it, []*sdk.CDNItemUnit{iu}, storeFileOptions); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// storeKnownFile creates an item for a file whose content, identified by its sha512 hash, is already stored
+// in the storage units for an item of the same project. No data is written, the new item units reference the existing content.
+// If the content is unknown, an error ErrNotFound is returned.
+func (s *Service) storeKnownFile(ctx context.Context, sig cdn.Signature, hash string, storeFileOptions StoreFileOptions) (*sdk.CDNItem, error) {
+	it, err := s.newFileItem(ctx, sig, storeFileOptions)
+	if err != nil {
+		return nil, err
+	}
+	it.Hash = hash
+
+	db := s.mustDBWithCtx(ctx)
+	var refItem *sdk.CDNItem
+	var ius []*sdk.CDNItemUnit
+	for _, su := range s.Units.Storages {
+		suloc, is := su.(storage.StorageUnitWithLocator)
+		if !is {
+			continue
+		}
+		loc, err := suloc.NewLocator(hash)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to compute convergent locator")
+		}
+
+		// Prevent the content from being purged until the new item unit is saved
+		unlock, err := s.Units.LockLocator(ctx, loc, su.ID())
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+
+		refItemUnit, err := storage.LoadCompletedItemUnitByUnitAndHashLocator(ctx, s.Mapper, db, su.ID(), s.Units.HashLocator(loc), it.Type, sig.ProjectKey)
+		if err != nil {
+			if sdk.ErrorIs(err, sdk.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if refItem == nil {
+			refItem, err = item.LoadByID(ctx, s.Mapper, db, refItemUnit.ItemID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		iu, err := s.Units.NewItemUnit(ctx, su, it)
+		if err != nil {
+			return nil, err
+		}
+		ius = append(ius, iu)
+	}
+	if len(ius) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	it.MD5 = refItem.MD5
+	it.Size = refItem.Size
+	it.Status = sdk.CDNStatusItemCompleted
+
+	if err := s.saveFileItem(ctx, sig, it, ius, storeFileOptions); err != nil {
+		return nil, err
+	}
+	log.Info(ctx, "item %s stored without upload, content is shared with item %s", it.APIRef.ToFilename(), refItem.ID)
+	return it, nil
+}
+
+// saveFileItem inserts a completed file item with its item units, then registers the run result on the API
+func (s *Service) saveFileItem(ctx context.Context, sig cdn.Signature, it *sdk.CDNItem, ius []*sdk.CDNItemUnit, storeFileOptions StoreFileOptions) error {
+	apiRef := it.APIRef
+	itemType := it.Type
+
 	// Insert Item and ItemUnit in database
 	tx, err := s.mustDBWithCtx(ctx).Begin()
 	if err != nil {
-		return nil, sdk.WithStack(err)
+		return sdk.WithStack(err)
 	}
 	defer tx.Rollback() //nolint
 
 	// Insert Item
 	if err := item.Insert(ctx, s.Mapper, tx, it); err != nil {
-		return nil, err
+		return err
 	}
 
-	// Insert Item Unit
-	iu.ItemID = iu.Item.ID
-	if err := storage.InsertItemUnit(ctx, s.Mapper, tx, iu); err != nil {
-		return nil, err
+	// Insert Item Units
+	for _, iu := range ius {
+		iu.ItemID = it.ID
+		if err := storage.InsertItemUnit(ctx, s.Mapper, tx, iu); err != nil {
+			return err
+		}
 	}
 
 	if !storeFileOptions.DisableApiRunResult {
@@ -198,7 +296,7 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 
 			bts, err := json.Marshal(result)
 			if err != nil {
-				return nil, sdk.WithStack(err)
+				return sdk.WithStack(err)
 			}
 			wrResult := sdk.WorkflowRunResult{
 				WorkflowRunID:     sig.RunID,
@@ -208,35 +306,36 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 				DataRaw:           json.RawMessage(bts),
 			}
 			if err := s.Client.QueueWorkflowRunResultsAdd(ctx, sig.JobID, wrResult); err != nil {
-				return nil, err
+				return err
 			}
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, sdk.WithStack(err)
+		return sdk.WithStack(err)
 	}
 
 	s.Units.PushInSyncQueue(ctx, it.ID, it.Created)
+	s.checkSoftQuotas(ctx, sig.ProjectKey, itemType)
 
 	// For worker cache item clean others with same ref to purge old cached data
 	if itemType == sdk.CDNTypeItemWorkerCache || itemType == sdk.CDNTypeItemWorkerCacheV2 {
 		tx, err := s.mustDBWithCtx(ctx).Begin()
 		if err != nil {
-			return nil, sdk.WithStack(err)
+			return sdk.WithStack(err)
 		}
 		defer tx.Rollback() //nolint
 
 		if err := s.cleanPreviousCachedData(ctx, tx, sig, apiRef.ToFilename()); err != nil {
-			return nil, err
+			return err
 		}
 
 		if err := tx.Commit(); err != nil {
-			return nil, sdk.WithStack(err)
+			return sdk.WithStack(err)
 		}
 	}
 
-	return it, nil
+	return nil
 }
 
 // Mark to delete all items for given cache tag except the most recent one.
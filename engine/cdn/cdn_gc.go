@@ -56,6 +56,10 @@ func (s *Service) itemsGC(ctx context.Context) {
 				ctx = sdk.ContextWithStacktrace(ctx, err)
 				log.Error(ctx, "cdn:CompleteWaitingItems: ContextWithStacktrace err: %v", err)
 			}
+			if err := s.applyLifecycleRules(ctx); err != nil {
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "cdn:CompleteWaitingItems: applyLifecycleRules err: %v", err)
+			}
 		}
 	}
 }
@@ -152,8 +156,13 @@ func (s *Service) cleanBuffer(ctx context.Context) error {
 		}
 		storageCount++
 	}
+	// Items with a lifecycle rule are not expected on every storage unit
+	lifecycleTypes := []string{}
+	for _, r := range s.Cfg.Units.Lifecycle {
+		lifecycleTypes = append(lifecycleTypes, string(r.ItemType))
+	}
 	for _, bu := range s.Units.Buffers {
-		itemIDs, err := storage.LoadAllSynchronizedItemIDs(s.mustDBWithCtx(ctx), bu.ID(), storageCount)
+		itemIDs, err := storage.LoadAllSynchronizedItemIDs(s.mustDBWithCtx(ctx), bu.ID(), storageCount, lifecycleTypes)
 		if err != nil {
 			return err
 		}
@@ -167,25 +176,87 @@ func (s *Service) cleanBuffer(ctx context.Context) error {
 			log.Error(ctx, "unable to load item units: %v", err)
 			continue
 		}
-		tx, err := s.mustDBWithCtx(ctx).Begin()
+		s.markBufferItemUnitsToDelete(ctx, itemUnitsIDs)
+	}
+
+	for _, r := range s.Cfg.Units.Lifecycle {
+		bu := s.Units.GetBuffer(r.ItemType)
+		if bu == nil {
+			continue
+		}
+		itemUnits, err := storage.LoadBufferedItemUnitsByType(s.mustDBWithCtx(ctx), bu.ID(), r.ItemType)
 		if err != nil {
-			ctx := sdk.ContextWithStacktrace(ctx, err)
-			log.Error(ctx, "unable to start transaction: %v", err)
+			return err
+		}
+		var itemUnitsIDs []string
+		for _, iu := range itemUnits {
+			if s.Units.IsSynchronized(r.ItemType, iu.Created, iu.UnitIDs) {
+				itemUnitsIDs = append(itemUnitsIDs, iu.ID)
+			}
+		}
+		log.Debug(ctx, "item of type %s to remove from buffer: %d", r.ItemType, len(itemUnitsIDs))
+		if len(itemUnitsIDs) == 0 {
 			continue
 		}
+		s.markBufferItemUnitsToDelete(ctx, itemUnitsIDs)
+	}
+	return nil
+}
 
-		if _, err := storage.MarkItemUnitToDelete(tx, itemUnitsIDs); err != nil {
-			_ = tx.Rollback()
-			ctx := sdk.ContextWithStacktrace(ctx, err)
-			log.Error(ctx, "unable to mark item as delete: %v", err)
+func (s *Service) markBufferItemUnitsToDelete(ctx context.Context, itemUnitsIDs []string) {
+	tx, err := s.mustDBWithCtx(ctx).Begin()
+	if err != nil {
+		ctx := sdk.ContextWithStacktrace(ctx, err)
+		log.Error(ctx, "unable to start transaction: %v", err)
+		return
+	}
+
+	if _, err := storage.MarkItemUnitToDelete(tx, itemUnitsIDs); err != nil {
+		_ = tx.Rollback()
+		ctx := sdk.ContextWithStacktrace(ctx, err)
+		log.Error(ctx, "unable to mark item as delete: %v", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback()
+		ctx := sdk.ContextWithStacktrace(ctx, err)
+		log.Error(ctx, "unable to commit transaction: %v", err)
+	}
+}
+
+// applyLifecycleRules removes the items from the storage units where they are expired, and marks to delete the items older than their retention
+func (s *Service) applyLifecycleRules(ctx context.Context) error {
+	limit := 1000
+	if err := s.Units.ExpireItemUnits(ctx, limit); err != nil {
+		return err
+	}
+
+	for _, r := range s.Cfg.Units.Lifecycle {
+		if r.DeleteAfterDays == 0 {
 			continue
 		}
+		ids, err := item.LoadIDsByTypeOlderThan(s.mustDBWithCtx(ctx), r.ItemType, r.DeleteAfterDays, limit)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		log.Info(ctx, "cdn:lifecycle: %d items of type %s to mark for deletion", len(ids), r.ItemType)
 
+		tx, err := s.mustDBWithCtx(ctx).Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		if err := item.MarkItemsAsToDelete(tx, ids); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 		if err := tx.Commit(); err != nil {
 			_ = tx.Rollback()
-			ctx := sdk.ContextWithStacktrace(ctx, err)
-			log.Error(ctx, "unable to commit transaction: %v", err)
-			continue
+			return sdk.WithStack(err)
 		}
 	}
 	return nil
@@ -0,0 +1,109 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/cdn/redis"
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	// logFollowMaxItems is the maximum count of log items followed for a run
+	logFollowMaxItems = 1000
+	// logFollowPageSize is the count of lines read at once from the buffer
+	logFollowPageSize = 100
+	// logFollowKeepAlive is the maximum delay between two status events, to keep the connection open
+	logFollowKeepAlive = 15 * time.Second
+)
+
+// logFollowItem is the state of a log item followed by a client
+type logFollowItem struct {
+	nextLine  int64
+	completed bool
+}
+
+// followItemLogLines returns the lines of the given log item from the given line number.
+// For an incoming item, lines are returned until a missing line, the next ones will be returned when it is received.
+func (s *Service) followItemLogLines(ctx context.Context, it sdk.CDNItem, from int64) ([]sdk.CDNLogSearchLine, error) {
+	ctx = context.WithValue(ctx, storage.FieldAPIRef, it.APIRefHash)
+	completed := it.Status == sdk.CDNStatusItemCompleted
+
+	var apiRef sdk.CDNLogAPIRefV2
+	if logRef, has := it.GetCDNLogApiRefV2(); has {
+		apiRef = *logRef
+	}
+
+	itemUnit, err := storage.LoadItemUnitByUnit(ctx, s.Mapper, s.mustDBWithCtx(ctx), s.Units.LogsBuffer().ID(), it.ID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	// A completed item that is no more in the buffer is read from a storage unit
+	if itemUnit == nil {
+		if !completed {
+			return nil, nil
+		}
+		lines, err := s.searchItemLogLines(ctx, it, func(string) bool { return true }, math.MaxInt32)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]sdk.CDNLogSearchLine, 0, len(lines))
+		for _, l := range lines {
+			if l.Number >= from {
+				res = append(res, l)
+			}
+		}
+		return res, nil
+	}
+
+	var res []sdk.CDNLogSearchLine
+	next := from
+	for {
+		rc, err := s.Units.LogsBuffer().NewAdvancedReader(ctx, *itemUnit, sdk.CDNReaderFormatJSON, next, logFollowPageSize, 0)
+		if err != nil {
+			return nil, err
+		}
+		var lines []redis.Line
+		err = json.NewDecoder(rc).Decode(&lines)
+		_ = rc.Close()
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to read lines from buffer")
+		}
+
+		for _, l := range lines {
+			// Lines can be received out of order, wait for the missing line
+			if !completed && l.Number != next {
+				return res, nil
+			}
+			res = append(res, sdk.CDNLogSearchLine{
+				APIRefHash: it.APIRefHash,
+				APIRef:     apiRef,
+				Number:     l.Number,
+				Value:      strings.TrimSuffix(l.Value, "\n"),
+			})
+			next = l.Number + 1
+		}
+		if len(lines) < logFollowPageSize {
+			return res, nil
+		}
+	}
+}
+
+// writeServerSentEvent writes an event with its JSON data
+func writeServerSentEvent(w io.Writer, event string, data interface{}) error {
+	btes, err := json.Marshal(data)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, btes); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
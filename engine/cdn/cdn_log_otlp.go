@@ -0,0 +1,217 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/rockbears/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+	"github.com/ovh/cds/sdk/log/hook/graylog"
+)
+
+const (
+	otlpProtocolGRPC = "grpc"
+	otlpProtocolHTTP = "http"
+)
+
+// otlpLoggerScope is the instrumentation scope of the exported log records
+const otlpLoggerScope = "github.com/ovh/cds/engine/cdn"
+
+// otlpLoggerTTL is the duration a logger is kept after the last log line of its job
+const otlpLoggerTTL = 20 * time.Minute
+
+// Attributes set on the resource of the logs of a job, or on each exported log record
+const (
+	otlpAttributeItemType     = "cds.item_type"
+	otlpAttributeProjectKey   = "cds.project_key"
+	otlpAttributeWorkflowName = "cds.workflow_name"
+	otlpAttributeRunID        = "cds.run_id"
+	otlpAttributeRunNumber    = "cds.run_number"
+	otlpAttributeRunAttempt   = "cds.run_attempt"
+	otlpAttributeJobName      = "cds.job_name"
+	otlpAttributeJobID        = "cds.job_id"
+	otlpAttributeStepName     = "cds.step_name"
+	otlpAttributeStepOrder    = "cds.step_order"
+	otlpAttributeServiceName  = "cds.service_name"
+	otlpAttributeHatcheryName = "cds.hatchery_name"
+	otlpAttributeWorkerName   = "cds.worker_name"
+)
+
+// initOTLPLogExporter creates the processor used to forward job logs to an OTLP collector
+func (s *Service) initOTLPLogExporter(ctx context.Context) error {
+	if !s.Cfg.OTLP.Enabled {
+		return nil
+	}
+
+	var exporter sdklog.Exporter
+	var err error
+	switch s.Cfg.OTLP.Protocol {
+	case otlpProtocolGRPC:
+		opts := []otlploggrpc.Option{otlploggrpc.WithEndpoint(s.Cfg.OTLP.Endpoint)}
+		if s.Cfg.OTLP.Insecure {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(s.Cfg.OTLP.Headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(s.Cfg.OTLP.Headers))
+		}
+		exporter, err = otlploggrpc.New(ctx, opts...)
+	case otlpProtocolHTTP:
+		opts := []otlploghttp.Option{otlploghttp.WithEndpoint(s.Cfg.OTLP.Endpoint)}
+		if s.Cfg.OTLP.URLPath != "" {
+			opts = append(opts, otlploghttp.WithURLPath(s.Cfg.OTLP.URLPath))
+		}
+		if s.Cfg.OTLP.Insecure {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(s.Cfg.OTLP.Headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(s.Cfg.OTLP.Headers))
+		}
+		exporter, err = otlploghttp.New(ctx, opts...)
+	default:
+		return sdk.WithStack(fmt.Errorf("invalid otlp protocol %q", s.Cfg.OTLP.Protocol))
+	}
+	if err != nil {
+		return sdk.WrapError(err, "unable to create otlp log exporter")
+	}
+
+	// The batch processor is shared by the loggers of all the jobs
+	s.otlpProcessor = sdklog.NewBatchProcessor(exporter)
+	s.otlpLoggers = gocache.New(otlpLoggerTTL, otlpLoggerTTL)
+
+	// Flush pending records on shutdown
+	s.GoRoutines.Run(ctx, "service.otlp-log-exporter.shutdown", func(ctx context.Context) {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.otlpProcessor.Shutdown(shutdownCtx); err != nil {
+			log.Error(ctx, "unable to shutdown otlp log exporter: %v", err)
+		}
+	})
+
+	log.Info(ctx, "Exporting job logs over OTLP/%s to %s", s.Cfg.OTLP.Protocol, s.Cfg.OTLP.Endpoint)
+	return nil
+}
+
+// exportOTLPLog forwards a log line to the OTLP collector if the export is enabled
+func (s *Service) exportOTLPLog(ctx context.Context, itemType sdk.CDNItemType, hm handledMessage) {
+	if s.otlpProcessor == nil {
+		return
+	}
+	content := strings.TrimSuffix(hm.Msg.Full, "\n")
+	if content == "" {
+		return
+	}
+	s.otlpJobLogger(hm.Signature).Emit(ctx, newOTLPLogRecord(itemType, hm, content))
+}
+
+// otlpJobLogger returns the logger of the job of the given signature. The project, workflow, run and job are set on
+// the resource of this logger, so a collector can group the logs of a job without reading each record.
+func (s *Service) otlpJobLogger(sig cdn.Signature) otellog.Logger {
+	attrs := otlpResourceAttributes(s.Cfg.Name, sig)
+	set := attribute.NewSet(attrs...)
+	key := set.Encoded(attribute.DefaultEncoder())
+	if l, has := s.otlpLoggers.Get(key); has {
+		s.otlpLoggers.SetDefault(key, l)
+		return l.(otellog.Logger)
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(s.otlpProcessor),
+		sdklog.WithResource(resource.NewSchemaless(attrs...)),
+	)
+	l := provider.Logger(otlpLoggerScope)
+	s.otlpLoggers.SetDefault(key, l)
+	return l
+}
+
+// otlpResourceAttributes returns the attributes of the resource of the logs of a job
+func otlpResourceAttributes(serviceName string, sig cdn.Signature) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", serviceName),
+		attribute.String(otlpAttributeProjectKey, sig.ProjectKey),
+		attribute.String(otlpAttributeWorkflowName, sig.WorkflowName),
+		attribute.String(otlpAttributeJobName, sig.JobName),
+	}
+	if sig.RunJobID != "" {
+		attrs = append(attrs,
+			attribute.String(otlpAttributeRunID, sig.WorkflowRunID),
+			attribute.Int64(otlpAttributeRunNumber, sig.RunNumber),
+			attribute.Int64(otlpAttributeRunAttempt, sig.RunAttempt),
+			attribute.String(otlpAttributeJobID, sig.RunJobID),
+		)
+	} else {
+		attrs = append(attrs,
+			attribute.String(otlpAttributeRunID, strconv.FormatInt(sig.RunID, 10)),
+			attribute.String(otlpAttributeJobID, strconv.FormatInt(sig.JobID, 10)),
+		)
+	}
+	return attrs
+}
+
+func newOTLPLogRecord(itemType sdk.CDNItemType, hm handledMessage, content string) otellog.Record {
+	var r otellog.Record
+	if hm.Signature.Timestamp > 0 {
+		r.SetTimestamp(time.Unix(0, hm.Signature.Timestamp))
+	}
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(otlpSeverity(graylog.Priority(hm.Msg.Level)))
+	r.SetBody(otellog.StringValue(content))
+
+	sig := hm.Signature
+	attrs := []otellog.KeyValue{
+		otellog.String(otlpAttributeItemType, string(itemType)),
+	}
+	if hm.HatcheryName != "" {
+		attrs = append(attrs, otellog.String(otlpAttributeHatcheryName, hm.HatcheryName))
+	}
+	switch {
+	case sig.Worker != nil:
+		attrs = append(attrs,
+			otellog.String(otlpAttributeStepName, sig.Worker.StepName),
+			otellog.Int64(otlpAttributeStepOrder, sig.Worker.StepOrder),
+			otellog.String(otlpAttributeWorkerName, sig.Worker.WorkerName),
+		)
+	case sig.Service != nil:
+		attrs = append(attrs,
+			otellog.String(otlpAttributeServiceName, sig.Service.RequirementName),
+			otellog.String(otlpAttributeWorkerName, sig.Service.WorkerName),
+		)
+	case sig.HatcheryService != nil:
+		attrs = append(attrs, otellog.String(otlpAttributeServiceName, sig.HatcheryService.ServiceName))
+	}
+	r.AddAttributes(attrs...)
+	return r
+}
+
+// otlpSeverity converts a syslog priority from a gelf message to an OpenTelemetry severity
+func otlpSeverity(p graylog.Priority) otellog.Severity {
+	switch p {
+	case graylog.LOG_EMERG:
+		return otellog.SeverityFatal4
+	case graylog.LOG_ALERT:
+		return otellog.SeverityFatal2
+	case graylog.LOG_CRIT:
+		return otellog.SeverityFatal
+	case graylog.LOG_ERR:
+		return otellog.SeverityError
+	case graylog.LOG_WARNING:
+		return otellog.SeverityWarn
+	case graylog.LOG_NOTICE:
+		return otellog.SeverityInfo2
+	case graylog.LOG_DEBUG:
+		return otellog.SeverityDebug
+	default:
+		return otellog.SeverityInfo
+	}
+}
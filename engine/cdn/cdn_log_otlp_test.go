@@ -0,0 +1,128 @@
+package cdn
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+	"github.com/ovh/cds/sdk/log/hook/graylog"
+)
+
+func TestNewOTLPLogRecord(t *testing.T) {
+	now := time.Now()
+	hm := handledMessage{
+		Msg: graylog.Message{Full: "container OOMKilled\n", Level: int32(graylog.LOG_ERR)},
+		Signature: cdn.Signature{
+			ProjectKey:    "PROJ",
+			WorkflowName:  "my-workflow",
+			WorkflowRunID: "run-id",
+			RunNumber:     12,
+			RunAttempt:    1,
+			JobName:       "build",
+			RunJobID:      "run-job-id",
+			Timestamp:     now.UnixNano(),
+			Worker: &cdn.SignatureWorker{
+				WorkerName: "my-worker",
+				StepName:   "script",
+				StepOrder:  2,
+			},
+		},
+		HatcheryName: "my-hatchery",
+	}
+
+	r := newOTLPLogRecord(sdk.CDNTypeItemJobStepLog, hm, "container OOMKilled")
+	require.Equal(t, "container OOMKilled", r.Body().AsString())
+	require.Equal(t, otellog.SeverityError, r.Severity())
+	require.Equal(t, now.UnixNano(), r.Timestamp().UnixNano())
+
+	attrs := make(map[string]string)
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value.String()
+		return true
+	})
+	require.Equal(t, map[string]string{
+		otlpAttributeItemType:     string(sdk.CDNTypeItemJobStepLog),
+		otlpAttributeStepName:     "script",
+		otlpAttributeStepOrder:    "2",
+		otlpAttributeWorkerName:   "my-worker",
+		otlpAttributeHatcheryName: "my-hatchery",
+	}, attrs)
+}
+
+// recordProcessor keeps the emitted log records
+type recordProcessor struct {
+	mutex   sync.Mutex
+	records []sdklog.Record
+}
+
+func (p *recordProcessor) OnEmit(_ context.Context, r *sdklog.Record) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.records = append(p.records, r.Clone())
+	return nil
+}
+
+func (p *recordProcessor) Enabled(context.Context, sdklog.EnabledParameters) bool { return true }
+func (p *recordProcessor) Shutdown(context.Context) error                         { return nil }
+func (p *recordProcessor) ForceFlush(context.Context) error                       { return nil }
+
+func TestExportOTLPLogResource(t *testing.T) {
+	processor := &recordProcessor{}
+	s := &Service{
+		Cfg:           Configuration{Name: "my-cdn"},
+		otlpProcessor: processor,
+		otlpLoggers:   gocache.New(otlpLoggerTTL, otlpLoggerTTL),
+	}
+
+	job1 := cdn.Signature{
+		ProjectKey:    "PROJ",
+		WorkflowName:  "my-workflow",
+		WorkflowRunID: "run-id",
+		RunNumber:     12,
+		RunAttempt:    1,
+		JobName:       "build",
+		RunJobID:      "run-job-1",
+		Worker:        &cdn.SignatureWorker{WorkerName: "my-worker", StepName: "script", StepOrder: 2},
+	}
+	job2 := job1
+	job2.JobName = "test"
+	job2.RunJobID = "run-job-2"
+
+	s.exportOTLPLog(context.TODO(), sdk.CDNTypeItemJobStepLog, handledMessage{Signature: job1, Msg: graylog.Message{Full: "line 1"}})
+	s.exportOTLPLog(context.TODO(), sdk.CDNTypeItemJobStepLog, handledMessage{Signature: job1, Msg: graylog.Message{Full: "line 2"}})
+	s.exportOTLPLog(context.TODO(), sdk.CDNTypeItemJobStepLog, handledMessage{Signature: job2, Msg: graylog.Message{Full: "line 3"}})
+
+	// A logger is created for each job
+	require.Equal(t, 2, s.otlpLoggers.ItemCount())
+	require.Len(t, processor.records, 3)
+
+	resources := make([]map[string]string, 0, len(processor.records))
+	for _, r := range processor.records {
+		attrs := make(map[string]string)
+		for _, kv := range r.Resource().Attributes() {
+			attrs[string(kv.Key)] = kv.Value.Emit()
+		}
+		resources = append(resources, attrs)
+	}
+	require.Equal(t, map[string]string{
+		"service.name":            "my-cdn",
+		otlpAttributeProjectKey:   "PROJ",
+		otlpAttributeWorkflowName: "my-workflow",
+		otlpAttributeRunID:        "run-id",
+		otlpAttributeRunNumber:    "12",
+		otlpAttributeRunAttempt:   "1",
+		otlpAttributeJobName:      "build",
+		otlpAttributeJobID:        "run-job-1",
+	}, resources[0])
+	require.Equal(t, resources[0], resources[1])
+	require.Equal(t, "test", resources[2][otlpAttributeJobName])
+	require.Equal(t, "run-job-2", resources[2][otlpAttributeJobID])
+}
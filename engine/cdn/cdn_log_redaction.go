@@ -0,0 +1,119 @@
+package cdn
+
+import (
+	"context"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+)
+
+const (
+	// projectLogRedactionTTL is the delay before the log redaction rules of a project are loaded again from the API
+	projectLogRedactionTTL = time.Minute
+)
+
+var keyLogRedactionCount = cache.Key("cdn", "log", "redaction")
+
+type projectLogRedaction struct {
+	blur    *sdk.Blur
+	expires time.Time
+}
+
+// redactLog applies the log redaction rules of the CDN and of the project on a job log message
+func (s *Service) redactLog(ctx context.Context, signature cdn.Signature, content string) string {
+	var count, n int
+	if s.logRedaction != nil {
+		content, n = s.logRedaction.Redact(content)
+		count += n
+	}
+
+	// Project rules are only available for workflow v2
+	if signature.RunJobID == "" {
+		return content
+	}
+	b, err := s.loadProjectLogRedaction(ctx, signature.ProjectKey)
+	if err != nil {
+		log.Error(ctx, "unable to load log redaction rules of project %s: %v", signature.ProjectKey, err)
+	} else if b != nil {
+		content, n = b.Redact(content)
+		count += n
+	}
+
+	if count > 0 {
+		// Logs of a step can be received by several CDN instances, the counter must be incremented atomically
+		if _, err := s.Cache.IncrByWithTTL(logRedactionCountKey(signature), int64(count), 3600*24); err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+		}
+	}
+	return content
+}
+
+// loadProjectLogRedaction returns the log redaction rules of the project, or nil if the project has no rules
+func (s *Service) loadProjectLogRedaction(ctx context.Context, projectKey string) (*sdk.Blur, error) {
+	if v, has := s.projectLogRedactions.Load(projectKey); has {
+		r := v.(projectLogRedaction)
+		if time.Now().Before(r.expires) {
+			return r.blur, nil
+		}
+	}
+
+	rules, err := s.Client.ProjectV2CDNLogRedactionRules(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	var b *sdk.Blur
+	if len(rules) > 0 {
+		b, err = sdk.NewBlur(nil, rules...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.projectLogRedactions.Store(projectKey, projectLogRedaction{blur: b, expires: time.Now().Add(projectLogRedactionTTL)})
+	return b, nil
+}
+
+// reportLogRedaction sends to the API the count of values redacted from a terminated log item
+func (s *Service) reportLogRedaction(ctx context.Context, signature cdn.Signature) {
+	if signature.RunJobID == "" {
+		return
+	}
+	key := logRedactionCountKey(signature)
+	var count int
+	found, err := s.Cache.Get(key, &count)
+	if err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+		return
+	}
+	if !found || count == 0 {
+		return
+	}
+	_ = s.Cache.Delete(key)
+
+	report := sdk.CDNLogRedactionReport{
+		RunJobID: signature.RunJobID,
+		Count:    count,
+	}
+	switch {
+	case signature.HatcheryService != nil:
+		report.ServiceName = signature.HatcheryService.ServiceName
+	case signature.Worker != nil:
+		report.StepName = signature.Worker.StepName
+	}
+	if err := s.Client.ProjectV2CDNLogRedactionReport(ctx, signature.ProjectKey, report); err != nil {
+		log.Error(ctx, "unable to send log redaction report for job %s: %v", signature.RunJobID, err)
+	}
+}
+
+func logRedactionCountKey(signature cdn.Signature) string {
+	switch {
+	case signature.HatcheryService != nil:
+		return cache.Key(keyLogRedactionCount, signature.RunJobID, "service", signature.HatcheryService.ServiceName)
+	case signature.Worker != nil:
+		return cache.Key(keyLogRedactionCount, signature.RunJobID, "step", signature.Worker.StepName)
+	}
+	return cache.Key(keyLogRedactionCount, signature.RunJobID)
+}
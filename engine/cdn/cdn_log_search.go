@@ -0,0 +1,147 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cdn/redis"
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	// logSearchMaxLines is the maximum count of lines returned by a search
+	logSearchMaxLines = 1000
+	// logSearchMaxItems is the maximum count of log items scanned by a search, latest items first.
+	// The search result is marked as truncated when this limit or the lines limit is reached.
+	logSearchMaxItems = 500
+)
+
+// logSearchWriter receives the raw content of a log item and keeps the lines matching the search
+type logSearchWriter struct {
+	item          sdk.CDNItem
+	match         func(string) bool
+	limit         int
+	currentNumber int64
+	currentBuffer []byte
+	lines         []sdk.CDNLogSearchLine
+}
+
+var _ io.Writer = new(logSearchWriter)
+
+func (w *logSearchWriter) full() bool {
+	return len(w.lines) >= w.limit
+}
+
+func (w *logSearchWriter) addLine(number int64, value string) {
+	if w.full() || !w.match(value) {
+		return
+	}
+	var apiRef sdk.CDNLogAPIRefV2
+	if logRef, has := w.item.GetCDNLogApiRefV2(); has {
+		apiRef = *logRef
+	}
+	w.lines = append(w.lines, sdk.CDNLogSearchLine{
+		APIRefHash: w.item.APIRefHash,
+		APIRef:     apiRef,
+		Number:     number,
+		Value:      value,
+	})
+}
+
+func (w *logSearchWriter) Write(p []byte) (int, error) {
+	// Once the limit is reached, remaining data is ignored
+	if w.full() {
+		return len(p), nil
+	}
+	w.currentBuffer = append(w.currentBuffer, p...)
+	lines := strings.Split(string(w.currentBuffer), "\n")
+	for i := 0; i < len(lines)-1; i++ {
+		w.addLine(w.currentNumber, lines[i])
+		w.currentNumber++
+	}
+	// The last part can be a partial line
+	w.currentBuffer = []byte(lines[len(lines)-1])
+	return len(p), nil
+}
+
+// flush handles the last line if it is not ended by \n
+func (w *logSearchWriter) flush() {
+	if len(w.currentBuffer) > 0 {
+		w.addLine(w.currentNumber, string(w.currentBuffer))
+		w.currentBuffer = nil
+	}
+}
+
+// searchItemLogLines returns the lines of the given log item that match, from the buffer if the item is in it, else streamed from a storage unit
+func (s *Service) searchItemLogLines(ctx context.Context, it sdk.CDNItem, match func(string) bool, limit int) ([]sdk.CDNLogSearchLine, error) {
+	ctx = context.WithValue(ctx, storage.FieldAPIRef, it.APIRefHash)
+	w := &logSearchWriter{item: it, match: match, limit: limit}
+
+	itemUnit, err := storage.LoadItemUnitByUnit(ctx, s.Mapper, s.mustDBWithCtx(ctx), s.Units.LogsBuffer().ID(), it.ID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	// If item is in Buffer, get from it
+	if itemUnit != nil {
+		rc, err := s.Units.LogsBuffer().NewAdvancedReader(ctx, *itemUnit, sdk.CDNReaderFormatJSON, 0, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close() // nolint
+
+		// Lines are decoded one by one to avoid loading the whole item in memory
+		dec := json.NewDecoder(rc)
+		if _, err := dec.Token(); err != nil {
+			return nil, sdk.WrapError(err, "unable to read lines from buffer")
+		}
+		for dec.More() && !w.full() {
+			var line redis.Line
+			if err := dec.Decode(&line); err != nil {
+				return nil, sdk.WrapError(err, "unable to read line from buffer")
+			}
+			w.addLine(line.Number, strings.TrimSuffix(line.Value, "\n"))
+		}
+		return w.lines, nil
+	}
+
+	// Get from storage
+	itemUnitID, unitName, err := s.getRandomItemUnitIDByItemID(ctx, it.ID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	unitStorage := s.Units.Storage(unitName)
+	if unitStorage == nil {
+		return nil, sdk.WithStack(fmt.Errorf("unable to find unit %s", unitName))
+	}
+
+	iu, err := storage.LoadItemUnitByID(ctx, s.Mapper, s.mustDBWithCtx(ctx), itemUnitID, gorpmapper.GetOptions.WithDecryption)
+	if err != nil {
+		return nil, err
+	}
+
+	storageReader, err := unitStorage.NewReader(ctx, *iu)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to open new reader for item unit %v", iu.ID)
+	}
+	defer func() {
+		if err := storageReader.Close(); err != nil {
+			log.Error(ctx, "searchItemLogLines> can't close reader: %+v", err)
+		}
+	}()
+
+	if err := unitStorage.Read(*iu, storageReader, w); err != nil {
+		return nil, err
+	}
+	w.flush()
+
+	return w.lines, nil
+}
@@ -0,0 +1,48 @@
+package cdn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLogSearchWriter(t *testing.T) {
+	it := sdk.CDNItem{
+		APIRefHash: "hash",
+		APIRef:     &sdk.CDNLogAPIRefV2{ProjectKey: "PROJ", WorkflowName: "my-workflow", RunJobID: "job-1"},
+	}
+	w := &logSearchWriter{
+		item:  it,
+		match: func(v string) bool { return strings.Contains(v, "OOMKilled") },
+		limit: 10,
+	}
+
+	// Lines can be split between two writes
+	_, err := w.Write([]byte("starting\ncontainer OOM"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("Killed\nexit 137\nOOMKilled"))
+	require.NoError(t, err)
+	w.flush()
+
+	require.Len(t, w.lines, 2)
+	require.Equal(t, int64(1), w.lines[0].Number)
+	require.Equal(t, "container OOMKilled", w.lines[0].Value)
+	require.Equal(t, "hash", w.lines[0].APIRefHash)
+	require.Equal(t, "job-1", w.lines[0].APIRef.RunJobID)
+	require.Equal(t, int64(3), w.lines[1].Number)
+	require.Equal(t, "OOMKilled", w.lines[1].Value)
+
+	w = &logSearchWriter{
+		item:  it,
+		match: func(v string) bool { return true },
+		limit: 2,
+	}
+	_, err = w.Write([]byte("a\nb\nc\nd\n"))
+	require.NoError(t, err)
+	w.flush()
+	require.Len(t, w.lines, 2)
+	require.Equal(t, "b", w.lines[1].Value)
+}
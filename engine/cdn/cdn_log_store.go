@@ -35,7 +35,8 @@ func (s *Service) sendToBufferWithRetry(ctx context.Context, hms []handledMessag
 				itemType = sdk.CDNTypeItemJobStepLog
 			}
 		}
-		currentLog := buildMessage(hm)
+		currentLog := s.redactLog(ctx, hm.Signature, buildMessage(hm))
+		hm.Msg.Full = currentLog
 		cpt := 0
 		for {
 			if err := s.storeLogs(ctx, itemType, hm.Signature, hm.IsTerminated, currentLog); err != nil {
@@ -48,6 +49,13 @@ func (s *Service) sendToBufferWithRetry(ctx context.Context, hms []handledMessag
 			}
 			break
 		}
+		s.exportOTLPLog(ctx, itemType, hm)
+		if hm.IsTerminated {
+			signature := hm.Signature
+			s.GoRoutines.Exec(ctx, "reportLogRedaction", func(ctx context.Context) {
+				s.reportLogRedaction(ctx, signature)
+			})
+		}
 	}
 	return nil
 }
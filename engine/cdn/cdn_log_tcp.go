@@ -157,7 +157,7 @@ func (s *Service) handleLogMessage(ctx context.Context, messageReceived []byte)
 func (s *Service) handleWorkerLog(ctx context.Context, unsafeSign cdn.Signature, sig interface{}, msg graylog.Message) error {
 	var signature cdn.Signature
 
-	var jobID string
+	var jobID, hatcheryName string
 	switch {
 	case unsafeSign.JobID != 0:
 		// Get worker data from cache
@@ -173,6 +173,7 @@ func (s *Service) handleWorkerLog(ctx context.Context, unsafeSign cdn.Signature,
 			return sdk.WithStack(sdk.ErrForbidden)
 		}
 		jobID = strconv.Itoa(int(signature.JobID))
+		hatcheryName = workerData.HatcheryName
 	case unsafeSign.RunJobID != "":
 		// Get worker data from cache
 		workerData, err := s.getWorkerV2(ctx, unsafeSign.Worker.WorkerName, GetWorkerOptions{NeedPrivateKey: true})
@@ -187,6 +188,7 @@ func (s *Service) handleWorkerLog(ctx context.Context, unsafeSign cdn.Signature,
 			return sdk.WithStack(sdk.ErrForbidden)
 		}
 		jobID = unsafeSign.RunJobID
+		hatcheryName = workerData.HatcheryName
 	}
 
 	terminatedI := msg.Extra["_"+cdslog.ExtraFieldTerminated]
@@ -196,6 +198,7 @@ func (s *Service) handleWorkerLog(ctx context.Context, unsafeSign cdn.Signature,
 		Signature:    signature,
 		Msg:          msg,
 		IsTerminated: terminated,
+		HatcheryName: hatcheryName,
 	}
 
 	sizeQueueKey := cache.Key(keyJobLogSize, jobID)
@@ -308,6 +311,7 @@ func (s *Service) handleServiceLog(ctx context.Context, unsafeSign cdn.Signature
 		Signature:    signature,
 		Msg:          msg,
 		IsTerminated: terminated,
+		HatcheryName: hatcheryName,
 	}
 
 	sizeQueueKey := cache.Key(keyJobLogSize, key)
@@ -0,0 +1,137 @@
+package cdn
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/cdn/item"
+	"github.com/ovh/cds/engine/cdn/quota"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	keyQuotaNotified = cache.Key("cdn", "quota", "notified")
+	keyQuotaSize     = cache.Key("cdn", "quota", "size")
+)
+
+// quotaSizeCacheTTL is the duration in seconds the size used by a project is cached, to avoid computing it on each upload
+const quotaSizeCacheTTL = 60
+
+// loadQuotaUsages returns the quotas of a project that apply to the given item type with their current usage.
+// An empty item type returns all the quotas of the project.
+func (s *Service) loadQuotaUsages(ctx context.Context, projectKey string, itemType sdk.CDNItemType) ([]sdk.CDNQuotaUsage, error) {
+	quotas, err := quota.LoadAllByProjectKey(ctx, s.Mapper, s.mustDBWithCtx(ctx), projectKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeQuotaUsages(ctx, quotas, itemType)
+}
+
+func (s *Service) computeQuotaUsages(ctx context.Context, quotas []sdk.CDNQuota, itemType sdk.CDNItemType) ([]sdk.CDNQuotaUsage, error) {
+	usages := make([]sdk.CDNQuotaUsage, 0, len(quotas))
+	for _, q := range quotas {
+		if itemType != "" && !q.Match(itemType) {
+			continue
+		}
+		size, err := s.computeQuotaSize(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, sdk.CDNQuotaUsage{CDNQuota: q, Size: size})
+	}
+	return usages, nil
+}
+
+// computeQuotaSize returns the size used by the project for the item type of the quota, the size is cached for quotaSizeCacheTTL
+func (s *Service) computeQuotaSize(ctx context.Context, q sdk.CDNQuota) (int64, error) {
+	key := cache.Key(keyQuotaSize, q.ProjectKey, string(q.ItemType))
+	var size int64
+	find, err := s.Cache.Get(key, &size)
+	if err != nil {
+		log.Error(ctx, "unable to get quota size from cache %s: %v", key, err)
+	}
+	if find {
+		return size, nil
+	}
+	size, err = item.ComputeSizeByProjectKeyAndType(s.mustDBWithCtx(ctx), q.ProjectKey, q.ItemType)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.Cache.SetWithTTL(key, size, quotaSizeCacheTTL); err != nil {
+		log.Error(ctx, "unable to cache quota size %s: %v", key, err)
+	}
+	return size, nil
+}
+
+// checkQuotas returns an error if the project reached the hard limit of one of its quotas for the given item type
+func (s *Service) checkQuotas(ctx context.Context, projectKey string, itemType sdk.CDNItemType) error {
+	usages, err := s.loadQuotaUsages(ctx, projectKey, itemType)
+	if err != nil {
+		return err
+	}
+	for _, u := range usages {
+		if u.HardLimit == 0 || u.Size < u.HardLimit {
+			continue
+		}
+		s.notifyQuota(ctx, u, true)
+		scope := "all item types"
+		if u.ItemType != "" {
+			scope = "item type " + string(u.ItemType)
+		}
+		return sdk.NewErrorFrom(sdk.ErrCDNQuotaExceeded, "project %s uses %d bytes on the CDN for %s, the limit is %d bytes", projectKey, u.Size, scope, u.HardLimit)
+	}
+	return nil
+}
+
+// checkSoftQuotas sends a notification for each quota of the project whose soft limit is reached for the given item type
+func (s *Service) checkSoftQuotas(ctx context.Context, projectKey string, itemType sdk.CDNItemType) {
+	usages, err := s.loadQuotaUsages(ctx, projectKey, itemType)
+	if err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+		return
+	}
+	for _, u := range usages {
+		if u.SoftLimit == 0 || u.Size < u.SoftLimit {
+			continue
+		}
+		s.notifyQuota(ctx, u, false)
+	}
+}
+
+// notifyQuota asks the API to send an event for the quota. A notification is sent at most once a day for each limit.
+func (s *Service) notifyQuota(ctx context.Context, u sdk.CDNQuotaUsage, hardLimit bool) {
+	lockKey := cache.Key(keyQuotaNotified, u.ID, strconv.FormatBool(hardLimit))
+	// The lock is never released, it expires to allow a new notification
+	b, err := s.Cache.Lock(lockKey, 24*time.Hour, 0, 1)
+	if err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+		return
+	}
+	if !b {
+		return
+	}
+
+	limit := "soft"
+	if hardLimit {
+		limit = "hard"
+	}
+	log.Info(ctx, "project %s reached the %s limit of its CDN quota %q: %d bytes used", u.ProjectKey, limit, u.ItemType, u.Size)
+	e := sdk.CDNQuotaEvent{
+		Quota:     u.CDNQuota,
+		Size:      u.Size,
+		HardLimit: hardLimit,
+	}
+	if err := s.Client.ProjectV2CDNQuotaEvent(ctx, u.ProjectKey, e); err != nil {
+		log.Error(ctx, "unable to send quota event for project %s: %v", u.ProjectKey, err)
+		_ = s.Cache.Unlock(lockKey)
+	}
+}
+
+func (s *Service) resetQuotaNotifications(q sdk.CDNQuota) {
+	_ = s.Cache.Unlock(cache.Key(keyQuotaNotified, q.ID, strconv.FormatBool(true)))
+	_ = s.Cache.Unlock(cache.Key(keyQuotaNotified, q.ID, strconv.FormatBool(false)))
+}
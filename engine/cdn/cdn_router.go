@@ -29,15 +29,20 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/item/duplicate", nil, r.POST(s.postDuplicateItemForJobHandler))
 	r.Handle("/item/upload", nil, r.POST(s.postUploadHandler, service.OverrideAuth(service.NoAuthMiddleware)))
+	r.Handle("/item/upload/dedup", nil, r.POST(s.postUploadDedupHandler, service.OverrideAuth(service.NoAuthMiddleware)))
 	r.Handle("/item/stream", nil, r.GET(s.getItemLogsStreamHandler, service.OverrideAuth(s.validJWTMiddleware)))
 	r.Handle("/item/{type}", nil, r.GET(s.getItemsHandler))
 	r.Handle("/item/{type}/lines", nil, r.GET(s.getItemsAllLogsLinesHandler, service.OverrideAuth(s.validJWTMiddleware)))
+	r.Handle("/item/{type}/follow", nil, r.GET(s.getItemsLogsFollowHandler, service.OverrideAuth(s.validJWTMiddleware)))
+	r.Handle("/item/{type}/search", nil, r.GET(s.getItemsLogsSearchHandler, service.OverrideAuth(s.validJWTMiddleware)))
 	r.Handle("/item/{type}/{apiRef}", nil, r.GET(s.getItemHandler, service.OverrideAuth(s.itemAccessMiddleware)), r.DELETE(s.deleteItemHandler))
 	r.Handle("/item/{type}/{apiRef}/checksync", nil, r.GET(s.getItemCheckSyncHandler, service.OverrideAuth(s.itemAccessMiddleware)))
 	r.Handle("/item/{type}/{apiRef}/download", nil, r.GET(s.getItemDownloadHandler, service.OverrideAuth(s.itemAccessMiddleware)))
 	r.Handle("/item/{type}/{apiRef}/download/{unit}", nil, r.GET(s.getItemDownloadInUnitHandler, service.OverrideAuth(s.itemAccessMiddleware)))
 	r.Handle("/item/{type}/{apiRef}/lines", nil, r.GET(s.getItemLogsLinesHandler, service.OverrideAuth(s.itemAccessMiddleware)))
 
+	r.Handle("/share/run-result/{token}", nil, r.GET(s.getRunResultShareDownloadHandler, service.OverrideAuth(service.NoAuthMiddleware)))
+
 	r.Handle("/unit", nil, r.GET(s.getUnitsHandler))
 	r.Handle("/unit/{id}", nil, r.DELETE(s.deleteUnitHandler))
 	r.Handle("/unit/{id}/item", nil, r.DELETE(s.markItemUnitAsDeleteHandler))
@@ -46,6 +51,9 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/size/item/project/{projectKey}", nil, r.GET(s.getSizeByProjectHandler))
 
+	r.Handle("/quota", nil, r.GET(s.getQuotasHandler), r.POST(s.postQuotaHandler))
+	r.Handle("/quota/{projectKey}", nil, r.GET(s.getProjectQuotasHandler), r.DELETE(s.deleteProjectQuotaHandler))
+
 	r.Handle("/admin/database/migration", nil, r.GET(s.getAdminDatabaseMigrationHandler))
 	r.Handle("/admin/database/migration/delete/{id}", nil, r.DELETE(s.deleteAdminDatabaseMigrationHandler))
 	r.Handle("/admin/database/migration/unlock/{id}", nil, r.POST(s.postAdminDatabaseMigrationUnlockHandler))
@@ -55,4 +63,5 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/admin/database/entity/{entity}/roll", nil, r.POST(s.postAdminDatabaseEntityRoll))
 
 	r.Handle("/admin/backend/{id}/resync/{type}", nil, r.POST(s.postAdminResyncBackendWithDatabaseHandler))
+	r.Handle("/admin/backend/{id}/verify", nil, r.GET(s.getAdminVerifyBackendHandler), r.POST(s.postAdminVerifyBackendHandler))
 }
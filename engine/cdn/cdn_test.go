@@ -23,6 +23,7 @@ import (
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/cdn/item"
+	"github.com/ovh/cds/engine/cdn/quota"
 	"github.com/ovh/cds/engine/cdn/storage"
 	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/engine/test"
@@ -52,6 +53,7 @@ func newTestService(t *testing.T) (*Service, *test.FakeTransaction) {
 	m := gorpmapper.New()
 	item.InitDBMapping(m)
 	storage.InitDBMapping(m)
+	quota.InitDBMapping(m)
 
 	log.Factory = log.NewTestingWrapper(t)
 	db, factory, cache, end := test.SetupPGToCancel(t, m, sdk.TypeCDN)
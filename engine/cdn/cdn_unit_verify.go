@@ -0,0 +1,273 @@
+package cdn
+
+import (
+	"context"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	// unitVerifyReportTTL is the duration in seconds of the retention of the last verification report of a unit
+	unitVerifyReportTTL = 3600 * 24 * 7
+	// unitVerifyReportMaxItems is the maximum count of damaged items detailed in a verification report
+	unitVerifyReportMaxItems = 1000
+	// unitVerifyBatchSize is the count of item units loaded at once during a full scan
+	unitVerifyBatchSize = 1000
+)
+
+var (
+	keyUnitVerifyReport = cache.Key("cdn", "unit", "verify", "report")
+	keyUnitVerifyLock   = cache.Key("cdn", "unit", "verify", "lock")
+)
+
+// unitVerifyOptions are the options of an integrity verification of a unit
+type unitVerifyOptions struct {
+	// sample is the count of random items to verify, 0 to verify all the items
+	sample int64
+	// repair removes the damaged copies and copies them again from a healthy unit
+	repair bool
+}
+
+// itemIntegrityCheck is a goroutine that periodically verifies a sample of the items stored on each storage unit
+func (s *Service) itemIntegrityCheck(ctx context.Context) {
+	frequency := s.Cfg.IntegrityCheck.FrequencyHours
+	if frequency <= 0 {
+		frequency = 24
+	}
+
+	tick := time.NewTicker(time.Duration(frequency) * time.Hour)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "cdn:integrity-check: %v", ctx.Err())
+			}
+			return
+		case <-tick.C:
+			opts := unitVerifyOptions{
+				sample: s.Cfg.IntegrityCheck.SampleSize,
+				repair: s.Cfg.IntegrityCheck.Repair,
+			}
+			for _, u := range s.Units.Storages {
+				report, err := s.verifyUnit(ctx, u, opts)
+				if err != nil {
+					ctx = sdk.ContextWithStacktrace(ctx, err)
+					log.Error(ctx, "cdn:integrity-check: unable to verify unit %s: %v", u.Name(), err)
+					continue
+				}
+				if report == nil {
+					continue
+				}
+				if report.Missing > 0 || report.Corrupted > 0 {
+					log.Warn(ctx, "cdn:integrity-check: unit %s: %d items checked, %d missing, %d corrupted, %d repaired",
+						u.Name(), report.Checked, report.Missing, report.Corrupted, report.Repaired)
+				}
+			}
+		}
+	}
+}
+
+// verifyUnit checks the content of the items stored on the given storage unit against their hash.
+// It returns nil if a verification of the unit is already running.
+func (s *Service) verifyUnit(ctx context.Context, u storage.StorageUnit, opts unitVerifyOptions) (*sdk.CDNUnitVerifyReport, error) {
+	lockKey := cache.Key(keyUnitVerifyLock, u.ID())
+	b, err := s.Cache.Lock(lockKey, 24*time.Hour, 0, 1)
+	if err != nil {
+		return nil, err
+	}
+	if !b {
+		log.Info(ctx, "cdn:integrity-check: verification of unit %s is already running", u.Name())
+		return nil, nil
+	}
+	defer func() {
+		if err := s.Cache.Unlock(lockKey); err != nil {
+			log.Error(ctx, "unable to release lock %s", lockKey)
+		}
+	}()
+
+	report := &sdk.CDNUnitVerifyReport{
+		UnitID:   u.ID(),
+		UnitName: u.Name(),
+		Started:  time.Now(),
+		Sample:   opts.sample,
+		Repair:   opts.repair,
+	}
+	s.saveUnitVerifyReport(ctx, *report)
+
+	log.Info(ctx, "cdn:integrity-check: starting verification of unit %s", u.Name())
+
+	if opts.sample > 0 {
+		ids, err := storage.LoadRandomItemUnitsIDsByUnitID(s.mustDBWithCtx(ctx), u.ID(), opts.sample)
+		if err != nil {
+			return nil, s.endUnitVerifyReport(ctx, report, err)
+		}
+		s.verifyItemUnits(ctx, u, ids, opts, report)
+	} else {
+		// Repaired item units are marked as to delete during the scan, so the pages are loaded after the last id and not with an offset
+		var lastID string
+		for {
+			ids, err := storage.LoadItemUnitsIDsByUnitIDAfterID(s.mustDBWithCtx(ctx), u.ID(), lastID, unitVerifyBatchSize)
+			if err != nil {
+				return nil, s.endUnitVerifyReport(ctx, report, err)
+			}
+			s.verifyItemUnits(ctx, u, ids, opts, report)
+			s.saveUnitVerifyReport(ctx, *report)
+			if int64(len(ids)) < unitVerifyBatchSize {
+				break
+			}
+			lastID = ids[len(ids)-1]
+		}
+	}
+
+	if err := s.endUnitVerifyReport(ctx, report, nil); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Service) verifyItemUnits(ctx context.Context, u storage.StorageUnit, ids []string, opts unitVerifyOptions, report *sdk.CDNUnitVerifyReport) {
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		iu, err := storage.LoadItemUnitByID(ctx, s.Mapper, s.mustDBWithCtx(ctx), id, gorpmapper.GetOptions.WithDecryption)
+		if err != nil {
+			// The item unit could have been purged since the ids were loaded
+			if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				log.Error(ctx, "cdn:integrity-check: unable to load item unit %s: %v", id, err)
+			}
+			continue
+		}
+		if iu.Item == nil || iu.Item.Status != sdk.CDNStatusItemCompleted || iu.Item.Hash == "" {
+			continue
+		}
+
+		report.Checked++
+		status, err := storage.VerifyItemUnit(ctx, u, *iu)
+		if status == "" {
+			if err != nil {
+				log.Error(ctx, "cdn:integrity-check: unable to verify item unit %s: %v", iu.ID, err)
+			}
+			continue
+		}
+
+		reportItem := sdk.CDNUnitVerifyReportItem{
+			ItemID:     iu.ItemID,
+			ItemUnitID: iu.ID,
+			APIRefHash: iu.Item.APIRefHash,
+			Type:       iu.Type,
+			Status:     status,
+		}
+		if err != nil {
+			reportItem.Error = err.Error()
+		}
+		switch status {
+		case sdk.CDNItemUnitVerifyStatusMissing:
+			report.Missing++
+		case sdk.CDNItemUnitVerifyStatusCorrupted:
+			report.Corrupted++
+		}
+		log.Warn(ctx, "cdn:integrity-check: item %s is %s on unit %s: %v", iu.ItemID, status, u.Name(), err)
+
+		if opts.repair {
+			repaired, err := s.repairItemUnit(ctx, u, *iu)
+			if err != nil {
+				log.Error(ctx, "cdn:integrity-check: unable to repair item %s on unit %s: %v", iu.ItemID, u.Name(), err)
+			}
+			if repaired {
+				reportItem.Repaired = true
+				report.Repaired++
+			}
+		}
+
+		if len(report.Items) < unitVerifyReportMaxItems {
+			report.Items = append(report.Items, reportItem)
+		}
+	}
+}
+
+// repairItemUnit synchronizes again the damaged item unit if a healthy copy of the item is available on another unit
+func (s *Service) repairItemUnit(ctx context.Context, u storage.StorageUnit, iu sdk.CDNItemUnit) (bool, error) {
+	itemUnits, err := storage.LoadAllItemUnitsByItemIDs(ctx, s.Mapper, s.mustDBWithCtx(ctx), iu.ItemID, gorpmapper.GetAllOptions.WithDecryption)
+	if err != nil {
+		return false, err
+	}
+
+	var healthy bool
+	for _, other := range itemUnits {
+		if other.UnitID == u.ID() {
+			continue
+		}
+		otherUnit := s.unitByID(other.UnitID)
+		if otherUnit == nil {
+			continue
+		}
+		if status, _ := storage.VerifyItemUnit(ctx, otherUnit, other); status == "" {
+			healthy = true
+			break
+		}
+	}
+	if !healthy {
+		log.Error(ctx, "cdn:integrity-check: no healthy copy of item %s found to repair unit %s", iu.ItemID, u.Name())
+		return false, nil
+	}
+
+	if err := s.Units.RepairItemUnit(ctx, u, iu); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// unitByID returns the running buffer or storage unit with given id
+func (s *Service) unitByID(id string) storage.Unit {
+	for _, u := range s.Units.Buffers {
+		if u.ID() == id {
+			return u
+		}
+	}
+	for _, u := range s.Units.Storages {
+		if u.ID() == id {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Service) endUnitVerifyReport(ctx context.Context, report *sdk.CDNUnitVerifyReport, err error) error {
+	now := time.Now()
+	report.Ended = &now
+	if err != nil {
+		report.Error = err.Error()
+	}
+	s.saveUnitVerifyReport(ctx, *report)
+	log.Info(ctx, "cdn:integrity-check: unit %s verified: %d items checked, %d missing, %d corrupted, %d repaired",
+		report.UnitName, report.Checked, report.Missing, report.Corrupted, report.Repaired)
+	return err
+}
+
+func (s *Service) saveUnitVerifyReport(ctx context.Context, report sdk.CDNUnitVerifyReport) {
+	if err := s.Cache.SetWithTTL(cache.Key(keyUnitVerifyReport, report.UnitID), report, unitVerifyReportTTL); err != nil {
+		log.Error(ctx, "cdn:integrity-check: unable to save report of unit %s: %v", report.UnitName, err)
+	}
+}
+
+// loadUnitVerifyReport returns the last verification report of a unit, or nil if the unit has not been verified recently
+func (s *Service) loadUnitVerifyReport(unitID string) (*sdk.CDNUnitVerifyReport, error) {
+	var report sdk.CDNUnitVerifyReport
+	found, err := s.Cache.Get(cache.Key(keyUnitVerifyReport, unitID), &report)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &report, nil
+}
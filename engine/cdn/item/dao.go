@@ -3,6 +3,7 @@ package item
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
@@ -166,6 +167,22 @@ func LoadWorkerCacheItemByProjectAndCacheTag(ctx context.Context, m *gorpmapper.
 	return getItem(ctx, m, db, query)
 }
 
+// LoadWorkerCacheItemByProjectAndCacheTagPrefix returns the most recent cache item with a tag starting with the given prefix
+func LoadWorkerCacheItemByProjectAndCacheTagPrefix(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, cacheType string, projKey string, cacheTagPrefix string) (*sdk.CDNItem, error) {
+	escapedPrefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(cacheTagPrefix)
+	query := gorpmapper.NewQuery(`
+		SELECT *
+		FROM item
+		WHERE type = $1
+		AND (api_ref->>'project_key')::text = $2
+		AND (api_ref->>'cache_tag')::text LIKE $3
+		AND to_delete = false
+    ORDER BY created DESC
+    LIMIT 1
+  `).Args(cacheType, projKey, escapedPrefix+"%")
+	return getItem(ctx, m, db, query)
+}
+
 func LoadWorkerCacheItemsByProjectAndCacheTag(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, projKey string, cacheTag string) ([]sdk.CDNItem, error) {
 	query := gorpmapper.NewQuery(`
 		SELECT *
@@ -204,6 +221,23 @@ func LoadByRunJobID(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecut
 	return getItems(ctx, m, db, query, opts...)
 }
 
+// LoadLogItemsV2ByProject load the latest v2 log items of a project, filtered by workflow, run and job when given
+func LoadLogItemsV2ByProject(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, itemType sdk.CDNItemType, projKey, workflowName, runID, runJobID string, limit int, opts ...gorpmapper.GetAllOptionFunc) ([]sdk.CDNItem, error) {
+	query := gorpmapper.NewQuery(`
+		SELECT *
+		FROM item
+		WHERE type = $1
+		AND api_ref->>'project_key' = $2
+		AND ($3 = '' OR api_ref->>'workflow_name' = $3)
+		AND ($4 = '' OR api_ref->>'run_id' = $4)
+		AND ($5 = '' OR api_ref->>'run_job_id' = $5)
+		AND to_delete = false
+		ORDER BY created DESC
+		LIMIT $6
+	`).Args(itemType, projKey, workflowName, runID, runJobID, limit)
+	return getItems(ctx, m, db, query, opts...)
+}
+
 // LoadByAPIRefHashAndType load an item by his job id, step order and type
 func LoadByAPIRefHashAndType(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, hash string, itemType sdk.CDNItemType, opts ...gorpmapper.GetOptionFunc) (*sdk.CDNItem, error) {
 	query := gorpmapper.NewQuery(`
@@ -256,6 +290,22 @@ func ComputeSizeByProjectKey(db gorp.SqlExecutor, projectKey string) (int64, err
 	return size, nil
 }
 
+// ComputeSizeByProjectKeyAndType returns the size of the items of a project that are not marked to delete.
+// An empty item type computes the size for all item types.
+func ComputeSizeByProjectKeyAndType(db gorp.SqlExecutor, projectKey string, itemType sdk.CDNItemType) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(size), 0) FROM item
+		WHERE api_ref->>'project_key' = $1
+		AND ($2 = '' OR type = $2)
+		AND to_delete = false
+	`
+	size, err := db.SelectInt(query, projectKey, string(itemType))
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	return size, nil
+}
+
 type Stat struct {
 	Status string `db:"status"`
 	Type   string `db:"type"`
@@ -415,6 +465,24 @@ func MarkItemsAsToDelete(db gorpmapper.SqlExecutorWithTx, ids []string) error {
 	return sdk.WithStack(err)
 }
 
+// LoadIDsByTypeOlderThan returns IDs of items of the given type created more than the given number of days ago.
+func LoadIDsByTypeOlderThan(db gorp.SqlExecutor, itemType sdk.CDNItemType, days int, limit int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM item
+		WHERE type = $1
+		  AND to_delete = false
+		  AND created < NOW() - $2 * INTERVAL '1 day'
+		ORDER BY created ASC
+		LIMIT $3
+	`
+	var ids []string
+	if _, err := db.Select(&ids, query, itemType, days, limit); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return ids, nil
+}
+
 // LoadExpiredWorkerCacheItemIDs returns IDs of worker-cache items whose expire_at date
 // has passed by at least gracePeriodDays.
 func LoadExpiredWorkerCacheItemIDs(db gorp.SqlExecutor, limit int, gracePeriodDays int) ([]string, error) {
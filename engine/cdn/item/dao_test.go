@@ -45,3 +45,47 @@ func TestLoadItem(t *testing.T) {
 	_, no := res.APIRef.(*sdk.CDNRunResultAPIRef)
 	require.False(t, no)
 }
+
+func TestLoadLogItemsV2ByProject(t *testing.T) {
+	m := gorpmapper.New()
+	item.InitDBMapping(m)
+
+	db, _ := test.SetupPGWithMapper(t, m, sdk.TypeCDN)
+	cdntest.ClearItem(t, context.TODO(), m, db)
+
+	projectKey := sdk.RandomString(10)
+	for _, runJobID := range []string{"job-1", "job-2"} {
+		apiRef := sdk.NewCDNLogApiRefV2(cdn.Signature{
+			ProjectKey:    projectKey,
+			WorkflowName:  "my-workflow",
+			WorkflowRunID: "run-1",
+			RunJobID:      runJobID,
+			Worker:        &cdn.SignatureWorker{StepName: "script", StepOrder: 0},
+		})
+		hashRef, err := apiRef.ToHash()
+		require.NoError(t, err)
+
+		i := sdk.CDNItem{
+			APIRef:     apiRef,
+			APIRefHash: hashRef,
+			Type:       sdk.CDNTypeItemJobStepLog,
+		}
+		require.NoError(t, item.Insert(context.TODO(), m, db, &i))
+		t.Cleanup(func() { _ = item.DeleteByID(db, i.ID) })
+	}
+
+	res, err := item.LoadLogItemsV2ByProject(context.TODO(), m, db, sdk.CDNTypeItemJobStepLog, projectKey, "my-workflow", "run-1", "", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	res, err = item.LoadLogItemsV2ByProject(context.TODO(), m, db, sdk.CDNTypeItemJobStepLog, projectKey, "", "", "job-2", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	logRef, has := res[0].GetCDNLogApiRefV2()
+	require.True(t, has)
+	require.Equal(t, "job-2", logRef.RunJobID)
+
+	res, err = item.LoadLogItemsV2ByProject(context.TODO(), m, db, sdk.CDNTypeItemJobStepLog, projectKey, "other-workflow", "", "", 10)
+	require.NoError(t, err)
+	require.Len(t, res, 0)
+}
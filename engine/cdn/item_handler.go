@@ -84,7 +84,19 @@ func (s *Service) getSizeByProjectHandler() service.Handler {
 			return err
 		}
 
-		return service.WriteJSON(w, size, http.StatusOK)
+		// With units=true, also return the size used on each storage unit
+		if !service.FormBool(r, "units") {
+			return service.WriteJSON(w, size, http.StatusOK)
+		}
+		unitSizes, err := storage.ComputeSizeByProjectKeyByUnit(s.mustDBWithCtx(ctx), projectKey)
+		if err != nil {
+			return err
+		}
+		res := sdk.CDNProjectSize{Size: size, Units: make([]sdk.CDNProjectUnitSize, 0, len(unitSizes))}
+		for _, us := range unitSizes {
+			res.Units = append(res.Units, sdk.CDNProjectUnitSize{Unit: us.UnitName, Size: us.Size})
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
 	}
 }
 
@@ -269,13 +281,21 @@ func (s *Service) getArtifacts(ctx context.Context, r *http.Request, w http.Resp
 func (s *Service) getWorkerCache(ctx context.Context, r *http.Request, w http.ResponseWriter, cacheType string) error {
 	projectKey := r.FormValue("projectkey")
 	cachetag := r.FormValue("cachetag")
+	cachetagPrefix := r.FormValue("cachetagprefix")
 
-	if projectKey == "" || cachetag == "" {
+	if projectKey == "" || (cachetag == "" && cachetagPrefix == "") {
 		return sdk.WrapError(sdk.ErrWrongRequest, "invalid data to get worker cache")
 	}
-	item, err := item.LoadWorkerCacheItemByProjectAndCacheTag(ctx, s.Mapper, s.mustDBWithCtx(ctx), cacheType, projectKey, cachetag)
+
+	var cacheItem *sdk.CDNItem
+	var err error
+	if cachetag != "" {
+		cacheItem, err = item.LoadWorkerCacheItemByProjectAndCacheTag(ctx, s.Mapper, s.mustDBWithCtx(ctx), cacheType, projectKey, cachetag)
+	} else {
+		cacheItem, err = item.LoadWorkerCacheItemByProjectAndCacheTagPrefix(ctx, s.Mapper, s.mustDBWithCtx(ctx), cacheType, projectKey, cachetagPrefix)
+	}
 	if err != nil {
 		return err
 	}
-	return service.WriteJSON(w, []sdk.CDNItem{*item}, http.StatusOK)
+	return service.WriteJSON(w, []sdk.CDNItem{*cacheItem}, http.StatusOK)
 }
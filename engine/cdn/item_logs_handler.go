@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/mux"
 	"github.com/rockbears/log"
 
+	"github.com/ovh/cds/engine/cdn/item"
 	"github.com/ovh/cds/engine/cdn/redis"
 	"github.com/ovh/cds/engine/cdn/storage"
 	"github.com/ovh/cds/engine/service"
@@ -264,3 +267,171 @@ func (s *Service) getItemLogsLinesHandler() service.Handler {
 		return service.Write(w, rc, http.StatusOK, "application/json")
 	}
 }
+
+func (s *Service) getItemsLogsSearchHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		itemType := sdk.CDNItemType(vars["type"])
+		if itemType != sdk.CDNTypeItemJobStepLog && itemType != sdk.CDNTypeItemServiceLogV2 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "search is only available on v2 log types")
+		}
+
+		projectKey := r.FormValue("project")
+		if projectKey == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing project")
+		}
+		pattern := r.FormValue("pattern")
+		if pattern == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing pattern")
+		}
+
+		match := func(value string) bool { return strings.Contains(value, pattern) }
+		if service.FormBool(r, "regex") {
+			reg, err := regexp.Compile(pattern)
+			if err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid regex %q: %v", pattern, err)
+			}
+			match = reg.MatchString
+		}
+
+		limit := int(service.FormUInt(r, "limit"))
+		if limit == 0 || limit > logSearchMaxLines {
+			limit = logSearchMaxLines
+		}
+
+		if err := s.Client.HasProjectRole(ctx, projectKey, s.sessionID(ctx), sdk.ProjectRoleRead); err != nil {
+			return sdk.NewErrorWithStack(err, sdk.ErrNotFound)
+		}
+
+		items, err := item.LoadLogItemsV2ByProject(ctx, s.Mapper, s.mustDBWithCtx(ctx), itemType, projectKey,
+			r.FormValue("workflow"), r.FormValue("run"), r.FormValue("job"), logSearchMaxItems)
+		if err != nil {
+			return err
+		}
+
+		res := sdk.CDNLogSearch{
+			Lines: make([]sdk.CDNLogSearchLine, 0),
+			// Older items than the latest logSearchMaxItems ones are not scanned
+			Truncated: len(items) >= logSearchMaxItems,
+		}
+		for _, it := range items {
+			lines, err := s.searchItemLogLines(ctx, it, match, limit-len(res.Lines))
+			if err != nil {
+				// An item that can't be read must not fail the whole search
+				log.Warn(ctx, "getItemsLogsSearchHandler> unable to search in item %s: %v", it.ID, err)
+				continue
+			}
+			res.Lines = append(res.Lines, lines...)
+			if len(res.Lines) >= limit {
+				res.Truncated = true
+				break
+			}
+		}
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
+
+func (s *Service) getItemsLogsFollowHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		itemType := sdk.CDNItemType(vars["type"])
+		if itemType != sdk.CDNTypeItemJobStepLog && itemType != sdk.CDNTypeItemServiceLogV2 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "follow is only available on v2 log types")
+		}
+
+		projectKey := r.FormValue("project")
+		if projectKey == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing project")
+		}
+		runID := r.FormValue("run")
+		if runID == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing run")
+		}
+		jobName := r.FormValue("job")
+
+		if err := s.Client.HasProjectRole(ctx, projectKey, s.sessionID(ctx), sdk.ProjectRoleRead); err != nil {
+			return sdk.NewErrorWithStack(err, sdk.ErrNotFound)
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			return sdk.WithStack(fmt.Errorf("streaming is not supported"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		followedItems := make(map[string]*logFollowItem)
+		var lastStatus *sdk.CDNLogFollowStatus
+		var lastStatusSent time.Time
+
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			items, err := item.LoadLogItemsV2ByProject(ctx, s.Mapper, s.mustDBWithCtx(ctx), itemType, projectKey, "", runID, "", logFollowMaxItems)
+			if err != nil {
+				log.Error(ctx, "getItemsLogsFollowHandler> unable to load items for run %s: %v", runID, err)
+				return nil
+			}
+
+			var status sdk.CDNLogFollowStatus
+			// Items are loaded latest first, send the oldest first
+			for i := len(items) - 1; i >= 0; i-- {
+				it := items[i]
+				if jobName != "" {
+					if logRef, has := it.GetCDNLogApiRefV2(); !has || logRef.RunJobName != jobName {
+						continue
+					}
+				}
+				followed, has := followedItems[it.ID]
+				if !has {
+					followed = &logFollowItem{}
+					followedItems[it.ID] = followed
+				}
+				if followed.completed {
+					status.Completed++
+					continue
+				}
+
+				lines, err := s.followItemLogLines(ctx, it, followed.nextLine)
+				if err != nil {
+					// An item that can't be read must not stop the stream
+					log.Warn(ctx, "getItemsLogsFollowHandler> unable to read item %s: %v", it.ID, err)
+					status.Incoming++
+					continue
+				}
+				for j := range lines {
+					if err := writeServerSentEvent(w, sdk.CDNLogFollowEventLine, lines[j]); err != nil {
+						log.Debug(ctx, "getItemsLogsFollowHandler> client disconnected: %v", err)
+						return nil
+					}
+					followed.nextLine = lines[j].Number + 1
+				}
+				if it.Status == sdk.CDNStatusItemCompleted {
+					followed.completed = true
+					status.Completed++
+				} else {
+					status.Incoming++
+				}
+			}
+
+			if lastStatus == nil || *lastStatus != status || time.Since(lastStatusSent) > logFollowKeepAlive {
+				if err := writeServerSentEvent(w, sdk.CDNLogFollowEventStatus, status); err != nil {
+					log.Debug(ctx, "getItemsLogsFollowHandler> client disconnected: %v", err)
+					return nil
+				}
+				lastStatus = &status
+				lastStatusSent = time.Now()
+			}
+			flusher.Flush()
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/authentication"
+	"github.com/ovh/cds/engine/cdn/item"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+)
+
+// getRunResultShareDownloadHandler serves a run result from a public link signed by the API. No session is required,
+// the download is allowed by the API that audits it and refuses expired or already used links. If the file could not
+// be sent, the download is reported as failed to the API so that it does not consume a single use link.
+func (s *Service) getRunResultShareDownloadHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		token := vars["token"]
+
+		var signature cdn.Signature
+		v := authentication.NewVerifier(s.ParsedAPIPublicKey)
+		if err := v.VerifyJWS(token, &signature); err != nil {
+			return sdk.NewErrorWithStack(err, sdk.ErrUnauthorized)
+		}
+		if signature.RunResultShare == nil {
+			return sdk.WithStack(sdk.ErrUnauthorized)
+		}
+
+		it, err := item.LoadByAPIRefHashAndType(ctx, s.Mapper, s.mustDBWithCtx(ctx), signature.RunResultShare.APIRefHash, sdk.CDNTypeItemRunResultV2)
+		if err != nil {
+			return err
+		}
+		if err := checkRunResultShareItem(signature, *it); err != nil {
+			return err
+		}
+
+		// Open the file before counting the download, so that a storage error does not consume a single use link
+		iu, unit, rc, err := s.getItemFileValue(ctx, sdk.CDNTypeItemRunResultV2, it.APIRefHash, getItemFileOptions{})
+		if err != nil {
+			return err
+		}
+		if rc == nil {
+			return sdk.WrapError(sdk.ErrNotFound, "no storage found that contains given item %s", it.APIRefHash)
+		}
+		defer func() {
+			if err := rc.Close(); err != nil {
+				log.Error(ctx, "getRunResultShareDownloadHandler> can't close reader: %+v", err)
+			}
+		}()
+
+		download, err := s.Client.ProjectV2CDNRunResultShareDownload(ctx, signature.ProjectKey, signature.RunResultShare.ShareID, sdk.V2WorkflowRunResultShareDownload{
+			RemoteAddr: s.remoteAddr(r),
+			UserAgent:  r.UserAgent(),
+		})
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", iu.Item.APIRef.ToFilename()))
+
+		if err := unit.Read(*iu, rc, w); err != nil {
+			// The client may be gone, the failure is reported even if the request context is canceled
+			if errFailed := s.Client.ProjectV2CDNRunResultShareDownloadFailed(context.WithoutCancel(ctx), signature.ProjectKey, signature.RunResultShare.ShareID, download.ID); errFailed != nil {
+				log.ErrorWithStackTrace(ctx, sdk.WrapError(errFailed, "unable to report failed download %s", download.ID))
+			}
+			return sdk.WithStack(err)
+		}
+		return nil
+	}
+}
+
+// checkRunResultShareItem checks that the item is the run result that was shared
+func checkRunResultShareItem(signature cdn.Signature, it sdk.CDNItem) error {
+	apiRef, has := it.GetCDNRunResultApiRefV2()
+	if !has {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	if apiRef.ProjectKey != signature.ProjectKey ||
+		apiRef.RunID != signature.WorkflowRunID ||
+		apiRef.RunResultID != signature.RunResultShare.RunResultID {
+		return sdk.WithStack(sdk.ErrForbidden)
+	}
+	return nil
+}
+
+func (s *Service) remoteAddr(r *http.Request) string {
+	var clientIP string
+	if s.Cfg.HTTP.HeaderXForwardedFor != "" {
+		clientIP = r.Header.Get(s.Cfg.HTTP.HeaderXForwardedFor)
+	}
+	if clientIP == "" {
+		clientIP = r.RemoteAddr
+	}
+	return clientIP
+}
@@ -0,0 +1,48 @@
+package cdn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+)
+
+func TestCheckRunResultShareItem(t *testing.T) {
+	signature := cdn.Signature{
+		ProjectKey:    "PROJ",
+		WorkflowRunID: "run-id",
+		RunResultShare: &cdn.SignatureRunResultShare{
+			ShareID:     "share-id",
+			RunResultID: "result-id",
+		},
+	}
+
+	it := sdk.CDNItem{
+		Type: sdk.CDNTypeItemRunResultV2,
+		APIRef: &sdk.CDNRunResultAPIRefV2{
+			ProjectKey:  "PROJ",
+			RunID:       "run-id",
+			RunResultID: "result-id",
+		},
+	}
+	require.NoError(t, checkRunResultShareItem(signature, it))
+
+	it.APIRef = &sdk.CDNRunResultAPIRefV2{
+		ProjectKey:  "PROJ",
+		RunID:       "run-id",
+		RunResultID: "other-result-id",
+	}
+	require.True(t, sdk.ErrorIs(checkRunResultShareItem(signature, it), sdk.ErrForbidden))
+
+	it.APIRef = &sdk.CDNRunResultAPIRefV2{
+		ProjectKey:  "OTHER",
+		RunID:       "run-id",
+		RunResultID: "result-id",
+	}
+	require.True(t, sdk.ErrorIs(checkRunResultShareItem(signature, it), sdk.ErrForbidden))
+
+	it.APIRef = &sdk.CDNWorkerCacheAPIRef{ProjectKey: "PROJ"}
+	require.True(t, sdk.ErrorIs(checkRunResultShareItem(signature, it), sdk.ErrNotFound))
+}
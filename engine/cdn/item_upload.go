@@ -62,3 +62,24 @@ func (s *Service) postUploadHandler() service.Handler {
 		return service.WriteJSON(w, item, http.StatusAccepted)
 	}
 }
+
+// postUploadDedupHandler creates the item without any upload if its content is already known by the CDN, else returns a not found error
+func (s *Service) postUploadDedupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		signature, err := s.verifySignatureFromRequest(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		hash := r.Header.Get("X-CDS-ITEM-HASH")
+		if hash == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing item hash")
+		}
+
+		item, err := s.storeKnownFile(ctx, *signature, hash, StoreFileOptions{})
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, item, http.StatusAccepted)
+	}
+}
@@ -0,0 +1,117 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAll(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, q gorpmapper.Query) ([]sdk.CDNQuota, error) {
+	var res []quotaDB
+	if err := m.GetAll(ctx, db, q, &res); err != nil {
+		return nil, err
+	}
+
+	quotas := make([]sdk.CDNQuota, 0, len(res))
+	for _, r := range res {
+		isValid, err := m.CheckSignature(r, r.Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !isValid {
+			log.Error(ctx, "quota.getAll> quota %s data corrupted", r.ID)
+			continue
+		}
+		quotas = append(quotas, r.CDNQuota)
+	}
+	return quotas, nil
+}
+
+func get(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, q gorpmapper.Query) (*sdk.CDNQuota, error) {
+	var r quotaDB
+	found, err := m.Get(ctx, db, q, &r)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get quota")
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	isValid, err := m.CheckSignature(r, r.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !isValid {
+		log.Error(ctx, "quota.get> quota %s data corrupted", r.ID)
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &r.CDNQuota, nil
+}
+
+// LoadAll returns all the quotas
+func LoadAll(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor) ([]sdk.CDNQuota, error) {
+	query := gorpmapper.NewQuery("SELECT * FROM quota ORDER BY project_key, item_type")
+	return getAll(ctx, m, db, query)
+}
+
+// LoadAllByProjectKey returns all the quotas of a project
+func LoadAllByProjectKey(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, projectKey string) ([]sdk.CDNQuota, error) {
+	query := gorpmapper.NewQuery("SELECT * FROM quota WHERE project_key = $1 ORDER BY item_type").Args(projectKey)
+	return getAll(ctx, m, db, query)
+}
+
+// LoadByProjectKeyAndType returns the quota of a project for the given item type, an empty type is for all item types
+func LoadByProjectKeyAndType(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, projectKey string, itemType sdk.CDNItemType) (*sdk.CDNQuota, error) {
+	query := gorpmapper.NewQuery("SELECT * FROM quota WHERE project_key = $1 AND item_type = $2").Args(projectKey, itemType)
+	return get(ctx, m, db, query)
+}
+
+// Upsert inserts the quota or updates the existing quota of the project for the same item type
+func Upsert(ctx context.Context, m *gorpmapper.Mapper, db gorpmapper.SqlExecutorWithTx, q *sdk.CDNQuota) error {
+	existing, err := LoadByProjectKeyAndType(ctx, m, db, q.ProjectKey, q.ItemType)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+
+	q.LastModified = time.Now()
+	if existing == nil {
+		q.ID = sdk.UUID()
+		q.Created = q.LastModified
+		dbQuota := quotaDB{CDNQuota: *q}
+		if err := m.InsertAndSign(ctx, db, &dbQuota); err != nil {
+			return sdk.WrapError(err, "unable to insert quota for project %s", q.ProjectKey)
+		}
+		*q = dbQuota.CDNQuota
+		return nil
+	}
+
+	q.ID = existing.ID
+	q.Created = existing.Created
+	dbQuota := quotaDB{CDNQuota: *q}
+	if err := m.UpdateAndSign(ctx, db, &dbQuota); err != nil {
+		return sdk.WrapError(err, "unable to update quota %s", q.ID)
+	}
+	*q = dbQuota.CDNQuota
+	return nil
+}
+
+// Delete removes the quota of a project for the given item type
+func Delete(db gorp.SqlExecutor, projectKey string, itemType sdk.CDNItemType) error {
+	res, err := db.Exec("DELETE FROM quota WHERE project_key = $1 AND item_type = $2", projectKey, itemType)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if n == 0 {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package quota
+
+import (
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func InitDBMapping(m *gorpmapper.Mapper) {
+	m.Register(m.NewTableMapping(quotaDB{}, "quota", false, "id"))
+}
+
+type quotaDB struct {
+	gorpmapper.SignedEntity
+	sdk.CDNQuota
+}
+
+func (q quotaDB) Canonical() gorpmapper.CanonicalForms {
+	_ = []interface{}{q.ID, q.ProjectKey, q.ItemType, q.SoftLimit, q.HardLimit} // Checks that fields exists at compilation
+	return []gorpmapper.CanonicalForm{
+		"{{.ID}}{{.ProjectKey}}{{.ItemType}}{{.SoftLimit}}{{.HardLimit}}",
+	}
+}
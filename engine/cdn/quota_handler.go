@@ -0,0 +1,87 @@
+package cdn
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/cdn/quota"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (s *Service) getQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		quotas, err := quota.LoadAll(ctx, s.Mapper, s.mustDBWithCtx(ctx))
+		if err != nil {
+			return err
+		}
+		usages, err := s.computeQuotaUsages(ctx, quotas, "")
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, usages, http.StatusOK)
+	}
+}
+
+func (s *Service) postQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var q sdk.CDNQuota
+		if err := service.UnmarshalBody(r, &q); err != nil {
+			return err
+		}
+		if err := q.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := s.mustDBWithCtx(ctx).Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+		if err := quota.Upsert(ctx, s.Mapper, tx, &q); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		// Limits may have changed, allow new notifications
+		s.resetQuotaNotifications(q)
+
+		return service.WriteJSON(w, q, http.StatusOK)
+	}
+}
+
+func (s *Service) getProjectQuotasHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["projectKey"]
+
+		usages, err := s.loadQuotaUsages(ctx, projectKey, "")
+		if err != nil {
+			return err
+		}
+		return service.WriteJSON(w, usages, http.StatusOK)
+	}
+}
+
+func (s *Service) deleteProjectQuotaHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["projectKey"]
+		itemType := sdk.CDNItemType(r.FormValue("type"))
+
+		q, err := quota.LoadByProjectKeyAndType(ctx, s.Mapper, s.mustDBWithCtx(ctx), projectKey, itemType)
+		if err != nil {
+			return err
+		}
+		if err := quota.Delete(s.mustDBWithCtx(ctx), projectKey, itemType); err != nil {
+			return err
+		}
+		s.resetQuotaNotifications(*q)
+
+		return service.WriteJSON(w, nil, http.StatusNoContent)
+	}
+}
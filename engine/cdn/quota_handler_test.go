@@ -0,0 +1,110 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/ovh/cds/engine/cdn/item"
+	"github.com/ovh/cds/engine/cdn/quota"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+)
+
+func TestQuotaHandlers(t *testing.T) {
+	s, db := newTestService(t)
+	ctx := context.TODO()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() { ctrl.Finish() })
+	mockCDSClient := mock_cdsclient.NewMockInterface(ctrl)
+	s.Client = mockCDSClient
+
+	projectKey := sdk.RandomString(10)
+	t.Cleanup(func() {
+		_, _ = db.Exec("DELETE FROM quota WHERE project_key = $1", projectKey)
+	})
+
+	// Add an item of 100 bytes for the project
+	it := sdk.CDNItem{
+		ID:         sdk.UUID(),
+		Size:       100,
+		Type:       sdk.CDNTypeItemWorkerCacheV2,
+		Status:     sdk.CDNStatusItemCompleted,
+		APIRefHash: sdk.RandomString(10),
+		APIRef: &sdk.CDNWorkerCacheAPIRef{
+			ProjectKey: projectKey,
+			CacheTag:   "my-cache",
+		},
+	}
+	require.NoError(t, item.Insert(ctx, s.Mapper, db, &it))
+	t.Cleanup(func() { _ = item.DeleteByID(db, it.ID) })
+
+	// Invalid quota
+	uri := s.Router.GetRoute("POST", s.postQuotaHandler, nil)
+	require.NotEmpty(t, uri)
+	req := newRequest(t, "POST", uri, sdk.CDNQuota{ProjectKey: projectKey, SoftLimit: 200, HardLimit: 100})
+	rec := httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 400, rec.Code)
+
+	// Quota for worker caches
+	req = newRequest(t, "POST", uri, sdk.CDNQuota{ProjectKey: projectKey, ItemType: sdk.CDNTypeItemWorkerCacheV2, SoftLimit: 50, HardLimit: 1000})
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+
+	// Update the quota
+	req = newRequest(t, "POST", uri, sdk.CDNQuota{ProjectKey: projectKey, ItemType: sdk.CDNTypeItemWorkerCacheV2, SoftLimit: 50, HardLimit: 100})
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+
+	quotas, err := quota.LoadAllByProjectKey(ctx, s.Mapper, db, projectKey)
+	require.NoError(t, err)
+	require.Len(t, quotas, 1)
+	require.Equal(t, int64(100), quotas[0].HardLimit)
+
+	// Get usage
+	uri = s.Router.GetRoute("GET", s.getProjectQuotasHandler, map[string]string{"projectKey": projectKey})
+	require.NotEmpty(t, uri)
+	req = newRequest(t, "GET", uri, nil)
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 200, rec.Code)
+
+	var usages []sdk.CDNQuotaUsage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &usages))
+	require.Len(t, usages, 1)
+	require.Equal(t, int64(100), usages[0].Size)
+
+	// Hard limit is reached for worker caches only
+	s.resetQuotaNotifications(quotas[0])
+	mockCDSClient.EXPECT().ProjectV2CDNQuotaEvent(gomock.Any(), projectKey, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, e sdk.CDNQuotaEvent) error {
+			require.True(t, e.HardLimit)
+			require.Equal(t, int64(100), e.Size)
+			return nil
+		},
+	).Times(1)
+	err = s.checkQuotas(ctx, projectKey, sdk.CDNTypeItemWorkerCacheV2)
+	require.Error(t, err)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrCDNQuotaExceeded))
+	// The notification is sent only once
+	require.Error(t, s.checkQuotas(ctx, projectKey, sdk.CDNTypeItemWorkerCacheV2))
+	require.NoError(t, s.checkQuotas(ctx, projectKey, sdk.CDNTypeItemRunResultV2))
+
+	// Delete the quota
+	uri = s.Router.GetRoute("DELETE", s.deleteProjectQuotaHandler, map[string]string{"projectKey": projectKey})
+	require.NotEmpty(t, uri)
+	req = newRequest(t, "DELETE", uri+"?type="+string(sdk.CDNTypeItemWorkerCacheV2), nil)
+	rec = httptest.NewRecorder()
+	s.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 204, rec.Code)
+
+	require.NoError(t, s.checkQuotas(ctx, projectKey, sdk.CDNTypeItemWorkerCacheV2))
+}
@@ -0,0 +1,221 @@
+package azureblob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/engine/cdn/storage/encryption"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+type AzureBlob struct {
+	client *azblob.Client
+	storage.AbstractUnit
+	encryption.ConvergentEncryption
+	config storage.AzureBlobStorageConfiguration
+}
+
+var (
+	_ storage.StorageUnit = new(AzureBlob)
+)
+
+const driverName = "azureblob"
+
+// resyncMinAge is the minimum age of a blob to be removed by a resynchronization, younger blobs can be under synchronization
+const resyncMinAge = time.Hour
+
+func init() {
+	storage.RegisterDriver(driverName, new(AzureBlob))
+}
+
+func (s *AzureBlob) GetDriverName() string {
+	return driverName
+}
+
+func (s *AzureBlob) Init(ctx context.Context, cfg interface{}) error {
+	config, is := cfg.(*storage.AzureBlobStorageConfiguration)
+	if !is {
+		return sdk.WithStack(fmt.Errorf("invalid configuration: %T", cfg))
+	}
+	s.config = *config
+	s.ConvergentEncryption = encryption.New(config.Encryption)
+
+	serviceURL := config.ServiceURL
+	if serviceURL == "" {
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", config.AccountName)
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+	if err != nil {
+		return sdk.WrapError(err, "unable to create azure shared key credential")
+	}
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	if err != nil {
+		return sdk.WrapError(err, "unable to create azure blob client")
+	}
+	s.client = client
+
+	_, err = s.client.ServiceClient().NewContainerClient(s.config.ContainerName).GetProperties(ctx, nil)
+	return sdk.WithStack(err)
+}
+
+func (s *AzureBlob) ItemExists(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, i sdk.CDNItem) (bool, error) {
+	iu, err := s.ExistsInDatabase(ctx, m, db, i.ID)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	blobName := s.getBlobName(*iu)
+	_, err = s.client.ServiceClient().NewContainerClient(s.config.ContainerName).NewBlobClient(blobName).GetProperties(ctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return false, nil
+		}
+		return false, sdk.WrapError(err, "unable to get properties of blob %s", blobName)
+	}
+	return true, nil
+}
+
+type azureBlobWriteCloser struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func (s *azureBlobWriteCloser) Write(btes []byte) (int, error) {
+	return s.pw.Write(btes)
+}
+
+// Close waits for the end of the upload
+func (s *azureBlobWriteCloser) Close() error {
+	if err := s.pw.Close(); err != nil {
+		return sdk.WithStack(err)
+	}
+	return <-s.done
+}
+
+var _ io.WriteCloser = new(azureBlobWriteCloser)
+
+func (s *AzureBlob) NewWriter(ctx context.Context, i sdk.CDNItemUnit) (io.WriteCloser, error) {
+	blobName := s.getBlobName(i)
+	log.Debug(ctx, "[%T] writing to %s", s, blobName)
+
+	pr, pw := io.Pipe()
+	w := &azureBlobWriteCloser{pw: pw, done: make(chan error, 1)}
+	go func() {
+		_, err := s.client.UploadStream(ctx, s.config.ContainerName, blobName, pr, nil)
+		if err != nil {
+			err = sdk.WrapError(err, "unable to upload blob %s", blobName)
+			_ = pr.CloseWithError(err)
+		}
+		w.done <- err
+	}()
+
+	return w, nil
+}
+
+func (s *AzureBlob) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.ReadCloser, error) {
+	blobName := s.getBlobName(i)
+	log.Debug(ctx, "[%T] reading from %s", s, blobName)
+
+	resp, err := s.client.DownloadStream(ctx, s.config.ContainerName, blobName, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WithStack(err)
+	}
+
+	return resp.Body, nil
+}
+
+func (s *AzureBlob) blobPrefix(itemType sdk.CDNItemType) string {
+	return path.Join(s.config.Prefix, string(itemType)) + "/"
+}
+
+func (s *AzureBlob) getBlobName(i sdk.CDNItemUnit) string {
+	return s.blobPrefix(i.Item.Type) + url.PathEscape(i.Locator)
+}
+
+// Status returns the status of the azure container
+func (s *AzureBlob) Status(ctx context.Context) []sdk.MonitoringStatusLine {
+	_, err := s.client.ServiceClient().NewContainerClient(s.config.ContainerName).GetProperties(ctx, nil)
+	if err != nil {
+		return []sdk.MonitoringStatusLine{{Component: "backend/" + s.Name(), Value: "AzureBlob KO" + err.Error(), Status: sdk.MonitoringStatusAlert}}
+	}
+	return []sdk.MonitoringStatusLine{{
+		Component: "backend/" + s.Name(),
+		Value:     fmt.Sprintf("AzureBlob OK (container %s)", s.config.ContainerName),
+		Status:    sdk.MonitoringStatusOK,
+	}}
+}
+
+func (s *AzureBlob) Remove(ctx context.Context, i sdk.CDNItemUnit) error {
+	blobName := s.getBlobName(i)
+	_, err := s.client.DeleteBlob(ctx, s.config.ContainerName, blobName, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return sdk.WrapError(err, "unable to delete blob %s", blobName)
+	}
+	return nil
+}
+
+// ResyncWithDatabase removes the blobs of the given type that are not referenced by any item unit
+func (s *AzureBlob) ResyncWithDatabase(ctx context.Context, db gorp.SqlExecutor, t sdk.CDNItemType, dryRun bool) {
+	prefix := s.blobPrefix(t)
+	pager := s.client.NewListBlobsFlatPager(s.config.ContainerName, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			log.Error(ctx, "azureblob: unable to list blobs with prefix %s: %v", prefix, err)
+			return
+		}
+		for _, b := range page.Segment.BlobItems {
+			if b.Name == nil {
+				continue
+			}
+			blobName := *b.Name
+			if b.Properties != nil && b.Properties.LastModified != nil && time.Since(*b.Properties.LastModified) < resyncMinAge {
+				continue
+			}
+			locator, err := url.PathUnescape(strings.TrimPrefix(blobName, prefix))
+			if err != nil {
+				log.Warn(ctx, "azureblob: invalid blob name %s: %v", blobName, err)
+				continue
+			}
+			has, err := storage.HasItemUnitsByUnitAndHashLocator(db, s.ID(), s.HashLocator(locator), t)
+			if err != nil {
+				log.Error(ctx, "azureblob: unable to check if unit item exist for blob %s: %v", blobName, err)
+				continue
+			}
+			if has {
+				continue
+			}
+			if !dryRun {
+				if _, err := s.client.DeleteBlob(ctx, s.config.ContainerName, blobName, nil); err != nil {
+					log.Error(ctx, "azureblob: unable to remove blob %s: %v", blobName, err)
+					continue
+				}
+				log.Info(ctx, "azureblob: blob %s has been deleted", blobName)
+			} else {
+				log.Info(ctx, "azureblob: blob %s should be deleted", blobName)
+			}
+		}
+	}
+}
@@ -14,8 +14,23 @@ import (
 )
 
 type ItemToSync struct {
-	ItemID  string    `db:"id"`
-	Created time.Time `db:"created"`
+	ItemID  string          `db:"id"`
+	Type    sdk.CDNItemType `db:"type"`
+	Created time.Time       `db:"created"`
+}
+
+// ItemUnitLocations is an item unit with the ids of all the units that store the item
+type ItemUnitLocations struct {
+	ID      string         `db:"id"`
+	ItemID  string         `db:"item_id"`
+	Created time.Time      `db:"created"`
+	UnitIDs pq.StringArray `db:"unit_ids"`
+}
+
+// UnitSize is the size of the items stored on a unit
+type UnitSize struct {
+	UnitName string `db:"unit_name"`
+	Size     int64  `db:"size"`
 }
 
 func getUnit(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, q gorpmapper.Query) (*sdk.CDNUnit, error) {
@@ -137,13 +152,13 @@ func DeleteItemUnit(m *gorpmapper.Mapper, db gorpmapper.SqlExecutorWithTx, iu *s
 	return nil
 }
 
-func LoadAllSynchronizedItemIDs(db gorp.SqlExecutor, bufferUnitID string, maxStorageCount int64) ([]string, error) {
+func LoadAllSynchronizedItemIDs(db gorp.SqlExecutor, bufferUnitID string, maxStorageCount int64, excludedTypes []string) ([]string, error) {
 	var itemIDs []string
 	query := `
 	WITH inBuffer as (
 		SELECT item_id
 		FROM storage_unit_item
-		WHERE unit_id = $2 AND last_modified < NOW() - INTERVAL '15 minutes'
+		WHERE unit_id = $2 AND last_modified < NOW() - INTERVAL '15 minutes' AND NOT type = ANY($3)
 	)
 	SELECT item_id
 	FROM storage_unit_item
@@ -151,12 +166,71 @@ func LoadAllSynchronizedItemIDs(db gorp.SqlExecutor, bufferUnitID string, maxSto
 	GROUP BY item_id
 	HAVING COUNT(unit_id) >= $1
 	`
-	if _, err := db.Select(&itemIDs, query, maxStorageCount, bufferUnitID); err != nil {
+	if excludedTypes == nil {
+		excludedTypes = []string{}
+	}
+	if _, err := db.Select(&itemIDs, query, maxStorageCount, bufferUnitID, pq.StringArray(excludedTypes)); err != nil {
 		return nil, sdk.WrapError(err, "unable to get item ids")
 	}
 	return itemIDs, nil
 }
 
+// LoadBufferedItemUnitsByType returns the item units of the given type in the buffer, with the units that store their item
+func LoadBufferedItemUnitsByType(db gorp.SqlExecutor, bufferUnitID string, itemType sdk.CDNItemType) ([]ItemUnitLocations, error) {
+	var res []ItemUnitLocations
+	query := `
+	SELECT buffer.id, buffer.item_id, item.created, array_agg(sui.unit_id) AS unit_ids
+	FROM storage_unit_item buffer
+	JOIN item ON item.id = buffer.item_id
+	JOIN storage_unit_item sui ON sui.item_id = buffer.item_id AND sui.unit_id <> buffer.unit_id AND sui.to_delete = false
+	WHERE buffer.unit_id = $1 AND buffer.type = $2 AND buffer.to_delete = false AND buffer.last_modified < NOW() - INTERVAL '15 minutes'
+	GROUP BY buffer.id, buffer.item_id, item.created
+	`
+	if _, err := db.Select(&res, query, bufferUnitID, itemType); err != nil {
+		return nil, sdk.WrapError(err, "unable to get buffered item units")
+	}
+	return res, nil
+}
+
+// LoadItemUnitsByUnitAndTypeOlderThan returns the item units of the given type on a unit for items older than the given number of days, with the units that store their item.
+// Item units are sorted by item creation date then id, only the item units after the given cursor are returned.
+func LoadItemUnitsByUnitAndTypeOlderThan(db gorp.SqlExecutor, unitID string, itemType sdk.CDNItemType, days int, after ItemUnitLocations, limit int) ([]ItemUnitLocations, error) {
+	var res []ItemUnitLocations
+	query := `
+	SELECT iu.id, iu.item_id, item.created, array_agg(sui.unit_id) AS unit_ids
+	FROM storage_unit_item iu
+	JOIN item ON item.id = iu.item_id
+	JOIN storage_unit_item sui ON sui.item_id = iu.item_id AND sui.to_delete = false
+	WHERE iu.unit_id = $1 AND iu.type = $2 AND iu.to_delete = false AND item.created < NOW() - $3 * INTERVAL '1 day'
+	AND (item.created, iu.id) > ($4, $5)
+	GROUP BY iu.id, iu.item_id, item.created
+	ORDER BY item.created ASC, iu.id ASC
+	LIMIT $6
+	`
+	if _, err := db.Select(&res, query, unitID, itemType, days, after.Created, after.ID, limit); err != nil {
+		return nil, sdk.WrapError(err, "unable to get expired item units")
+	}
+	return res, nil
+}
+
+// ComputeSizeByProjectKeyByUnit returns the size used by a project on each unit
+func ComputeSizeByProjectKeyByUnit(db gorp.SqlExecutor, projectKey string) ([]UnitSize, error) {
+	var res []UnitSize
+	query := `
+	SELECT storage_unit.name AS unit_name, COALESCE(SUM(item.size), 0) AS size
+	FROM storage_unit_item
+	JOIN item ON item.id = storage_unit_item.item_id
+	JOIN storage_unit ON storage_unit.id = storage_unit_item.unit_id
+	WHERE item.api_ref->>'project_key' = $1 AND storage_unit_item.to_delete = false
+	GROUP BY storage_unit.name
+	ORDER BY storage_unit.name
+	`
+	if _, err := db.Select(&res, query, projectKey); err != nil {
+		return nil, sdk.WrapError(err, "unable to compute size by unit")
+	}
+	return res, nil
+}
+
 func LoadLastLogItemUnitByNodeJobRunIDOrRunJobID(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, unitID string, nodeJobRunIDOrRunJobID string, opts ...gorpmapper.GetOptionFunc) (*sdk.CDNItemUnit, error) {
 	url := `
 		SELECT sui.* FROM storage_unit_item  sui
@@ -193,6 +267,31 @@ func HasItemUnitsByUnitAndHashLocator(db gorp.SqlExecutor, unitID string, hashLo
 	return len(ids) > 0, sdk.WithStack(err)
 }
 
+// CountItemUnitsByUnitAndHashLocator returns the count of item units that reference the content with given hash locator on a unit
+func CountItemUnitsByUnitAndHashLocator(db gorp.SqlExecutor, unitID string, hashLocator string, itemType sdk.CDNItemType) (int64, error) {
+	query := "SELECT COUNT(id) FROM storage_unit_item WHERE unit_id = $1 AND hash_locator = $2 AND type = $3 AND to_delete = false"
+	nb, err := db.SelectInt(query, unitID, hashLocator, itemType)
+	return nb, sdk.WithStack(err)
+}
+
+// LoadCompletedItemUnitByUnitAndHashLocator returns an item unit of a completed item of the given project that holds the content with given hash locator on a unit
+func LoadCompletedItemUnitByUnitAndHashLocator(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, unitID string, hashLocator string, itemType sdk.CDNItemType, projectKey string, opts ...gorpmapper.GetOptionFunc) (*sdk.CDNItemUnit, error) {
+	query := gorpmapper.NewQuery(`
+		SELECT storage_unit_item.*
+		FROM storage_unit_item
+		JOIN item ON item.id = storage_unit_item.item_id
+		WHERE storage_unit_item.unit_id = $1
+		AND storage_unit_item.hash_locator = $2
+		AND storage_unit_item.type = $3
+		AND storage_unit_item.to_delete = false
+		AND item.api_ref->>'project_key' = $4
+		AND item.status = $5
+		AND item.to_delete = false
+		LIMIT 1
+	`).Args(unitID, hashLocator, itemType, projectKey, sdk.CDNStatusItemCompleted)
+	return getItemUnit(ctx, m, db, query, opts...)
+}
+
 func HashItemUnitByApiRefHash(db gorp.SqlExecutor, apiRefHash string, unitID string) (bool, error) {
 	query := `
 		SELECT count(sui.id) FROM storage_unit_item sui
@@ -276,6 +375,38 @@ func LoadAllItemUnitsIDsByUnitID(db gorp.SqlExecutor, unitID string, offset, lim
 	return IDs, nil
 }
 
+// LoadItemUnitsIDsByUnitIDAfterID returns the ids of the item units of a unit that are greater than the given id, ordered by id.
+// Paging on the last loaded id keeps working while item units are marked as to delete.
+func LoadItemUnitsIDsByUnitIDAfterID(db gorp.SqlExecutor, unitID, lastID string, limit int64) ([]string, error) {
+	var IDs []string
+	query := "SELECT id FROM storage_unit_item WHERE unit_id = $1 AND to_delete = false AND id > $2 ORDER BY id ASC LIMIT $3"
+	if _, err := db.Select(&IDs, query, unitID, lastID, limit); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return IDs, nil
+}
+
+// LoadRandomItemUnitsIDsByUnitID returns the ids of a random sample of the item units of a unit
+func LoadRandomItemUnitsIDsByUnitID(db gorp.SqlExecutor, unitID string, limit int64) ([]string, error) {
+	var IDs []string
+	query := "SELECT id FROM storage_unit_item WHERE unit_id = $1 AND to_delete = false ORDER BY random() LIMIT $2"
+	if _, err := db.Select(&IDs, query, unitID, limit); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return IDs, nil
+}
+
+// MarkItemUnitsToDeleteByUnitAndHashLocator marks all the item units that reference the content with given hash locator on a unit
+// as to delete, and returns the ids of their items
+func MarkItemUnitsToDeleteByUnitAndHashLocator(db gorpmapper.SqlExecutorWithTx, unitID string, hashLocator string, itemType sdk.CDNItemType) ([]string, error) {
+	var itemIDs []string
+	query := "UPDATE storage_unit_item SET to_delete = true WHERE unit_id = $1 AND hash_locator = $2 AND type = $3 AND to_delete = false RETURNING item_id"
+	if _, err := db.Select(&itemIDs, query, unitID, hashLocator, itemType); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return itemIDs, nil
+}
+
 func LoadAllItemUnitsByItemIDs(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, itemID string, opts ...gorpmapper.GetAllOptionFunc) ([]sdk.CDNItemUnit, error) {
 	query := gorpmapper.NewQuery("SELECT * FROM storage_unit_item WHERE item_id = $1 AND to_delete = false").Args(itemID)
 	allItemUnits, err := getAllItemUnits(ctx, m, db, query, opts...)
@@ -341,7 +472,7 @@ func LoadAllItemIDUnknownByUnit(db gorp.SqlExecutor, unitID string, offset int64
     		FROM storage_unit_item
     		WHERE unit_id = $1
 		)
-		SELECT item.id, item.created
+		SELECT item.id, item.type, item.created
 		FROM item
 		LEFT JOIN inUnit on item.id = inUnit.item_id
 		WHERE inUnit.unit_id is NULL AND item.status = $2 AND item.to_delete = false
@@ -127,3 +127,60 @@ func TestLoadAllItemIDUnknownByUnit(t *testing.T) {
 	require.Equal(t, 3, len(itemIDS))
 
 }
+
+func TestLoadCompletedItemUnitByUnitAndHashLocator(t *testing.T) {
+	m := gorpmapper.New()
+	item.InitDBMapping(m)
+	storage.InitDBMapping(m)
+	db, _ := test.SetupPGWithMapper(t, m, sdk.TypeCDN)
+
+	cdntest.ClearItem(t, context.TODO(), m, db)
+	cdntest.ClearUnits(t, context.TODO(), m, db)
+
+	unit := sdk.CDNUnit{ID: sdk.UUID(), Name: "local_storage", Created: time.Now()}
+	require.NoError(t, storage.InsertUnit(context.TODO(), m, db, &unit))
+
+	newItem := func(projKey string, status string) sdk.CDNItem {
+		i := sdk.CDNItem{
+			ID:         sdk.UUID(),
+			APIRefHash: sdk.RandomString(10),
+			APIRef:     &sdk.CDNLogAPIRefV2{ProjectKey: projKey},
+			Type:       sdk.CDNTypeItemJobStepLog,
+			Status:     status,
+		}
+		require.NoError(t, item.Insert(context.TODO(), m, db, &i))
+		return i
+	}
+	newItemUnit := func(i sdk.CDNItem, hashLocator string) sdk.CDNItemUnit {
+		iu := sdk.CDNItemUnit{
+			ID:          sdk.UUID(),
+			ItemID:      i.ID,
+			UnitID:      unit.ID,
+			Type:        i.Type,
+			HashLocator: hashLocator,
+		}
+		require.NoError(t, storage.InsertItemUnit(context.TODO(), m, db, &iu))
+		return iu
+	}
+
+	i1 := newItem("PROJ1", sdk.CDNStatusItemCompleted)
+	iu1 := newItemUnit(i1, "hash1")
+	i2 := newItem("PROJ2", sdk.CDNStatusItemCompleted)
+	newItemUnit(i2, "hash1")
+	i3 := newItem("PROJ1", sdk.CDNStatusItemIncoming)
+	newItemUnit(i3, "hash2")
+
+	res, err := storage.LoadCompletedItemUnitByUnitAndHashLocator(context.TODO(), m, db, unit.ID, "hash1", sdk.CDNTypeItemJobStepLog, "PROJ1")
+	require.NoError(t, err)
+	require.Equal(t, iu1.ID, res.ID)
+
+	_, err = storage.LoadCompletedItemUnitByUnitAndHashLocator(context.TODO(), m, db, unit.ID, "hash2", sdk.CDNTypeItemJobStepLog, "PROJ1")
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	_, err = storage.LoadCompletedItemUnitByUnitAndHashLocator(context.TODO(), m, db, unit.ID, "hash1", sdk.CDNTypeItemJobStepLog, "PROJ3")
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	nb, err := storage.CountItemUnitsByUnitAndHashLocator(db, unit.ID, "hash1", sdk.CDNTypeItemJobStepLog)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), nb)
+}
@@ -0,0 +1,195 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	gcstorage "cloud.google.com/go/storage"
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/engine/cdn/storage/encryption"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+type GCS struct {
+	client *gcstorage.Client
+	storage.AbstractUnit
+	encryption.ConvergentEncryption
+	config storage.GCSStorageConfiguration
+}
+
+var (
+	_ storage.StorageUnit = new(GCS)
+)
+
+const driverName = "gcs"
+
+// resyncMinAge is the minimum age of an object to be removed by a resynchronization, younger objects can be under synchronization
+const resyncMinAge = time.Hour
+
+func init() {
+	storage.RegisterDriver(driverName, new(GCS))
+}
+
+func (s *GCS) GetDriverName() string {
+	return driverName
+}
+
+func (s *GCS) Init(ctx context.Context, cfg interface{}) error {
+	config, is := cfg.(*storage.GCSStorageConfiguration)
+	if !is {
+		return sdk.WithStack(fmt.Errorf("invalid configuration: %T", cfg))
+	}
+	s.config = *config
+	s.ConvergentEncryption = encryption.New(config.Encryption)
+
+	var opts []option.ClientOption
+	if config.CredentialsFile != "" {
+		opts = append(opts, option.WithAuthCredentialsFile(option.ServiceAccount, config.CredentialsFile))
+	}
+	// If a custom endpoint is set, use it (eg. fake-gcs-server)
+	if config.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(config.Endpoint))
+	}
+	if config.DisableAuth {
+		opts = append(opts, option.WithoutAuthentication())
+	}
+
+	client, err := gcstorage.NewClient(ctx, opts...)
+	if err != nil {
+		return sdk.WrapError(err, "unable to create google cloud storage client")
+	}
+	s.client = client
+
+	_, err = s.bucket().Attrs(ctx)
+	return sdk.WithStack(err)
+}
+
+func (s *GCS) bucket() *gcstorage.BucketHandle {
+	return s.client.Bucket(s.config.BucketName)
+}
+
+func (s *GCS) ItemExists(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, i sdk.CDNItem) (bool, error) {
+	iu, err := s.ExistsInDatabase(ctx, m, db, i.ID)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	objectName := s.getObjectName(*iu)
+	if _, err := s.bucket().Object(objectName).Attrs(ctx); err != nil {
+		if errors.Is(err, gcstorage.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, sdk.WrapError(err, "unable to get attributes of object %s", objectName)
+	}
+	return true, nil
+}
+
+func (s *GCS) NewWriter(ctx context.Context, i sdk.CDNItemUnit) (io.WriteCloser, error) {
+	objectName := s.getObjectName(i)
+	log.Debug(ctx, "[%T] writing to %s", s, objectName)
+
+	// Data is uploaded while it is written, the upload is completed on Close
+	return s.bucket().Object(objectName).NewWriter(ctx), nil
+}
+
+func (s *GCS) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.ReadCloser, error) {
+	objectName := s.getObjectName(i)
+	log.Debug(ctx, "[%T] reading from %s", s, objectName)
+
+	r, err := s.bucket().Object(objectName).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, gcstorage.ErrObjectNotExist) {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WithStack(err)
+	}
+
+	return r, nil
+}
+
+func (s *GCS) objectPrefix(itemType sdk.CDNItemType) string {
+	return path.Join(s.config.Prefix, string(itemType)) + "/"
+}
+
+func (s *GCS) getObjectName(i sdk.CDNItemUnit) string {
+	return s.objectPrefix(i.Item.Type) + url.PathEscape(i.Locator)
+}
+
+// Status returns the status of the gcs bucket
+func (s *GCS) Status(ctx context.Context) []sdk.MonitoringStatusLine {
+	if _, err := s.bucket().Attrs(ctx); err != nil {
+		return []sdk.MonitoringStatusLine{{Component: "backend/" + s.Name(), Value: "GCS KO" + err.Error(), Status: sdk.MonitoringStatusAlert}}
+	}
+	return []sdk.MonitoringStatusLine{{
+		Component: "backend/" + s.Name(),
+		Value:     fmt.Sprintf("GCS OK (bucket %s)", s.config.BucketName),
+		Status:    sdk.MonitoringStatusOK,
+	}}
+}
+
+func (s *GCS) Remove(ctx context.Context, i sdk.CDNItemUnit) error {
+	objectName := s.getObjectName(i)
+	if err := s.bucket().Object(objectName).Delete(ctx); err != nil {
+		if errors.Is(err, gcstorage.ErrObjectNotExist) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return sdk.WrapError(err, "unable to delete object %s", objectName)
+	}
+	return nil
+}
+
+// ResyncWithDatabase removes the objects of the given type that are not referenced by any item unit
+func (s *GCS) ResyncWithDatabase(ctx context.Context, db gorp.SqlExecutor, t sdk.CDNItemType, dryRun bool) {
+	prefix := s.objectPrefix(t)
+	it := s.bucket().Objects(ctx, &gcstorage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return
+		}
+		if err != nil {
+			log.Error(ctx, "gcs: unable to list objects with prefix %s: %v", prefix, err)
+			return
+		}
+		if time.Since(attrs.Updated) < resyncMinAge {
+			continue
+		}
+		locator, err := url.PathUnescape(strings.TrimPrefix(attrs.Name, prefix))
+		if err != nil {
+			log.Warn(ctx, "gcs: invalid object name %s: %v", attrs.Name, err)
+			continue
+		}
+		has, err := storage.HasItemUnitsByUnitAndHashLocator(db, s.ID(), s.HashLocator(locator), t)
+		if err != nil {
+			log.Error(ctx, "gcs: unable to check if unit item exist for object %s: %v", attrs.Name, err)
+			continue
+		}
+		if has {
+			continue
+		}
+		if !dryRun {
+			if err := s.bucket().Object(attrs.Name).Delete(ctx); err != nil {
+				log.Error(ctx, "gcs: unable to remove object %s: %v", attrs.Name, err)
+				continue
+			}
+			log.Info(ctx, "gcs: object %s has been deleted", attrs.Name)
+		} else {
+			log.Info(ctx, "gcs: object %s should be deleted", attrs.Name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package gcs
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/ovh/cds/engine/cdn/storage"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/symmecrypt/ciphers/aesgcm"
+	"github.com/ovh/symmecrypt/convergent"
+	"github.com/rockbears/log"
+	"github.com/stretchr/testify/require"
+)
+
+// To run the test, run the make fake_gcs_start from the tests directory
+// Then export the mentionned env variables: GCS_BUCKET and GCS_ENDPOINT
+// If not set, the test is skipped
+func TestGCS(t *testing.T) {
+	log.Factory = log.NewTestingWrapper(t)
+	if os.Getenv("GCS_ENDPOINT") == "" {
+		t.Logf("skipping this test: GCS_ENDPOINT is not set")
+		t.SkipNow()
+	}
+
+	var driver = new(GCS)
+	err := driver.Init(context.TODO(), &storage.GCSStorageConfiguration{
+		BucketName:  os.Getenv("GCS_BUCKET"),
+		Endpoint:    os.Getenv("GCS_ENDPOINT"),
+		DisableAuth: true,
+		Prefix:      "tests",
+		Encryption: []convergent.ConvergentEncryptionConfig{
+			{
+				Cipher:      aesgcm.CipherName,
+				LocatorSalt: "secret_locator_salt",
+				SecretValue: "secret_value",
+			},
+		},
+	})
+	require.NoError(t, err, "unable to initialiaze gcs driver")
+
+	itemUnit := sdk.CDNItemUnit{
+		Locator: "a_locator",
+		Item: &sdk.CDNItem{
+			Type: sdk.CDNTypeItemStepLog,
+		},
+	}
+	w, err := driver.NewWriter(context.TODO(), itemUnit)
+	require.NoError(t, err)
+	require.NotNil(t, w)
+
+	_, err = w.Write([]byte("something"))
+	require.NoError(t, err)
+
+	err = w.Close()
+	require.NoError(t, err)
+
+	r, err := driver.NewReader(context.TODO(), itemUnit)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	btes, err := io.ReadAll(r)
+	require.NoError(t, err)
+	err = r.Close()
+	require.NoError(t, err)
+
+	require.Equal(t, "something", string(btes))
+
+	require.NoError(t, driver.Remove(context.TODO(), itemUnit))
+	err = driver.Remove(context.TODO(), itemUnit)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func checkLifecycleConfiguration(config Configuration) error {
+	itemTypes := make(map[sdk.CDNItemType]struct{}, len(config.Lifecycle))
+	for _, rule := range config.Lifecycle {
+		if err := rule.ItemType.Validate(); err != nil {
+			return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Invalid lifecycle item type %q", rule.ItemType))
+		}
+		if _, has := itemTypes[rule.ItemType]; has {
+			return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Duplicated lifecycle rule for item type %q", rule.ItemType))
+		}
+		itemTypes[rule.ItemType] = struct{}{}
+
+		if rule.DeleteAfterDays < 0 {
+			return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Invalid deleteAfterDays for item type %q", rule.ItemType))
+		}
+		if len(rule.Tiers) == 0 {
+			return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Missing lifecycle tiers for item type %q", rule.ItemType))
+		}
+		for _, tier := range rule.Tiers {
+			if _, has := config.Storages[tier.Unit]; !has {
+				return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Unknown storage unit %q in lifecycle rule for item type %q", tier.Unit, rule.ItemType))
+			}
+			if tier.MaxAgeDays < 0 {
+				return sdk.WithStack(fmt.Errorf("invalid CDN configuration. Invalid maxAgeDays for storage unit %q in lifecycle rule for item type %q", tier.Unit, rule.ItemType))
+			}
+		}
+	}
+	return nil
+}
+
+// Tier returns the tier of the rule for the given storage unit
+func (r LifecycleRule) Tier(unitName string) (LifecycleTier, bool) {
+	for _, t := range r.Tiers {
+		if t.Unit == unitName {
+			return t, true
+		}
+	}
+	return LifecycleTier{}, false
+}
+
+// Keeps returns true if the tier should store an item created at the given date
+func (t LifecycleTier) Keeps(created time.Time) bool {
+	return t.MaxAgeDays == 0 || time.Since(created) < time.Duration(t.MaxAgeDays)*24*time.Hour
+}
+
+// LifecycleRule returns the lifecycle rule for the given item type
+func (x RunningStorageUnits) LifecycleRule(itemType sdk.CDNItemType) (LifecycleRule, bool) {
+	for _, r := range x.config.Lifecycle {
+		if r.ItemType == itemType {
+			return r, true
+		}
+	}
+	return LifecycleRule{}, false
+}
+
+// ShouldStore returns true if the storage unit should store an item of the given type created at the given date
+func (x RunningStorageUnits) ShouldStore(unitName string, itemType sdk.CDNItemType, created time.Time) bool {
+	rule, has := x.LifecycleRule(itemType)
+	if !has {
+		return true
+	}
+	tier, has := rule.Tier(unitName)
+	if !has {
+		return false
+	}
+	return tier.Keeps(created)
+}
+
+// IsSynchronized returns true if the item is stored on every storage unit that should store it
+func (x RunningStorageUnits) IsSynchronized(itemType sdk.CDNItemType, created time.Time, unitIDs []string) bool {
+	var hasStorageUnit bool
+	for _, s := range x.Storages {
+		if sdk.IsInArray(s.ID(), unitIDs) {
+			hasStorageUnit = true
+			continue
+		}
+		if s.CanSync() && x.ShouldStore(s.Name(), itemType, created) {
+			return false
+		}
+	}
+	// At least one copy must exist outside the buffers
+	return hasStorageUnit
+}
+
+// ExpireItemUnits marks to delete the item units that are older than the maximum age of their tier.
+// An item unit is kept if the item is not yet stored on another tier that should keep it.
+func (x *RunningStorageUnits) ExpireItemUnits(ctx context.Context, limit int) error {
+	for _, rule := range x.config.Lifecycle {
+		for _, tier := range rule.Tiers {
+			if tier.MaxAgeDays == 0 {
+				continue
+			}
+			s := x.Storage(tier.Unit)
+			if s == nil {
+				continue
+			}
+			if err := x.expireItemUnits(ctx, rule, tier, s, limit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (x *RunningStorageUnits) expireItemUnits(ctx context.Context, rule LifecycleRule, tier LifecycleTier, s StorageUnit, limit int) error {
+	// Item units that can't be expired yet are skipped with a cursor, so the next ones are checked
+	var cursor ItemUnitLocations
+	for {
+		itemUnits, err := LoadItemUnitsByUnitAndTypeOlderThan(x.db, s.ID(), rule.ItemType, tier.MaxAgeDays, cursor, limit)
+		if err != nil {
+			return err
+		}
+		if len(itemUnits) == 0 {
+			return nil
+		}
+		if err := x.markExpiredItemUnits(ctx, rule, s, itemUnits); err != nil {
+			return err
+		}
+		if len(itemUnits) < limit {
+			return nil
+		}
+		cursor = itemUnits[len(itemUnits)-1]
+	}
+}
+
+func (x *RunningStorageUnits) markExpiredItemUnits(ctx context.Context, rule LifecycleRule, s StorageUnit, itemUnits []ItemUnitLocations) error {
+	var ids []string
+	for _, iu := range itemUnits {
+		// Find another tier that keeps the item
+		var keptElsewhere bool
+		for _, otherUnitID := range iu.UnitIDs {
+			other := x.storageByID(otherUnitID)
+			if other == nil || other.ID() == s.ID() {
+				continue
+			}
+			if otherTier, has := rule.Tier(other.Name()); has && otherTier.Keeps(iu.Created) {
+				keptElsewhere = true
+				break
+			}
+		}
+		if !keptElsewhere {
+			log.Debug(ctx, "item %s is expired on %s but it is not yet stored on another tier", iu.ItemID, s.Name())
+			continue
+		}
+		ids = append(ids, iu.ID)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := x.db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	n, err := MarkItemUnitToDelete(tx, ids)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+	log.Info(ctx, "lifecycle: %d items of type %s expired on %s", n, rule.ItemType, s.Name())
+	return nil
+}
+
+func (x RunningStorageUnits) storageByID(id string) StorageUnit {
+	for _, s := range x.Storages {
+		if s.ID() == id {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestCheckLifecycleConfiguration(t *testing.T) {
+	config := Configuration{
+		Storages: map[string]StorageConfiguration{
+			"s3":    {},
+			"swift": {},
+		},
+		Lifecycle: []LifecycleRule{
+			{
+				ItemType:        sdk.CDNTypeItemWorkerCacheV2,
+				Tiers:           []LifecycleTier{{Unit: "s3", MaxAgeDays: 30}, {Unit: "swift"}},
+				DeleteAfterDays: 365,
+			},
+		},
+	}
+	require.NoError(t, checkLifecycleConfiguration(config))
+
+	config.Lifecycle = append(config.Lifecycle, LifecycleRule{ItemType: sdk.CDNTypeItemWorkerCacheV2, Tiers: []LifecycleTier{{Unit: "s3"}}})
+	require.Error(t, checkLifecycleConfiguration(config))
+
+	config.Lifecycle = []LifecycleRule{{ItemType: sdk.CDNTypeItemRunResultV2, Tiers: []LifecycleTier{{Unit: "unknown"}}}}
+	require.Error(t, checkLifecycleConfiguration(config))
+
+	config.Lifecycle = []LifecycleRule{{ItemType: sdk.CDNTypeItemRunResultV2}}
+	require.Error(t, checkLifecycleConfiguration(config))
+
+	config.Lifecycle = []LifecycleRule{{ItemType: "unknown", Tiers: []LifecycleTier{{Unit: "s3"}}}}
+	require.Error(t, checkLifecycleConfiguration(config))
+}
+
+func TestShouldStore(t *testing.T) {
+	x := RunningStorageUnits{
+		config: Configuration{
+			Lifecycle: []LifecycleRule{
+				{
+					ItemType: sdk.CDNTypeItemWorkerCacheV2,
+					Tiers:    []LifecycleTier{{Unit: "s3", MaxAgeDays: 30}, {Unit: "swift"}},
+				},
+			},
+		},
+	}
+
+	recent := time.Now().Add(-24 * time.Hour)
+	old := time.Now().Add(-31 * 24 * time.Hour)
+
+	// Items without rule are stored everywhere
+	require.True(t, x.ShouldStore("s3", sdk.CDNTypeItemRunResultV2, old))
+	require.True(t, x.ShouldStore("other", sdk.CDNTypeItemRunResultV2, old))
+
+	require.True(t, x.ShouldStore("s3", sdk.CDNTypeItemWorkerCacheV2, recent))
+	require.False(t, x.ShouldStore("s3", sdk.CDNTypeItemWorkerCacheV2, old))
+	require.True(t, x.ShouldStore("swift", sdk.CDNTypeItemWorkerCacheV2, old))
+	require.False(t, x.ShouldStore("other", sdk.CDNTypeItemWorkerCacheV2, recent))
+}
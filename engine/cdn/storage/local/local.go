@@ -111,6 +111,9 @@ func (s *AbstractLocal) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.Re
 	}
 	log.Debug(ctx, "[%T] reading from %s", s, path)
 	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
 	return f, sdk.WithStack(err)
 }
 
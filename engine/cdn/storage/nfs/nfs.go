@@ -231,6 +231,9 @@ func (n *Buffer) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.ReadClose
 	}
 	log.Debug(ctx, "[%T] reading from %s", n, path)
 	f, err := target.Open(path)
+	if os.IsNotExist(err) {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
 
 	nfsReader := &Reader{ctx: ctx, dialMount: dial, target: target, reader: f}
 
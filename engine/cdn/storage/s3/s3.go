@@ -11,6 +11,7 @@ import (
 	"github.com/rockbears/log"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	s3session "github.com/aws/aws-sdk-go/aws/session"
@@ -159,6 +160,9 @@ func (s *S3) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.ReadCloser, e
 	})
 
 	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
 		return nil, sdk.WithStack(err)
 	}
 
@@ -9,6 +9,7 @@ import (
 
 	"github.com/rockbears/log"
 
+	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/sdk"
 )
@@ -123,6 +124,29 @@ func (r RunningStorageUnits) NewSource(ctx context.Context, refItemUnit sdk.CDNI
 	return &iuSource{iu: refItemUnit, source: unit}, nil
 }
 
+// CountItemUnitsByLocatorByUnit returns the count of references to the content with given locator on a unit
+func (r RunningStorageUnits) CountItemUnitsByLocatorByUnit(locator string, unitID string, itemType sdk.CDNItemType) (int64, error) {
+	return CountItemUnitsByUnitAndHashLocator(r.db, unitID, r.HashLocator(locator), itemType)
+}
+
+// LockLocator prevents the content with given locator on a unit to be removed while a reference is added or removed.
+// The returned func releases the lock.
+func (r RunningStorageUnits) LockLocator(ctx context.Context, locator string, unitID string) (func(), error) {
+	lockKey := cache.Key("cdn", "locator", "lock", unitID, r.HashLocator(locator))
+	hasLock, err := r.cache.Lock(lockKey, 20*time.Minute, 100, 50)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to lock %s", lockKey)
+	}
+	if !hasLock {
+		return nil, sdk.WithStack(sdk.ErrLocked)
+	}
+	return func() {
+		if err := r.cache.Unlock(lockKey); err != nil {
+			log.Error(ctx, "unable to release lock %s: %v", lockKey, err)
+		}
+	}, nil
+}
+
 func (r RunningStorageUnits) GetItemUnitByLocatorByUnit(locator string, unitID string, itemType sdk.CDNItemType) (bool, error) {
 	// Load all the itemUnit for the unit and the same hashLocator
 	hashLocator := r.HashLocator(locator)
@@ -57,6 +57,10 @@ func Init(ctx context.Context, m *gorpmapper.Mapper, store cache.Store, db *gorp
 		return nil, sdk.WithStack(fmt.Errorf("invalid CDN configuration. HashLocatorSalt is too short"))
 	}
 
+	if err := checkLifecycleConfiguration(config); err != nil {
+		return nil, err
+	}
+
 	countLogBuffer := 0
 	countFileBuffer := 0
 	for _, bu := range config.Buffers {
@@ -192,7 +196,7 @@ func Init(ctx context.Context, m *gorpmapper.Mapper, store cache.Store, db *gorp
 			if !is {
 				return nil, sdk.WithStack(fmt.Errorf("local driver is not a storage unit driver"))
 			}
-			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync}) // convert from MBytes to Bytes
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
 
 			if err := sd.Init(ctx, cfg.Local); err != nil {
 				return nil, err
@@ -205,7 +209,7 @@ func Init(ctx context.Context, m *gorpmapper.Mapper, store cache.Store, db *gorp
 			if !is {
 				return nil, sdk.WithStack(fmt.Errorf("swift driver is not a storage unit driver"))
 			}
-			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync}) // convert from MBytes to Bytes
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
 
 			if err := sd.Init(ctx, cfg.Swift); err != nil {
 				return nil, err
@@ -218,7 +222,7 @@ func Init(ctx context.Context, m *gorpmapper.Mapper, store cache.Store, db *gorp
 			if !is {
 				return nil, sdk.WithStack(fmt.Errorf("webdav driver is not a storage unit driver"))
 			}
-			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync}) // convert from MBytes to Bytes
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
 
 			if err := sd.Init(ctx, cfg.Webdav); err != nil {
 				return nil, err
@@ -231,12 +235,38 @@ func Init(ctx context.Context, m *gorpmapper.Mapper, store cache.Store, db *gorp
 			if !is {
 				return nil, sdk.WithStack(fmt.Errorf("s3 driver is not a storage unit driver"))
 			}
-			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync}) // convert from MBytes to Bytes
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
 
 			if err := sd.Init(ctx, cfg.S3); err != nil {
 				return nil, err
 			}
 			storageUnit = sd
+		case cfg.AzureBlob != nil:
+			log.Info(ctx, "Initializing azureblob backend...")
+			d := GetDriver("azureblob")
+			sd, is := d.(StorageUnit)
+			if !is {
+				return nil, sdk.WithStack(fmt.Errorf("azureblob driver is not a storage unit driver"))
+			}
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
+
+			if err := sd.Init(ctx, cfg.AzureBlob); err != nil {
+				return nil, err
+			}
+			storageUnit = sd
+		case cfg.GCS != nil:
+			log.Info(ctx, "Initializing gcs backend...")
+			d := GetDriver("gcs")
+			sd, is := d.(StorageUnit)
+			if !is {
+				return nil, sdk.WithStack(fmt.Errorf("gcs driver is not a storage unit driver"))
+			}
+			sd.New(gorts, AbstractUnitConfig{syncBandwidth: float64(cfg.SyncBandwidth) * 1024 * 1024, syncParrallel: cfg.SyncParallel, disableSync: cfg.DisableSync, hashLocatorSalt: config.HashLocatorSalt}) // convert from MBytes to Bytes
+
+			if err := sd.Init(ctx, cfg.GCS); err != nil {
+				return nil, err
+			}
+			storageUnit = sd
 		default:
 			return nil, sdk.WithStack(errors.New("unsupported storage unit"))
 		}
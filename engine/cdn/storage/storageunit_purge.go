@@ -33,57 +33,79 @@ func (x *RunningStorageUnits) Purge(ctx context.Context, s Interface) error {
 		ctx = context.WithValue(ctx, FieldAPIRef, ui.Item.APIRefHash)
 		ctx = context.WithValue(ctx, FieldSize, ui.Item.Size)
 
-		exists, err := s.ItemExists(ctx, x.m, x.db, *ui.Item)
+		if err := x.purgeItemUnit(ctx, s, ui); err != nil {
+			if sdk.ErrorIs(err, sdk.ErrLocked) {
+				log.Info(ctx, "item unit %s is locked on %s, it will be purged later", ui.ID, s.Name())
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// purgeItemUnit removes the content of the item unit from the unit if no other item unit references it, then deletes the item unit
+func (x *RunningStorageUnits) purgeItemUnit(ctx context.Context, s Interface, ui sdk.CDNItemUnit) error {
+	_, hasLocator := s.(StorageUnitWithLocator)
+	if hasLocator {
+		unlock, err := x.LockLocator(ctx, ui.Locator, s.ID())
 		if err != nil {
-			log.Error(ctx, "error on ItemExists: err:%s", err)
-			continue
+			return err
 		}
+		defer unlock()
+	}
 
-		if exists {
-			var hasItemUnit bool
-			if _, hasLocator := s.(StorageUnitWithLocator); hasLocator {
-				var err error
-				hasItemUnit, err = x.GetItemUnitByLocatorByUnit(ui.Locator, s.ID(), ui.Type)
-				if err != nil {
-					return err
-				}
-			}
+	exists, err := s.ItemExists(ctx, x.m, x.db, *ui.Item)
+	if err != nil {
+		log.Error(ctx, "error on ItemExists: err:%s", err)
+		return nil
+	}
 
-			if hasItemUnit {
-				log.Info(ctx, "item %s will not be deleted from %s", ui.ID, s.Name())
-			} else {
-				if err := s.Remove(ctx, ui); err != nil {
-					if sdk.ErrorIs(err, sdk.ErrNotFound) {
-						log.Info(ctx, "Item %s has already been deleted from %s", ui.ItemID, s.Name())
-						continue
-					}
-					ctx = sdk.ContextWithStacktrace(ctx, err)
-					log.Error(ctx, "unable to remove item %s on %s: %v", ui.ID, s.Name(), err)
-					continue
-				}
-				log.Info(ctx, "item %s deleted on %s", ui.ID, s.Name())
+	if exists {
+		// Content is shared between all the item units with the same locator, count the remaining references
+		var references int64
+		if hasLocator {
+			var err error
+			references, err = x.CountItemUnitsByLocatorByUnit(ui.Locator, s.ID(), ui.Type)
+			if err != nil {
+				return err
 			}
 		}
 
-		tx, err := x.db.Begin()
-		if err != nil {
-			return sdk.WithStack(err)
+		if references > 0 {
+			log.Info(ctx, "item %s will not be deleted from %s, content is still referenced %d times", ui.ID, s.Name(), references)
+		} else {
+			if err := s.Remove(ctx, ui); err != nil {
+				if sdk.ErrorIs(err, sdk.ErrNotFound) {
+					log.Info(ctx, "Item %s has already been deleted from %s", ui.ItemID, s.Name())
+					return nil
+				}
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "unable to remove item %s on %s: %v", ui.ID, s.Name(), err)
+				return nil
+			}
+			log.Info(ctx, "item %s deleted on %s", ui.ID, s.Name())
 		}
+	}
 
-		if err := DeleteItemUnit(x.m, tx, &ui); err != nil {
-			ctx = sdk.ContextWithStacktrace(ctx, err)
-			log.Error(ctx, "unable to delete item unit %s: %v", ui.ID, err)
-			_ = tx.Rollback() // nolint
-			continue
-		}
+	tx, err := x.db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
 
-		if err := tx.Commit(); err != nil {
-			_ = tx.Rollback() // nolint
-			return sdk.WithStack(err)
-		}
+	if err := DeleteItemUnit(x.m, tx, &ui); err != nil {
+		ctx = sdk.ContextWithStacktrace(ctx, err)
+		log.Error(ctx, "unable to delete item unit %s: %v", ui.ID, err)
+		_ = tx.Rollback() // nolint
+		return nil
+	}
 
-		log.Info(ctx, "item %s deleted on %s", ui.ID, s.Name())
+	if err := tx.Commit(); err != nil {
+		_ = tx.Rollback() // nolint
+		return sdk.WithStack(err)
 	}
 
+	log.Info(ctx, "item %s deleted on %s", ui.ID, s.Name())
 	return nil
 }
@@ -65,6 +65,9 @@ func (x *RunningStorageUnits) FillWithUnknownItems(ctx context.Context, s Storag
 		k := cache.Key(KeyBackendSync, s.Name())
 		for _, item := range itemsToSync {
 			ctx = context.WithValue(ctx, FielID, item.ItemID)
+			if !x.ShouldStore(s.Name(), item.Type, item.Created) {
+				continue
+			}
 			if err := x.cache.ScoredSetAdd(ctx, k, item.ItemID, float64(item.Created.Unix())); err != nil {
 				log.Error(ctx, "FillWithUnknownItems> unable to push item %s into %s", item.ItemID, k)
 				continue
@@ -98,6 +101,11 @@ func (x *RunningStorageUnits) processItem(ctx context.Context, db *gorp.DbMap, s
 	ctx = context.WithValue(ctx, FieldAPIRef, it.APIRefHash)
 	ctx = context.WithValue(ctx, FieldSize, it.Size)
 	log.Info(ctx, "processing item %s on %s", it.ID, s.Name())
+	if !x.ShouldStore(s.Name(), it.Type, it.Created) {
+		log.Info(ctx, "Item %s should not be stored on %s according to lifecycle rules", id, s.Name())
+		x.RemoveFromRedisSyncQueue(ctx, s, id)
+		return nil
+	}
 	if _, err = LoadItemUnitByUnit(ctx, x.m, db, s.ID(), id); err == nil {
 		log.Info(ctx, "Item %s already sync on %s", id, s.Name())
 		return nil
@@ -121,6 +129,13 @@ func (x *RunningStorageUnits) runItem(ctx context.Context, db *gorp.DbMap, dest
 	}
 	iu.Item = item
 
+	// Lock the content on the destination unit so it can't be purged until the item unit is saved
+	unlock, err := x.LockLocator(ctx, iu.Locator, dest.ID())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Check if the content (based on the locator) is already known from the destination unit
 	has, err := x.GetItemUnitByLocatorByUnit(iu.Locator, dest.ID(), iu.Type)
 	if err != nil {
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// VerifyItemUnit reads the content of the item unit from the unit and checks it against the hash of the item.
+// It returns an empty status if the content is healthy, or if it could not be verified because of an error of the unit.
+func VerifyItemUnit(ctx context.Context, u Unit, iu sdk.CDNItemUnit) (sdk.CDNItemUnitVerifyStatus, error) {
+	if iu.Item == nil {
+		return "", sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	reader, err := u.NewReader(ctx, iu)
+	if err != nil {
+		// Only a content that the unit does not have is missing, other errors (network, credentials...) may be transient
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return sdk.CDNItemUnitVerifyStatusMissing, err
+		}
+		return "", err
+	}
+	defer reader.Close() // nolint
+
+	h := sha512.New()
+	if err := u.Read(iu, reader, h); err != nil {
+		return sdk.CDNItemUnitVerifyStatusCorrupted, err
+	}
+	if hash := hex.EncodeToString(h.Sum(nil)); hash != iu.Item.Hash {
+		return sdk.CDNItemUnitVerifyStatusCorrupted, sdk.NewErrorFrom(sdk.ErrInvalidData, "hash mismatch")
+	}
+	return "", nil
+}
+
+// RepairItemUnit marks the damaged content of the item unit as to delete, then pushes the items that referenced it
+// in the synchronization queue of the unit to copy them again from another unit.
+func (x *RunningStorageUnits) RepairItemUnit(ctx context.Context, s StorageUnit, iu sdk.CDNItemUnit) error {
+	unlock, err := x.LockLocator(ctx, iu.Locator, s.ID())
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tx, err := x.db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	// With deduplication the content is shared by all the item units with the same locator, they are all damaged
+	itemIDs := []string{iu.ItemID}
+	if _, hasLocator := s.(StorageUnitWithLocator); hasLocator {
+		itemIDs, err = MarkItemUnitsToDeleteByUnitAndHashLocator(tx, s.ID(), iu.HashLocator, iu.Type)
+	} else {
+		_, err = MarkItemUnitToDelete(tx, []string{iu.ID})
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	k := cache.Key(KeyBackendSync, s.Name())
+	for _, id := range itemIDs {
+		if err := x.cache.ScoredSetAdd(ctx, k, id, float64(time.Now().Unix())); err != nil {
+			log.Error(ctx, "unable to push item %s into %s: %v", id, k, err)
+		}
+	}
+	return nil
+}
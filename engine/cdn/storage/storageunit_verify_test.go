@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/go-gorp/gorp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// memoryUnit is a unit that stores the content of the item units in memory
+type memoryUnit struct {
+	contents map[string][]byte
+}
+
+func (m memoryUnit) Read(_ sdk.CDNItemUnit, r io.Reader, w io.Writer) error {
+	_, err := io.Copy(w, r)
+	return err
+}
+
+func (m memoryUnit) NewReader(_ context.Context, i sdk.CDNItemUnit) (io.ReadCloser, error) {
+	content, has := m.contents[i.ID]
+	if !has {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (m memoryUnit) GetDriverName() string { return "memory" }
+
+func (m memoryUnit) ResyncWithDatabase(_ context.Context, _ gorp.SqlExecutor, _ sdk.CDNItemType, _ bool) {
+}
+
+// unavailableUnit is a unit that cannot be reached
+type unavailableUnit struct {
+	memoryUnit
+}
+
+func (unavailableUnit) NewReader(_ context.Context, _ sdk.CDNItemUnit) (io.ReadCloser, error) {
+	return nil, sdk.WithStack(errors.New("connection refused"))
+}
+
+func TestVerifyItemUnit(t *testing.T) {
+	content := []byte("my artifact content")
+	sum := sha512.Sum512(content)
+	it := &sdk.CDNItem{ID: "item", Hash: hex.EncodeToString(sum[:])}
+
+	u := memoryUnit{contents: map[string][]byte{
+		"healthy":   content,
+		"corrupted": []byte("my artifact c0ntent"),
+	}}
+
+	status, err := VerifyItemUnit(context.TODO(), u, sdk.CDNItemUnit{ID: "healthy", Item: it})
+	require.NoError(t, err)
+	require.Empty(t, status)
+
+	status, err = VerifyItemUnit(context.TODO(), u, sdk.CDNItemUnit{ID: "corrupted", Item: it})
+	require.Error(t, err)
+	require.Equal(t, sdk.CDNItemUnitVerifyStatusCorrupted, status)
+
+	status, err = VerifyItemUnit(context.TODO(), u, sdk.CDNItemUnit{ID: "missing", Item: it})
+	require.Error(t, err)
+	require.Equal(t, sdk.CDNItemUnitVerifyStatusMissing, status)
+
+	// An error of the unit does not mean that the content is missing
+	status, err = VerifyItemUnit(context.TODO(), unavailableUnit{u}, sdk.CDNItemUnit{ID: "healthy", Item: it})
+	require.Error(t, err)
+	require.Empty(t, status)
+}
@@ -98,6 +98,9 @@ func (s *Swift) NewReader(ctx context.Context, i sdk.CDNItemUnit) (io.ReadCloser
 	log.Debug(ctx, "[%T] reading from %s/%s", s, container, object)
 	file, _, err := s.client.ObjectOpen(container, object, true, nil)
 	if err != nil {
+		if err == swift.ObjectNotFound {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
 		return nil, sdk.WithStack(err)
 	}
 	return file, nil
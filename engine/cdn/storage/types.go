@@ -52,11 +52,12 @@ type Interface interface {
 }
 
 type AbstractUnit struct {
-	GoRoutines    *sdk.GoRoutines
-	u             sdk.CDNUnit
-	syncChan      chan string
-	syncBandwidth float64
-	disableSync   bool
+	GoRoutines      *sdk.GoRoutines
+	u               sdk.CDNUnit
+	syncChan        chan string
+	syncBandwidth   float64
+	disableSync     bool
+	hashLocatorSalt string
 }
 
 func (a *AbstractUnit) ExistsInDatabase(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, id string) (*sdk.CDNItemUnit, error) {
@@ -86,6 +87,12 @@ func (a *AbstractUnit) New(gorts *sdk.GoRoutines, config AbstractUnitConfig) {
 	}
 	a.syncBandwidth = config.syncBandwidth / float64(config.syncParrallel)
 	a.disableSync = config.disableSync
+	a.hashLocatorSalt = config.hashLocatorSalt
+}
+
+// HashLocator returns the hash of the locator, as stored for the unit item units
+func (a *AbstractUnit) HashLocator(loc string) string {
+	return hashLocator(a.hashLocatorSalt, loc)
 }
 
 func (a *AbstractUnit) SyncItemChannel() chan string { return a.syncChan }
@@ -126,9 +133,10 @@ type FileBufferUnit interface {
 }
 
 type AbstractUnitConfig struct {
-	syncParrallel int64
-	syncBandwidth float64
-	disableSync   bool
+	syncParrallel   int64
+	syncBandwidth   float64
+	disableSync     bool
+	hashLocatorSalt string
 }
 
 type StorageUnit interface {
@@ -154,6 +162,20 @@ type Configuration struct {
 	SyncNbElements  int64                           `toml:"syncNbElements" default:"100" json:"syncNbElements" comment:"nb items to synchronize from the buffer"`
 	PurgeSeconds    int                             `toml:"purgeSeconds" default:"5" json:"purgeSeconds" comment:"each n seconds, all storage backends will have to start to delete storage unit item with deleted flag"`
 	PurgeNbElements int                             `toml:"purgeNbElements" default:"1000" json:"purgeNbElements" comment:"nb items to delete in each purge loop"`
+	Lifecycle       []LifecycleRule                 `toml:"lifecycle" json:"lifecycle" mapstructure:"lifecycle" comment:"lifecycle rules by item type, items of a type without rule are stored on every storage unit"`
+}
+
+// LifecycleRule defines on which storage units and for how long the items of a type are stored
+type LifecycleRule struct {
+	ItemType        sdk.CDNItemType `toml:"itemType" json:"itemType" comment:"type of the items, e.g. job-step-log, run-result-v2, worker-cache-v2"`
+	Tiers           []LifecycleTier `toml:"tiers" json:"tiers" mapstructure:"tiers" comment:"storage units that store the items, other storage units will not receive them"`
+	DeleteAfterDays int             `toml:"deleteAfterDays" json:"deleteAfterDays" comment:"delete the items older than this number of days, 0 to never delete them"`
+}
+
+// LifecycleTier is a storage unit that keeps the items of a lifecycle rule for a given duration
+type LifecycleTier struct {
+	Unit       string `toml:"unit" json:"unit" comment:"name of the storage unit"`
+	MaxAgeDays int    `toml:"maxAgeDays" json:"maxAgeDays" comment:"remove the items from the storage unit when they are older than this number of days, 0 to keep them"`
 }
 
 type BufferConfiguration struct {
@@ -171,13 +193,15 @@ const (
 )
 
 type StorageConfiguration struct {
-	SyncParallel  int64                       `toml:"syncParallel" json:"sync_parallel" comment:"number of parallel sync processes"`
-	SyncBandwidth int64                       `toml:"syncBandwidth" json:"sync_bandwidth" comment:"global bandwith shared by the sync processes (in Mb)"`
-	DisableSync   bool                        `toml:"disableSync" json:"disable_sync" comment:"flag to disabled backend synchronization"`
-	Local         *LocalStorageConfiguration  `toml:"local" json:"local,omitempty" mapstructure:"local"`
-	Swift         *SwiftStorageConfiguration  `toml:"swift" json:"swift,omitempty" mapstructure:"swift"`
-	Webdav        *WebdavStorageConfiguration `toml:"webdav" json:"webdav,omitempty" mapstructure:"webdav"`
-	S3            *S3StorageConfiguration     `toml:"s3" json:"s3,omitempty" mapstructure:"s3"`
+	SyncParallel  int64                          `toml:"syncParallel" json:"sync_parallel" comment:"number of parallel sync processes"`
+	SyncBandwidth int64                          `toml:"syncBandwidth" json:"sync_bandwidth" comment:"global bandwith shared by the sync processes (in Mb)"`
+	DisableSync   bool                           `toml:"disableSync" json:"disable_sync" comment:"flag to disabled backend synchronization"`
+	Local         *LocalStorageConfiguration     `toml:"local" json:"local,omitempty" mapstructure:"local"`
+	Swift         *SwiftStorageConfiguration     `toml:"swift" json:"swift,omitempty" mapstructure:"swift"`
+	Webdav        *WebdavStorageConfiguration    `toml:"webdav" json:"webdav,omitempty" mapstructure:"webdav"`
+	S3            *S3StorageConfiguration        `toml:"s3" json:"s3,omitempty" mapstructure:"s3"`
+	AzureBlob     *AzureBlobStorageConfiguration `toml:"azureblob" json:"azureblob,omitempty" mapstructure:"azureblob"`
+	GCS           *GCSStorageConfiguration       `toml:"gcs" json:"gcs,omitempty" mapstructure:"gcs"`
 }
 
 type LocalStorageConfiguration struct {
@@ -212,6 +236,24 @@ type S3StorageConfiguration struct {
 	Encryption          []convergent.ConvergentEncryptionConfig `toml:"encryption" json:"-" mapstructure:"encryption"`
 }
 
+type AzureBlobStorageConfiguration struct {
+	AccountName   string                                  `toml:"accountName" json:"accountName" comment:"Name of the Azure storage account"`
+	AccountKey    string                                  `toml:"accountKey" json:"-" comment:"Shared key of the Azure storage account"`
+	ServiceURL    string                                  `toml:"serviceURL" json:"serviceURL" comment:"Blob service URL (optional), default is https://<accountName>.blob.core.windows.net/" commented:"true"` //optional
+	ContainerName string                                  `toml:"containerName" json:"containerName" comment:"Name of the container to use when storing artifacts"`
+	Prefix        string                                  `toml:"prefix" json:"prefix" comment:"A subfolder of the container to store blobs in, if left empty will store at the root of the container"`
+	Encryption    []convergent.ConvergentEncryptionConfig `toml:"encryption" json:"-" mapstructure:"encryption"`
+}
+
+type GCSStorageConfiguration struct {
+	BucketName      string                                  `toml:"bucketName" json:"bucketName" comment:"Name of the Google Cloud Storage bucket to use when storing artifacts"`
+	Prefix          string                                  `toml:"prefix" json:"prefix" comment:"A subfolder of the bucket to store objects in, if left empty will store at the root of the bucket"`
+	CredentialsFile string                                  `toml:"credentialsFile" json:"credentialsFile" comment:"The path for the service account key file, if left empty the application default credentials are used"`
+	Endpoint        string                                  `toml:"endpoint" json:"endpoint" comment:"Google Cloud Storage API Endpoint (optional)" commented:"true"`                            //optional
+	DisableAuth     bool                                    `toml:"disableAuth" json:"disableAuth" comment:"Disable authentication, only for an emulator like fake-gcs-server" commented:"true"` //optional
+	Encryption      []convergent.ConvergentEncryptionConfig `toml:"encryption" json:"-" mapstructure:"encryption"`
+}
+
 type WebdavStorageConfiguration struct {
 	Address    string                                  `toml:"address" json:"address"`
 	Username   string                                  `toml:"username" json:"username"`
@@ -243,9 +285,11 @@ type RunningStorageUnits struct {
 }
 
 func (x RunningStorageUnits) HashLocator(loc string) string {
-	salt := []byte(x.config.HashLocatorSalt)
-	hashLocator := hex.EncodeToString(pbkdf2.Key([]byte(loc), salt, 4096, 32, sha1.New))
-	return hashLocator
+	return hashLocator(x.config.HashLocatorSalt, loc)
+}
+
+func hashLocator(salt string, loc string) string {
+	return hex.EncodeToString(pbkdf2.Key([]byte(loc), []byte(salt), 4096, 32, sha1.New))
 }
 
 func (x RunningStorageUnits) FileBuffer() FileBufferUnit {
@@ -340,4 +384,6 @@ type LogConfig struct {
 	// Step logs
 	StepMaxSize        int64 `toml:"stepMaxSize" default:"15728640" comment:"Max step logs size in bytes (default: 15MB)" json:"stepMaxSize"`
 	StepLinesRateLimit int64 `toml:"stepLinesRateLimit" default:"1800" comment:"Number of lines that a worker can send by seconds" json:"stepLinesRateLimit"`
+	// Redaction rules applied on every received log line, in addition to the rules of the projects
+	Redaction []sdk.LogRedactionRule `toml:"redaction" json:"redaction" mapstructure:"redaction" commented:"true" comment:"Log redaction rules applied on every job log line, in addition to the rules of the projects"`
 }
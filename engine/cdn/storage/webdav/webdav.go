@@ -98,7 +98,14 @@ func (s *Webdav) NewReader(_ context.Context, i sdk.CDNItemUnit) (io.ReadCloser,
 	if err != nil {
 		return nil, err
 	}
-	return s.client.ReadStream(f)
+	rc, err := s.client.ReadStream(f)
+	if err != nil {
+		if pathErr, ok := err.(*os.PathError); ok && pathErr.Err.Error() == "404" {
+			return nil, sdk.WithStack(sdk.ErrNotFound)
+		}
+		return nil, sdk.WithStack(err)
+	}
+	return rc, nil
 }
 
 func (s *Webdav) Status(_ context.Context) []sdk.MonitoringStatusLine {
@@ -5,7 +5,9 @@ import (
 	"sync"
 	"time"
 
+	gocache "github.com/patrickmn/go-cache"
 	"go.opencensus.io/stats"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"golang.org/x/time/rate"
 
 	"github.com/ovh/cds/engine/api"
@@ -25,6 +27,7 @@ type handledMessage struct {
 	Signature    cdn.Signature
 	Msg          graylog.Message
 	IsTerminated bool
+	HatcheryName string
 }
 
 // Service is the stuct representing a CDN µService
@@ -56,6 +59,10 @@ type Service struct {
 		ItemToDelete             *stats.Int64Measure
 		ItemUnitToDelete         *stats.Int64Measure
 	}
+	otlpProcessor            sdklog.Processor
+	otlpLoggers              *gocache.Cache
+	logRedaction             *sdk.Blur
+	projectLogRedactions     sync.Map
 	storageUnitLags          sync.Map
 	storageUnitPreviousLags  sync.Map
 	storageUnitSizes         sync.Map
@@ -79,6 +86,7 @@ type Configuration struct {
 	} `toml:"cache" comment:"######################\n CDN Cache Settings \n######################" json:"cache"`
 	API     service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
 	Log     storage.LogConfig               `toml:"log" json:"log" comment:"###########################\n Log settings.\n##########################"`
+	OTLP    OTLPLogConfiguration            `toml:"otlp" json:"otlp" comment:"###########################\n OpenTelemetry logs export settings.\n Forward every step and service log line to an OTLP endpoint\n##########################"`
 	Units   storage.Configuration           `toml:"storageUnits" json:"storageUnits" mapstructure:"storageUnits" comment:"###########################\n Storage Units settings.\n##########################"`
 	Metrics struct {
 		Frequency int64 `toml:"frequency" default:"30" json:"frequency" comment:"each 30s, metrics are computed"`
@@ -94,6 +102,22 @@ type Configuration struct {
 		BatchSize        int `toml:"batchSize" default:"1000" json:"batchSize" comment:"Number of expired worker cache items to mark for deletion per batch"`
 		GracePeriodDays  int `toml:"gracePeriodDays" default:"730" json:"gracePeriodDays" comment:"Only delete items expired for longer than this number of days (default: 730 ~ 2 years)"`
 	} `toml:"workerCachePurge" comment:"######################\n Worker cache purge settings \n######################" json:"workerCachePurge"`
+	IntegrityCheck struct {
+		Enable         bool  `toml:"enable" default:"false" json:"enable" comment:"Enable the periodic verification of the items stored on the storage units"`
+		FrequencyHours int   `toml:"frequencyHours" default:"24" json:"frequencyHours" comment:"Frequency in hours between each verification"`
+		SampleSize     int64 `toml:"sampleSize" default:"1000" json:"sampleSize" comment:"Number of random items to verify on each storage unit, 0 to verify all the items"`
+		Repair         bool  `toml:"repair" default:"true" json:"repair" comment:"Synchronize again the missing or corrupted items from a healthy unit"`
+	} `toml:"integrityCheck" comment:"######################\n Storage units integrity check settings \n######################" json:"integrityCheck"`
+}
+
+// OTLPLogConfiguration is the configuration of the export of job logs over OTLP
+type OTLPLogConfiguration struct {
+	Enabled  bool              `toml:"enabled" default:"false" json:"enabled" comment:"Enable the export of job logs over OTLP"`
+	Protocol string            `toml:"protocol" default:"grpc" json:"protocol" comment:"OTLP protocol: grpc or http"`
+	Endpoint string            `toml:"endpoint" default:"localhost:4317" json:"endpoint" comment:"OTLP collector address (host:port)"`
+	URLPath  string            `toml:"urlPath" default:"" json:"urlPath" commented:"true" comment:"URL path for http protocol (default: /v1/logs)"`
+	Insecure bool              `toml:"insecure" default:"false" json:"insecure" comment:"Disable TLS to connect to the collector"`
+	Headers  map[string]string `toml:"headers" json:"headers" commented:"true" comment:"Headers sent with each export request"`
 }
 
 type rateLimiter struct {
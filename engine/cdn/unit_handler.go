@@ -141,3 +141,53 @@ func (s *Service) postAdminResyncBackendWithDatabaseHandler() service.Handler {
 		return nil
 	}
 }
+
+func (s *Service) postAdminVerifyBackendHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		unitID := vars["id"]
+
+		var u storage.StorageUnit
+		for _, su := range s.Units.Storages {
+			if su.ID() == unitID {
+				u = su
+				break
+			}
+		}
+		if u == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "storage unit %s not found, only storage units can be verified", unitID)
+		}
+
+		opts := unitVerifyOptions{
+			sample: service.FormInt64(r, "sample"),
+			repair: service.FormBool(r, "repair"),
+		}
+		if opts.sample < 0 {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid sample size")
+		}
+
+		s.GoRoutines.Exec(context.Background(), "VerifyUnit-"+unitID, func(ctx context.Context) {
+			if _, err := s.verifyUnit(ctx, u, opts); err != nil {
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "unable to verify unit %s: %v", u.Name(), err)
+			}
+		})
+		return service.WriteJSON(w, nil, http.StatusAccepted)
+	}
+}
+
+func (s *Service) getAdminVerifyBackendHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		unitID := vars["id"]
+
+		report, err := s.loadUnitVerifyReport(unitID)
+		if err != nil {
+			return err
+		}
+		if report == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no verification report found for unit %s", unitID)
+		}
+		return service.WriteJSON(w, report, http.StatusOK)
+	}
+}
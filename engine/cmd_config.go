@@ -12,6 +12,7 @@ import (
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/cdn"
+	"github.com/ovh/cds/engine/hatchery/firecracker"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -75,7 +76,7 @@ For advanced usage, Debug and Tracing section can be generated as:
 	$ engine config new debug tracing [µService(s)...]
 
 All options
-	$ engine config new [debug] [tracing] [api] [hatchery:local] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate]
+	$ engine config new [debug] [tracing] [api] [hatchery:local] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hatchery:firecracker] [elasticsearch] [hooks] [vcs] [repositories] [migrate]
 
 `,
 
@@ -179,6 +180,14 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.Hatchery != nil && conf.Hatchery.Firecracker != nil && conf.Hatchery.Firecracker.API.HTTP.URL != "" {
+			fmt.Printf("checking hatchery:firecracker configuration...\n")
+			if err := firecracker.New().CheckConfiguration(*conf.Hatchery.Firecracker); err != nil {
+				fmt.Printf("hatchery:firecracker Configuration: %v\n", err)
+				hasError = true
+			}
+		}
+
 		if conf.VCS != nil && conf.VCS.API.HTTP.URL != "" {
 			fmt.Printf("checking vcs configuration...\n")
 			if err := vcs.New().CheckConfiguration(*conf.VCS); err != nil {
@@ -14,6 +14,7 @@ import (
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/cdn"
 	"github.com/ovh/cds/engine/elasticsearch"
+	"github.com/ovh/cds/engine/hatchery/firecracker"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -75,6 +76,7 @@ They are the components responsible for spawning workers. Supported integrations
 * Docker Swarm
 * Openstack
 * Vsphere
+* Firecracker microVMs
 
 #### Hooks
 This component operates CDS workflow hooks
@@ -90,7 +92,7 @@ This component operates CDS CDN to handle storage
 
 Start all of this with a single command:
 
-	$ engine start [api] [cdn] [hatchery:local] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [elasticsearch] [hooks] [vcs] [repositories] [migrate] [ui]
+	$ engine start [api] [cdn] [hatchery:local] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hatchery:firecracker] [elasticsearch] [hooks] [vcs] [repositories] [migrate] [ui]
 
 All the services are using the same configuration file format.
 
@@ -191,6 +193,14 @@ See $ engine config command for more details.
 				names = append(names, conf.Hatchery.VSphere.Name)
 				types = append(types, sdk.TypeHatchery)
 
+			case sdk.TypeHatchery + ":firecracker":
+				if conf.Hatchery.Firecracker == nil {
+					sdk.Exit("Unable to start: missing service %s configuration", a)
+				}
+				serviceConfs = append(serviceConfs, serviceConf{arg: a, service: firecracker.New(), cfg: *conf.Hatchery.Firecracker})
+				names = append(names, conf.Hatchery.Firecracker.Name)
+				types = append(types, sdk.TypeHatchery)
+
 			case sdk.TypeHooks:
 				if conf.Hooks == nil {
 					sdk.Exit("Unable to start: missing service %s configuration", a)
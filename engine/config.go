@@ -26,6 +26,7 @@ import (
 	"github.com/ovh/cds/engine/database"
 	"github.com/ovh/cds/engine/elasticsearch"
 	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/hatchery/firecracker"
 	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -53,7 +54,7 @@ func configBootstrap(args []string) Configuration {
 	if len(args) == 0 {
 		args = []string{
 			"api", "ui", "migrate", "hooks", "vcs", "repositories", "elasticsearch", "cdn",
-			"hatchery:local", "hatchery:kubernetes", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere",
+			"hatchery:local", "hatchery:kubernetes", "hatchery:openstack", "hatchery:swarm", "hatchery:vsphere", "hatchery:firecracker",
 		}
 	}
 
@@ -130,6 +131,16 @@ func configBootstrap(args []string) Configuration {
 			conf.Hatchery.VSphere.GuestCredentials = []vsphere.GuestCredential{{
 				ModelVMWare: "debian12",
 			}}
+		case sdk.TypeHatchery + ":firecracker":
+			conf.Hatchery.Firecracker = &firecracker.HatcheryConfiguration{}
+			defaults.SetDefaults(conf.Hatchery.Firecracker)
+			conf.Hatchery.Firecracker.Name = "cds-hatchery-firecracker-" + namesgenerator.GetRandomNameCDS()
+			conf.Hatchery.Firecracker.HTTP.Port = 8086
+			conf.Hatchery.Firecracker.Flavors = []firecracker.FlavorConfig{
+				{Name: "small", CPUs: 1, MemoryMB: 1024},
+				{Name: "medium", CPUs: 2, MemoryMB: 4096},
+				{Name: "large", CPUs: 4, MemoryMB: 8192},
+			}
 		case sdk.TypeHooks:
 			conf.Hooks = &hooks.Configuration{}
 			defaults.SetDefaults(conf.Hooks)
@@ -477,6 +488,35 @@ func configSetStartupData(conf *Configuration) (string, error) {
 			h.VSphere.RSAPrivateKey = string(privateKeyPEM)
 		}
 
+		if h.Firecracker != nil {
+			var cfg = api.StartupConfigConsumer{
+				ID:          sdk.UUID(),
+				Name:        h.Firecracker.Name,
+				Description: "Autogenerated configuration for firecracker hatchery",
+				Type:        api.StartupConfigConsumerTypeHatchery,
+			}
+			var c = sdk.AuthUserConsumer{
+				AuthConsumer: sdk.AuthConsumer{
+					ID:              cfg.ID,
+					Name:            cfg.Name,
+					Description:     cfg.Description,
+					Type:            sdk.ConsumerBuiltin,
+					ValidityPeriods: validityPediod,
+				},
+				AuthConsumerUser: sdk.AuthUserConsumerData{
+					Data: map[string]string{},
+				},
+			}
+			h.Firecracker.API.Token, err = builtin.NewSigninConsumerToken(&c)
+			if err != nil {
+				return "", err
+			}
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+			privateKey, _ := jws.NewRandomRSAKey()
+			privateKeyPEM, _ := jws.ExportPrivateKey(privateKey)
+			h.Firecracker.RSAPrivateKey = string(privateKeyPEM)
+		}
+
 		if h.Swarm != nil {
 			var cfg = api.StartupConfigConsumer{
 				ID:          sdk.UUID(),
@@ -791,6 +831,23 @@ func getInitTokenFromExistingConfiguration(conf Configuration) (string, error) {
 			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
 		}
 
+		if h.Firecracker != nil {
+			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Firecracker.API.Token)
+			if err != nil {
+				return "", fmt.Errorf("cannot parse hatchery:firecracker signin token: %v", err)
+			}
+			if iat < globalIAT {
+				globalIAT = iat
+			}
+			var cfg = api.StartupConfigConsumer{
+				ID:          consumerID,
+				Name:        h.Firecracker.Name,
+				Description: "Autogenerated configuration for firecracker hatchery",
+				Type:        api.StartupConfigConsumerTypeHatchery,
+			}
+			startupCfg.Consumers = append(startupCfg.Consumers, cfg)
+		}
+
 		if h.Swarm != nil {
 			consumerID, iat, err := builtin.CheckSigninConsumerToken(h.Swarm.API.Token)
 			if err != nil {
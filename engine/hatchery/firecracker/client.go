@@ -0,0 +1,145 @@
+package firecracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// apiClient calls the firecracker API exposed on the unix socket of a microVM
+type apiClient struct {
+	socket     string
+	httpClient *http.Client
+}
+
+type machineConfig struct {
+	VCPUCount  int `json:"vcpu_count"`
+	MemSizeMib int `json:"mem_size_mib"`
+}
+
+type bootSource struct {
+	KernelImagePath string `json:"kernel_image_path"`
+	BootArgs        string `json:"boot_args"`
+}
+
+type drive struct {
+	DriveID      string `json:"drive_id"`
+	PathOnHost   string `json:"path_on_host"`
+	IsRootDevice bool   `json:"is_root_device"`
+	IsReadOnly   bool   `json:"is_read_only"`
+}
+
+type networkInterface struct {
+	IfaceID     string `json:"iface_id"`
+	GuestMac    string `json:"guest_mac"`
+	HostDevName string `json:"host_dev_name"`
+}
+
+type mmdsConfig struct {
+	Version           string   `json:"version"`
+	NetworkInterfaces []string `json:"network_interfaces"`
+}
+
+type instanceAction struct {
+	ActionType string `json:"action_type"`
+}
+
+type apiError struct {
+	FaultMessage string `json:"fault_message"`
+}
+
+func newAPIClient(socket string) *apiClient {
+	return &apiClient{
+		socket: socket,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+// waitSocket waits for firecracker to create its API socket
+func (c *apiClient) waitSocket(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		if _, err := os.Stat(c.socket); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return sdk.WithStack(fmt.Errorf("firecracker API socket %s not available: %v", c.socket, ctx.Err()))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (c *apiClient) put(ctx context.Context, path string, body interface{}) error {
+	btes, err := json.Marshal(body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, "http://localhost"+path, bytes.NewReader(btes))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "unable to call firecracker API %s", path)
+	}
+	defer resp.Body.Close() // nolint
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		var e apiError
+		if err := json.Unmarshal(respBody, &e); err == nil && e.FaultMessage != "" {
+			return sdk.WithStack(fmt.Errorf("firecracker API %s: %s", path, e.FaultMessage))
+		}
+		return sdk.WithStack(fmt.Errorf("firecracker API %s: HTTP %d: %s", path, resp.StatusCode, respBody))
+	}
+	return nil
+}
+
+func (c *apiClient) setMachineConfig(ctx context.Context, cfg machineConfig) error {
+	return c.put(ctx, "/machine-config", cfg)
+}
+
+func (c *apiClient) setBootSource(ctx context.Context, src bootSource) error {
+	return c.put(ctx, "/boot-source", src)
+}
+
+func (c *apiClient) setDrive(ctx context.Context, d drive) error {
+	return c.put(ctx, "/drives/"+d.DriveID, d)
+}
+
+func (c *apiClient) setNetworkInterface(ctx context.Context, iface networkInterface) error {
+	return c.put(ctx, "/network-interfaces/"+iface.IfaceID, iface)
+}
+
+// setMetadata enables the metadata service on given interface and sets its content
+func (c *apiClient) setMetadata(ctx context.Context, ifaceID string, data interface{}) error {
+	if err := c.put(ctx, "/mmds/config", mmdsConfig{Version: "V2", NetworkInterfaces: []string{ifaceID}}); err != nil {
+		return err
+	}
+	return c.put(ctx, "/mmds", data)
+}
+
+func (c *apiClient) start(ctx context.Context) error {
+	return c.put(ctx, "/actions", instanceAction{ActionType: "InstanceStart"})
+}
@@ -0,0 +1,62 @@
+package firecracker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClient(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "firecracker.socket")
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	calls := map[string]map[string]interface{}{}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var data map[string]interface{}
+		_ = json.Unmarshal(body, &data)
+		mu.Lock()
+		calls[r.Method+" "+r.URL.Path] = data
+		mu.Unlock()
+		if r.URL.Path == "/actions" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"fault_message":"kernel not found"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go srv.Serve(l) // nolint
+	defer srv.Close()
+
+	ctx := context.Background()
+	c := newAPIClient(socket)
+	require.NoError(t, c.waitSocket(ctx, time.Second))
+	require.NoError(t, c.setMachineConfig(ctx, machineConfig{VCPUCount: 2, MemSizeMib: 2048}))
+	require.NoError(t, c.setDrive(ctx, drive{DriveID: "rootfs", PathOnHost: "/rootfs.ext4", IsRootDevice: true}))
+	require.NoError(t, c.setMetadata(ctx, "eth0", map[string]string{"user_data": "worker"}))
+	err = c.start(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kernel not found")
+
+	require.Equal(t, float64(2), calls["PUT /machine-config"]["vcpu_count"])
+	require.Equal(t, float64(2048), calls["PUT /machine-config"]["mem_size_mib"])
+	require.Equal(t, true, calls["PUT /drives/rootfs"]["is_root_device"])
+	require.Equal(t, "V2", calls["PUT /mmds/config"]["version"])
+	require.Equal(t, "worker", calls["PUT /mmds"]["user_data"])
+	require.Equal(t, "InstanceStart", calls["PUT /actions"]["action_type"])
+}
+
+func TestAPIClientWaitSocket(t *testing.T) {
+	c := newAPIClient(filepath.Join(t.TempDir(), "firecracker.socket"))
+	require.Error(t, c.waitSocket(context.Background(), 50*time.Millisecond))
+}
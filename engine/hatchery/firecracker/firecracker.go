@@ -0,0 +1,393 @@
+package firecracker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+var _ hatchery.InterfaceWithModels = new(HatcheryFirecracker)
+
+var imageNameRe = regexp.MustCompile("^[a-zA-Z0-9][a-zA-Z0-9._-]*$")
+
+// New instanciates a new hatchery firecracker
+func New() *HatcheryFirecracker {
+	s := new(HatcheryFirecracker)
+	s.GoRoutines = sdk.NewGoRoutines(context.Background())
+	return s
+}
+
+// Init cdsclient config.
+func (h *HatcheryFirecracker) Init(config interface{}) (cdsclient.ServiceConfig, error) {
+	var cfg cdsclient.ServiceConfig
+	sConfig, ok := config.(HatcheryConfiguration)
+	if !ok {
+		return cfg, sdk.WithStack(fmt.Errorf("invalid firecracker hatchery configuration"))
+	}
+	h.Router = &api.Router{
+		Mux:    mux.NewRouter(),
+		Config: sConfig.HTTP,
+	}
+	cfg.Host = sConfig.API.HTTP.URL
+	cfg.Token = sConfig.API.Token
+	cfg.TokenV2 = sConfig.API.TokenV2
+	cfg.InsecureSkipVerifyTLS = sConfig.API.HTTP.Insecure
+	cfg.RequestSecondsTimeout = sConfig.API.RequestTimeout
+	return cfg, nil
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryFirecracker) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	h.Common.Common.ServiceName = h.Config.Name
+	h.Common.Common.ServiceType = sdk.TypeHatchery
+	h.HTTPURL = h.Config.URL
+	h.MaxHeartbeatFailures = h.Config.API.MaxHeartbeatFailures
+	var err error
+	h.Common.Common.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM([]byte(h.Config.RSAPrivateKey))
+	if err != nil {
+		return fmt.Errorf("unable to parse RSA private Key: %v", err)
+	}
+	h.Common.Common.Region = h.Config.Provision.Region
+	h.Common.Common.IgnoreJobWithNoRegion = h.Config.Provision.IgnoreJobWithNoRegion
+	h.Common.Common.ModelType = h.ModelType()
+
+	return nil
+}
+
+// Status returns sdk.MonitoringStatus, implements interface service.Service
+func (h *HatcheryFirecracker) Status(ctx context.Context) *sdk.MonitoringStatus {
+	m := h.NewMonitoringStatus()
+	ws, err := h.WorkersStarted(ctx)
+	if err != nil {
+		ctx = log.ContextWithStackTrace(ctx, err)
+		log.Warn(ctx, err.Error())
+	}
+	maxWorkerDisplay := fmt.Sprintf("%d", h.Config.Provision.MaxWorker)
+	if h.Config.Provision.MaxWorker == 0 {
+		maxWorkerDisplay = "unlimited"
+	}
+	cpus, memoryMB := h.allocatedResources()
+	m.AddLine(sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%s", len(ws), maxWorkerDisplay), Status: sdk.MonitoringStatusOK})
+	m.AddLine(sdk.MonitoringStatusLine{Component: "vCPUs", Value: fmt.Sprintf("%d", cpus), Status: sdk.MonitoringStatusOK})
+	m.AddLine(sdk.MonitoringStatusLine{Component: "Memory (MB)", Value: fmt.Sprintf("%d", memoryMB), Status: sdk.MonitoringStatusOK})
+	return m
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryFirecracker) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid hatchery firecracker configuration")
+	}
+
+	if err := hconfig.Check(); err != nil {
+		return fmt.Errorf("Invalid hatchery firecracker configuration: %v", err)
+	}
+
+	if hconfig.FirecrackerBinary == "" {
+		return fmt.Errorf("firecrackerBinary is mandatory")
+	}
+	if _, err := os.Stat(hconfig.FirecrackerBinary); err != nil {
+		return fmt.Errorf("invalid firecrackerBinary: %v", err)
+	}
+	if hconfig.JailerBinary != "" {
+		if _, err := os.Stat(hconfig.JailerBinary); err != nil {
+			return fmt.Errorf("invalid jailerBinary: %v", err)
+		}
+	}
+
+	if hconfig.ChrootBaseDir == "" {
+		return fmt.Errorf("chrootBaseDir is mandatory")
+	}
+	if ok, err := sdk.DirectoryExists(hconfig.ImagesDir); !ok {
+		return fmt.Errorf("imagesDir doesn't exist")
+	} else if err != nil {
+		return fmt.Errorf("invalid imagesDir: %v", err)
+	}
+	if !imageNameRe.MatchString(hconfig.DefaultKernel) {
+		return fmt.Errorf("invalid defaultKernel %q", hconfig.DefaultKernel)
+	}
+
+	if _, err := newNetwork(hconfig.Network); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(hconfig.Flavors))
+	for _, f := range hconfig.Flavors {
+		if f.Name == "" || f.CPUs <= 0 || f.MemoryMB <= 0 {
+			return fmt.Errorf("invalid flavor %q: name, cpus and memoryMB are mandatory", f.Name)
+		}
+		if _, has := names[strings.ToLower(f.Name)]; has {
+			return fmt.Errorf("flavor %q is defined twice", f.Name)
+		}
+		names[strings.ToLower(f.Name)] = struct{}{}
+	}
+	if hconfig.DefaultFlavor != "" {
+		if _, has := names[strings.ToLower(hconfig.DefaultFlavor)]; !has {
+			return fmt.Errorf("default flavor %q is not defined", hconfig.DefaultFlavor)
+		}
+	}
+	if hconfig.DefaultCPUs <= 0 || hconfig.DefaultMemoryMB <= 0 {
+		return fmt.Errorf("defaultCpus and defaultMemoryMB must be positive")
+	}
+
+	return nil
+}
+
+func (h *HatcheryFirecracker) Signin(ctx context.Context, clientConfig cdsclient.ServiceConfig, srvConfig interface{}) error {
+	if err := h.Common.Signin(ctx, clientConfig, srvConfig); err != nil {
+		return err
+	}
+	if err := h.Common.SigninV2(ctx, clientConfig, srvConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Start inits client and routines for hatchery
+func (h *HatcheryFirecracker) Start(ctx context.Context) error {
+	return hatchery.Create(ctx, h)
+}
+
+// Serve start the hatchery server
+func (h *HatcheryFirecracker) Serve(ctx context.Context) error {
+	return h.CommonServe(ctx, h)
+}
+
+// Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryFirecracker) Configuration() service.HatcheryCommonConfiguration {
+	return h.Config.HatcheryCommonConfiguration
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryFirecracker) ModelType() string {
+	return sdk.WorkerModelTypeFirecracker
+}
+
+// NeedRegistration returns false, firecracker hatchery only supports worker models v2
+func (h *HatcheryFirecracker) NeedRegistration(_ context.Context, _ *sdk.Model) bool {
+	return false
+}
+
+// WorkerModelsEnabled returns no model, firecracker hatchery only supports worker models v2
+func (h *HatcheryFirecracker) WorkerModelsEnabled() ([]sdk.Model, error) {
+	return nil, nil
+}
+
+// WorkerModelSecretList returns secret for given model.
+func (h *HatcheryFirecracker) WorkerModelSecretList(m sdk.Model) (sdk.WorkerModelSecrets, error) {
+	return h.CDSClient().WorkerModelSecretList(m.Group.Name, m.Name)
+}
+
+// CanSpawn return wether or not hatchery can spawn model.
+func (h *HatcheryFirecracker) CanSpawn(ctx context.Context, model sdk.WorkerStarterWorkerModel, jobID string, requirements []sdk.Requirement) bool {
+	ctx, end := telemetry.Span(ctx, "firecracker.CanSpawn")
+	defer end()
+
+	if model.ModelV2 == nil || model.ModelV2.Type != sdk.WorkerModelTypeFirecracker {
+		log.Debug(ctx, "CanSpawn> job %s has no firecracker worker model", jobID)
+		return false
+	}
+
+	for _, r := range requirements {
+		if r.Type == sdk.ServiceRequirement || r.Type == sdk.HostnameRequirement {
+			return false
+		}
+	}
+
+	if _, err := h.imagePath(model.GetFirecrackerImage(), ".ext4"); err != nil {
+		log.Debug(ctx, "CanSpawn> job %s: %v", jobID, err)
+		return false
+	}
+	if _, err := h.kernelPath(model); err != nil {
+		log.Debug(ctx, "CanSpawn> job %s: %v", jobID, err)
+		return false
+	}
+	return true
+}
+
+// CanAllocateResources checks that the vCPUs and memory of the flavor of the job are available
+func (h *HatcheryFirecracker) CanAllocateResources(ctx context.Context, model sdk.WorkerStarterWorkerModel, jobID string, requirements []sdk.Requirement) (bool, error) {
+	flavorName, cpus, memoryMB := h.resources(model, requirements)
+	usedCPUs, usedMemoryMB := h.allocatedResources()
+
+	if h.Config.MaxCPUs > 0 && usedCPUs+cpus > h.Config.MaxCPUs {
+		log.Info(ctx, "CanAllocateResources> job %s with flavor %q: CPU limit reached: %d + %d > %d", jobID, flavorName, usedCPUs, cpus, h.Config.MaxCPUs)
+		return false, nil
+	}
+	if h.Config.MaxMemoryMB > 0 && usedMemoryMB+memoryMB > h.Config.MaxMemoryMB {
+		log.Info(ctx, "CanAllocateResources> job %s with flavor %q: memory limit reached: %d + %d > %d", jobID, flavorName, usedMemoryMB, memoryMB, h.Config.MaxMemoryMB)
+		return false, nil
+	}
+	return true, nil
+}
+
+// resources returns the flavor name, vCPUs and memory of the microVM for given model and requirements
+func (h *HatcheryFirecracker) resources(model sdk.WorkerStarterWorkerModel, requirements []sdk.Requirement) (string, int, int) {
+	cpus, memoryMB := h.Config.DefaultCPUs, h.Config.DefaultMemoryMB
+	flavorName := model.GetFlavor(requirements, h.Config.DefaultFlavor)
+	if f := h.getFlavor(flavorName); f != nil {
+		cpus, memoryMB = f.CPUs, f.MemoryMB
+	} else {
+		flavorName = ""
+	}
+	if model.Memory > 0 {
+		memoryMB = int(model.Memory)
+	}
+	return flavorName, cpus, memoryMB
+}
+
+func (h *HatcheryFirecracker) getFlavor(name string) *FlavorConfig {
+	if name == "" {
+		return nil
+	}
+	for i := range h.Config.Flavors {
+		if strings.EqualFold(h.Config.Flavors[i].Name, name) {
+			return &h.Config.Flavors[i]
+		}
+	}
+	return nil
+}
+
+func (h *HatcheryFirecracker) allocatedResources() (int, int) {
+	h.Lock()
+	defer h.Unlock()
+	var cpus, memoryMB int
+	for _, vm := range h.workers {
+		cpus += vm.cpus
+		memoryMB += vm.memoryMB
+	}
+	return cpus, memoryMB
+}
+
+// imagePath returns the path of an image in the images directory. The name comes from the worker model
+// and must not be a path.
+func (h *HatcheryFirecracker) imagePath(name, ext string) (string, error) {
+	if !imageNameRe.MatchString(name) {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid firecracker image name %q", name)
+	}
+	p := filepath.Join(h.Config.ImagesDir, name+ext)
+	if _, err := os.Stat(p); err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "firecracker image %q not found", name)
+	}
+	return p, nil
+}
+
+func (h *HatcheryFirecracker) kernelPath(model sdk.WorkerStarterWorkerModel) (string, error) {
+	kernel := model.GetFirecrackerKernel()
+	if kernel == "" {
+		kernel = h.Config.DefaultKernel
+	}
+	return h.imagePath(kernel, "")
+}
+
+// WorkersStarted returns the names of the microVMs started but
+// not necessarily register on CDS yet
+func (h *HatcheryFirecracker) WorkersStarted(ctx context.Context) ([]string, error) {
+	h.Lock()
+	defer h.Unlock()
+	workers := make([]string, 0, len(h.workers))
+	for n := range h.workers {
+		workers = append(workers, n)
+	}
+	return workers, nil
+}
+
+// InitHatchery initializes the network of the microVMs and starts the routines of the hatchery
+func (h *HatcheryFirecracker) InitHatchery(ctx context.Context) error {
+	h.workers = make(map[string]*microVM)
+	var err error
+	h.network, err = newNetwork(h.Config.Network)
+	if err != nil {
+		return err
+	}
+	if err := h.Common.Init(ctx, h); err != nil {
+		return err
+	}
+	h.GoRoutines.Run(ctx, "hatchery firecracker routines", func(ctx context.Context) {
+		h.routines(ctx)
+	})
+	return nil
+}
+
+func (h *HatcheryFirecracker) routines(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.GoRoutines.Exec(ctx, "firecracker-killAwolWorkers", func(ctx context.Context) {
+				if err := h.killAwolWorkers(ctx); err != nil {
+					log.Warn(ctx, "Cannot kill awol workers: %s", err)
+				}
+			})
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "Hatchery> firecracker> Exiting routines")
+			}
+			return
+		}
+	}
+}
+
+// killAwolWorkers stops the microVMs whose worker is unknown or disabled on the API
+func (h *HatcheryFirecracker) killAwolWorkers(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	apiWorkers, err := h.WorkerList(ctx)
+	if err != nil {
+		return err
+	}
+
+	mAPIWorkers := make(map[string]sdk.PoolWorker, len(apiWorkers))
+	for _, w := range apiWorkers {
+		mAPIWorkers[w.GetName()] = w
+	}
+
+	h.Lock()
+	defer h.Unlock()
+	for name, vm := range h.workers {
+		if w, ok := mAPIWorkers[name]; !ok {
+			// The worker needs time to boot and register
+			if time.Since(vm.created) < 3*time.Minute {
+				continue
+			}
+			log.Info(ctx, "Killing AWOL worker %s", name)
+		} else if w.GetStatus() == sdk.StatusDisabled {
+			log.Info(ctx, "Killing disabled worker %s", name)
+		} else {
+			continue
+		}
+		// The resources of the microVM are released when its process exits
+		if err := vm.cmd.Process.Kill(); err != nil {
+			log.Warn(ctx, "Error killing worker %s: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package firecracker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestHatcheryFirecrackerResources(t *testing.T) {
+	h := New()
+	h.Config.DefaultCPUs = 1
+	h.Config.DefaultMemoryMB = 512
+	h.Config.Flavors = []FlavorConfig{
+		{Name: "small", CPUs: 2, MemoryMB: 2048},
+		{Name: "large", CPUs: 8, MemoryMB: 16384},
+	}
+
+	model := sdk.WorkerStarterWorkerModel{
+		ModelV2:         &sdk.V2WorkerModel{Type: sdk.WorkerModelTypeFirecracker},
+		FirecrackerSpec: sdk.V2WorkerModelFirecrackerSpec{Image: "debian12", Flavor: "small"},
+	}
+	flavor, cpus, memoryMB := h.resources(model, nil)
+	require.Equal(t, "small", flavor)
+	require.Equal(t, 2, cpus)
+	require.Equal(t, 2048, memoryMB)
+
+	// The flavor of the job overrides the flavor of the model
+	model.Flavor = "LARGE"
+	flavor, cpus, memoryMB = h.resources(model, nil)
+	require.Equal(t, "LARGE", flavor)
+	require.Equal(t, 8, cpus)
+	require.Equal(t, 16384, memoryMB)
+
+	// The memory of the job overrides the memory of the flavor
+	model.Memory = 4096
+	_, cpus, memoryMB = h.resources(model, nil)
+	require.Equal(t, 8, cpus)
+	require.Equal(t, 4096, memoryMB)
+
+	model = sdk.WorkerStarterWorkerModel{ModelV2: &sdk.V2WorkerModel{Type: sdk.WorkerModelTypeFirecracker}, Flavor: "unknown"}
+	flavor, cpus, memoryMB = h.resources(model, nil)
+	require.Equal(t, "", flavor)
+	require.Equal(t, 1, cpus)
+	require.Equal(t, 512, memoryMB)
+}
+
+func TestHatcheryFirecrackerCanSpawn(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debian12.ext4"), []byte("rootfs"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vmlinux"), []byte("kernel"), 0600))
+
+	h := New()
+	h.Config.ImagesDir = dir
+	h.Config.DefaultKernel = "vmlinux"
+	ctx := context.Background()
+
+	model := sdk.WorkerStarterWorkerModel{
+		ModelV2:         &sdk.V2WorkerModel{Type: sdk.WorkerModelTypeFirecracker},
+		FirecrackerSpec: sdk.V2WorkerModelFirecrackerSpec{Image: "debian12"},
+	}
+	require.True(t, h.CanSpawn(ctx, model, "1", nil))
+	require.False(t, h.CanSpawn(ctx, model, "1", []sdk.Requirement{{Type: sdk.ServiceRequirement, Value: "pg"}}))
+
+	model.FirecrackerSpec.Image = "../debian12"
+	require.False(t, h.CanSpawn(ctx, model, "1", nil))
+
+	model.FirecrackerSpec.Image = "ubuntu"
+	require.False(t, h.CanSpawn(ctx, model, "1", nil))
+
+	model.FirecrackerSpec = sdk.V2WorkerModelFirecrackerSpec{Image: "debian12", Kernel: "vmlinux-6.1"}
+	require.False(t, h.CanSpawn(ctx, model, "1", nil))
+
+	model = sdk.WorkerStarterWorkerModel{
+		ModelV2:    &sdk.V2WorkerModel{Type: sdk.WorkerModelTypeDocker},
+		DockerSpec: sdk.V2WorkerModelDockerSpec{Image: "debian12"},
+	}
+	require.False(t, h.CanSpawn(ctx, model, "1", nil))
+}
+
+func TestHatcheryFirecrackerCanAllocateResources(t *testing.T) {
+	h := New()
+	h.Config.DefaultCPUs = 2
+	h.Config.DefaultMemoryMB = 2048
+	h.Config.MaxCPUs = 4
+	h.Config.MaxMemoryMB = 6144
+	h.workers = map[string]*microVM{"w1": {cpus: 2, memoryMB: 2048}}
+	ctx := context.Background()
+
+	model := sdk.WorkerStarterWorkerModel{ModelV2: &sdk.V2WorkerModel{Type: sdk.WorkerModelTypeFirecracker}}
+	ok, err := h.CanAllocateResources(ctx, model, "1", nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	h.workers["w2"] = &microVM{cpus: 2, memoryMB: 2048}
+	ok, err = h.CanAllocateResources(ctx, model, "1", nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	h.Config.MaxCPUs = 0
+	model.Memory = 4096
+	ok, err = h.CanAllocateResources(ctx, model, "1", nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
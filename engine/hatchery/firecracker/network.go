@@ -0,0 +1,134 @@
+package firecracker
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"sync"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// network allocates a /30 of the configured subnet to each microVM
+type network struct {
+	mu        sync.Mutex
+	base      uint32
+	slots     int
+	used      map[int]struct{}
+	tapPrefix string
+}
+
+// vmNetwork is the tap device and the addresses of a microVM
+type vmNetwork struct {
+	slot    int
+	tap     string
+	hostIP  net.IP
+	guestIP net.IP
+	mac     string
+}
+
+func newNetwork(cfg NetworkConfiguration) (*network, error) {
+	_, subnet, err := net.ParseCIDR(cfg.Subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet %q: %v", cfg.Subnet, err)
+	}
+	ip4 := subnet.IP.To4()
+	if ip4 == nil {
+		return nil, fmt.Errorf("invalid subnet %q: only IPv4 is supported", cfg.Subnet)
+	}
+	ones, bits := subnet.Mask.Size()
+	if bits-ones < 2 {
+		return nil, fmt.Errorf("invalid subnet %q: at least a /30 is required", cfg.Subnet)
+	}
+	// Tap device names are limited to 15 characters
+	if len(cfg.TapPrefix)+5 > 15 {
+		return nil, fmt.Errorf("invalid tap prefix %q: 10 characters max", cfg.TapPrefix)
+	}
+	slots := 1 << uint(bits-ones-2)
+	if slots > 99999 {
+		slots = 99999
+	}
+	return &network{
+		base:      binary.BigEndian.Uint32(ip4),
+		slots:     slots,
+		used:      make(map[int]struct{}),
+		tapPrefix: cfg.TapPrefix,
+	}, nil
+}
+
+// allocate returns the first free /30 of the subnet
+func (n *network) allocate() (vmNetwork, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for slot := 0; slot < n.slots; slot++ {
+		if _, has := n.used[slot]; has {
+			continue
+		}
+		n.used[slot] = struct{}{}
+		return n.vmNetwork(slot), nil
+	}
+	return vmNetwork{}, sdk.WithStack(fmt.Errorf("no address available in subnet for a new microVM"))
+}
+
+func (n *network) release(slot int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.used, slot)
+}
+
+func (n *network) vmNetwork(slot int) vmNetwork {
+	host := n.base + uint32(slot)*4 + 1
+	guest := host + 1
+	hostIP := make(net.IP, 4)
+	binary.BigEndian.PutUint32(hostIP, host)
+	guestIP := make(net.IP, 4)
+	binary.BigEndian.PutUint32(guestIP, guest)
+	return vmNetwork{
+		slot:    slot,
+		tap:     n.tapPrefix + strconv.Itoa(slot),
+		hostIP:  hostIP,
+		guestIP: guestIP,
+		// Locally administered MAC address computed from the guest IP
+		mac: fmt.Sprintf("06:00:%02x:%02x:%02x:%02x", guestIP[0], guestIP[1], guestIP[2], guestIP[3]),
+	}
+}
+
+// bootArgs returns the kernel parameter that configures eth0 in the guest
+func (v vmNetwork) bootArgs(nameserver string) string {
+	args := fmt.Sprintf("ip=%s::%s:255.255.255.252::eth0:off", v.guestIP, v.hostIP)
+	if nameserver != "" {
+		args += ":" + nameserver
+	}
+	return args
+}
+
+// createTap creates the tap device of the microVM, owned by the user that runs firecracker
+func createTap(ctx context.Context, v vmNetwork, uid, gid int) error {
+	cmds := [][]string{
+		{"tuntap", "add", "dev", v.tap, "mode", "tap", "user", strconv.Itoa(uid), "group", strconv.Itoa(gid)},
+		{"addr", "add", v.hostIP.String() + "/30", "dev", v.tap},
+		{"link", "set", v.tap, "up"},
+	}
+	for _, args := range cmds {
+		if err := runIP(ctx, args...); err != nil {
+			_ = deleteTap(ctx, v)
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteTap(ctx context.Context, v vmNetwork) error {
+	return runIP(ctx, "link", "del", v.tap)
+}
+
+func runIP(ctx context.Context, args ...string) error {
+	out, err := exec.CommandContext(ctx, "ip", args...).CombinedOutput()
+	if err != nil {
+		return sdk.WithStack(fmt.Errorf("ip %v: %v: %s", args, err, out))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package firecracker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkAllocate(t *testing.T) {
+	n, err := newNetwork(NetworkConfiguration{Subnet: "172.30.0.0/29", TapPrefix: "cdsfc"})
+	require.NoError(t, err)
+
+	v0, err := n.allocate()
+	require.NoError(t, err)
+	require.Equal(t, "cdsfc0", v0.tap)
+	require.Equal(t, "172.30.0.1", v0.hostIP.String())
+	require.Equal(t, "172.30.0.2", v0.guestIP.String())
+	require.Equal(t, "06:00:ac:1e:00:02", v0.mac)
+	require.Equal(t, "ip=172.30.0.2::172.30.0.1:255.255.255.252::eth0:off:1.1.1.1", v0.bootArgs("1.1.1.1"))
+
+	v1, err := n.allocate()
+	require.NoError(t, err)
+	require.Equal(t, "cdsfc1", v1.tap)
+	require.Equal(t, "172.30.0.6", v1.guestIP.String())
+
+	// The /29 only contains two /30
+	_, err = n.allocate()
+	require.Error(t, err)
+
+	n.release(v0.slot)
+	v2, err := n.allocate()
+	require.NoError(t, err)
+	require.Equal(t, v0, v2)
+}
+
+func TestNewNetworkInvalid(t *testing.T) {
+	_, err := newNetwork(NetworkConfiguration{Subnet: "172.30.0.1/31", TapPrefix: "cdsfc"})
+	require.Error(t, err)
+	_, err = newNetwork(NetworkConfiguration{Subnet: "fd00::/64", TapPrefix: "cdsfc"})
+	require.Error(t, err)
+	_, err = newNetwork(NetworkConfiguration{Subnet: "172.30.0.0/16", TapPrefix: "a-too-long-prefix"})
+	require.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package firecracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+const (
+	kernelFile = "vmlinux"
+	rootfsFile = "rootfs.ext4"
+	socketFile = "run/firecracker.socket"
+)
+
+// SpawnWorker boots a new microVM that runs the worker
+func (h *HatcheryFirecracker) SpawnWorker(ctx context.Context, spawnArgs hatchery.SpawnArguments) error {
+	log.Debug(ctx, "spawnWorker> spawning worker %s model:%s for job %s", spawnArgs.WorkerName, spawnArgs.Model.GetName(), spawnArgs.JobID)
+
+	if spawnArgs.Model.ModelV2 == nil || !sdk.IsValidUUID(spawnArgs.JobID) {
+		return sdk.WithStack(fmt.Errorf("firecracker hatchery only supports jobs with a worker model v2"))
+	}
+
+	rootfs, err := h.imagePath(spawnArgs.Model.GetFirecrackerImage(), ".ext4")
+	if err != nil {
+		return err
+	}
+	kernel, err := h.kernelPath(spawnArgs.Model)
+	if err != nil {
+		return err
+	}
+
+	flavorName, cpus, memoryMB := h.resources(spawnArgs.Model, spawnArgs.Requirements)
+	flavorInfo := sdk.V2SendJobRunInfo{
+		Level:   sdk.WorkflowRunInfoLevelInfo,
+		Time:    time.Now(),
+		Message: fmt.Sprintf("worker %q will use the flavor %q (%d vCPUs, %d MB)", spawnArgs.WorkerName, flavorName, cpus, memoryMB),
+	}
+	if err := h.CDSClientV2().V2QueuePushJobInfo(ctx, spawnArgs.Region, spawnArgs.JobID, flavorInfo); err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+	}
+
+	userData, err := h.userData(ctx, spawnArgs)
+	if err != nil {
+		return err
+	}
+
+	vmNet, err := h.network.allocate()
+	if err != nil {
+		return err
+	}
+	vm := &microVM{
+		id:       sdk.UUID(),
+		created:  time.Now(),
+		cpus:     cpus,
+		memoryMB: memoryMB,
+		net:      vmNet,
+		jailed:   h.Config.JailerBinary != "",
+	}
+	vm.dir = filepath.Join(h.Config.ChrootBaseDir, filepath.Base(h.Config.FirecrackerBinary), vm.id, "root")
+
+	start := time.Now()
+	if err := h.startMicroVM(ctx, vm, kernel, rootfs, userData); err != nil {
+		h.releaseMicroVM(ctx, vm)
+		return err
+	}
+	log.Info(ctx, "spawnWorker> microVM %s of worker %s started in %s", vm.id, spawnArgs.WorkerName, time.Since(start))
+
+	h.Lock()
+	h.workers[spawnArgs.WorkerName] = vm
+	h.Unlock()
+
+	go func() {
+		if err := vm.cmd.Wait(); err != nil {
+			log.Info(ctx, "hatchery> firecracker> microVM %s of worker %s exited: %v", vm.id, spawnArgs.WorkerName, err)
+		}
+		h.Lock()
+		delete(h.workers, spawnArgs.WorkerName)
+		h.Unlock()
+		h.releaseMicroVM(context.Background(), vm)
+	}()
+
+	return nil
+}
+
+// userData returns the script that the guest reads from the metadata service to start the worker
+func (h *HatcheryFirecracker) userData(ctx context.Context, spawnArgs hatchery.SpawnArguments) (string, error) {
+	workerConfig := h.GenerateWorkerConfig(ctx, h, spawnArgs)
+	udataParam := struct {
+		API    string
+		Config string
+	}{
+		API:    workerConfig.APIEndpoint,
+		Config: workerConfig.EncodeBase64(),
+	}
+
+	udata := spawnArgs.Model.GetPreCmd() + "\n" + spawnArgs.Model.GetCmd() + " --config {{.Config}}\n" + spawnArgs.Model.GetPostCmd()
+	tmpl, err := template.New("udata").Parse(udata)
+	if err != nil {
+		return "", sdk.WithStack(err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, udataParam); err != nil {
+		return "", sdk.WithStack(err)
+	}
+	return buffer.String(), nil
+}
+
+func (h *HatcheryFirecracker) startMicroVM(ctx context.Context, vm *microVM, kernel, rootfs, userData string) error {
+	if err := os.MkdirAll(filepath.Join(vm.dir, filepath.Dir(socketFile)), 0750); err != nil {
+		return sdk.WithStack(err)
+	}
+	// The rootfs is written by the guest, each microVM gets its own copy. On a filesystem with reflinks the copy is instant.
+	for src, dst := range map[string]string{kernel: kernelFile, rootfs: rootfsFile} {
+		dst = filepath.Join(vm.dir, dst)
+		if out, err := exec.CommandContext(ctx, "cp", "--reflink=auto", "--sparse=always", src, dst).CombinedOutput(); err != nil {
+			return sdk.WithStack(fmt.Errorf("unable to copy %s: %v: %s", src, err, out))
+		}
+		if vm.jailed {
+			if err := os.Chown(dst, h.Config.UID, h.Config.GID); err != nil {
+				return sdk.WithStack(err)
+			}
+		}
+	}
+
+	if err := createTap(ctx, vm.net, h.Config.UID, h.Config.GID); err != nil {
+		return err
+	}
+
+	if vm.jailed {
+		vm.cmd = exec.Command(h.Config.JailerBinary,
+			"--id", vm.id,
+			"--exec-file", h.Config.FirecrackerBinary,
+			"--uid", strconv.Itoa(h.Config.UID),
+			"--gid", strconv.Itoa(h.Config.GID),
+			"--chroot-base-dir", h.Config.ChrootBaseDir,
+			"--",
+			"--api-sock", "/"+socketFile,
+		)
+	} else {
+		vm.cmd = exec.Command(h.Config.FirecrackerBinary, "--api-sock", filepath.Join(vm.dir, socketFile))
+	}
+	console := consoleWriter{vmID: vm.id}
+	vm.cmd.Stdout = console
+	vm.cmd.Stderr = console
+	if err := vm.cmd.Start(); err != nil {
+		return sdk.WrapError(err, "unable to start firecracker")
+	}
+
+	client := newAPIClient(filepath.Join(vm.dir, socketFile))
+	if err := client.waitSocket(ctx, 5*time.Second); err != nil {
+		return err
+	}
+
+	bootArgs := strings.TrimSpace(h.Config.BootArgs + " " + vm.net.bootArgs(h.Config.Network.Nameserver))
+	if err := client.setMachineConfig(ctx, machineConfig{VCPUCount: vm.cpus, MemSizeMib: vm.memoryMB}); err != nil {
+		return err
+	}
+	if err := client.setBootSource(ctx, bootSource{KernelImagePath: vm.path(kernelFile), BootArgs: bootArgs}); err != nil {
+		return err
+	}
+	if err := client.setDrive(ctx, drive{DriveID: "rootfs", PathOnHost: vm.path(rootfsFile), IsRootDevice: true}); err != nil {
+		return err
+	}
+	if err := client.setNetworkInterface(ctx, networkInterface{IfaceID: "eth0", GuestMac: vm.net.mac, HostDevName: vm.net.tap}); err != nil {
+		return err
+	}
+	if err := client.setMetadata(ctx, "eth0", map[string]interface{}{"cds": map[string]string{"user_data": userData}}); err != nil {
+		return err
+	}
+	return client.start(ctx)
+}
+
+// path returns the path of a file of the microVM as seen by firecracker
+func (vm *microVM) path(file string) string {
+	if vm.jailed {
+		return "/" + file
+	}
+	return filepath.Join(vm.dir, file)
+}
+
+// releaseMicroVM stops the microVM if it is still running and removes its host resources
+func (h *HatcheryFirecracker) releaseMicroVM(ctx context.Context, vm *microVM) {
+	if vm.cmd != nil && vm.cmd.Process != nil && vm.cmd.ProcessState == nil {
+		_ = vm.cmd.Process.Kill()
+		_ = vm.cmd.Wait()
+	}
+	if err := deleteTap(ctx, vm.net); err != nil {
+		log.Warn(ctx, "hatchery> firecracker> unable to delete tap device of microVM %s: %v", vm.id, err)
+	}
+	h.network.release(vm.net.slot)
+	// Remove the directory of the microVM created by the jailer, parent of the chroot
+	if err := os.RemoveAll(filepath.Dir(vm.dir)); err != nil {
+		log.Warn(ctx, "hatchery> firecracker> unable to remove directory of microVM %s: %v", vm.id, err)
+	}
+}
+
+// consoleWriter logs the serial console of a microVM
+type consoleWriter struct {
+	vmID string
+}
+
+func (w consoleWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		log.Debug(context.Background(), "hatchery> firecracker> %s> %s", w.vmID, line)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,71 @@
+package firecracker
+
+import (
+	"os/exec"
+	"sync"
+	"time"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/engine/service"
+)
+
+// HatcheryConfiguration is the configuration for firecracker hatchery
+type HatcheryConfiguration struct {
+	service.HatcheryCommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
+
+	FirecrackerBinary string `mapstructure:"firecrackerBinary" toml:"firecrackerBinary" default:"/usr/local/bin/firecracker" commented:"false" comment:"Path of the firecracker binary" json:"firecrackerBinary"`
+	JailerBinary      string `mapstructure:"jailerBinary" toml:"jailerBinary" default:"/usr/local/bin/jailer" commented:"false" comment:"Path of the jailer binary. If empty, the microVMs are started without jailer, this should only be used for tests" json:"jailerBinary"`
+	ChrootBaseDir     string `mapstructure:"chrootBaseDir" toml:"chrootBaseDir" default:"/srv/jailer" commented:"false" comment:"Directory where the jailer creates the chroot of each microVM" json:"chrootBaseDir"`
+	UID               int    `mapstructure:"uid" toml:"uid" default:"10000" commented:"false" comment:"Unprivileged user ID used by the jailer to run firecracker" json:"uid"`
+	GID               int    `mapstructure:"gid" toml:"gid" default:"10000" commented:"false" comment:"Unprivileged group ID used by the jailer to run firecracker" json:"gid"`
+
+	ImagesDir     string `mapstructure:"imagesDir" toml:"imagesDir" default:"/var/lib/cds-engine/firecracker" commented:"false" comment:"Directory of the rootfs and kernel images. The image 'debian12' of a worker model is the file <imagesDir>/debian12.ext4.\n This directory should be on a filesystem that supports reflinks (btrfs, xfs) to copy the rootfs of each microVM instantly" json:"imagesDir"`
+	DefaultKernel string `mapstructure:"defaultKernel" toml:"defaultKernel" default:"vmlinux" commented:"false" comment:"Kernel image in imagesDir used when the worker model does not set one" json:"defaultKernel"`
+	BootArgs      string `mapstructure:"bootArgs" toml:"bootArgs" default:"console=ttyS0 reboot=k panic=1 pci=off" commented:"true" comment:"Kernel command line. The network configuration of the microVM is appended" json:"bootArgs"`
+
+	Network NetworkConfiguration `mapstructure:"network" toml:"network" json:"network"`
+
+	Flavors         []FlavorConfig `mapstructure:"flavors" toml:"flavors" commented:"true" comment:"vCPU/memory sizing of the microVMs, selected with the flavor of the job" json:"flavors,omitempty"`
+	DefaultFlavor   string         `mapstructure:"defaultFlavor" toml:"defaultFlavor" default:"" commented:"true" comment:"Flavor used when no flavor is specified in worker model or job" json:"defaultFlavor,omitempty"`
+	DefaultCPUs     int            `mapstructure:"defaultCpus" toml:"defaultCpus" default:"2" commented:"true" comment:"vCPUs of a microVM without flavor" json:"defaultCpus"`
+	DefaultMemoryMB int            `mapstructure:"defaultMemoryMB" toml:"defaultMemoryMB" default:"2048" commented:"true" comment:"Memory (MB) of a microVM without flavor" json:"defaultMemoryMB"`
+	MaxCPUs         int            `mapstructure:"maxCpus" toml:"maxCpus" default:"0" commented:"true" comment:"Maximum total vCPUs this hatchery may allocate. 0 means no limit" json:"maxCpus"`
+	MaxMemoryMB     int            `mapstructure:"maxMemoryMB" toml:"maxMemoryMB" default:"0" commented:"true" comment:"Maximum total memory (MB) this hatchery may allocate. 0 means no limit" json:"maxMemoryMB"`
+}
+
+// NetworkConfiguration defines the addresses of the tap devices of the microVMs
+type NetworkConfiguration struct {
+	Subnet     string `mapstructure:"subnet" toml:"subnet" default:"172.30.0.0/16" commented:"false" comment:"Each microVM gets a /30 of this subnet: the first address is set on its tap device, the second one in the guest" json:"subnet"`
+	TapPrefix  string `mapstructure:"tapPrefix" toml:"tapPrefix" default:"cdsfc" commented:"false" comment:"Prefix of the tap devices names" json:"tapPrefix"`
+	Nameserver string `mapstructure:"nameserver" toml:"nameserver" default:"" commented:"true" comment:"DNS server given to the guest kernel, available in /proc/net/pnp" json:"nameserver"`
+}
+
+// FlavorConfig defines the vCPU and memory of a microVM
+type FlavorConfig struct {
+	Name     string `mapstructure:"name" toml:"name" json:"name"`
+	CPUs     int    `mapstructure:"cpus" toml:"cpus" json:"cpus"`
+	MemoryMB int    `mapstructure:"memoryMB" toml:"memoryMB" json:"memoryMB"`
+}
+
+// HatcheryFirecracker spawns workers in firecracker microVMs
+type HatcheryFirecracker struct {
+	hatcheryCommon.Common
+	Config HatcheryConfiguration
+	sync.Mutex
+	workers map[string]*microVM
+	network *network
+}
+
+// microVM is a running firecracker process and the host resources allocated for it
+type microVM struct {
+	id       string
+	cmd      *exec.Cmd
+	created  time.Time
+	cpus     int
+	memoryMB int
+	net      vmNetwork
+	// dir contains the kernel, rootfs and api socket of the microVM
+	dir string
+	// jailed is true if paths given to the API are relative to dir
+	jailed bool
+}
@@ -17,10 +17,11 @@ func NewHatcheryKubernetesTest(t *testing.T) *HatcheryKubernetes {
 	h.Client = cdsclient.New(cdsclient.Config{Host: "http://lolcat.api", InsecureSkipVerifyTLS: false})
 	gock.InterceptClient(h.Client.(cdsclient.Raw).HTTPClient())
 
-	clientSet, errCl := kubernetes.NewForConfig(&rest.Config{Host: "http://lolcat.kube"})
+	cfg := &rest.Config{Host: "http://lolcat.kube"}
+	clientSet, errCl := kubernetes.NewForConfig(cfg)
 	require.NoError(t, errCl)
 
-	h.kubeClient = &kubernetesClient{clientSet}
+	h.kubeClient = &kubernetesClient{client: clientSet, config: cfg}
 	gock.InterceptClient(clientSet.CoreV1().RESTClient().(*rest.RESTClient).Client)
 
 	h.Config.Name = "my-hatchery"
@@ -37,8 +37,10 @@ func (h *HatcheryKubernetes) killAwolWorkers(ctx context.Context) error {
 			continue
 		}
 
+		h.reportServicesStatus(ctx, pod)
+
 		var toDelete bool
-		for _, container := range pod.Status.ContainerStatuses {
+		for _, container := range podContainerStatuses(pod) {
 			terminated := (container.State.Terminated != nil && (container.State.Terminated.Reason == "Completed" || container.State.Terminated.Reason == "Error"))
 			errImagePull := (container.State.Waiting != nil && container.State.Waiting.Reason == "ErrImagePull")
 			if terminated || errImagePull {
@@ -63,7 +65,8 @@ func (h *HatcheryKubernetes) killAwolWorkers(ctx context.Context) error {
 					break
 				}
 			}
-			if !found && time.Since(pod.CreationTimestamp.Time) > 3*time.Minute {
+			recentlyClaimed := h.warmPool != nil && h.warmPool.RecentlyClaimed(pod.Name)
+			if !found && !recentlyClaimed && time.Since(pod.CreationTimestamp.Time) > 3*time.Minute {
 				toDelete = true
 				log.Debug(ctx, "pod %s/%s didn't match a registered worker and was started since %v", pod.Namespace, pod.Name, pod.CreationTimestamp.Time)
 			}
@@ -75,7 +78,7 @@ func (h *HatcheryKubernetes) killAwolWorkers(ctx context.Context) error {
 				labels[hatchery.LabelServiceJobName] = annotations[hatchery.LabelServiceJobName]
 				// Browse container to send end log for each service
 				servicesLogs := make([]cdslog.Message, 0)
-				for _, container := range pod.Spec.Containers {
+				for _, container := range podContainers(pod) {
 					subsStr := containerServiceNameRegexp.FindAllStringSubmatch(container.Name, -1)
 					if len(subsStr) < 1 {
 						continue
@@ -139,9 +142,16 @@ func (h *HatcheryKubernetes) killAwolWorkers(ctx context.Context) error {
 			if err := h.deleteSecretByWorkerName(ctx, labels[LABEL_WORKER_NAME]); err != nil {
 				log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot delete secret for worker %s", labels[LABEL_WORKER_NAME]))
 			}
+			// The secrets of a pool worker are created with the name of its pod
+			if _, ok := labels[LABEL_WARM_POOL]; ok && pod.Name != labels[LABEL_WORKER_NAME] {
+				if err := h.deleteSecretByWorkerName(ctx, pod.Name); err != nil {
+					log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot delete secret for pool worker %s", pod.Name))
+				}
+			}
 
 			log.Debug(ctx, "pod %s/%s killed", pod.Namespace, pod.Name)
 		}
 	}
+	h.pruneServicesStatus(pods.Items)
 	return globalErr
 }
@@ -20,6 +20,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	"github.com/ovh/cds/engine/api"
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -51,6 +52,15 @@ func (h *HatcheryKubernetes) InitHatchery(ctx context.Context) error {
 		}
 	})
 
+	h.warmPool = hatcheryCommon.NewWarmPool(h.Config.WarmPool)
+	if h.warmPool != nil {
+		h.GoRoutines.Run(ctx, "hatchery kubernetes warm pool", func(ctx context.Context) {
+			h.warmPoolRoutine(ctx)
+		})
+	} else if err := h.deleteWarmPods(ctx); err != nil {
+		log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot delete pool workers"))
+	}
+
 	return nil
 }
 
@@ -146,6 +156,9 @@ func (h *HatcheryKubernetes) Status(ctx context.Context) *sdk.MonitoringStatus {
 		maxWorkerDisplay = "unlimited"
 	}
 	m.AddLine(sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%s", len(ws), maxWorkerDisplay), Status: sdk.MonitoringStatusOK})
+	if h.warmPool != nil {
+		m.AddLine(h.warmPool.MonitoringStatusLine())
+	}
 	return m
 }
 
@@ -220,6 +233,10 @@ func (h *HatcheryKubernetes) CanSpawn(ctx context.Context, model sdk.WorkerStart
 			log.Debug(ctx, "CanSpawn> Job %s with worker model %s cannot be spawned. Got osarch %s and want %s", jobID, model.ModelV2.Name, modelOSArch, h.Config.OSArch)
 			return false
 		}
+		if err := h.checkKubernetesSpec(model.GetKubernetesSpec()); err != nil {
+			log.Debug(ctx, "CanSpawn> Job %s with worker model %s cannot be spawned: %v", jobID, model.ModelV2.Name, err)
+			return false
+		}
 	}
 
 	// Service and Hostname requirement are not supported
@@ -252,11 +269,6 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		logJob = fmt.Sprintf("for workflow job %s,", spawnArgs.JobID)
 	}
 
-	cpu := h.Config.DefaultCPU
-	if cpu == "" {
-		cpu = "500m"
-	}
-
 	memory := int64(h.Config.DefaultMemory)
 	if memory == 0 {
 		memory = 1024
@@ -277,9 +289,14 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		}
 	}
 
-	ephemeralStorage := h.Config.DefaultEphemeralStorage
-	if ephemeralStorage == "" {
-		ephemeralStorage = "1Gi"
+	if h.warmPool != nil && hatcheryCommon.WarmPoolEligible(spawnArgs) {
+		key := hatcheryCommon.WarmPoolKey(spawnArgs.Model, memory)
+		h.warmPool.RecordJob(key, spawnArgs.Model, memory)
+		if h.claimWarmWorker(ctx, key, spawnArgs) {
+			h.warmPool.Hit(ctx)
+			return nil
+		}
+		h.warmPool.Miss(ctx)
 	}
 
 	workerConfig := h.GenerateWorkerConfig(ctx, h, spawnArgs)
@@ -339,20 +356,6 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		},
 	})
 
-	var limits apiv1.ResourceList
-	if h.Config.DisableCPULimit {
-		limits = apiv1.ResourceList{
-			apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
-			apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
-		}
-	} else {
-		limits = apiv1.ResourceList{
-			apiv1.ResourceCPU:              resource.MustParse(cpu),
-			apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
-			apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
-		}
-	}
-
 	var gracePeriodSecs int64
 	podSchema := apiv1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -376,14 +379,7 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 					Env:             envs,
 					Command:         strings.Fields(spawnArgs.Model.GetShell()),
 					Args:            []string{cmd},
-					Resources: apiv1.ResourceRequirements{
-						Requests: apiv1.ResourceList{
-							apiv1.ResourceCPU:              resource.MustParse(cpu),
-							apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
-							apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
-						},
-						Limits: limits,
-					},
+					Resources:       h.workerResources(memory),
 				},
 			},
 		},
@@ -396,6 +392,10 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		}
 	}
 
+	if err := applyKubernetesSpec(&podSchema, spawnArgs.Model.GetKubernetesSpec()); err != nil {
+		return sdk.WrapError(err, "cannot apply kubernetes spec of worker model %s", spawnArgs.Model.GetName())
+	}
+
 	// Check here to add secret if needed
 	if spawnArgs.Model.IsPrivate() || (spawnArgs.Model.GetDockerUsername() != "" && spawnArgs.Model.GetDockerPassword() != "") {
 		secretRegistryName, err := h.createRegistrySecret(ctx, spawnArgs.Model, workerConfig.Name)
@@ -504,7 +504,14 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 		if err != nil {
 			return err
 		}
-		podSchema.Spec.Containers = append(podSchema.Spec.Containers, serviceContainer)
+		if h.Config.DisableNativeSidecars {
+			podSchema.Spec.Containers = append(podSchema.Spec.Containers, serviceContainer)
+		} else {
+			// Native sidecars are started before the worker and stopped when the worker exits
+			restartPolicy := apiv1.ContainerRestartPolicyAlways
+			serviceContainer.RestartPolicy = &restartPolicy
+			podSchema.Spec.InitContainers = append(podSchema.Spec.InitContainers, serviceContainer)
+		}
 		podSchema.Spec.HostAliases[0].Hostnames[nService+1] = strings.ToLower(sName)
 		nService++
 	}
@@ -514,6 +521,42 @@ func (h *HatcheryKubernetes) SpawnWorker(ctx context.Context, spawnArgs hatchery
 	return sdk.WithStack(err)
 }
 
+// workerResources returns the resources of the container of a worker
+func (h *HatcheryKubernetes) workerResources(memory int64) apiv1.ResourceRequirements {
+	cpu := h.Config.DefaultCPU
+	if cpu == "" {
+		cpu = "500m"
+	}
+
+	ephemeralStorage := h.Config.DefaultEphemeralStorage
+	if ephemeralStorage == "" {
+		ephemeralStorage = "1Gi"
+	}
+
+	var limits apiv1.ResourceList
+	if h.Config.DisableCPULimit {
+		limits = apiv1.ResourceList{
+			apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
+			apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+		}
+	} else {
+		limits = apiv1.ResourceList{
+			apiv1.ResourceCPU:              resource.MustParse(cpu),
+			apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
+			apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+		}
+	}
+
+	return apiv1.ResourceRequirements{
+		Requests: apiv1.ResourceList{
+			apiv1.ResourceCPU:              resource.MustParse(cpu),
+			apiv1.ResourceMemory:           *resource.NewScaledQuantity(memory, resource.Mega),
+			apiv1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+		},
+		Limits: limits,
+	}
+}
+
 func (h *HatcheryKubernetes) SpawnWorkerService(ctx context.Context, spawnArgs hatchery.SpawnArguments, podSchema *apiv1.Pod, nService int, sName string, service sdk.V2JobService) (apiv1.Container, error) {
 	serviceMemory := int64(1024)
 	if sm, ok := service.Env["CDS_SERVICE_MEMORY"]; ok {
@@ -537,8 +580,9 @@ func (h *HatcheryKubernetes) SpawnWorkerService(ctx context.Context, spawnArgs h
 
 	serviceContainer := apiv1.Container{
 		// this name is used into service logs get, see containerServiceNameRegexp
-		Name:  fmt.Sprintf("service-%d-%s", nService, strings.ToLower(sName)),
-		Image: service.Image,
+		Name:           fmt.Sprintf("service-%d-%s", nService, strings.ToLower(sName)),
+		Image:          service.Image,
+		ReadinessProbe: serviceReadinessProbe(service.Readiness),
 		Resources: apiv1.ResourceRequirements{
 			Requests: apiv1.ResourceList{
 				apiv1.ResourceCPU:              resource.MustParse(serviceCPU),
@@ -590,6 +634,11 @@ func (h *HatcheryKubernetes) WorkersStarted(ctx context.Context) ([]string, erro
 	}
 	workerNames := make([]string, 0, list.Size())
 	for _, pod := range list.Items {
+		// A claimed pool worker keeps the name of its pod
+		if name := pod.GetLabels()[LABEL_WORKER_NAME]; name != "" {
+			workerNames = append(workerNames, name)
+			continue
+		}
 		workerNames = append(workerNames, pod.GetName())
 	}
 	return workerNames, nil
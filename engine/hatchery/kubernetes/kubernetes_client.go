@@ -1,7 +1,10 @@
 package kubernetes
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 	"os"
 	"time"
 
@@ -10,11 +13,14 @@ import (
 	"github.com/rockbears/log"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 const (
@@ -40,7 +46,7 @@ func initKubeClient(config HatcheryConfiguration) (KubernetesClient, error) {
 		if err != nil {
 			return nil, sdk.WrapError(err, "Cannot create client with newForConfig")
 		}
-		return &kubernetesClient{clientSet}, nil
+		return &kubernetesClient{client: clientSet, config: cfg}, nil
 	}
 
 	if config.KubernetesMasterURL != "" {
@@ -64,7 +70,7 @@ func initKubeClient(config HatcheryConfiguration) (KubernetesClient, error) {
 			return nil, sdk.WrapError(err, "Cannot create new config")
 		}
 
-		return &kubernetesClient{clientSet}, nil
+		return &kubernetesClient{client: clientSet, config: configK8s}, nil
 	}
 
 	cfg, err := rest.InClusterConfig()
@@ -77,7 +83,7 @@ func initKubeClient(config HatcheryConfiguration) (KubernetesClient, error) {
 		return nil, sdk.WrapError(err, "Unable to configure k8s client with InClusterConfig")
 	}
 
-	return &kubernetesClient{clientSet}, nil
+	return &kubernetesClient{client: clientSet, config: cfg}, nil
 }
 
 // getStartingConfig implements ConfigAccess
@@ -110,6 +116,8 @@ type KubernetesClient interface {
 	PodDelete(ctx context.Context, ns string, name string, options metav1.DeleteOptions) error
 	PodGetRawLogs(ctx context.Context, ns string, name string, options *corev1.PodLogOptions) ([]byte, error)
 	PodList(ctx context.Context, ns string, options metav1.ListOptions) (*corev1.PodList, error)
+	PodPatchLabels(ctx context.Context, ns string, name string, labels map[string]string) error
+	PodExec(ctx context.Context, ns string, name string, container string, cmd []string, stdin io.Reader) error
 	SecretCreate(ctx context.Context, ns string, spec *corev1.Secret, options metav1.CreateOptions) (*corev1.Secret, error)
 	SecretDelete(ctx context.Context, ns string, name string, options metav1.DeleteOptions) error
 	SecretGet(ctx context.Context, ns string, name string, options metav1.GetOptions) (*corev1.Secret, error)
@@ -119,6 +127,7 @@ type KubernetesClient interface {
 
 type kubernetesClient struct {
 	client *kubernetes.Clientset
+	config *rest.Config
 }
 
 var (
@@ -149,6 +158,41 @@ func (k *kubernetesClient) PodList(ctx context.Context, ns string, opts metav1.L
 	return pods, sdk.WrapError(err, "unable to list pods in namespace %s", ns)
 }
 
+func (k *kubernetesClient) PodPatchLabels(ctx context.Context, ns string, name string, labels map[string]string) error {
+	ctx = context.WithValue(ctx, logNS, ns)
+	ctx = context.WithValue(ctx, logPod, name)
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels}})
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	_, err = k.client.CoreV1().Pods(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return sdk.WrapError(err, "unable to patch labels of pod %s", name)
+}
+
+// PodExec runs a command in a container of a pod, stdin is given to the command
+func (k *kubernetesClient) PodExec(ctx context.Context, ns string, name string, container string, cmd []string, stdin io.Reader) error {
+	ctx = context.WithValue(ctx, logNS, ns)
+	ctx = context.WithValue(ctx, logPod, name)
+	log.Debug(ctx, "exec in pod %s", name)
+	req := k.client.CoreV1().RESTClient().Post().Resource("pods").Name(name).Namespace(ns).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
+	if err != nil {
+		return sdk.WrapError(err, "unable to exec in pod %s", name)
+	}
+	var stdout, stderr bytes.Buffer
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdin: stdin, Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return sdk.WrapError(err, "unable to exec in pod %s: %s", name, stderr.String())
+	}
+	return nil
+}
+
 func (k *kubernetesClient) SecretCreate(ctx context.Context, ns string, spec *corev1.Secret, options metav1.CreateOptions) (*corev1.Secret, error) {
 	secret, err := k.client.CoreV1().Secrets(ns).Create(ctx, spec, options)
 	return secret, sdk.WrapError(err, "unable to create secret %s", spec.Name)
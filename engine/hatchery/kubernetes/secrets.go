@@ -19,7 +19,7 @@ import (
 // This is used as a "gc", in the nominal case, the deletion of secrets is done when removing workers with killAwolWorkers
 func (h *HatcheryKubernetes) deleteSecrets(ctx context.Context) error {
 	pods, err := h.kubeClient.PodList(ctx, h.Config.Namespace, metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=%s,%s", LABEL_HATCHERY_NAME, h.Config.Name, LABEL_WORKER_NAME),
+		LabelSelector: fmt.Sprintf("%s=%s", LABEL_HATCHERY_NAME, h.Config.Name),
 	})
 	if err != nil {
 		return sdk.WrapError(err, "cannot get pods with secret")
@@ -46,7 +46,8 @@ func (h *HatcheryKubernetes) deleteSecrets(ctx context.Context) error {
 			if podLabels == nil {
 				continue
 			}
-			if w, ok := secretLabels[LABEL_WORKER_NAME]; ok && podLabels[LABEL_WORKER_NAME] == w {
+			// The secrets of a pool worker are created with the name of its pod
+			if w, ok := secretLabels[LABEL_WORKER_NAME]; ok && (podLabels[LABEL_WORKER_NAME] == w || pod.Name == w) {
 				found = true
 				break
 			}
@@ -55,7 +55,7 @@ func (h *HatcheryKubernetes) getServicesLogs(ctx context.Context) error {
 		workerName := pod.ObjectMeta.Name
 
 		var sinceSeconds int64 = 10
-		for _, container := range pod.Spec.Containers {
+		for _, container := range podContainers(pod) {
 			_, has := labels[hatchery.LabelServiceID]
 			serviceVersion, hasv2 := labels[hatchery.LabelServiceVersion]
 			if !has && !hasv2 {
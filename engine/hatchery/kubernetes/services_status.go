@@ -0,0 +1,135 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rockbears/log"
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// serviceReadinessProbe returns the readiness probe of the container of a job service, nil if the service has no readiness command
+func serviceReadinessProbe(r sdk.V2JobServiceReadiness) *apiv1.Probe {
+	if r.Command == "" {
+		return nil
+	}
+	probe := &apiv1.Probe{
+		ProbeHandler: apiv1.ProbeHandler{
+			Exec: &apiv1.ExecAction{Command: []string{"sh", "-c", r.Command}},
+		},
+	}
+	if interval, err := time.ParseDuration(r.Interval); err == nil && interval >= time.Second {
+		probe.PeriodSeconds = int32(interval.Seconds())
+	}
+	if timeout, err := time.ParseDuration(r.Timeout); err == nil && timeout >= time.Second {
+		probe.TimeoutSeconds = int32(timeout.Seconds())
+	}
+	if r.Retries > 0 {
+		probe.FailureThreshold = int32(r.Retries)
+	}
+	return probe
+}
+
+// podContainers returns the containers of a pod, services started as native sidecars are init containers
+func podContainers(pod apiv1.Pod) []apiv1.Container {
+	containers := make([]apiv1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	return append(containers, pod.Spec.Containers...)
+}
+
+// podContainerStatuses returns the statuses of the containers of a pod, including the init containers
+func podContainerStatuses(pod apiv1.Pod) []apiv1.ContainerStatus {
+	statuses := make([]apiv1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	return append(statuses, pod.Status.ContainerStatuses...)
+}
+
+// serviceFailure returns the reason why the container of a service can't run, empty if the container is starting or running
+func serviceFailure(status apiv1.ContainerStatus) (string, string) {
+	if w := status.State.Waiting; w != nil {
+		switch w.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			return "image", fmt.Sprintf("unable to pull image %s: %s", status.Image, w.Message)
+		case "CrashLoopBackOff":
+			msg := "container keeps crashing"
+			if t := status.LastTerminationState.Terminated; t != nil {
+				msg = fmt.Sprintf("container keeps crashing, last exit code %d (%s)", t.ExitCode, t.Reason)
+			}
+			return "crash", msg
+		case "CreateContainerConfigError", "CreateContainerError":
+			return "create", fmt.Sprintf("unable to create container: %s", w.Message)
+		}
+	}
+	if t := status.State.Terminated; t != nil && t.ExitCode != 0 {
+		return "exit", fmt.Sprintf("container exited with code %d (%s)", t.ExitCode, t.Reason)
+	}
+	return "", ""
+}
+
+// reportServicesStatus sends a job info for each service of a job v2 that can't start or fails before the end of the job.
+// A failure is reported once per container of a pod.
+func (h *HatcheryKubernetes) reportServicesStatus(ctx context.Context, pod apiv1.Pod) {
+	labels := pod.GetLabels()
+	if labels[hatchery.LabelServiceVersion] != hatchery.ValueLabelServiceVersion2 || pod.Status.Phase == apiv1.PodSucceeded || pod.Status.Phase == apiv1.PodFailed {
+		return
+	}
+	// Services are stopped when the worker exits
+	for _, status := range pod.Status.ContainerStatuses {
+		if len(pod.Spec.Containers) > 0 && status.Name == pod.Spec.Containers[0].Name && status.State.Terminated != nil {
+			return
+		}
+	}
+	for _, status := range podContainerStatuses(pod) {
+		subsStr := containerServiceNameRegexp.FindStringSubmatch(status.Name)
+		if len(subsStr) < 3 {
+			continue
+		}
+		failure, msg := serviceFailure(status)
+		if failure == "" || !h.markServiceStatus(pod.Name, status.Name, failure) {
+			continue
+		}
+		if err := h.CDSClientV2().V2QueuePushJobInfo(ctx, labels[hatchery.LabelServiceRegion], labels[hatchery.LabelServiceJobID], sdk.V2SendJobRunInfo{
+			Time:    time.Now(),
+			Level:   sdk.WorkflowRunInfoLevelError,
+			Message: fmt.Sprintf("service %s failed: %s", subsStr[2], msg),
+		}); err != nil {
+			log.Warn(ctx, "unable to send job info for job %s: %v", labels[hatchery.LabelServiceJobID], err)
+		}
+	}
+}
+
+// markServiceStatus records the status of the container of a service, it returns false if the status was already recorded
+func (h *HatcheryKubernetes) markServiceStatus(podName, containerName, status string) bool {
+	h.servicesStatusMutex.Lock()
+	defer h.servicesStatusMutex.Unlock()
+	if h.servicesStatus == nil {
+		h.servicesStatus = make(map[string]map[string]string)
+	}
+	if h.servicesStatus[podName] == nil {
+		h.servicesStatus[podName] = make(map[string]string)
+	}
+	if h.servicesStatus[podName][containerName] == status {
+		return false
+	}
+	h.servicesStatus[podName][containerName] = status
+	return true
+}
+
+// pruneServicesStatus forgets the status of the services of the pods that don't exist anymore
+func (h *HatcheryKubernetes) pruneServicesStatus(pods []apiv1.Pod) {
+	h.servicesStatusMutex.Lock()
+	defer h.servicesStatusMutex.Unlock()
+	names := make(map[string]struct{}, len(pods))
+	for _, pod := range pods {
+		names[pod.Name] = struct{}{}
+	}
+	for name := range h.servicesStatus {
+		if _, has := names[name]; !has {
+			delete(h.servicesStatus, name)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gopkg.in/h2non/gock.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func TestHatcheryKubernetes_SpawnWorkerServices(t *testing.T) {
+	for _, disableNativeSidecars := range []bool{false, true} {
+		t.Run(fmt.Sprintf("disableNativeSidecars=%t", disableNativeSidecars), func(t *testing.T) {
+			defer gock.Off()
+			h := NewHatcheryKubernetesTest(t)
+			h.Config.DisableNativeSidecars = disableNativeSidecars
+
+			gock.New("http://lolcat.kube").Post("/api/v1/namespaces/cds-workers/secrets").Reply(http.StatusOK).JSON(v1.Secret{})
+			var pod v1.Pod
+			gock.New("http://lolcat.kube").Post("/api/v1/namespaces/cds-workers/pods").
+				AddMatcher(func(r *http.Request, rr *gock.Request) (bool, error) {
+					btes, err := io.ReadAll(r.Body)
+					if err != nil {
+						return false, err
+					}
+					return true, json.Unmarshal(btes, &pod)
+				}).
+				Reply(http.StatusOK).JSON(v1.Pod{})
+
+			require.NoError(t, h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
+				JobID:      sdk.UUID(),
+				Model:      testWarmPoolModel(),
+				WorkerName: "my-worker",
+				Services: map[string]sdk.V2JobService{
+					"postgres": {
+						Image: "postgres:16",
+						Env:   map[string]string{"POSTGRES_PASSWORD": "pg"},
+						Readiness: sdk.V2JobServiceReadiness{
+							Command:  "pg_isready",
+							Interval: "10s",
+							Timeout:  "5s",
+							Retries:  6,
+						},
+					},
+				},
+			}))
+			require.True(t, gock.IsDone())
+
+			var service v1.Container
+			if disableNativeSidecars {
+				require.Empty(t, pod.Spec.InitContainers)
+				require.Len(t, pod.Spec.Containers, 2)
+				service = pod.Spec.Containers[1]
+				require.Nil(t, service.RestartPolicy)
+			} else {
+				require.Len(t, pod.Spec.InitContainers, 1)
+				require.Len(t, pod.Spec.Containers, 1)
+				service = pod.Spec.InitContainers[0]
+				require.Equal(t, v1.ContainerRestartPolicyAlways, *service.RestartPolicy)
+			}
+			require.Equal(t, "service-0-postgres", service.Name)
+			require.Equal(t, "postgres:16", service.Image)
+			require.Equal(t, &v1.Probe{
+				ProbeHandler:     v1.ProbeHandler{Exec: &v1.ExecAction{Command: []string{"sh", "-c", "pg_isready"}}},
+				PeriodSeconds:    10,
+				TimeoutSeconds:   5,
+				FailureThreshold: 6,
+			}, service.ReadinessProbe)
+			require.Equal(t, []string{"worker", "postgres"}, pod.Spec.HostAliases[0].Hostnames)
+		})
+	}
+}
+
+func TestHatcheryKubernetes_ReportServicesStatus(t *testing.T) {
+	h := NewHatcheryKubernetesTest(t)
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_cdsclient.NewMockHatcheryServiceClient(ctrl)
+	h.Clientv2 = mockClient
+	t.Cleanup(func() { ctrl.Finish() })
+
+	var infos []sdk.V2SendJobRunInfo
+	mockClient.EXPECT().V2QueuePushJobInfo(gomock.Any(), "my-region", "my-job", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, regionName string, jobRunID string, msg sdk.V2SendJobRunInfo) error {
+			infos = append(infos, msg)
+			return nil
+		},
+	).Times(2)
+
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-worker",
+			Labels: map[string]string{
+				LABEL_HATCHERY_NAME:           "my-hatchery",
+				LABEL_WORKER_NAME:             "my-worker",
+				hatchery.LabelServiceVersion:  hatchery.ValueLabelServiceVersion2,
+				hatchery.LabelServiceJobID:    "my-job",
+				hatchery.LabelServiceRegion:   "my-region",
+				hatchery.LabelServiceReqName:  "redis",
+				hatchery.LabelServiceRunJobID: "my-job",
+			},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "service-0-postgres"}, {Name: "service-1-redis"}},
+			Containers:     []v1.Container{{Name: "my-worker"}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodPending,
+			InitContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "service-0-postgres",
+					Image: "postgres:166",
+					State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ErrImagePull", Message: "manifest unknown"}},
+				},
+				{
+					Name:  "service-1-redis",
+					State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+
+	h.reportServicesStatus(context.TODO(), pod)
+	// A failure is reported once
+	pod.Status.InitContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+	h.reportServicesStatus(context.TODO(), pod)
+
+	pod.Status.InitContainerStatuses[1].State = v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	pod.Status.InitContainerStatuses[1].LastTerminationState = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}
+	h.reportServicesStatus(context.TODO(), pod)
+
+	require.Len(t, infos, 2)
+	require.Equal(t, sdk.WorkflowRunInfoLevelError, infos[0].Level)
+	require.Equal(t, "service postgres failed: unable to pull image postgres:166: manifest unknown", infos[0].Message)
+	require.Equal(t, "service redis failed: container keeps crashing, last exit code 1 (Error)", infos[1].Message)
+
+	// Services stopped at the end of the job are not reported
+	pod.Status.ContainerStatuses = []v1.ContainerStatus{{Name: "my-worker", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}}}
+	pod.Status.InitContainerStatuses[1].State = v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 143, Reason: "Error"}}
+	h.reportServicesStatus(context.TODO(), pod)
+
+	h.pruneServicesStatus(nil)
+	require.Empty(t, h.servicesStatus)
+}
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"regexp"
+	"sync"
 
 	"github.com/ovh/cds/engine/service"
 
@@ -11,6 +12,7 @@ import (
 const (
 	LABEL_HATCHERY_NAME = "CDS_HATCHERY_NAME"
 	LABEL_WORKER_NAME   = "CDS_WORKER_NAME"
+	LABEL_WARM_POOL     = "CDS_WARM_POOL"
 )
 
 var containerServiceNameRegexp = regexp.MustCompile(`service-([0-9]+)-(.*)`)
@@ -57,6 +59,20 @@ type HatcheryConfiguration struct {
 	DeleteSecretsInterval int `mapstructure:"deleteSecretsInterval" toml:"deleteSecretsInterval" commented:"true" comment:"Delete kubernetes worker secrets not used (seconds)" json:"deleteSecretsInterval"`
 	// KillAwolWorkersInterval used by killAwolWorkers to remove unused workers
 	KillAwolWorkersInterval int `mapstructure:"killAwolWorkersInterval" toml:"killAwolWorkersInterval" commented:"true" comment:"Kill awol worker interval (seconds)" json:"killAwolWorkersInterval"`
+	// DisableNativeSidecars starts the services of the jobs v2 as regular containers
+	DisableNativeSidecars bool `mapstructure:"disableNativeSidecars" toml:"disableNativeSidecars" default:"false" commented:"true" comment:"Start the services of the jobs v2 as regular containers instead of native sidecars, for Kubernetes clusters older than 1.29" json:"disableNativeSidecars"`
+	// WarmPool pools of pre-started workers
+	WarmPool hatcheryCommon.WarmPoolConfiguration `mapstructure:"warmPool" toml:"warmPool" comment:"Pools of pre-started workers for the worker models v2.\n Pool workers count in provision.maxWorker." json:"warmPool"`
+	// WorkerModelSpec pod settings the worker models are allowed to use
+	WorkerModelSpec WorkerModelSpecConfiguration `mapstructure:"workerModelSpec" toml:"workerModelSpec" comment:"Pod settings the worker models v2 are allowed to use in their kubernetes spec.\n Worker models using other service accounts, runtime classes, secrets or persistent volume claims are not spawned." json:"workerModelSpec"`
+}
+
+type WorkerModelSpecConfiguration struct {
+	AllowedServiceAccounts        []string `mapstructure:"allowedServiceAccounts" toml:"allowedServiceAccounts" default:"" commented:"true" comment:"Service accounts the worker pods can use" json:"allowedServiceAccounts"`
+	AllowedRuntimeClasses         []string `mapstructure:"allowedRuntimeClasses" toml:"allowedRuntimeClasses" default:"" commented:"true" comment:"Runtime classes the worker pods can use. Example: [\"gvisor\", \"nvidia\"]" json:"allowedRuntimeClasses"`
+	AllowedSecrets                []string `mapstructure:"allowedSecrets" toml:"allowedSecrets" default:"" commented:"true" comment:"Secrets of the namespace the worker pods can mount" json:"allowedSecrets"`
+	AllowedConfigMaps             []string `mapstructure:"allowedConfigMaps" toml:"allowedConfigMaps" default:"" commented:"true" comment:"Config maps of the namespace the worker pods can mount" json:"allowedConfigMaps"`
+	AllowedPersistentVolumeClaims []string `mapstructure:"allowedPersistentVolumeClaims" toml:"allowedPersistentVolumeClaims" default:"" commented:"true" comment:"Persistent volume claims of the namespace the worker pods can mount" json:"allowedPersistentVolumeClaims"`
 }
 
 type CustomAnnotation struct {
@@ -69,4 +85,8 @@ type HatcheryKubernetes struct {
 	hatcheryCommon.Common
 	Config     HatcheryConfiguration
 	kubeClient KubernetesClient
+	warmPool   *hatcheryCommon.WarmPool
+
+	servicesStatusMutex sync.Mutex
+	servicesStatus      map[string]map[string]string
 }
@@ -0,0 +1,233 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rockbears/log"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func (h *HatcheryKubernetes) warmPoolRoutine(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.warmPool.Config.RefreshInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.refillWarmPool(ctx); err != nil {
+				log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot refill warm pool"))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// listWarmPods returns the pool workers not claimed by a job
+func (h *HatcheryKubernetes) listWarmPods(ctx context.Context) ([]apiv1.Pod, error) {
+	pods, err := h.kubeClient.PodList(ctx, h.Config.Namespace, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s,!%s", LABEL_HATCHERY_NAME, h.Config.Name, LABEL_WARM_POOL, LABEL_WORKER_NAME),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// deleteWarmPods deletes the pool workers left by a previous configuration
+func (h *HatcheryKubernetes) deleteWarmPods(ctx context.Context) error {
+	pods, err := h.listWarmPods(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		if err := h.kubeClient.PodDelete(ctx, h.Config.Namespace, pod.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isWarmPodFailed returns true if the pool worker can't be claimed anymore
+func isWarmPodFailed(pod apiv1.Pod) bool {
+	if pod.Status.Phase == apiv1.PodFailed || pod.Status.Phase == apiv1.PodSucceeded {
+		return true
+	}
+	for _, c := range pod.Status.ContainerStatuses {
+		if c.State.Terminated != nil {
+			return true
+		}
+		if c.State.Waiting != nil && (c.State.Waiting.Reason == "ErrImagePull" || c.State.Waiting.Reason == "ImagePullBackOff") {
+			return true
+		}
+	}
+	return false
+}
+
+// refillWarmPool deletes the pool workers that are not needed anymore and creates the missing ones
+func (h *HatcheryKubernetes) refillWarmPool(ctx context.Context) error {
+	pods, err := h.listWarmPods(ctx)
+	if err != nil {
+		return err
+	}
+	members := make([]hatcheryCommon.WarmPoolMember, 0, len(pods))
+	for _, pod := range pods {
+		members = append(members, hatcheryCommon.WarmPoolMember{
+			ID:      pod.Name,
+			Key:     pod.Labels[LABEL_WARM_POOL],
+			Created: pod.CreationTimestamp.Time,
+			Failed:  isWarmPodFailed(pod),
+		})
+	}
+
+	capacity := math.MaxInt32
+	if h.Config.Provision.MaxWorker > 0 {
+		workers, err := h.WorkersStarted(ctx)
+		if err != nil {
+			return err
+		}
+		capacity = h.Config.Provision.MaxWorker - len(workers) - len(members)
+	}
+
+	plan := h.warmPool.Plan(ctx, members, capacity)
+	for _, m := range plan.Remove {
+		log.Debug(ctx, "hatchery> kubernetes> refillWarmPool> delete pool worker %s", m.ID)
+		if err := h.kubeClient.PodDelete(ctx, h.Config.Namespace, m.ID, metav1.DeleteOptions{}); err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+		}
+	}
+	for _, t := range plan.Create {
+		if err := h.createWarmPod(ctx, t); err != nil {
+			log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot create pool worker for model %s", t.Model.GetName()))
+		}
+	}
+	return nil
+}
+
+// createWarmPod creates a pod that waits to be claimed by a job. The configuration of the worker is given on claim.
+func (h *HatcheryKubernetes) createWarmPod(ctx context.Context, t hatcheryCommon.WarmPoolTarget) error {
+	name := hatcheryCommon.WarmPoolNamePrefix + sdk.RandomString(10)
+	workerConfig := h.GenerateWorkerConfig(ctx, h, hatchery.SpawnArguments{WorkerName: name, Model: t.Model, JobID: "0"})
+
+	tmpl, err := template.New("cmd").Parse(t.Model.GetCmd())
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, struct{ API string }{API: workerConfig.APIEndpoint}); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	envsWm := workerConfig.InjectEnvVars
+	envsWm["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", t.Memory)
+	envsWm["CDS_FROM_WORKER_IMAGE"] = "true"
+	for envName, envValue := range t.Model.GetDockerEnvs() {
+		envsWm[envName] = envValue
+	}
+	envs := make([]apiv1.EnvVar, 0, len(envsWm))
+	for envName, envValue := range envsWm {
+		envs = append(envs, apiv1.EnvVar{Name: envName, Value: envValue})
+	}
+
+	var gracePeriodSecs int64
+	podSchema := apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:                       name,
+			Namespace:                  h.Config.Namespace,
+			DeletionGracePeriodSeconds: &gracePeriodSecs,
+			Labels: map[string]string{
+				LABEL_HATCHERY_NAME: h.Configuration().Name,
+				LABEL_WARM_POOL:     t.Key,
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: apiv1.PodSpec{
+			RestartPolicy:                 apiv1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriodSecs,
+			Containers: []apiv1.Container{
+				{
+					Name:            name,
+					Image:           t.Model.GetDockerImage(),
+					ImagePullPolicy: apiv1.PullAlways,
+					Env:             envs,
+					Command:         strings.Fields(t.Model.GetShell()),
+					Args:            []string{hatcheryCommon.WarmPoolCommand(buffer.String())},
+					Resources:       h.workerResources(t.Memory),
+				},
+			},
+		},
+	}
+
+	for _, a := range h.Config.CustomAnnotations {
+		if a.Key != "" && a.Value != "" {
+			podSchema.Annotations[a.Key] = a.Value
+		}
+	}
+
+	if err := applyKubernetesSpec(&podSchema, t.Model.GetKubernetesSpec()); err != nil {
+		return err
+	}
+
+	if t.Model.IsPrivate() || (t.Model.GetDockerUsername() != "" && t.Model.GetDockerPassword() != "") {
+		secretRegistryName, err := h.createRegistrySecret(ctx, t.Model, name)
+		if err != nil {
+			return sdk.WrapError(err, "cannot create registry secret for pool worker %s", name)
+		}
+		podSchema.Spec.ImagePullSecrets = []apiv1.LocalObjectReference{{Name: secretRegistryName}}
+	}
+
+	_, err = h.kubeClient.PodCreate(ctx, h.Config.Namespace, &podSchema, metav1.CreateOptions{})
+	return sdk.WithStack(err)
+}
+
+// claimWarmWorker gives the configuration of the worker of a job to an idle pool worker.
+// It returns false if there is no pool worker for the job, or if it can't be claimed.
+func (h *HatcheryKubernetes) claimWarmWorker(ctx context.Context, key string, spawnArgs hatchery.SpawnArguments) bool {
+	pods, err := h.listWarmPods(ctx)
+	if err != nil {
+		log.ErrorWithStackTrace(ctx, err)
+		return false
+	}
+	for _, pod := range pods {
+		if pod.Labels[LABEL_WARM_POOL] != key || pod.Status.Phase != apiv1.PodRunning || isWarmPodFailed(pod) || !h.warmPool.Claim(pod.Name) {
+			continue
+		}
+		// The label of the worker name links the pod to its worker
+		if err := h.kubeClient.PodPatchLabels(ctx, h.Config.Namespace, pod.Name, map[string]string{LABEL_WORKER_NAME: spawnArgs.WorkerName}); err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+			h.warmPool.Release(pod.Name)
+			continue
+		}
+
+		workerConfig := h.GenerateWorkerConfig(ctx, h, spawnArgs)
+		stdin := bytes.NewReader(hatcheryCommon.WarmPoolClaimEnv(workerConfig.EncodeBase64()))
+		if err := h.kubeClient.PodExec(ctx, h.Config.Namespace, pod.Name, pod.Spec.Containers[0].Name, hatcheryCommon.WarmPoolClaimCommand(), stdin); err != nil {
+			log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "cannot claim pool worker %s", pod.Name))
+			if err := h.kubeClient.PodDelete(ctx, h.Config.Namespace, pod.Name, metav1.DeleteOptions{}); err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+			}
+			return false
+		}
+
+		if err := h.CDSClientV2().V2QueuePushJobInfo(ctx, spawnArgs.Region, spawnArgs.JobID, sdk.V2SendJobRunInfo{
+			Time:    time.Now(),
+			Level:   sdk.WorkflowRunInfoLevelInfo,
+			Message: fmt.Sprintf("worker %s started from the warm pool of %s in pod %s", spawnArgs.WorkerName, spawnArgs.Model.GetDockerImage(), pod.Name),
+		}); err != nil {
+			log.Warn(ctx, "unable to send job info for job %s: %v", spawnArgs.JobID, err)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func testWarmPoolModel() sdk.WorkerStarterWorkerModel {
+	return sdk.WorkerStarterWorkerModel{
+		ModelV2:    &sdk.V2WorkerModel{Name: "my-model", OSArch: "linux/amd64"},
+		Cmd:        "worker --api {{.API}}",
+		Shell:      "sh -c",
+		DockerSpec: sdk.V2WorkerModelDockerSpec{Image: "model:9"},
+	}
+}
+
+func TestHatcheryKubernetes_RefillWarmPool(t *testing.T) {
+	require.NoError(t, hatchery.InitMetrics(context.Background()))
+	defer gock.Off()
+	h := NewHatcheryKubernetesTest(t)
+	h.warmPool = hatcheryCommon.NewWarmPool(hatcheryCommon.WarmPoolConfiguration{Enable: true, MaxSize: 3, MaxTotal: 10, IdleTimeout: 1800})
+
+	model := testWarmPoolModel()
+	key := hatcheryCommon.WarmPoolKey(model, 1024)
+	h.warmPool.RecordJob(key, model, 1024)
+	h.warmPool.RecordJob(key, model, 1024)
+
+	podsList := v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "cds-warm-failed",
+					Labels:            map[string]string{LABEL_HATCHERY_NAME: "my-hatchery", LABEL_WARM_POOL: key},
+					CreationTimestamp: metav1.Now(),
+				},
+				Status: v1.PodStatus{Phase: v1.PodFailed},
+			},
+		},
+	}
+	gock.New("http://lolcat.kube").Get("/api/v1/namespaces/cds-workers/pods").
+		MatchParam("labelSelector", "CDS_HATCHERY_NAME=my-hatchery,CDS_WARM_POOL,!CDS_WORKER_NAME").
+		Reply(http.StatusOK).JSON(podsList)
+	gock.New("http://lolcat.kube").Delete("/api/v1/namespaces/cds-workers/pods/cds-warm-failed").Reply(http.StatusOK).JSON(nil)
+
+	var created []v1.Pod
+	gock.New("http://lolcat.kube").Post("/api/v1/namespaces/cds-workers/pods").Times(2).
+		AddMatcher(func(r *http.Request, rr *gock.Request) (bool, error) {
+			btes, err := io.ReadAll(r.Body)
+			if err != nil {
+				return false, err
+			}
+			var pod v1.Pod
+			if err := json.Unmarshal(btes, &pod); err != nil {
+				return false, err
+			}
+			created = append(created, pod)
+			return true, nil
+		}).
+		Reply(http.StatusOK).JSON(v1.Pod{})
+
+	require.NoError(t, h.refillWarmPool(context.TODO()))
+	require.True(t, gock.IsDone())
+
+	require.Len(t, created, 2)
+	for _, pod := range created {
+		require.Equal(t, key, pod.Labels[LABEL_WARM_POOL])
+		require.NotContains(t, pod.Labels, LABEL_WORKER_NAME)
+		require.Equal(t, "model:9", pod.Spec.Containers[0].Image)
+		require.Equal(t, []string{"sh", "-c"}, pod.Spec.Containers[0].Command)
+		require.Contains(t, pod.Spec.Containers[0].Args[0], "/tmp/.cds-worker.ready")
+		for _, e := range pod.Spec.Containers[0].Env {
+			require.NotEqual(t, "CDS_CONFIG", e.Name)
+		}
+	}
+}
+
+func TestHatcheryKubernetes_ClaimWarmWorkerError(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryKubernetesTest(t)
+	h.warmPool = hatcheryCommon.NewWarmPool(hatcheryCommon.WarmPoolConfiguration{Enable: true})
+
+	model := testWarmPoolModel()
+	key := hatcheryCommon.WarmPoolKey(model, 1024)
+
+	podsList := v1.PodList{
+		Items: []v1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cds-warm-pending",
+					Labels: map[string]string{LABEL_HATCHERY_NAME: "my-hatchery", LABEL_WARM_POOL: key},
+				},
+				Status: v1.PodStatus{Phase: v1.PodPending},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "cds-warm-running",
+					Labels: map[string]string{LABEL_HATCHERY_NAME: "my-hatchery", LABEL_WARM_POOL: key},
+				},
+				Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "cds-warm-running"}}},
+				Status: v1.PodStatus{Phase: v1.PodRunning},
+			},
+		},
+	}
+	gock.New("http://lolcat.kube").Get("/api/v1/namespaces/cds-workers/pods").Reply(http.StatusOK).JSON(podsList)
+	gock.New("http://lolcat.kube").Patch("/api/v1/namespaces/cds-workers/pods/cds-warm-running").Reply(http.StatusInternalServerError)
+
+	claimed := h.claimWarmWorker(context.TODO(), key, hatchery.SpawnArguments{
+		JobID:      sdk.UUID(),
+		Model:      model,
+		WorkerName: "my-worker",
+	})
+	require.False(t, claimed)
+	require.True(t, gock.IsDone())
+	// The pool worker can be claimed by another job
+	require.True(t, h.warmPool.Claim("cds-warm-running"))
+}
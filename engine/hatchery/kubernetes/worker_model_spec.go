@@ -0,0 +1,100 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"slices"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// kubernetesSpec is the kubernetes spec of a worker model decoded with the kubernetes API types,
+// the fields of the worker model spec are named as in the kubernetes API.
+type kubernetesSpec struct {
+	apiv1.PodSpec
+	VolumeMounts []apiv1.VolumeMount        `json:"volumeMounts"`
+	Resources    apiv1.ResourceRequirements `json:"resources"`
+	Sidecars     []apiv1.Container          `json:"sidecars"`
+}
+
+// checkKubernetesSpec returns an error if the kubernetes spec of a worker model uses settings not allowed by the hatchery
+func (h *HatcheryKubernetes) checkKubernetesSpec(spec *sdk.V2WorkerModelKubernetesSpec) error {
+	if spec == nil {
+		return nil
+	}
+	cfg := h.Config.WorkerModelSpec
+	if spec.ServiceAccountName != "" && !slices.Contains(cfg.AllowedServiceAccounts, spec.ServiceAccountName) {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "service account %s is not allowed", spec.ServiceAccountName)
+	}
+	if spec.RuntimeClassName != "" && !slices.Contains(cfg.AllowedRuntimeClasses, spec.RuntimeClassName) {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "runtime class %s is not allowed", spec.RuntimeClassName)
+	}
+	for _, v := range spec.Volumes {
+		if v.Secret != nil && !slices.Contains(cfg.AllowedSecrets, v.Secret.SecretName) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "secret %s is not allowed", v.Secret.SecretName)
+		}
+		if v.ConfigMap != nil && !slices.Contains(cfg.AllowedConfigMaps, v.ConfigMap.Name) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "config map %s is not allowed", v.ConfigMap.Name)
+		}
+		if v.PersistentVolumeClaim != nil && !slices.Contains(cfg.AllowedPersistentVolumeClaims, v.PersistentVolumeClaim.ClaimName) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "persistent volume claim %s is not allowed", v.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return nil
+}
+
+// applyKubernetesSpec merges the kubernetes spec of a worker model into the pod of a worker.
+// The worker container must be the first container of the pod.
+func applyKubernetesSpec(pod *apiv1.Pod, spec *sdk.V2WorkerModelKubernetesSpec) error {
+	if spec == nil {
+		return nil
+	}
+	btes, err := json.Marshal(spec)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	var s kubernetesSpec
+	if err := json.Unmarshal(btes, &s); err != nil {
+		return sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid kubernetes spec: %v", err)
+	}
+
+	if len(s.NodeSelector) > 0 && pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string, len(s.NodeSelector))
+	}
+	for k, v := range s.NodeSelector {
+		pod.Spec.NodeSelector[k] = v
+	}
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, s.Tolerations...)
+	if s.Affinity != nil {
+		pod.Spec.Affinity = s.Affinity
+	}
+	if s.ServiceAccountName != "" {
+		pod.Spec.ServiceAccountName = s.ServiceAccountName
+	}
+	if s.RuntimeClassName != nil {
+		pod.Spec.RuntimeClassName = s.RuntimeClassName
+	}
+	if s.SecurityContext != nil {
+		pod.Spec.SecurityContext = s.SecurityContext
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, s.Volumes...)
+
+	worker := &pod.Spec.Containers[0]
+	worker.VolumeMounts = append(worker.VolumeMounts, s.VolumeMounts...)
+	if len(s.Resources.Requests) > 0 && worker.Resources.Requests == nil {
+		worker.Resources.Requests = make(apiv1.ResourceList, len(s.Resources.Requests))
+	}
+	for name, quantity := range s.Resources.Requests {
+		worker.Resources.Requests[name] = quantity
+	}
+	if len(s.Resources.Limits) > 0 && worker.Resources.Limits == nil {
+		worker.Resources.Limits = make(apiv1.ResourceList, len(s.Resources.Limits))
+	}
+	for name, quantity := range s.Resources.Limits {
+		worker.Resources.Limits[name] = quantity
+	}
+
+	pod.Spec.Containers = append(pod.Spec.Containers, s.Sidecars...)
+	return nil
+}
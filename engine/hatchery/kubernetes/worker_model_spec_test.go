@@ -0,0 +1,134 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func testKubernetesSpecModel() sdk.WorkerStarterWorkerModel {
+	runAsNonRoot := true
+	return sdk.WorkerStarterWorkerModel{
+		ModelV2: &sdk.V2WorkerModel{Name: "cuda", OSArch: "linux/amd64"},
+		Cmd:     "worker --api {{.API}}",
+		Shell:   "sh -c",
+		DockerSpec: sdk.V2WorkerModelDockerSpec{
+			Image: "nvidia/cuda:12.3.1-base-ubuntu22.04",
+			Kubernetes: &sdk.V2WorkerModelKubernetesSpec{
+				NodeSelector: map[string]string{"cloud.google.com/gke-accelerator": "nvidia-tesla-t4"},
+				Tolerations:  []sdk.V2WorkerModelKubernetesToleration{{Key: "nvidia.com/gpu", Operator: "Exists", Effect: "NoSchedule"}},
+				Affinity: &sdk.V2WorkerModelKubernetesAffinity{
+					NodeAffinity: &sdk.V2WorkerModelKubernetesNodeAffinity{
+						Required: &sdk.V2WorkerModelKubernetesNodeSelector{
+							NodeSelectorTerms: []sdk.V2WorkerModelKubernetesNodeSelectorTerm{{
+								MatchExpressions: []sdk.V2WorkerModelKubernetesNodeSelectorRequirement{{Key: "kubernetes.io/arch", Operator: "In", Values: []string{"amd64"}}},
+							}},
+						},
+					},
+				},
+				ServiceAccountName: "cds-gpu",
+				RuntimeClassName:   "nvidia",
+				SecurityContext:    &sdk.V2WorkerModelKubernetesSecurityContext{RunAsNonRoot: &runAsNonRoot},
+				Resources:          &sdk.V2WorkerModelKubernetesResources{Limits: map[string]string{"nvidia.com/gpu": "1"}},
+				Volumes: []sdk.V2WorkerModelKubernetesVolume{
+					{Name: "cache", EmptyDir: &sdk.V2WorkerModelKubernetesEmptyDir{SizeLimit: "10Gi"}},
+					{Name: "datasets", PersistentVolumeClaim: &sdk.V2WorkerModelKubernetesPersistentVolume{ClaimName: "datasets", ReadOnly: true}},
+				},
+				VolumeMounts: []sdk.V2WorkerModelKubernetesVolumeMount{{Name: "datasets", MountPath: "/datasets", ReadOnly: true}},
+				Sidecars: []sdk.V2WorkerModelKubernetesSidecar{{
+					Name:         "docker",
+					Image:        "docker:dind-rootless",
+					Env:          []sdk.V2WorkerModelKubernetesEnvVar{{Name: "DOCKER_TLS_CERTDIR", Value: ""}},
+					VolumeMounts: []sdk.V2WorkerModelKubernetesVolumeMount{{Name: "cache", MountPath: "/var/lib/docker"}},
+				}},
+			},
+		},
+	}
+}
+
+func TestHatcheryKubernetes_CanSpawnKubernetesSpec(t *testing.T) {
+	h := NewHatcheryKubernetesTest(t)
+	h.Config.OSArch = []string{"linux/amd64"}
+	model := testKubernetesSpecModel()
+
+	require.False(t, h.CanSpawn(context.TODO(), model, sdk.UUID(), nil))
+
+	h.Config.WorkerModelSpec = WorkerModelSpecConfiguration{
+		AllowedServiceAccounts:        []string{"cds-gpu"},
+		AllowedRuntimeClasses:         []string{"nvidia"},
+		AllowedPersistentVolumeClaims: []string{"datasets"},
+	}
+	require.True(t, h.CanSpawn(context.TODO(), model, sdk.UUID(), nil))
+
+	model.DockerSpec.Kubernetes.Volumes = append(model.DockerSpec.Kubernetes.Volumes, sdk.V2WorkerModelKubernetesVolume{
+		Name:      "certs",
+		ConfigMap: &sdk.V2WorkerModelKubernetesConfigMap{Name: "ca-certificates"},
+	})
+	require.False(t, h.CanSpawn(context.TODO(), model, sdk.UUID(), nil))
+
+	h.Config.WorkerModelSpec.AllowedConfigMaps = []string{"ca-certificates"}
+	require.True(t, h.CanSpawn(context.TODO(), model, sdk.UUID(), nil))
+
+	model.DockerSpec.Kubernetes.Volumes = append(model.DockerSpec.Kubernetes.Volumes, sdk.V2WorkerModelKubernetesVolume{
+		Name:   "config",
+		Secret: &sdk.V2WorkerModelKubernetesSecret{SecretName: "cds-worker-config-other"},
+	})
+	require.False(t, h.CanSpawn(context.TODO(), model, sdk.UUID(), nil))
+}
+
+func TestHatcheryKubernetes_SpawnWorkerKubernetesSpec(t *testing.T) {
+	defer gock.Off()
+	h := NewHatcheryKubernetesTest(t)
+
+	gock.New("http://lolcat.kube").Post("/api/v1/namespaces/cds-workers/secrets").Reply(http.StatusOK).JSON(v1.Secret{})
+	var pod v1.Pod
+	gock.New("http://lolcat.kube").Post("/api/v1/namespaces/cds-workers/pods").
+		AddMatcher(func(r *http.Request, rr *gock.Request) (bool, error) {
+			btes, err := io.ReadAll(r.Body)
+			if err != nil {
+				return false, err
+			}
+			return true, json.Unmarshal(btes, &pod)
+		}).
+		Reply(http.StatusOK).JSON(v1.Pod{})
+
+	require.NoError(t, h.SpawnWorker(context.TODO(), hatchery.SpawnArguments{
+		JobID:      sdk.UUID(),
+		Model:      testKubernetesSpecModel(),
+		WorkerName: "my-worker",
+	}))
+	require.True(t, gock.IsDone())
+
+	require.Equal(t, map[string]string{"cloud.google.com/gke-accelerator": "nvidia-tesla-t4"}, pod.Spec.NodeSelector)
+	require.Equal(t, []v1.Toleration{{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}}, pod.Spec.Tolerations)
+	require.Equal(t, "kubernetes.io/arch", pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Key)
+	require.Equal(t, "cds-gpu", pod.Spec.ServiceAccountName)
+	require.Equal(t, "nvidia", *pod.Spec.RuntimeClassName)
+	require.True(t, *pod.Spec.SecurityContext.RunAsNonRoot)
+	require.Len(t, pod.Spec.Volumes, 2)
+	require.Equal(t, resource.MustParse("10Gi"), *pod.Spec.Volumes[0].EmptyDir.SizeLimit)
+	require.Equal(t, "datasets", pod.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+
+	require.Len(t, pod.Spec.Containers, 2)
+	worker := pod.Spec.Containers[0]
+	require.Equal(t, "my-worker", worker.Name)
+	require.Equal(t, []v1.VolumeMount{{Name: "datasets", MountPath: "/datasets", ReadOnly: true}}, worker.VolumeMounts)
+	require.Equal(t, resource.MustParse("1"), worker.Resources.Limits["nvidia.com/gpu"])
+	require.Contains(t, worker.Resources.Limits, v1.ResourceMemory)
+
+	sidecar := pod.Spec.Containers[1]
+	require.Equal(t, "docker", sidecar.Name)
+	require.Equal(t, "docker:dind-rootless", sidecar.Image)
+	require.Equal(t, []v1.EnvVar{{Name: "DOCKER_TLS_CERTDIR"}}, sidecar.Env)
+	require.Equal(t, []v1.VolumeMount{{Name: "cache", MountPath: "/var/lib/docker"}}, sidecar.VolumeMounts)
+}
@@ -120,6 +120,10 @@ func (h *HatcheryLocal) CheckConfiguration(cfg interface{}) error {
 	} else if err != nil {
 		return fmt.Errorf("Invalid basedir: %v", err)
 	}
+
+	if err := checkSandboxConfiguration(hconfig.Sandbox); err != nil {
+		return fmt.Errorf("Invalid hatchery local configuration: %v", err)
+	}
 	return nil
 }
 
@@ -154,6 +158,12 @@ func (h *HatcheryLocal) Serve(ctx context.Context) error {
 		return sdk.NewErrorFrom(err, "cannot download worker binary from api")
 	}
 
+	if h.Config.Sandbox.Enable {
+		if err := h.initSandbox(); err != nil {
+			return err
+		}
+	}
+
 	return h.CommonServe(ctx, h)
 }
 
@@ -222,7 +232,7 @@ func (h *HatcheryLocal) Configuration() service.HatcheryCommonConfiguration {
 }
 
 // CanSpawn return wether or not hatchery can spawn model.
-// requirements are not supported
+// service requirements are not supported, memory and flavor requirements only in sandbox mode
 func (h *HatcheryLocal) CanSpawn(ctx context.Context, _ sdk.WorkerStarterWorkerModel, jobID string, requirements []sdk.Requirement) bool {
 	ctx, end := telemetry.Span(ctx, "local.CanSpawn")
 	defer end()
@@ -235,8 +245,12 @@ func (h *HatcheryLocal) CanSpawn(ctx context.Context, _ sdk.WorkerStarterWorkerM
 	}
 
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement || r.Type == sdk.FlavorRequirement {
-			log.Debug(ctx, "CanSpawn false service or memory")
+		if r.Type == sdk.ServiceRequirement {
+			log.Debug(ctx, "CanSpawn false service")
+			return false
+		}
+		if (r.Type == sdk.MemoryRequirement || r.Type == sdk.FlavorRequirement) && !h.Config.Sandbox.Enable {
+			log.Debug(ctx, "CanSpawn false memory or flavor without sandbox")
 			return false
 		}
 
@@ -252,6 +266,12 @@ func (h *HatcheryLocal) CanSpawn(ctx context.Context, _ sdk.WorkerStarterWorkerM
 // killWorker kill a local process
 func (h *HatcheryLocal) killWorker(ctx context.Context, name string, workerCmd workerCmd) error {
 	log.Info(ctx, "KillLocalWorker> Killing %s", name)
+	if workerCmd.sandbox != nil {
+		// Kill all the processes of the job, not only the worker
+		if err := workerCmd.sandbox.kill(); err != nil {
+			log.Warn(ctx, "KillLocalWorker> %v", err)
+		}
+	}
 	return workerCmd.cmd.Process.Kill()
 }
 
@@ -313,6 +333,9 @@ func (h *HatcheryLocal) localWorkerIndexCleanup() {
 		if workerCmd.cmd.ProcessState != nil && workerCmd.cmd.ProcessState.Exited() {
 			log.Debug(context.TODO(), "process %s has been removed", name)
 			needToDeleteWorkers = append(needToDeleteWorkers, name)
+			if workerCmd.sandbox != nil {
+				workerCmd.sandbox.cleanup(context.TODO())
+			}
 		}
 	}
 
@@ -360,6 +383,9 @@ func (h *HatcheryLocal) killAwolWorkers() error {
 			if err := h.killWorker(ctx, name, workerCmd); err != nil {
 				log.Warn(ctx, "Error killing worker %s :%s", name, err)
 			}
+			if workerCmd.sandbox != nil {
+				workerCmd.sandbox.cleanup(ctx)
+			}
 			killedWorkers = append(killedWorkers, name)
 		}
 	}
@@ -403,6 +429,8 @@ func (h *HatcheryLocal) checkRequirement(r sdk.Requirement) (bool, error) {
 		return h == r.Value, nil
 	case sdk.SecretRequirement:
 		return true, nil
+	case sdk.MemoryRequirement, sdk.FlavorRequirement:
+		return h.Config.Sandbox.Enable, nil
 	default:
 		log.Debug(context.TODO(), "checkRequirement> %v don't work on this hatchery", r.Type)
 		return false, nil
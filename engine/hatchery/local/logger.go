@@ -17,7 +17,7 @@ type Logger interface {
 	Fatalf(fmt string, values ...interface{})
 }
 
-func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) error {
+func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, sb *sandbox, logger Logger) error {
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("Failure due to internal error: unable to capture stdout: %v", err)
@@ -62,13 +62,23 @@ func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) erro
 	}()
 
 	if err := cmd.Start(); err != nil {
+		if sb != nil {
+			sb.cleanup(context.Background())
+		}
 		return fmt.Errorf("unable to start command: %v", err)
 	}
 
 	h.Lock()
-	h.workers[name] = workerCmd{cmd: cmd, created: time.Now()}
+	h.workers[name] = workerCmd{cmd: cmd, created: time.Now(), sandbox: sb}
 	h.Unlock()
 
+	if sb != nil {
+		if err := sb.start(context.Background(), h.Config.Sandbox, cmd.Process.Pid); err != nil {
+			logger.Errorf("unable to start sandbox: %v", err)
+			_ = cmd.Process.Kill()
+		}
+	}
+
 	<-outchan
 	<-errchan
 	if err := cmd.Wait(); err != nil {
@@ -0,0 +1,300 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	sandboxNetworkHost        = "host"
+	sandboxNetworkSlirp4netns = "slirp4netns"
+)
+
+// sandboxInitScript runs as root of the new namespaces before the worker. It waits for the hatchery
+// to configure the sandbox from the host, then mounts a private /proc, a tmpfs workspace and a tmpfs /tmp
+// that are only visible in the mount namespace of the worker.
+const sandboxInitScript = `set -e
+workspace="$1"; size="$2"; network="$3"; shift 3
+read -r _ <&3 || true
+exec 3<&-
+mount --make-rprivate /
+mount -t proc proc /proc
+mount -t tmpfs -o size="${size}m",mode=0700 tmpfs "$workspace"
+mount -t tmpfs -o mode=1777 tmpfs /tmp
+if [ "$network" = "slirp4netns" ]; then
+  echo "nameserver 10.0.2.3" > /tmp/.resolv.conf
+  mount --bind /tmp/.resolv.conf /etc/resolv.conf
+fi
+cd "$workspace"
+exec "$@"
+`
+
+// sandbox holds the host resources that isolate a worker
+type sandbox struct {
+	name      string
+	basedir   string
+	cgroupDir string
+	cgroup    *os.File
+	// init is given to the worker as fd 3, the hatchery writes on ready when the sandbox is configured
+	init  *os.File
+	ready *os.File
+	slirp *exec.Cmd
+}
+
+// checkSandboxConfiguration checks the sandbox configuration if it is enabled
+func checkSandboxConfiguration(cfg SandboxConfiguration) error {
+	if !cfg.Enable {
+		return nil
+	}
+	if sdk.GOOS != "linux" {
+		return fmt.Errorf("sandbox is only supported on Linux")
+	}
+	// The root of the sandbox must not be the hatchery user, that owns the workspaces of the other workers and the hatchery files
+	if cfg.UID <= 0 || cfg.GID <= 0 {
+		return fmt.Errorf("invalid sandbox configuration: uid and gid of a dedicated host user are mandatory")
+	}
+	if cfg.UID == os.Getuid() || cfg.GID == os.Getgid() {
+		return fmt.Errorf("invalid sandbox configuration: uid and gid must differ from the user and group of the hatchery")
+	}
+	if !filepath.IsAbs(cfg.CgroupParent) {
+		return fmt.Errorf("invalid sandbox cgroupParent %q: absolute path expected", cfg.CgroupParent)
+	}
+	switch cfg.Network {
+	case sandboxNetworkHost:
+	case sandboxNetworkSlirp4netns:
+		if _, err := exec.LookPath(cfg.Slirp4netns); err != nil {
+			return fmt.Errorf("invalid sandbox slirp4netns binary: %v", err)
+		}
+	default:
+		return fmt.Errorf("invalid sandbox network %q: host or slirp4netns expected", cfg.Network)
+	}
+	if cfg.WorkspaceSizeMB <= 0 || cfg.DefaultCPUs <= 0 || cfg.DefaultMemoryMB <= 0 {
+		return fmt.Errorf("invalid sandbox configuration: workspaceSizeMB, defaultCpus and defaultMemoryMB must be positive")
+	}
+	for _, f := range cfg.Flavors {
+		if f.Name == "" || f.CPUs <= 0 || f.MemoryMB <= 0 {
+			return fmt.Errorf("invalid sandbox flavor %q: name, cpus and memoryMB are mandatory", f.Name)
+		}
+	}
+	return nil
+}
+
+// initSandbox creates the parent cgroup of the workers and enables the controllers used for their limits
+func (h *HatcheryLocal) initSandbox() error {
+	cfg := h.Config.Sandbox
+	if err := os.MkdirAll(cfg.CgroupParent, 0755); err != nil {
+		return sdk.WrapError(err, "unable to create cgroup %s", cfg.CgroupParent)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.CgroupParent, "cgroup.subtree_control"), []byte("+cpu +memory +pids"), 0644); err != nil {
+		return sdk.WrapError(err, "unable to enable cpu, memory and pids controllers in cgroup %s", cfg.CgroupParent)
+	}
+	// The worker binary must be readable by the user mapped to root in the sandbox
+	for _, p := range []string{h.BasedirDedicated, filepath.Join(h.BasedirDedicated, h.getWorkerBinaryName())} {
+		if err := os.Chown(p, cfg.UID, cfg.GID); err != nil {
+			return sdk.WrapError(err, "unable to change owner of %s", p)
+		}
+	}
+	return nil
+}
+
+// sandboxResources returns the flavor, CPUs and memory of a worker from the flavor and memory of the job
+func (cfg SandboxConfiguration) sandboxResources(model sdk.WorkerStarterWorkerModel, requirements []sdk.Requirement) (string, int, int) {
+	flavorName := model.Flavor
+	var memoryMB int64 = model.Memory
+	for _, r := range requirements {
+		switch r.Type {
+		case sdk.FlavorRequirement:
+			flavorName = r.Value
+		case sdk.MemoryRequirement:
+			if m, err := strconv.ParseInt(r.Value, 10, 64); err == nil {
+				memoryMB = m
+			}
+		}
+	}
+
+	cpus, mem := cfg.DefaultCPUs, cfg.DefaultMemoryMB
+	name := ""
+	for _, f := range cfg.Flavors {
+		if f.Name == flavorName {
+			name, cpus, mem = f.Name, f.CPUs, f.MemoryMB
+			break
+		}
+	}
+	if memoryMB > 0 {
+		mem = int(memoryMB)
+	}
+	return name, cpus, mem
+}
+
+// newSandbox creates the cgroup of a worker with its limits
+func (h *HatcheryLocal) newSandbox(name, basedir string, cpus, memoryMB int) (*sandbox, error) {
+	cfg := h.Config.Sandbox
+	sb := &sandbox{
+		name:      name,
+		basedir:   basedir,
+		cgroupDir: filepath.Join(cfg.CgroupParent, filepath.Base(basedir)),
+	}
+	if err := os.Mkdir(sb.cgroupDir, 0755); err != nil {
+		return nil, sdk.WrapError(err, "unable to create cgroup %s", sb.cgroupDir)
+	}
+	if err := writeCgroupLimits(sb.cgroupDir, cpus, memoryMB, cfg.PidsMax); err != nil {
+		sb.cleanup(context.Background())
+		return nil, err
+	}
+	var err error
+	sb.cgroup, err = os.Open(sb.cgroupDir)
+	if err != nil {
+		sb.cleanup(context.Background())
+		return nil, sdk.WithStack(err)
+	}
+	sb.init, sb.ready, err = os.Pipe()
+	if err != nil {
+		sb.cleanup(context.Background())
+		return nil, sdk.WithStack(err)
+	}
+	return sb, nil
+}
+
+func writeCgroupLimits(dir string, cpus, memoryMB, pidsMax int) error {
+	limits := [][2]string{
+		{"memory.max", strconv.Itoa(memoryMB * 1024 * 1024)},
+		{"memory.swap.max", "0"},
+		{"cpu.max", fmt.Sprintf("%d 100000", cpus*100000)},
+	}
+	if pidsMax > 0 {
+		limits = append(limits, [2]string{"pids.max", strconv.Itoa(pidsMax)})
+	}
+	for _, l := range limits {
+		if err := os.WriteFile(filepath.Join(dir, l[0]), []byte(l[1]), 0644); err != nil {
+			// Swap accounting may be disabled on the host
+			if l[0] == "memory.swap.max" && os.IsNotExist(err) {
+				continue
+			}
+			return sdk.WrapError(err, "unable to set %s of cgroup %s", l[0], dir)
+		}
+	}
+	return nil
+}
+
+// sandboxCmd returns the command that runs the worker in new namespaces, in the cgroup of the sandbox
+func (h *HatcheryLocal) sandboxCmd(sb *sandbox, workerBinary string, args ...string) (*exec.Cmd, error) {
+	cfg := h.Config.Sandbox
+	attr, err := sandboxSysProcAttr(cfg.UID, cfg.GID, int(sb.cgroup.Fd()), cfg.Network == sandboxNetworkSlirp4netns)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chown(sb.basedir, cfg.UID, cfg.GID); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	shArgs := append([]string{"-c", sandboxInitScript, "cds-sandbox", sb.basedir, strconv.Itoa(cfg.WorkspaceSizeMB), cfg.Network, workerBinary}, args...)
+	cmd := h.LocalWorkerRunner.NewCmd(context.Background(), "/bin/sh", shArgs...)
+	cmd.SysProcAttr = attr
+	cmd.ExtraFiles = []*os.File{sb.init}
+	return cmd, nil
+}
+
+// start configures the sandbox from the host once the process is started, then releases the worker
+func (sb *sandbox) start(ctx context.Context, cfg SandboxConfiguration, pid int) error {
+	// The child has its own copy of the read end of the pipe
+	_ = sb.init.Close()
+	defer sb.ready.Close() // nolint
+
+	if cfg.Network == sandboxNetworkSlirp4netns {
+		if err := sb.startSlirp4netns(ctx, cfg.Slirp4netns, pid); err != nil {
+			return err
+		}
+	}
+	if _, err := sb.ready.Write([]byte("\n")); err != nil {
+		return sdk.WrapError(err, "unable to release sandbox of worker %s", sb.name)
+	}
+	return nil
+}
+
+// startSlirp4netns connects the network namespace of the worker to the host with a user-mode network stack
+func (sb *sandbox) startSlirp4netns(ctx context.Context, binary string, pid int) error {
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer readyR.Close() // nolint
+
+	sb.slirp = exec.Command(binary, "--configure", "--mtu=65520", "--disable-host-loopback", "--ready-fd=3", strconv.Itoa(pid), "tap0")
+	sb.slirp.ExtraFiles = []*os.File{readyW}
+	if err := sb.slirp.Start(); err != nil {
+		_ = readyW.Close()
+		return sdk.WrapError(err, "unable to start slirp4netns for worker %s", sb.name)
+	}
+	_ = readyW.Close()
+	go func() {
+		if err := sb.slirp.Wait(); err != nil {
+			log.Debug(ctx, "hatchery> local> slirp4netns of worker %s exited: %v", sb.name, err)
+		}
+	}()
+
+	_ = readyR.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if _, err := readyR.Read(make([]byte, 1)); err != nil {
+		return sdk.WrapError(err, "slirp4netns of worker %s is not ready", sb.name)
+	}
+	return nil
+}
+
+// kill kills all the processes of the sandbox
+func (sb *sandbox) kill() error {
+	if err := os.WriteFile(filepath.Join(sb.cgroupDir, "cgroup.kill"), []byte("1"), 0644); err != nil {
+		return sdk.WrapError(err, "unable to kill cgroup %s", sb.cgroupDir)
+	}
+	return nil
+}
+
+// cleanup kills the remaining processes of the sandbox and removes its cgroup and workspace
+func (sb *sandbox) cleanup(ctx context.Context) {
+	for _, f := range []*os.File{sb.cgroup, sb.init, sb.ready} {
+		if f != nil {
+			_ = f.Close()
+		}
+	}
+	if sb.slirp != nil && sb.slirp.Process != nil {
+		_ = sb.slirp.Process.Kill()
+	}
+	if _, err := os.Stat(sb.cgroupDir); err == nil {
+		_ = sb.kill()
+		// The cgroup can only be removed once all its processes have exited
+		var err error
+		for i := 0; i < 10; i++ {
+			if err = os.Remove(sb.cgroupDir); err == nil || os.IsNotExist(err) {
+				err = nil
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err != nil {
+			log.Warn(ctx, "hatchery> local> unable to remove cgroup %s of worker %s: %v", sb.cgroupDir, sb.name, err)
+		}
+	}
+	// The tmpfs workspace disappeared with the mount namespace, only the mount point remains on the host
+	if err := os.RemoveAll(sb.basedir); err != nil {
+		log.Warn(ctx, "hatchery> local> unable to remove workspace %s of worker %s: %v", sb.basedir, sb.name, err)
+	}
+}
+
+// sandboxInfo describes the limits of a sandboxed worker for the job logs
+func sandboxInfo(workerName, flavor string, cpus, memoryMB int, cfg SandboxConfiguration) string {
+	msg := fmt.Sprintf("worker %q runs in a sandbox with %d CPUs and %d MB of memory", workerName, cpus, memoryMB)
+	if flavor != "" {
+		msg += fmt.Sprintf(" (flavor %q)", flavor)
+	}
+	if cfg.Network == sandboxNetworkSlirp4netns {
+		msg += ", with an isolated network"
+	}
+	return msg
+}
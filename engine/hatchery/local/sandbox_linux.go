@@ -0,0 +1,21 @@
+package local
+
+import (
+	"syscall"
+)
+
+// sandboxSysProcAttr runs the process in new user, mount, pid, ipc and uts namespaces, in the given cgroup.
+// Root of the user namespace is mapped to uid and gid on the host.
+func sandboxSysProcAttr(uid, gid, cgroupFD int, newNetwork bool) (*syscall.SysProcAttr, error) {
+	flags := syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	if newNetwork {
+		flags |= syscall.CLONE_NEWNET
+	}
+	return &syscall.SysProcAttr{
+		Cloneflags:  uintptr(flags),
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: uid, Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: 0, HostID: gid, Size: 1}},
+		UseCgroupFD: true,
+		CgroupFD:    cgroupFD,
+	}, nil
+}
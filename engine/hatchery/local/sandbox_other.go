@@ -0,0 +1,12 @@
+//go:build !linux
+
+package local
+
+import (
+	"fmt"
+	"syscall"
+)
+
+func sandboxSysProcAttr(uid, gid, cgroupFD int, newNetwork bool) (*syscall.SysProcAttr, error) {
+	return nil, fmt.Errorf("sandbox is only supported on Linux")
+}
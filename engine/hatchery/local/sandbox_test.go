@@ -0,0 +1,153 @@
+package local
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSandboxResources(t *testing.T) {
+	cfg := SandboxConfiguration{
+		DefaultCPUs:     2,
+		DefaultMemoryMB: 4096,
+		Flavors: []SandboxFlavor{
+			{Name: "large", CPUs: 8, MemoryMB: 16384},
+		},
+	}
+
+	flavor, cpus, memoryMB := cfg.sandboxResources(sdk.WorkerStarterWorkerModel{}, nil)
+	require.Equal(t, "", flavor)
+	require.Equal(t, 2, cpus)
+	require.Equal(t, 4096, memoryMB)
+
+	// Job v2: runs-on flavor and memory
+	flavor, cpus, memoryMB = cfg.sandboxResources(sdk.WorkerStarterWorkerModel{Flavor: "large", Memory: 1024}, nil)
+	require.Equal(t, "large", flavor)
+	require.Equal(t, 8, cpus)
+	require.Equal(t, 1024, memoryMB)
+
+	// Job v1: requirements
+	flavor, cpus, memoryMB = cfg.sandboxResources(sdk.WorkerStarterWorkerModel{}, []sdk.Requirement{
+		{Type: sdk.FlavorRequirement, Value: "large"},
+	})
+	require.Equal(t, "large", flavor)
+	require.Equal(t, 8, cpus)
+	require.Equal(t, 16384, memoryMB)
+
+	flavor, cpus, memoryMB = cfg.sandboxResources(sdk.WorkerStarterWorkerModel{Flavor: "unknown"}, []sdk.Requirement{
+		{Type: sdk.MemoryRequirement, Value: "512"},
+	})
+	require.Equal(t, "", flavor)
+	require.Equal(t, 2, cpus)
+	require.Equal(t, 512, memoryMB)
+}
+
+func TestCheckSandboxConfiguration(t *testing.T) {
+	cfg := SandboxConfiguration{
+		Enable:          true,
+		CgroupParent:    "/sys/fs/cgroup/cds-hatchery-local",
+		Network:         sandboxNetworkHost,
+		WorkspaceSizeMB: 1024,
+		DefaultCPUs:     2,
+		DefaultMemoryMB: 4096,
+		UID:             os.Getuid() + 100000,
+		GID:             os.Getgid() + 100000,
+	}
+	require.NoError(t, checkSandboxConfiguration(cfg))
+
+	// The root of the sandbox must be a dedicated user
+	invalid := cfg
+	invalid.UID, invalid.GID = 0, 0
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid = cfg
+	invalid.UID = os.Getuid()
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid = cfg
+	invalid.GID = os.Getgid()
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid = cfg
+	invalid.CgroupParent = "cds"
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid = cfg
+	invalid.Network = "bridge"
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid = cfg
+	invalid.Flavors = []SandboxFlavor{{Name: "small"}}
+	require.Error(t, checkSandboxConfiguration(invalid))
+
+	invalid.Enable = false
+	require.NoError(t, checkSandboxConfiguration(invalid))
+}
+
+func TestWriteCgroupLimits(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeCgroupLimits(dir, 2, 512, 100))
+
+	for file, expected := range map[string]string{
+		"memory.max":      "536870912",
+		"memory.swap.max": "0",
+		"cpu.max":         "200000 100000",
+		"pids.max":        "100",
+	} {
+		btes, err := os.ReadFile(filepath.Join(dir, file))
+		require.NoError(t, err)
+		require.Equal(t, expected, string(btes), file)
+	}
+}
+
+func TestSandboxCmd(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("the workspace is given to the sandbox user, this requires root")
+	}
+	h := New()
+	h.Config.Sandbox = SandboxConfiguration{
+		Enable:          true,
+		UID:             100000,
+		GID:             100000,
+		CgroupParent:    t.TempDir(),
+		Network:         sandboxNetworkHost,
+		WorkspaceSizeMB: 1024,
+		PidsMax:         100,
+	}
+	basedir := filepath.Join(t.TempDir(), "0123456789abcdef")
+	require.NoError(t, os.Mkdir(basedir, 0700))
+
+	sb, err := h.newSandbox("my-worker", basedir, 2, 512)
+	require.NoError(t, err)
+	require.DirExists(t, filepath.Join(h.Config.Sandbox.CgroupParent, "0123456789abcdef"))
+
+	cmd, err := h.sandboxCmd(sb, "/var/lib/cds-engine/worker", "--config", "xxx")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/bin/sh", "-c", sandboxInitScript, "cds-sandbox", basedir, "1024", "host", "/var/lib/cds-engine/worker", "--config", "xxx"}, cmd.Args)
+	require.True(t, cmd.SysProcAttr.UseCgroupFD)
+	require.Len(t, cmd.ExtraFiles, 1)
+
+	btes, err := os.ReadFile(filepath.Join(sb.cgroupDir, "memory.max"))
+	require.NoError(t, err)
+	require.Equal(t, "536870912", string(btes))
+
+	// A real cgroup is removed by the kernel with its interface files
+	require.NoError(t, os.RemoveAll(sb.cgroupDir))
+	sb.cleanup(context.TODO())
+	require.NoDirExists(t, basedir)
+}
+
+func TestCanSpawnSandbox(t *testing.T) {
+	h := New()
+	reqs := []sdk.Requirement{{Type: sdk.MemoryRequirement, Value: "1024"}}
+	require.False(t, h.CanSpawn(context.TODO(), sdk.WorkerStarterWorkerModel{}, "1", reqs))
+
+	h.Config.Sandbox.Enable = true
+	require.True(t, h.CanSpawn(context.TODO(), sdk.WorkerStarterWorkerModel{}, "1", reqs))
+	require.False(t, h.CanSpawn(context.TODO(), sdk.WorkerStarterWorkerModel{}, "1", []sdk.Requirement{{Type: sdk.ServiceRequirement, Value: "pg"}}))
+}
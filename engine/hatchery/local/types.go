@@ -13,7 +13,30 @@ import (
 // HatcheryConfiguration is the configuration for local hatchery
 type HatcheryConfiguration struct {
 	service.HatcheryCommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
-	Basedir                             string `mapstructure:"basedir" toml:"basedir" default:"/var/lib/cds-engine" comment:"BaseDir for worker workspace" json:"basedir"`
+	Basedir                             string               `mapstructure:"basedir" toml:"basedir" default:"/var/lib/cds-engine" comment:"BaseDir for worker workspace" json:"basedir"`
+	Sandbox                             SandboxConfiguration `mapstructure:"sandbox" toml:"sandbox" comment:"Run each worker in Linux namespaces with cgroup v2 limits" json:"sandbox"`
+}
+
+// SandboxConfiguration is the configuration of the isolation of the workers
+type SandboxConfiguration struct {
+	Enable          bool            `mapstructure:"enable" toml:"enable" default:"false" commented:"true" comment:"Run each worker in its own user, mount, pid, ipc and uts namespaces with a private tmpfs workspace. Linux only" json:"enable"`
+	UID             int             `mapstructure:"uid" toml:"uid" default:"0" commented:"true" comment:"Host user ID mapped to root in the sandbox, mandatory with the sandbox. It must be a dedicated user, different from the user of the hatchery" json:"uid"`
+	GID             int             `mapstructure:"gid" toml:"gid" default:"0" commented:"true" comment:"Host group ID mapped to root in the sandbox, mandatory with the sandbox. It must be a dedicated group, different from the group of the hatchery" json:"gid"`
+	CgroupParent    string          `mapstructure:"cgroupParent" toml:"cgroupParent" default:"/sys/fs/cgroup/cds-hatchery-local" commented:"true" comment:"cgroup v2 directory, writable by the hatchery, where a cgroup is created for each worker" json:"cgroupParent"`
+	Network         string          `mapstructure:"network" toml:"network" default:"host" commented:"true" comment:"host: the worker uses the network of the host.\n slirp4netns: the worker gets its own network namespace connected with slirp4netns, without access to the host loopback" json:"network"`
+	Slirp4netns     string          `mapstructure:"slirp4netns" toml:"slirp4netns" default:"slirp4netns" commented:"true" comment:"Path of the slirp4netns binary" json:"slirp4netns"`
+	WorkspaceSizeMB int             `mapstructure:"workspaceSizeMB" toml:"workspaceSizeMB" default:"10240" commented:"true" comment:"Size of the tmpfs workspace of a worker (MB), counted in its memory" json:"workspaceSizeMB"`
+	PidsMax         int             `mapstructure:"pidsMax" toml:"pidsMax" default:"4096" commented:"true" comment:"Maximum count of processes of a worker. 0 means no limit" json:"pidsMax"`
+	DefaultCPUs     int             `mapstructure:"defaultCpus" toml:"defaultCpus" default:"2" commented:"true" comment:"CPUs of a worker without flavor" json:"defaultCpus"`
+	DefaultMemoryMB int             `mapstructure:"defaultMemoryMB" toml:"defaultMemoryMB" default:"4096" commented:"true" comment:"Memory (MB) of a worker without flavor nor memory requirement" json:"defaultMemoryMB"`
+	Flavors         []SandboxFlavor `mapstructure:"flavors" toml:"flavors" commented:"true" comment:"CPU/memory limits selected with the flavor of the job" json:"flavors,omitempty"`
+}
+
+// SandboxFlavor defines the CPU and memory limits of a worker
+type SandboxFlavor struct {
+	Name     string `mapstructure:"name" toml:"name" json:"name"`
+	CPUs     int    `mapstructure:"cpus" toml:"cpus" json:"cpus"`
+	MemoryMB int    `mapstructure:"memoryMB" toml:"memoryMB" json:"memoryMB"`
 }
 
 // HatcheryLocal implements HatcheryMode interface for local usage
@@ -31,6 +54,7 @@ type HatcheryLocal struct {
 type workerCmd struct {
 	cmd     *exec.Cmd
 	created time.Time
+	sandbox *sandbox
 }
 
 type LocalWorkerRunner interface {
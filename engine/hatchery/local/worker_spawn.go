@@ -9,6 +9,7 @@ import (
 	"os/exec"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/rockbears/log"
 
@@ -39,8 +40,12 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	}
 	rndstr := hex.EncodeToString(bs)[0:16]
 	basedir := path.Join(h.Config.Basedir, rndstr)
-	// Create the directory
-	if err := os.MkdirAll(basedir, os.FileMode(0755)); err != nil {
+	// Create the directory, the workspace of a sandboxed worker is only readable by its user
+	mode := os.FileMode(0755)
+	if h.Config.Sandbox.Enable {
+		mode = 0700
+	}
+	if err := os.MkdirAll(basedir, mode); err != nil {
 		return err
 	}
 
@@ -52,11 +57,39 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 
 	// Prefix the command with the directory where the worker binary has been downloaded
 	log.Info(ctx, "Command exec: %v", workerBinary)
-	var cmd *exec.Cmd
+	args := []string{"--config", workerConfig.EncodeBase64()}
 	if spawnArgs.RegisterOnly {
-		cmd = h.LocalWorkerRunner.NewCmd(context.Background(), workerBinary, "register", "--config", workerConfig.EncodeBase64())
+		args = append([]string{"register"}, args...)
+	}
+	var cmd *exec.Cmd
+	var sb *sandbox
+	if h.Config.Sandbox.Enable {
+		flavor, cpus, memoryMB := h.Config.Sandbox.sandboxResources(spawnArgs.Model, spawnArgs.Requirements)
+		var err error
+		sb, err = h.newSandbox(spawnArgs.WorkerName, basedir, cpus, memoryMB)
+		if err != nil {
+			_ = os.RemoveAll(basedir)
+			return err
+		}
+		cmd, err = h.sandboxCmd(sb, workerBinary, args...)
+		if err != nil {
+			sb.cleanup(ctx)
+			return err
+		}
+		msg := sandboxInfo(spawnArgs.WorkerName, flavor, cpus, memoryMB, h.Config.Sandbox)
+		log.Info(ctx, "HatcheryLocal.SpawnWorker> %s", msg)
+		if !spawnArgs.RegisterOnly && sdk.IsValidUUID(spawnArgs.JobID) {
+			info := sdk.V2SendJobRunInfo{
+				Level:   sdk.WorkflowRunInfoLevelInfo,
+				Time:    time.Now(),
+				Message: msg,
+			}
+			if err := h.CDSClientV2().V2QueuePushJobInfo(ctx, spawnArgs.Region, spawnArgs.JobID, info); err != nil {
+				log.ErrorWithStackTrace(ctx, err)
+			}
+		}
 	} else {
-		cmd = h.LocalWorkerRunner.NewCmd(context.Background(), workerBinary, "--config", workerConfig.EncodeBase64())
+		cmd = h.LocalWorkerRunner.NewCmd(context.Background(), workerBinary, args...)
 	}
 	cmd.Dir = basedir
 
@@ -71,7 +104,7 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	// Wait in a goroutine so that when process exits, Wait() update cmd.ProcessState
 	go func() {
 		log.Debug(ctx, "hatchery> local> starting worker: %s", spawnArgs.WorkerName)
-		if err := h.startCmd(spawnArgs.WorkerName, cmd, localWorkerLogger{spawnArgs.WorkerName}); err != nil {
+		if err := h.startCmd(spawnArgs.WorkerName, cmd, sb, localWorkerLogger{spawnArgs.WorkerName}); err != nil {
 			log.Error(ctx, "hatchery> local> %v", err)
 		}
 	}()
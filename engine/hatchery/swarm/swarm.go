@@ -20,6 +20,7 @@ import (
 	"github.com/rockbears/log"
 	"golang.org/x/net/context"
 
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -199,6 +200,13 @@ func (h *HatcherySwarm) InitHatchery(ctx context.Context) error {
 		h.routines(ctx)
 	})
 
+	h.warmPool = hatcheryCommon.NewWarmPool(h.Config.WarmPool)
+	if h.warmPool != nil {
+		h.GoRoutines.Run(ctx, "warm-pool", func(ctx context.Context) {
+			h.warmPoolRoutine(ctx)
+		})
+	}
+
 	if h.Config.WorkerMetricsRefreshDelay > 0 {
 		h.GoRoutines.Run(ctx, "worker-metrics", func(ctx context.Context) {
 			h.StartWorkerMetricsRoutine(ctx, h.Config.WorkerMetricsRefreshDelay)
@@ -220,54 +228,26 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	telemetry.Current(ctx, telemetry.Tag(telemetry.TagWorker, spawnArgs.WorkerName), telemetry.Tag(telemetry.TagWorkflowNodeJobRun, spawnArgs.JobID))
 	log.Debug(ctx, "hatchery> swarm> SpawnWorker> Spawning worker %s", spawnArgs.WorkerName)
 
-	// Choose a dockerEngine
-	var dockerClient *dockerClient
-	var foundDockerClient bool
-
-	//  To choose a docker client by the number of containers
-	fillrate := float64(-1)
-
-	_, next := telemetry.Span(ctx, "swarm.chooseDockerEngine")
-	for dname, dclient := range h.dockerClients {
-		ctxList, cancelList := context.WithTimeout(context.Background(), 3*time.Second)
-		containers, err := dclient.ContainerList(ctxList, container.ListOptions{All: true})
-		if err != nil {
-			log.Error(ctx, "hatchery> swarm> SpawnWorker> unable to list containers on %s: %v", dname, err)
-			cancelList()
-			continue
-		}
-		cancelList()
-
-		if len(containers) == 0 {
-			dockerClient = h.dockerClients[dname]
-			foundDockerClient = true
-			break
-		}
-
-		var nbContainersFromHatchery int
-		for _, cont := range containers {
-			if hatcheryName, ok := cont.Labels[LabelHatchery]; ok && hatcheryName == h.Config.Name {
-				nbContainersFromHatchery++
-			}
+	if h.warmPool != nil && hatcheryCommon.WarmPoolEligible(spawnArgs) {
+		memory := int64(h.Config.DefaultMemory)
+		if spawnArgs.Model.Memory != 0 {
+			memory = spawnArgs.Model.Memory
 		}
-
-		// If client has enough space to start a container
-		if nbContainersFromHatchery < h.dockerClients[dname].MaxContainers {
-			clientFillRate := float64(nbContainersFromHatchery) / float64(h.dockerClients[dname].MaxContainers)
-			if fillrate > clientFillRate || fillrate == -1 {
-				fillrate = clientFillRate
-				dockerClient = h.dockerClients[dname]
-				foundDockerClient = true
-			}
-			if fillrate == 0 {
-				break
-			}
+		key := hatcheryCommon.WarmPoolKey(spawnArgs.Model, memory)
+		h.warmPool.RecordJob(key, spawnArgs.Model, memory)
+		if h.claimWarmWorker(ctx, key, spawnArgs) {
+			h.warmPool.Hit(ctx)
+			return nil
 		}
+		h.warmPool.Miss(ctx)
 	}
-	next()
 
-	if !foundDockerClient {
-		return fmt.Errorf("unable to found suitable docker engine")
+	dockerClient, err := h.chooseDockerClient(ctx)
+	if err != nil {
+		return err
+	}
+	if h.warmPool != nil {
+		h.evictWarmWorker(ctx, dockerClient)
 	}
 
 	//Memory for the worker
@@ -296,7 +276,6 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 					networkAlias = "worker"
 					if err := h.createNetwork(ctx, dockerClient, network); err != nil {
 						log.Warn(ctx, "hatchery> swarm> SpawnWorker> Unable to create network %s on %s for jobID %d : %v", network, dockerClient.name, spawnArgs.JobID, err)
-						next()
 						return err
 					}
 				}
@@ -388,7 +367,6 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 			networkAlias = "worker"
 			if err := h.createNetwork(ctx, dockerClient, network); err != nil {
 				log.Warn(ctx, "hatchery> swarm> SpawnWorker> Unable to create network %s on %s for jobID %d : %v", network, dockerClient.name, spawnArgs.JobID, err)
-				next()
 				return err
 			}
 		}
@@ -479,6 +457,56 @@ func (h *HatcherySwarm) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 	return nil
 }
 
+// chooseDockerClient returns the docker engine with the lowest fill rate. Idle pool workers are not counted,
+// they are removed to start a worker on a full engine.
+func (h *HatcherySwarm) chooseDockerClient(ctx context.Context) (*dockerClient, error) {
+	var dockerClient *dockerClient
+
+	//  To choose a docker client by the number of containers
+	fillrate := float64(-1)
+
+	_, end := telemetry.Span(ctx, "swarm.chooseDockerEngine")
+	defer end()
+	for dname, dclient := range h.dockerClients {
+		ctxList, cancelList := context.WithTimeout(context.Background(), 3*time.Second)
+		containers, err := dclient.ContainerList(ctxList, container.ListOptions{All: true})
+		if err != nil {
+			log.Error(ctx, "hatchery> swarm> SpawnWorker> unable to list containers on %s: %v", dname, err)
+			cancelList()
+			continue
+		}
+		cancelList()
+
+		if len(containers) == 0 {
+			return h.dockerClients[dname], nil
+		}
+
+		var nbContainersFromHatchery int
+		for _, cont := range containers {
+			if hatcheryName, ok := cont.Labels[LabelHatchery]; ok && hatcheryName == h.Config.Name && !isIdleWarmWorker(cont) {
+				nbContainersFromHatchery++
+			}
+		}
+
+		// If client has enough space to start a container
+		if nbContainersFromHatchery < h.dockerClients[dname].MaxContainers {
+			clientFillRate := float64(nbContainersFromHatchery) / float64(h.dockerClients[dname].MaxContainers)
+			if fillrate > clientFillRate || fillrate == -1 {
+				fillrate = clientFillRate
+				dockerClient = h.dockerClients[dname]
+			}
+			if fillrate == 0 {
+				break
+			}
+		}
+	}
+
+	if dockerClient == nil {
+		return nil, fmt.Errorf("unable to found suitable docker engine")
+	}
+	return dockerClient, nil
+}
+
 // v2
 func (h *HatcherySwarm) SpawnWorkerService(ctx context.Context, dockerClient *dockerClient, spawnArgs hatchery.SpawnArguments, sName string, service sdk.V2JobService, network string) (string, error) {
 	serviceMemory := int64(1024)
@@ -644,7 +672,7 @@ func (h *HatcherySwarm) WorkersStarted(ctx context.Context) ([]string, error) {
 		}
 		workers := containers.FilterWorkers()
 		for _, w := range workers {
-			res = append(res, w.Labels[LabelWorkerName])
+			res = append(res, workerName(w))
 		}
 	}
 	return res, nil
@@ -718,6 +746,10 @@ func (h *HatcherySwarm) listAwolWorkers(dockerClientName string, containers Cont
 			log.Debug(ctx, "hatchery> swarm> listAwolWorkers> container %s(status=%s) is too young", c.Names[0], c.Status)
 			continue
 		}
+		if !strings.Contains(c.Status, "Exited") && h.warmPool != nil && h.warmPool.RecentlyClaimed(c.ID) {
+			log.Debug(ctx, "hatchery> swarm> listAwolWorkers> pool worker %s(status=%s) is claimed too recently", c.Names[0], c.Status)
+			continue
+		}
 
 		//If there isn't any worker registered on the API. Kill the container
 		if len(apiworkers) == 0 {
@@ -771,6 +803,18 @@ func (h *HatcherySwarm) killAwolWorker(ctx context.Context) error {
 			}
 		}
 
+		// Remove the pool workers left by a previous configuration
+		if h.warmPool == nil {
+			for _, c := range containers {
+				if isIdleWarmWorker(c) {
+					log.Debug(ctx, "hatchery> swarm> killAwolWorker> Delete pool worker %s on %s", c.Names[0], dockerClient.name)
+					if err := h.killAndRemoveContainer(ctx, dockerClient, c.ID); err != nil {
+						log.Debug(ctx, "hatchery> swarm> killAwolWorker> %v", err)
+					}
+				}
+			}
+		}
+
 		// creating a map of containers names
 		mContainers := map[string]struct{}{}
 		for i := range containers {
@@ -76,6 +76,9 @@ func (h *HatcherySwarm) Status(ctx context.Context) *sdk.MonitoringStatus {
 		maxWorkerDisplay = "unlimited"
 	}
 	m.AddLine(sdk.MonitoringStatusLine{Component: "Workers", Value: fmt.Sprintf("%d/%s", len(ws), maxWorkerDisplay), Status: sdk.MonitoringStatusOK})
+	if h.warmPool != nil {
+		m.AddLine(h.warmPool.MonitoringStatusLine())
+	}
 	var nbErrorImageList, nbErrorGetContainers int
 	for dockerName, dockerClient := range h.dockerClients {
 		//Check images
@@ -1,12 +1,14 @@
 package swarm
 
 import (
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"golang.org/x/net/context"
 
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/telemetry"
 )
@@ -22,16 +24,38 @@ func (c Containers) GetByID(id string) *types.Container {
 	return nil
 }
 
+// FilterWorkers returns the containers of the workers, including the pool workers claimed by a job
 func (c Containers) FilterWorkers() Containers {
 	res := make(Containers, 0, len(c))
 	for i := range c {
 		if _, ok := c[i].Labels[LabelWorkerName]; ok {
 			res = append(res, c[i])
+		} else if _, ok := c[i].Labels[LabelWarmPool]; ok && !isIdleWarmWorker(c[i]) {
+			res = append(res, c[i])
 		}
 	}
 	return res
 }
 
+// workerName returns the name of the worker of a container. A claimed pool worker is renamed with the name of the worker.
+func workerName(c types.Container) string {
+	if name, ok := c.Labels[LabelWorkerName]; ok {
+		return name
+	}
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// isIdleWarmWorker returns true if the container is a pool worker not claimed by a job
+func isIdleWarmWorker(c types.Container) bool {
+	if _, ok := c.Labels[LabelWarmPool]; !ok {
+		return false
+	}
+	return strings.HasPrefix(workerName(c), hatcheryCommon.WarmPoolNamePrefix)
+}
+
 func (h *HatcherySwarm) getContainers(ctx context.Context, dockerClient *dockerClient, options container.ListOptions) (Containers, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
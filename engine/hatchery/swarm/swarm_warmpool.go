@@ -0,0 +1,204 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	types "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/rockbears/log"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func (h *HatcherySwarm) warmPoolRoutine(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(h.warmPool.Config.RefreshInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.refillWarmPool(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// refillWarmPool removes the idle pool workers that are not needed anymore and starts the missing ones
+func (h *HatcherySwarm) refillWarmPool(ctx context.Context) {
+	type engine struct {
+		client *dockerClient
+		free   int
+	}
+	var members []hatcheryCommon.WarmPoolMember
+	var engines []*engine
+	var capacity int
+	membersEngine := make(map[string]*engine)
+	for _, dockerClient := range h.dockerClients {
+		cs, err := h.getContainers(ctx, dockerClient, container.ListOptions{All: true})
+		if err != nil {
+			log.Warn(ctx, "hatchery> swarm> refillWarmPool> %v", err)
+			continue
+		}
+		e := &engine{client: dockerClient, free: dockerClient.MaxContainers - len(cs)}
+		engines = append(engines, e)
+		if e.free > 0 {
+			capacity += e.free
+		}
+		for _, c := range cs {
+			if !isIdleWarmWorker(c) {
+				continue
+			}
+			members = append(members, hatcheryCommon.WarmPoolMember{
+				ID:      c.ID,
+				Key:     c.Labels[LabelWarmPool],
+				Created: time.Unix(c.Created, 0),
+				Failed:  c.State != "running",
+			})
+			membersEngine[c.ID] = e
+		}
+	}
+
+	plan := h.warmPool.Plan(ctx, members, capacity)
+	for _, m := range plan.Remove {
+		log.Debug(ctx, "hatchery> swarm> refillWarmPool> remove pool worker %s", m.ID)
+		if err := h.killAndRemoveContainer(ctx, membersEngine[m.ID].client, m.ID); err != nil {
+			log.Error(ctx, "hatchery> swarm> refillWarmPool> %v", err)
+		}
+	}
+	for _, t := range plan.Create {
+		// Start the pool worker on the engine with the most free slots
+		sort.Slice(engines, func(i, j int) bool { return engines[i].free > engines[j].free })
+		if len(engines) == 0 || engines[0].free <= 0 {
+			return
+		}
+		engines[0].free--
+		if err := h.createWarmWorker(ctx, engines[0].client, t); err != nil {
+			ctx = sdk.ContextWithStacktrace(ctx, err)
+			log.Error(ctx, "hatchery> swarm> refillWarmPool> unable to start pool worker of model %s: %v", t.Model.GetName(), err)
+		}
+	}
+}
+
+// createWarmWorker starts a worker that waits to be claimed by a job. The image is pulled and the container is started
+// with the environment of the model, the configuration of the worker is given on claim.
+func (h *HatcherySwarm) createWarmWorker(ctx context.Context, dockerClient *dockerClient, t hatcheryCommon.WarmPoolTarget) error {
+	spawnArgs := hatchery.SpawnArguments{
+		WorkerName: hatcheryCommon.WarmPoolNamePrefix + sdk.RandomString(10),
+		Model:      t.Model,
+		JobID:      "0",
+	}
+	workerConfig := h.GenerateWorkerConfig(ctx, h, spawnArgs)
+
+	tmpl, err := template.New("cmd").Parse(t.Model.GetCmd())
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, struct{ API string }{API: workerConfig.APIEndpoint}); err != nil {
+		return sdk.WithStack(err)
+	}
+	cmds := strings.Fields(t.Model.GetShell())
+	cmds = append(cmds, hatcheryCommon.WarmPoolCommand(buffer.String()))
+
+	envsWm := workerConfig.InjectEnvVars
+	envsWm["CDS_MODEL_MEMORY"] = fmt.Sprintf("%d", t.Memory)
+	for envName, envValue := range t.Model.GetDockerEnvs() {
+		envsWm[envName] = envValue
+	}
+	envs := make([]string, 0, len(envsWm))
+	for envName, envValue := range envsWm {
+		envs = append(envs, envName+"="+envValue)
+	}
+
+	args := containerArgs{
+		name:  spawnArgs.WorkerName,
+		image: t.Model.GetDockerImage(),
+		cmd:   cmds,
+		labels: map[string]string{
+			LabelWorkerModelPath: t.Model.GetFullPath(),
+			LabelHatchery:        h.Config.Name,
+			LabelWarmPool:        t.Key,
+		},
+		memory:     t.Memory,
+		entryPoint: []string{},
+		env:        envs,
+	}
+	return h.createAndStartContainer(ctx, dockerClient, args, spawnArgs)
+}
+
+// claimWarmWorker gives the configuration of the worker of a job to an idle pool worker.
+// It returns false if there is no pool worker for the job, or if it can't be claimed.
+func (h *HatcherySwarm) claimWarmWorker(ctx context.Context, key string, spawnArgs hatchery.SpawnArguments) bool {
+	for _, dockerClient := range h.dockerClients {
+		cs, err := h.getContainers(ctx, dockerClient, container.ListOptions{})
+		if err != nil {
+			log.Warn(ctx, "hatchery> swarm> claimWarmWorker> %v", err)
+			continue
+		}
+		for _, c := range cs {
+			if !isIdleWarmWorker(c) || c.Labels[LabelWarmPool] != key || c.State != "running" || !h.warmPool.Claim(c.ID) {
+				continue
+			}
+			// The container is renamed so its worker is found on the API
+			if err := dockerClient.ContainerRename(ctx, c.ID, spawnArgs.WorkerName); err != nil {
+				log.Warn(ctx, "hatchery> swarm> claimWarmWorker> unable to rename pool worker %s on %s: %v", workerName(c), dockerClient.name, err)
+				h.warmPool.Release(c.ID)
+				continue
+			}
+
+			workerConfig := h.GenerateWorkerConfig(ctx, h, spawnArgs)
+			archive, err := hatcheryCommon.WarmPoolClaimArchive(workerConfig.EncodeBase64())
+			if err == nil {
+				err = dockerClient.CopyToContainer(ctx, c.ID, hatcheryCommon.WarmPoolDir, bytes.NewReader(archive), types.CopyToContainerOptions{})
+			}
+			if err != nil {
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "hatchery> swarm> claimWarmWorker> unable to claim pool worker %s on %s: %v", sdk.StringFirstN(c.ID, 12), dockerClient.name, err)
+				if err := h.killAndRemoveContainer(ctx, dockerClient, c.ID); err != nil {
+					log.Error(ctx, "hatchery> swarm> claimWarmWorker> %v", err)
+				}
+				return false
+			}
+
+			if err := h.CDSClientV2().V2QueuePushJobInfo(ctx, spawnArgs.Region, spawnArgs.JobID, sdk.V2SendJobRunInfo{
+				Time:    time.Now(),
+				Level:   sdk.WorkflowRunInfoLevelInfo,
+				Message: fmt.Sprintf("worker %s started from the warm pool of %s on %s", spawnArgs.WorkerName, c.Image, dockerClient.name),
+			}); err != nil {
+				log.Warn(ctx, "unable to send job info for job %s: %v", spawnArgs.JobID, err)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// evictWarmWorker removes the oldest idle pool worker of a full docker engine to make room for a worker
+func (h *HatcherySwarm) evictWarmWorker(ctx context.Context, dockerClient *dockerClient) {
+	cs, err := h.getContainers(ctx, dockerClient, container.ListOptions{All: true})
+	if err != nil || len(cs) < dockerClient.MaxContainers {
+		return
+	}
+	var oldest *types.Container
+	for i := range cs {
+		if isIdleWarmWorker(cs[i]) && (oldest == nil || cs[i].Created < oldest.Created) {
+			oldest = &cs[i]
+		}
+	}
+	if oldest == nil || !h.warmPool.Claim(oldest.ID) {
+		return
+	}
+	log.Info(ctx, "hatchery> swarm> evictWarmWorker> remove pool worker %s on %s", workerName(*oldest), dockerClient.name)
+	if err := h.killAndRemoveContainer(ctx, dockerClient, oldest.ID); err != nil {
+		log.Error(ctx, "hatchery> swarm> evictWarmWorker> %v", err)
+	}
+}
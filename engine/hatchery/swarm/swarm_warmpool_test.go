@@ -0,0 +1,107 @@
+package swarm
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gopkg.in/h2non/gock.v1"
+
+	hatcheryCommon "github.com/ovh/cds/engine/hatchery"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func TestHatcherySwarm_WorkersStartedWithWarmPool(t *testing.T) {
+	defer gock.Off()
+	h := InitTestHatcherySwarm(t)
+	h.Config.Name = "swarmy"
+	containers := []types.Container{
+		{
+			Names:  []string{"/w1"},
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWorkerName: "w1"},
+		},
+		{
+			Names:  []string{"/cds-warm-abcdef"},
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWarmPool: "key"},
+		},
+		{
+			Names:  []string{"/w2"},
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWarmPool: "key"},
+		},
+	}
+	gock.New("https://lolcat.local").Get("/v6.66/containers/json").Reply(http.StatusOK).JSON(containers)
+
+	s, err := h.WorkersStarted(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []string{"w1", "w2"}, s)
+	require.True(t, gock.IsDone())
+}
+
+func TestHatcherySwarm_ClaimWarmWorker(t *testing.T) {
+	defer gock.Off()
+	h := InitTestHatcherySwarm(t)
+	h.Config.Name = "swarmy"
+	h.warmPool = hatcheryCommon.NewWarmPool(hatcheryCommon.WarmPoolConfiguration{Enable: true})
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_cdsclient.NewMockHatcheryServiceClient(ctrl)
+	h.Clientv2 = mockClient
+	t.Cleanup(func() { ctrl.Finish() })
+
+	var info sdk.V2SendJobRunInfo
+	mockClient.EXPECT().V2QueuePushJobInfo(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, regionName string, jobRunID string, msg sdk.V2SendJobRunInfo) error {
+			info = msg
+			return nil
+		},
+	)
+
+	model := sdk.WorkerStarterWorkerModel{
+		ModelV2:    &sdk.V2WorkerModel{Name: "my-model", OSArch: "linux/amd64"},
+		Cmd:        "worker",
+		Shell:      "sh -c",
+		DockerSpec: sdk.V2WorkerModelDockerSpec{Image: "model:9"},
+	}
+	key := hatcheryCommon.WarmPoolKey(model, 1024)
+
+	containers := []types.Container{
+		{
+			ID:     "exited",
+			Names:  []string{"/cds-warm-exited"},
+			State:  "exited",
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWarmPool: key},
+		},
+		{
+			ID:     "other-model",
+			Names:  []string{"/cds-warm-other"},
+			State:  "running",
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWarmPool: "other"},
+		},
+		{
+			ID:     "idle",
+			Names:  []string{"/cds-warm-idle"},
+			Image:  "model:9",
+			State:  "running",
+			Labels: map[string]string{LabelHatchery: "swarmy", LabelWarmPool: key},
+		},
+	}
+	gock.New("https://lolcat.local").Get("/v6.66/containers/json").Reply(http.StatusOK).JSON(containers)
+	gock.New("https://lolcat.local").Post("/v6.66/containers/idle/rename").MatchParam("name", "swarmy-worker1").Reply(http.StatusNoContent)
+	gock.New("https://lolcat.local").Put("/v6.66/containers/idle/archive").MatchParam("path", "/tmp").Reply(http.StatusOK)
+
+	claimed := h.claimWarmWorker(context.TODO(), key, hatchery.SpawnArguments{
+		JobID:      sdk.UUID(),
+		Model:      model,
+		WorkerName: "swarmy-worker1",
+	})
+	require.True(t, claimed)
+	require.True(t, gock.IsDone())
+	require.Contains(t, info.Message, "started from the warm pool")
+	require.True(t, h.warmPool.RecentlyClaimed("idle"))
+	require.False(t, h.warmPool.Claim("idle"))
+}
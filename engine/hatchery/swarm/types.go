@@ -16,6 +16,7 @@ const (
 	LabelWorkerRequirements = "worker_requirements"
 	LabelWorkerModelPath    = "worker_model_path"
 	LabelJobID              = "job_id"
+	LabelWarmPool           = "warm_pool"
 )
 
 // HatcheryConfiguration is the configuration for hatchery
@@ -47,6 +48,8 @@ type HatcheryConfiguration struct {
 	WorkerMetricsRefreshDelay int64 `toml:"workerMetricsRefreshDelay" json:"workerMetricsRefreshDelay" commented:"true" comment:"Interval to compute worker metrics (in seconds), set to 0 will disable worker metrics."`
 
 	ExcludedBinariesRequirement []string `mapstructure:"excludedBinariesRequirement" toml:"excludedBinariesRequirement" default:"" commented:"true" comment:"If a job don't have any model requirement, check if there is no excluded binaries" json:"excludedBinariesRequirement"`
+
+	WarmPool hatcheryCommon.WarmPoolConfiguration `mapstructure:"warmPool" toml:"warmPool" comment:"Pools of pre-started workers for the worker models v2.\n Pool workers count in maxContainers, they are removed when a job needs room to start." json:"warmPool"`
 }
 
 // HatcherySwarm is a hatchery which can be connected to a remote to a docker remote api
@@ -54,6 +57,7 @@ type HatcherySwarm struct {
 	hatcheryCommon.Common
 	Config        HatcheryConfiguration
 	dockerClients map[string]*dockerClient
+	warmPool      *hatcheryCommon.WarmPool
 	workerMetrics struct {
 		CPU               *stats.Float64Measure
 		CPURequest        *stats.Float64Measure
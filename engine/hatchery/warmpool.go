@@ -0,0 +1,386 @@
+package hatchery
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+const (
+	// WarmPoolNamePrefix is the prefix of the name of the idle pool workers
+	WarmPoolNamePrefix = "cds-warm-"
+	// WarmPoolDir is the directory of a pool worker where the hatchery writes the worker configuration on claim
+	WarmPoolDir       = "/tmp"
+	warmPoolEnvFile   = ".cds-worker.env"
+	warmPoolReadyFile = ".cds-worker.ready"
+	// warmPoolClaimGrace is the delay given to a claimed pool worker to register on the API
+	warmPoolClaimGrace = 5 * time.Minute
+)
+
+// WarmPoolConfiguration is the configuration of the pools of pre-started workers of a container hatchery
+type WarmPoolConfiguration struct {
+	Enable          bool `mapstructure:"enable" toml:"enable" default:"false" commented:"true" comment:"Pre-start workers for the worker models v2 used by recent jobs, so jobs do not wait for image pulls" json:"enable"`
+	MinSize         int  `mapstructure:"minSize" toml:"minSize" default:"0" commented:"true" comment:"Minimum count of pool workers of a worker model used during historyDays" json:"minSize"`
+	MaxSize         int  `mapstructure:"maxSize" toml:"maxSize" default:"3" commented:"true" comment:"Maximum count of pool workers of a worker model" json:"maxSize"`
+	MaxTotal        int  `mapstructure:"maxTotal" toml:"maxTotal" default:"10" commented:"true" comment:"Maximum count of pool workers of all the worker models" json:"maxTotal"`
+	Lookahead       int  `mapstructure:"lookahead" toml:"lookahead" default:"600" commented:"true" comment:"The pool of a worker model is sized for the jobs expected in this period (seconds), from the jobs of the last period and of the same time of the previous days" json:"lookahead"`
+	HistoryDays     int  `mapstructure:"historyDays" toml:"historyDays" default:"7" commented:"true" comment:"Days of queue history used to size the pools. The history is kept in memory, it is lost when the hatchery restarts" json:"historyDays"`
+	IdleTimeout     int  `mapstructure:"idleTimeout" toml:"idleTimeout" default:"1800" commented:"true" comment:"Pool workers not used after this delay (seconds) are removed" json:"idleTimeout"`
+	RefreshInterval int  `mapstructure:"refreshInterval" toml:"refreshInterval" default:"30" commented:"true" comment:"Interval (seconds) between two refills of the pools" json:"refreshInterval"`
+}
+
+// WarmPoolTarget is a worker model with the size of its pool
+type WarmPoolTarget struct {
+	Key    string
+	Model  sdk.WorkerStarterWorkerModel
+	Memory int64
+	Size   int
+}
+
+// WarmPoolMember is an idle pool worker, as listed by the hatchery
+type WarmPoolMember struct {
+	ID      string
+	Key     string
+	Created time.Time
+	// Failed is true if the worker is not running anymore
+	Failed bool
+}
+
+// WarmPoolPlan is the list of pool workers to create and to remove
+type WarmPoolPlan struct {
+	Create []WarmPoolTarget
+	Remove []WarmPoolMember
+}
+
+type warmPoolModel struct {
+	model  sdk.WorkerStarterWorkerModel
+	memory int64
+	starts []time.Time
+}
+
+// WarmPool records the queue history of each worker model to size its pool of pre-started workers
+type WarmPool struct {
+	Config  WarmPoolConfiguration
+	mutex   sync.Mutex
+	models  map[string]*warmPoolModel
+	claimed map[string]time.Time
+	hits    int64
+	misses  int64
+	idle    int
+	now     func() time.Time
+}
+
+// NewWarmPool returns a warm pool, or nil if it is not enabled
+func NewWarmPool(cfg WarmPoolConfiguration) *WarmPool {
+	if !cfg.Enable {
+		return nil
+	}
+	if cfg.Lookahead <= 0 {
+		cfg.Lookahead = 600
+	}
+	if cfg.HistoryDays <= 0 {
+		cfg.HistoryDays = 7
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 30
+	}
+	return &WarmPool{
+		Config:  cfg,
+		models:  make(map[string]*warmPoolModel),
+		claimed: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// WarmPoolEligible returns true if the worker of a job can be taken from a warm pool.
+// Only jobs v2 without services are eligible.
+func WarmPoolEligible(spawnArgs hatchery.SpawnArguments) bool {
+	if spawnArgs.RegisterOnly || spawnArgs.Model.ModelV2 == nil || !sdk.IsValidUUID(spawnArgs.JobID) || len(spawnArgs.Services) > 0 {
+		return false
+	}
+	for _, r := range spawnArgs.Requirements {
+		if r.Type == sdk.ServiceRequirement {
+			return false
+		}
+	}
+	return true
+}
+
+// WarmPoolKey identifies the pool workers that can run a job: same image, command, environment, pod settings and memory
+func WarmPoolKey(model sdk.WorkerStarterWorkerModel, memory int64) string {
+	envs := model.GetDockerEnvs()
+	keys := make([]string, 0, len(envs))
+	for k := range envs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s\n%s\n%d\n", model.GetName(), model.ModelV2.OSArch, model.GetDockerImage(), model.GetShell(), model.GetCmd(), memory)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, envs[k])
+	}
+	if spec := model.GetKubernetesSpec(); spec != nil {
+		btes, _ := json.Marshal(spec)
+		h.Write(btes)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// WarmPoolCommand returns the command of a pool worker: it waits for the worker configuration, then runs the command of the model
+func WarmPoolCommand(cmd string) string {
+	ready := WarmPoolDir + "/" + warmPoolReadyFile
+	env := WarmPoolDir + "/" + warmPoolEnvFile
+	return fmt.Sprintf("while [ ! -f %s ]; do sleep 1; done; . %s; rm -f %s %s; %s", ready, env, env, ready, cmd)
+}
+
+// WarmPoolClaimCommand returns the command that writes the worker configuration read from stdin in a pool worker
+func WarmPoolClaimCommand() []string {
+	return []string{"sh", "-c", fmt.Sprintf("cat > %[1]s/%[2]s && touch %[1]s/%[3]s", WarmPoolDir, warmPoolEnvFile, warmPoolReadyFile)}
+}
+
+// WarmPoolClaimEnv returns the content of the environment file that gives its configuration to a pool worker
+func WarmPoolClaimEnv(workerConfig string) []byte {
+	return []byte("export CDS_CONFIG='" + strings.ReplaceAll(workerConfig, "'", `'\''`) + "'\n")
+}
+
+// WarmPoolClaimArchive returns a tar archive of the files to copy in WarmPoolDir to claim a pool worker
+func WarmPoolClaimArchive(workerConfig string) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	// The ready file must be written after the environment file
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{warmPoolEnvFile, WarmPoolClaimEnv(workerConfig)},
+		{warmPoolReadyFile, nil},
+	}
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0600, Size: int64(len(f.content))}); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		if _, err := tw.Write(f.content); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RecordJob adds a job started with given model to the queue history
+func (p *WarmPool) RecordJob(key string, model sdk.WorkerStarterWorkerModel, memory int64) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	m, has := p.models[key]
+	if !has {
+		m = &warmPoolModel{}
+		p.models[key] = m
+	}
+	// Keep the last model to create the pool workers with up to date credentials
+	m.model = model
+	m.memory = memory
+	m.starts = append(m.starts, p.now())
+}
+
+// Claim reserves an idle pool worker for a job, it returns false if the worker is already reserved
+func (p *WarmPool) Claim(id string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, has := p.claimed[id]; has {
+		return false
+	}
+	p.claimed[id] = p.now()
+	return true
+}
+
+// RecentlyClaimed returns true if the pool worker was claimed too recently to have registered on the API
+func (p *WarmPool) RecentlyClaimed(id string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	t, has := p.claimed[id]
+	return has && p.now().Sub(t) < warmPoolClaimGrace
+}
+
+// Release cancels the reservation of a pool worker
+func (p *WarmPool) Release(id string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.claimed, id)
+}
+
+// Hit records a job that got a pool worker
+func (p *WarmPool) Hit(ctx context.Context) {
+	p.mutex.Lock()
+	p.hits++
+	p.mutex.Unlock()
+	stats.Record(ctx, hatchery.GetMetrics().WarmPoolHits.M(1))
+}
+
+// Miss records a job that did not get a pool worker
+func (p *WarmPool) Miss(ctx context.Context) {
+	p.mutex.Lock()
+	p.misses++
+	p.mutex.Unlock()
+	stats.Record(ctx, hatchery.GetMetrics().WarmPoolMisses.M(1))
+}
+
+// MonitoringStatusLine returns the size and the hit rate of the pools
+func (p *WarmPool) MonitoringStatusLine() sdk.MonitoringStatusLine {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	rate := "n/a"
+	if total := p.hits + p.misses; total > 0 {
+		rate = fmt.Sprintf("%d%%", p.hits*100/total)
+	}
+	return sdk.MonitoringStatusLine{
+		Component: "Warm pool",
+		Value:     fmt.Sprintf("%d idle workers, hit rate %s (%d/%d)", p.idle, rate, p.hits, p.hits+p.misses),
+		Status:    sdk.MonitoringStatusOK,
+	}
+}
+
+// Plan computes the pool workers to create and to remove from the idle pool workers.
+// capacity is the count of workers the hatchery can still start.
+func (p *WarmPool) Plan(ctx context.Context, members []WarmPoolMember, capacity int) WarmPoolPlan {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	targets := p.targets(now)
+	var plan WarmPoolPlan
+
+	// Forget the old reservations of the workers that are not idle anymore
+	listed := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		listed[m.ID] = struct{}{}
+	}
+	for id, t := range p.claimed {
+		if _, has := listed[id]; !has && now.Sub(t) > warmPoolClaimGrace {
+			delete(p.claimed, id)
+		}
+	}
+
+	idle := make(map[string][]WarmPoolMember)
+	for _, m := range members {
+		if _, has := p.claimed[m.ID]; has {
+			continue
+		}
+		switch {
+		case m.Failed:
+			plan.Remove = append(plan.Remove, m)
+		case p.Config.IdleTimeout > 0 && now.Sub(m.Created) > time.Duration(p.Config.IdleTimeout)*time.Second:
+			plan.Remove = append(plan.Remove, m)
+		default:
+			idle[m.Key] = append(idle[m.Key], m)
+		}
+	}
+
+	sizes := make(map[string]int, len(targets))
+	for _, t := range targets {
+		sizes[t.Key] = t.Size
+	}
+	var nbIdle int
+	for key, ms := range idle {
+		// Remove the oldest workers over the size of the pool
+		sort.Slice(ms, func(i, j int) bool { return ms[i].Created.After(ms[j].Created) })
+		if excess := len(ms) - sizes[key]; excess > 0 {
+			plan.Remove = append(plan.Remove, ms[len(ms)-excess:]...)
+			ms = ms[:len(ms)-excess]
+		}
+		nbIdle += len(ms)
+	}
+	p.idle = nbIdle
+	stats.Record(ctx, hatchery.GetMetrics().WarmPoolWorkers.M(int64(nbIdle)))
+
+	for _, t := range targets {
+		for i := len(idle[t.Key]); i < t.Size && capacity > 0; i++ {
+			plan.Create = append(plan.Create, t)
+			capacity--
+		}
+	}
+	return plan
+}
+
+// targets returns the pool size of each worker model, biggest pools first
+func (p *WarmPool) targets(now time.Time) []WarmPoolTarget {
+	history := time.Duration(p.Config.HistoryDays) * 24 * time.Hour
+	targets := make([]WarmPoolTarget, 0, len(p.models))
+	for key, m := range p.models {
+		// Prune the history
+		i := 0
+		for i < len(m.starts) && now.Sub(m.starts[i]) > history {
+			i++
+		}
+		m.starts = m.starts[i:]
+		if len(m.starts) == 0 {
+			delete(p.models, key)
+			continue
+		}
+		size := p.predict(m.starts, now)
+		if size < p.Config.MinSize {
+			size = p.Config.MinSize
+		}
+		if p.Config.MaxSize > 0 && size > p.Config.MaxSize {
+			size = p.Config.MaxSize
+		}
+		targets = append(targets, WarmPoolTarget{Key: key, Model: m.model, Memory: m.memory, Size: size})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Size != targets[j].Size {
+			return targets[i].Size > targets[j].Size
+		}
+		return targets[i].Key < targets[j].Key
+	})
+
+	total := p.Config.MaxTotal
+	for i := range targets {
+		if total >= 0 && p.Config.MaxTotal > 0 {
+			if targets[i].Size > total {
+				targets[i].Size = total
+			}
+			total -= targets[i].Size
+		}
+	}
+	return targets
+}
+
+// predict returns the count of jobs expected during the lookahead period: the highest value between
+// the count of jobs of the last period and the mean count of jobs of the same period of the previous days
+func (p *WarmPool) predict(starts []time.Time, now time.Time) int {
+	lookahead := time.Duration(p.Config.Lookahead) * time.Second
+	count := func(from time.Time) int {
+		var n int
+		to := from.Add(lookahead)
+		for _, s := range starts {
+			if !s.Before(from) && s.Before(to) {
+				n++
+			}
+		}
+		return n
+	}
+
+	recent := float64(count(now.Add(-lookahead)))
+	var sameTime int
+	for d := 1; d <= p.Config.HistoryDays; d++ {
+		sameTime += count(now.Add(-time.Duration(d) * 24 * time.Hour))
+	}
+	daily := float64(sameTime) / float64(p.Config.HistoryDays)
+	return int(math.Ceil(math.Max(recent, daily)))
+}
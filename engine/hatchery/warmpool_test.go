@@ -0,0 +1,129 @@
+package hatchery
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	sdkhatchery "github.com/ovh/cds/sdk/hatchery"
+)
+
+func testWarmPoolModel(image string) sdk.WorkerStarterWorkerModel {
+	return sdk.WorkerStarterWorkerModel{
+		ModelV2:    &sdk.V2WorkerModel{Name: "my-model", OSArch: "linux/amd64"},
+		Cmd:        "worker --api {{.API}}",
+		Shell:      "sh -c",
+		DockerSpec: sdk.V2WorkerModelDockerSpec{Image: image, Envs: map[string]string{"FOO": "bar"}},
+	}
+}
+
+func TestWarmPoolKey(t *testing.T) {
+	m := testWarmPoolModel("golang:1.21")
+	require.Equal(t, WarmPoolKey(m, 1024), WarmPoolKey(testWarmPoolModel("golang:1.21"), 1024))
+	require.Len(t, WarmPoolKey(m, 1024), 16)
+	require.NotEqual(t, WarmPoolKey(m, 1024), WarmPoolKey(m, 2048))
+	require.NotEqual(t, WarmPoolKey(m, 1024), WarmPoolKey(testWarmPoolModel("golang:1.22"), 1024))
+}
+
+func TestWarmPoolEligible(t *testing.T) {
+	args := sdkhatchery.SpawnArguments{JobID: sdk.UUID(), Model: testWarmPoolModel("golang:1.21")}
+	require.True(t, WarmPoolEligible(args))
+
+	args.Requirements = []sdk.Requirement{{Type: sdk.ServiceRequirement, Name: "pg", Value: "postgres:14"}}
+	require.False(t, WarmPoolEligible(args))
+
+	args.Requirements = nil
+	args.RegisterOnly = true
+	require.False(t, WarmPoolEligible(args))
+
+	require.False(t, WarmPoolEligible(sdkhatchery.SpawnArguments{JobID: "42", Model: sdk.WorkerStarterWorkerModel{ModelV1: &sdk.Model{}}}))
+}
+
+func TestWarmPoolPlan(t *testing.T) {
+	require.NoError(t, sdkhatchery.InitMetrics(context.Background()))
+
+	now := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	p := NewWarmPool(WarmPoolConfiguration{Enable: true, MaxSize: 3, MaxTotal: 4, Lookahead: 600, HistoryDays: 7, IdleTimeout: 1800})
+	p.now = func() time.Time { return now }
+
+	busy, quiet := testWarmPoolModel("busy:1"), testWarmPoolModel("quiet:1")
+	busyKey, quietKey := WarmPoolKey(busy, 0), WarmPoolKey(quiet, 0)
+
+	// 5 jobs at 9:00 every day of last week, the pool of busy is sized for 5 jobs, limited by maxSize
+	for d := 1; d <= 7; d++ {
+		p.now = func() time.Time { return now.Add(-time.Duration(d)*24*time.Hour + time.Minute) }
+		for i := 0; i < 5; i++ {
+			p.RecordJob(busyKey, busy, 0)
+		}
+	}
+	// 2 jobs in the last minutes for quiet
+	p.now = func() time.Time { return now.Add(-time.Minute) }
+	p.RecordJob(quietKey, quiet, 0)
+	p.RecordJob(quietKey, quiet, 0)
+	// A job too old to be kept in the history
+	p.now = func() time.Time { return now.Add(-8 * 24 * time.Hour) }
+	p.RecordJob("old", busy, 0)
+	p.now = func() time.Time { return now }
+
+	members := []WarmPoolMember{
+		{ID: "busy-1", Key: busyKey, Created: now.Add(-time.Minute)},
+		{ID: "busy-idle", Key: busyKey, Created: now.Add(-time.Hour)},
+		{ID: "busy-failed", Key: busyKey, Created: now.Add(-time.Minute), Failed: true},
+		{ID: "unknown", Key: "unknown", Created: now.Add(-time.Minute)},
+	}
+	plan := p.Plan(context.TODO(), members, 10)
+
+	var removed []string
+	for _, m := range plan.Remove {
+		removed = append(removed, m.ID)
+	}
+	require.ElementsMatch(t, []string{"busy-idle", "busy-failed", "unknown"}, removed)
+
+	// busy gets 3 workers and quiet 1 because of maxTotal, busy-1 is already started
+	created := map[string]int{}
+	for _, c := range plan.Create {
+		created[c.Key]++
+	}
+	require.Equal(t, map[string]int{busyKey: 2, quietKey: 1}, created)
+
+	// The capacity of the hatchery limits the workers to create
+	plan = p.Plan(context.TODO(), members, 1)
+	require.Len(t, plan.Create, 1)
+	require.Equal(t, busyKey, plan.Create[0].Key)
+
+	// A claimed worker is not counted in the pool
+	require.True(t, p.Claim("busy-1"))
+	require.False(t, p.Claim("busy-1"))
+	plan = p.Plan(context.TODO(), members, 10)
+	require.Len(t, plan.Create, 4)
+	p.Release("busy-1")
+}
+
+func TestWarmPoolClaimArchive(t *testing.T) {
+	btes, err := WarmPoolClaimArchive("it's a config")
+	require.NoError(t, err)
+
+	tr := tar.NewReader(bytes.NewReader(btes))
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		if hdr.Name == warmPoolEnvFile {
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			require.Equal(t, "export CDS_CONFIG='it'\\''s a config'\n", string(content))
+		}
+	}
+	require.Equal(t, []string{warmPoolEnvFile, warmPoolReadyFile}, names)
+	require.Equal(t, "while [ ! -f /tmp/.cds-worker.ready ]; do sleep 1; done; . /tmp/.cds-worker.env; rm -f /tmp/.cds-worker.env /tmp/.cds-worker.ready; worker", WarmPoolCommand("worker"))
+}
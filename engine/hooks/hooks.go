@@ -20,6 +20,7 @@ import (
 func New() *Service {
 	s := new(Service)
 	s.GoRoutines = sdk.NewGoRoutines(context.Background())
+	s.messageBus = &messageBusConsumers{hooks: make(map[string]sdk.V2WorkflowHook)}
 	return s
 }
 
@@ -192,6 +193,10 @@ func (s *Service) Serve(c context.Context) error {
 		s.GoRoutines.RunWithRestart(ctx, "schedulerv2", func(ctx context.Context) {
 			s.schedulerExecutionRoutine(ctx)
 		})
+
+		s.GoRoutines.RunWithRestart(ctx, "messagebusv2", func(ctx context.Context) {
+			s.messageBusRoutine(ctx)
+		})
 	}
 
 	if s.Cfg.WebhooksPublicKeySign != "" {
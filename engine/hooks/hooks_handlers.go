@@ -211,6 +211,8 @@ func (s *Service) handleManualWorkflowEvent(ctx context.Context, runRequest sdk.
 			TargetBranch:     runRequest.UserRequest.Branch,
 			TargetTag:        runRequest.UserRequest.Tag,
 			JobInputs:        runRequest.UserRequest.JobInputs,
+			Inputs:           runRequest.UserRequest.Inputs,
+			Debug:            runRequest.UserRequest.Debug,
 			IsInMaintenance:  s.Maintenance,
 		},
 		DeprecatedAdminMFA: runRequest.AdminMFA,
@@ -52,34 +52,9 @@ func (s *Service) startKafkaHook(ctx context.Context, t *sdk.Task) error {
 		return sdk.WrapError(err, "Cannot get kafka configuration for %s/%s", projectKey, kafkaIntegration)
 	}
 
-	var config = sarama.NewConfig()
-	if _, ok := pf.Config["disableTLS"]; ok && pf.Config["disableTLS"].Value == "true" {
-		config.Net.TLS.Enable = false
-	} else {
-		config.Net.TLS.Enable = true
-	}
-	if _, ok := pf.Config["disableSASL"]; ok && pf.Config["disableSASL"].Value == "true" {
-		config.Net.SASL.Enable = false
-	} else {
-		config.Net.SASL.Enable = true
-		config.Net.SASL.User = pf.Config["username"].Value
-		config.Net.SASL.Password = pf.Config["password"].Value
-	}
-	if _, ok := pf.Config["user"]; ok && pf.Config["user"].Value != "" {
-		config.ClientID = pf.Config["user"].Value
-	} else {
-		config.ClientID = "cds"
-	}
-
-	config.Consumer.Return.Errors = true
-	if v, ok := pf.Config["version"]; ok && v.Value != "" {
-		kafkaVersion, err := sarama.ParseKafkaVersion(pf.Config["version"].Value)
-		if err != nil {
-			return fmt.Errorf("error parsing Kafka version %v err:%s", kafkaVersion, err)
-		}
-		config.Version = kafkaVersion
-	} else {
-		config.Version = sarama.V0_10_2_0
+	config, err := kafkaConsumerConfig(pf)
+	if err != nil {
+		return err
 	}
 
 	var group = fmt.Sprintf("%s.%s", config.Net.SASL.User, t.UUID)
@@ -114,6 +89,41 @@ func (s *Service) startKafkaHook(ctx context.Context, t *sdk.Task) error {
 	return nil
 }
 
+// kafkaConsumerConfig builds the consumer configuration from a kafka project integration
+func kafkaConsumerConfig(pf sdk.ProjectIntegration) (*sarama.Config, error) {
+	var config = sarama.NewConfig()
+	if _, ok := pf.Config["disableTLS"]; ok && pf.Config["disableTLS"].Value == "true" {
+		config.Net.TLS.Enable = false
+	} else {
+		config.Net.TLS.Enable = true
+	}
+	if _, ok := pf.Config["disableSASL"]; ok && pf.Config["disableSASL"].Value == "true" {
+		config.Net.SASL.Enable = false
+	} else {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = pf.Config["username"].Value
+		config.Net.SASL.Password = pf.Config["password"].Value
+	}
+	if _, ok := pf.Config["user"]; ok && pf.Config["user"].Value != "" {
+		config.ClientID = pf.Config["user"].Value
+	} else {
+		config.ClientID = "cds"
+	}
+
+	config.Consumer.Return.Errors = true
+	if v, ok := pf.Config["version"]; ok && v.Value != "" {
+		kafkaVersion, err := sarama.ParseKafkaVersion(pf.Config["version"].Value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Kafka version %v err:%s", kafkaVersion, err)
+		}
+		config.Version = kafkaVersion
+	} else {
+		config.Version = sarama.V0_10_2_0
+	}
+
+	return config, nil
+}
+
 // handler represents a Sarama consumer group consumer
 type handler struct {
 	task *sdk.Task
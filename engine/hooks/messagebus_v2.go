@@ -0,0 +1,362 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/pkg/errors"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+/*
+Kafka and RabbitMQ workflow hooks are stored in the API database. Each hooks µservice instance runs a consumer
+for every hook: kafka consumer groups and rabbitMQ competing consumers ensure that a message is handled only once.
+*/
+
+const (
+	messageBusSyncDelay = 1 * time.Minute
+	// messageBusMaxAttempts is the count of attempts to save a message before it is given back to the message bus
+	messageBusMaxAttempts = 5
+	// messageBusRetryDelay is the delay before the second attempt to save a message, it is doubled after each attempt
+	messageBusRetryDelay    = 1 * time.Second
+	messageBusMaxRetryDelay = 30 * time.Second
+	// messageBusRestartDelay is the delay before consuming again a kafka topic after a message could not be saved
+	messageBusRestartDelay = 5 * time.Second
+)
+
+// messageBusConsumers contains the hooks for which a consumer is running, indexed by hook ID
+type messageBusConsumers struct {
+	mutex sync.Mutex
+	hooks map[string]sdk.V2WorkflowHook
+}
+
+func messageBusConsumerName(hookID string) string {
+	return "v2-messagebus-" + hookID
+}
+
+// messageBusRoutine starts and stops the kafka and rabbitMQ consumers regarding the hooks declared in the API
+func (s *Service) messageBusRoutine(ctx context.Context) {
+	tick := time.NewTicker(messageBusSyncDelay)
+	defer tick.Stop()
+	for {
+		if err := s.syncMessageBusConsumers(ctx); err != nil {
+			log.ErrorWithStackTrace(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			s.stopAllMessageBusConsumers()
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func (s *Service) syncMessageBusConsumers(ctx context.Context) error {
+	hooks, err := s.Client.HookListAllMessageBusHooks(ctx)
+	if err != nil {
+		return sdk.WrapError(err, "unable to list message bus hooks")
+	}
+
+	s.messageBus.mutex.Lock()
+	defer s.messageBus.mutex.Unlock()
+
+	hooksByID := make(map[string]sdk.V2WorkflowHook, len(hooks))
+	for _, h := range hooks {
+		hooksByID[h.ID] = h
+	}
+
+	// Stop consumers of removed hooks
+	for id, h := range s.messageBus.hooks {
+		if _, has := hooksByID[id]; has {
+			continue
+		}
+		log.Info(ctx, "stopping %s consumer for workflow %s/%s/%s", h.Type, h.VCSName, h.RepositoryName, h.WorkflowName)
+		s.GoRoutines.Stop(messageBusConsumerName(id))
+		delete(s.messageBus.hooks, id)
+	}
+
+	// Start consumers of new hooks
+	for id, h := range hooksByID {
+		if _, has := s.messageBus.hooks[id]; has {
+			continue
+		}
+		log.Info(ctx, "starting %s consumer for workflow %s/%s/%s", h.Type, h.VCSName, h.RepositoryName, h.WorkflowName)
+		var errStart error
+		switch h.Type {
+		case sdk.WorkflowHookTypeKafka:
+			errStart = s.startKafkaV2Consumer(ctx, h)
+		case sdk.WorkflowHookTypeRabbitMQ:
+			errStart = s.startRabbitMQV2Consumer(ctx, h)
+		default:
+			continue
+		}
+		if errStart != nil {
+			// The consumer will be started again on next synchronization
+			log.Error(ctx, "unable to start %s consumer for workflow %s/%s/%s: %v", h.Type, h.VCSName, h.RepositoryName, h.WorkflowName, errStart)
+			continue
+		}
+		s.messageBus.hooks[id] = h
+	}
+	return nil
+}
+
+func (s *Service) stopAllMessageBusConsumers() {
+	s.messageBus.mutex.Lock()
+	defer s.messageBus.mutex.Unlock()
+	for id := range s.messageBus.hooks {
+		s.GoRoutines.Stop(messageBusConsumerName(id))
+		delete(s.messageBus.hooks, id)
+	}
+}
+
+func (s *Service) startKafkaV2Consumer(ctx context.Context, h sdk.V2WorkflowHook) error {
+	pf, err := s.Client.ProjectIntegrationGet(h.ProjectKey, h.Data.Integration, true)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get kafka integration %s/%s", h.ProjectKey, h.Data.Integration)
+	}
+	config, err := kafkaConsumerConfig(pf)
+	if err != nil {
+		return err
+	}
+
+	// The consumer group doesn't depend on the hook ID to keep offsets when the workflow is updated,
+	// it contains the topic to keep a consumer group for each topic consumed by the workflow
+	group := fmt.Sprintf("%s.cds.%s.%s.%s.%s.%s", config.Net.SASL.User, h.ProjectKey, h.VCSName, strings.ReplaceAll(h.RepositoryName, "/", "."), h.WorkflowName, h.Data.Topic)
+	consumerGroup, err := sarama.NewConsumerGroup(strings.Split(pf.Config["broker url"].Value, ","), group, config)
+	if err != nil {
+		return fmt.Errorf("unable to create kafka consumer on %s for topic %s: %v", pf.Config["broker url"].Value, h.Data.Topic, err)
+	}
+
+	s.GoRoutines.Run(s.Router.Background, messageBusConsumerName(h.ID), func(ctx context.Context) {
+		defer consumerGroup.Close() // nolint
+		go func() {
+			for err := range consumerGroup.Errors() {
+				log.Error(ctx, "kafka consumer error on topic %s for workflow %s: %v", h.Data.Topic, h.WorkflowName, err)
+			}
+		}()
+		for ctx.Err() == nil {
+			// The handler cancels the session when a message could not be saved, the topic is then consumed again
+			// from the last committed offset, that is the offset of this message
+			sessionCtx, cancel := context.WithCancel(ctx)
+			handler := &kafkaV2Handler{s: s, hook: h, restart: cancel}
+			err := consumerGroup.Consume(sessionCtx, []string{h.Data.Topic}, handler)
+			restarted := sessionCtx.Err() != nil && ctx.Err() == nil
+			cancel()
+			if err != nil {
+				log.ErrorWithStackTrace(ctx, errors.WithMessage(err, "error on consume"))
+			}
+			if err != nil || restarted {
+				time.Sleep(messageBusRestartDelay)
+			}
+		}
+	})
+	return nil
+}
+
+// kafkaV2Handler consumes kafka messages for a workflow hook
+type kafkaV2Handler struct {
+	s       *Service
+	hook    sdk.V2WorkflowHook
+	restart context.CancelFunc
+}
+
+func (h *kafkaV2Handler) Setup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaV2Handler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *kafkaV2Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	for message := range claim.Messages() {
+		err := retryMessageBusEvent(ctx, messageBusRetryDelay, func() error {
+			return h.s.enqueueMessageBusEvent(ctx, h.hook, message.Value)
+		})
+		if err != nil {
+			if !sdk.ErrorIs(err, sdk.ErrInvalidData) {
+				// The offset of a partition is committed up to the last marked message, the following messages must not be marked.
+				// The session is stopped to consume the partition again from this message.
+				log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "unable to save message at offset %d of partition %d on topic %s, consuming again", message.Offset, message.Partition, message.Topic))
+				h.restart()
+				return nil
+			}
+			// Invalid message, it is dropped
+			log.Warn(ctx, "%v", err)
+		}
+		session.MarkMessage(message, "delivered")
+	}
+	return nil
+}
+
+func (s *Service) startRabbitMQV2Consumer(ctx context.Context, h sdk.V2WorkflowHook) error {
+	pf, err := s.Client.ProjectIntegrationGet(h.ProjectKey, h.Data.Integration, true)
+	if err != nil {
+		return sdk.WrapError(err, "unable to get rabbitMQ integration %s/%s", h.ProjectKey, h.Data.Integration)
+	}
+	uri := fmt.Sprintf("amqp://%s:%s@%s", pf.Config["username"].Value, pf.Config["password"].Value, pf.Config["uri"].Value)
+
+	consumer, err := newConsumer(uri, "", "", h.Data.Queue, "", "cds-"+h.ID)
+	if err != nil {
+		return fmt.Errorf("unable to create rabbitMQ consumer on %s for queue %s: %v", pf.Config["uri"].Value, h.Data.Queue, err)
+	}
+	deliveries, err := consumer.channel.Consume(
+		h.Data.Queue, // name
+		consumer.tag, // consumerTag,
+		false,        // noAck
+		false,        // exclusive
+		false,        // noLocal
+		false,        // noWait
+		nil,          // arguments
+	)
+	if err != nil {
+		_ = consumer.conn.Close()
+		return fmt.Errorf("unable to consume rabbitMQ queue %s: %v", h.Data.Queue, err)
+	}
+
+	s.GoRoutines.Run(s.Router.Background, messageBusConsumerName(h.ID), func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			_ = consumer.channel.Cancel(consumer.tag, false)
+			_ = consumer.conn.Close()
+		}()
+		for d := range deliveries {
+			err := retryMessageBusEvent(ctx, messageBusRetryDelay, func() error {
+				return s.enqueueMessageBusEvent(ctx, h, d.Body)
+			})
+			if err != nil {
+				// Invalid messages and messages that could not be saved after all the attempts are rejected
+				// to the dead letter exchange of the queue if any
+				if sdk.ErrorIs(err, sdk.ErrInvalidData) {
+					log.Warn(ctx, "%v", err)
+				} else {
+					log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "unable to save message from queue %s after %d attempts, rejecting it", h.Data.Queue, messageBusMaxAttempts))
+				}
+				_ = d.Nack(false, false)
+				continue
+			}
+			_ = d.Ack(false)
+		}
+		if ctx.Err() == nil {
+			// Connection lost, the consumer will be started again on next synchronization
+			log.Warn(ctx, "rabbitMQ consumer on queue %s for workflow %s stopped", h.Data.Queue, h.WorkflowName)
+			s.removeMessageBusConsumer(h.ID)
+		}
+	})
+	return nil
+}
+
+func (s *Service) removeMessageBusConsumer(hookID string) {
+	s.messageBus.mutex.Lock()
+	defer s.messageBus.mutex.Unlock()
+	s.GoRoutines.Stop(messageBusConsumerName(hookID))
+	delete(s.messageBus.hooks, hookID)
+}
+
+// retryMessageBusEvent calls f until it succeeds or returns an invalid data error, at most messageBusMaxAttempts times.
+// The delay between two attempts is doubled after each attempt.
+func retryMessageBusEvent(ctx context.Context, delay time.Duration, f func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = f()
+		if err == nil || sdk.ErrorIs(err, sdk.ErrInvalidData) || attempt >= messageBusMaxAttempts {
+			return err
+		}
+		log.Warn(ctx, "unable to save message (attempt %d/%d), retrying in %s: %v", attempt, messageBusMaxAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > messageBusMaxRetryDelay {
+			delay = messageBusMaxRetryDelay
+		}
+	}
+}
+
+// computeMessageBusEvent returns the message as available in cds.event. A message that is not a JSON object
+// is available in cds.event.payload.
+func computeMessageBusEvent(message []byte) map[string]interface{} {
+	event := make(map[string]interface{})
+	if err := sdk.JSONUnmarshal(message, &event); err != nil || event == nil {
+		return map[string]interface{}{"payload": string(message)}
+	}
+	return event
+}
+
+// checkMessageBusFilter returns true if the message matches the filter of the hook
+func checkMessageBusFilter(ctx context.Context, h sdk.V2WorkflowHook, event map[string]interface{}) (bool, error) {
+	if h.Data.MessageFilter == "" {
+		return true, nil
+	}
+	ap := sdk.NewActionParser(map[string]interface{}{"cds": map[string]interface{}{"event": event}}, sdk.DefaultFuncs)
+	return ap.InterpolateToBool(ctx, h.Data.MessageFilter)
+}
+
+func (s *Service) enqueueMessageBusEvent(ctx context.Context, h sdk.V2WorkflowHook, message []byte) error {
+	event := computeMessageBusEvent(message)
+	match, err := checkMessageBusFilter(ctx, h, event)
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrInvalidData, "unable to check filter %q for workflow %s/%s/%s: %v", h.Data.MessageFilter, h.VCSName, h.RepositoryName, h.WorkflowName, err)
+	}
+	if !match {
+		log.Debug(ctx, "message doesn't match filter %q for workflow %s/%s/%s", h.Data.MessageFilter, h.VCSName, h.RepositoryName, h.WorkflowName)
+		return nil
+	}
+
+	repoKey := s.Dao.GetRepositoryMemberKey(h.VCSName, h.RepositoryName)
+	if s.Dao.FindRepository(ctx, repoKey) == nil {
+		if _, err := s.Dao.CreateRepository(ctx, h.VCSName, h.RepositoryName); err != nil {
+			return sdk.WrapError(err, "unable to create repository %s", repoKey)
+		}
+	}
+
+	eventName := sdk.WorkflowHookEventNameKafka
+	if h.Type == sdk.WorkflowHookTypeRabbitMQ {
+		eventName = sdk.WorkflowHookEventNameRabbitMQ
+	}
+	bts, _ := json.Marshal(event)
+	he := &sdk.HookRepositoryEvent{
+		UUID:           sdk.UUID(),
+		Created:        time.Now().UnixNano(),
+		EventName:      eventName,
+		VCSServerName:  h.VCSName,
+		RepositoryName: h.RepositoryName,
+		Body:           bts,
+		ExtractData: sdk.HookRepositoryEventExtractData{
+			Commit:       h.Commit,
+			Ref:          h.Ref,
+			CDSEventName: eventName,
+			MessageBus: &sdk.HookRepositoryEventExtractedDataMessageBus{
+				HookID:         h.ID,
+				TargetVCS:      h.Data.VCSServer,
+				TargetRepo:     h.Data.RepositoryName,
+				TargetWorkflow: h.WorkflowName,
+				TargetProject:  h.ProjectKey,
+				Integration:    h.Data.Integration,
+				Topic:          h.Data.Topic,
+				Queue:          h.Data.Queue,
+			},
+		},
+		Status:              sdk.HookEventStatusScheduled,
+		ProcessingTimestamp: time.Now().UnixNano(),
+		LastUpdate:          time.Now().UnixNano(),
+	}
+
+	if err := s.Dao.SaveRepositoryEvent(ctx, he); err != nil {
+		return sdk.WrapError(err, "unable to create repository event %s", he.GetFullName())
+	}
+	if err := s.Dao.EnqueueRepositoryEvent(ctx, he); err != nil {
+		return sdk.WrapError(err, "unable to enqueue repository event %s", he.GetFullName())
+	}
+	return nil
+}
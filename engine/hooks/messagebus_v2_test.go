@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestComputeMessageBusEvent(t *testing.T) {
+	event := computeMessageBusEvent([]byte(`{"action": "deploy", "version": "1.2.0"}`))
+	require.Equal(t, "deploy", event["action"])
+	require.Equal(t, "1.2.0", event["version"])
+
+	event = computeMessageBusEvent([]byte(`plain text`))
+	require.Equal(t, map[string]interface{}{"payload": "plain text"}, event)
+
+	event = computeMessageBusEvent([]byte(`["a", "b"]`))
+	require.Equal(t, map[string]interface{}{"payload": `["a", "b"]`}, event)
+}
+
+func TestCheckMessageBusFilter(t *testing.T) {
+	ctx := context.TODO()
+	event := computeMessageBusEvent([]byte(`{"action": "deploy", "env": "prod"}`))
+
+	h := sdk.V2WorkflowHook{Type: sdk.WorkflowHookTypeKafka}
+	match, err := checkMessageBusFilter(ctx, h, event)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	h.Data.MessageFilter = "${{ cds.event.action == 'deploy' && cds.event.env == 'prod' }}"
+	match, err = checkMessageBusFilter(ctx, h, event)
+	require.NoError(t, err)
+	require.True(t, match)
+
+	h.Data.MessageFilter = "${{ cds.event.action == 'rollback' }}"
+	match, err = checkMessageBusFilter(ctx, h, event)
+	require.NoError(t, err)
+	require.False(t, match)
+}
+
+func TestRetryMessageBusEvent(t *testing.T) {
+	ctx := context.TODO()
+
+	// A transient error is retried until the message is saved
+	var calls int
+	err := retryMessageBusEvent(ctx, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return sdk.WithStack(errors.New("redis is down"))
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+
+	// An invalid message is not retried
+	calls = 0
+	err = retryMessageBusEvent(ctx, time.Millisecond, func() error {
+		calls++
+		return sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid filter")
+	})
+	require.True(t, sdk.ErrorIs(err, sdk.ErrInvalidData))
+	require.Equal(t, 1, calls)
+
+	// The message is given back after the last attempt
+	calls = 0
+	err = retryMessageBusEvent(ctx, time.Millisecond, func() error {
+		calls++
+		return sdk.WithStack(errors.New("redis is down"))
+	})
+	require.Error(t, err)
+	require.Equal(t, messageBusMaxAttempts, calls)
+}
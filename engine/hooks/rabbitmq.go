@@ -154,16 +154,19 @@ func newConsumer(amqpURI, exchange, exchangeType, queueName, key, ctag string) (
 		return nil, fmt.Errorf("Channel: %s", err)
 	}
 
-	if err = c.channel.ExchangeDeclare(
-		exchange,     // name of the exchange
-		exchangeType, // type
-		true,         // durable
-		false,        // delete when complete
-		false,        // internal
-		false,        // noWait
-		nil,          // arguments
-	); err != nil {
-		return nil, fmt.Errorf("Exchange Declare: %s", err)
+	// Without exchange, the queue is consumed as is
+	if exchange != "" {
+		if err = c.channel.ExchangeDeclare(
+			exchange,     // name of the exchange
+			exchangeType, // type
+			true,         // durable
+			false,        // delete when complete
+			false,        // internal
+			false,        // noWait
+			nil,          // arguments
+		); err != nil {
+			return nil, fmt.Errorf("Exchange Declare: %s", err)
+		}
 	}
 
 	queue, err := c.channel.QueueDeclare(
@@ -178,14 +181,16 @@ func newConsumer(amqpURI, exchange, exchangeType, queueName, key, ctag string) (
 		return nil, fmt.Errorf("Queue Declare: %s", err)
 	}
 
-	if err = c.channel.QueueBind(
-		queue.Name, // name of the queue
-		key,        // bindingKey
-		exchange,   // sourceExchange
-		false,      // noWait
-		nil,        // arguments
-	); err != nil {
-		return nil, fmt.Errorf("Queue Bind: %s", err)
+	if exchange != "" {
+		if err = c.channel.QueueBind(
+			queue.Name, // name of the queue
+			key,        // bindingKey
+			exchange,   // sourceExchange
+			false,      // noWait
+			nil,        // arguments
+		); err != nil {
+			return nil, fmt.Errorf("Queue Bind: %s", err)
+		}
 	}
 
 	return c, nil
@@ -205,6 +205,14 @@ func (s *Service) triggerWorkflows(ctx context.Context, hre *sdk.HookRepositoryE
 					runRequest.Cron = hre.ExtractData.Scheduler.Cron
 					runRequest.CronTimezone = hre.ExtractData.Scheduler.Timezone
 					runRequest.Sha = wh.TargetCommit
+				case sdk.WorkflowHookTypeKafka:
+					runRequest.Integration = wh.Data.Integration
+					runRequest.Topic = wh.Data.Topic
+					runRequest.Sha = wh.TargetCommit
+				case sdk.WorkflowHookTypeRabbitMQ:
+					runRequest.Integration = wh.Data.Integration
+					runRequest.Topic = wh.Data.Queue
+					runRequest.Sha = wh.TargetCommit
 				case sdk.WorkflowHookTypeWorkflowRun:
 					runRequest.WorkflowRun = hre.ExtractData.WorkflowRun.Workflow
 					runRequest.WorkflowRunID = hre.ExtractData.WorkflowRun.WorkflowRunID
@@ -212,6 +220,8 @@ func (s *Service) triggerWorkflows(ctx context.Context, hre *sdk.HookRepositoryE
 					// Manual run can override repo and vcs
 					runRequest.TargetRepository = wh.Data.RepositoryName
 					runRequest.JobInputs = hre.ExtractData.Manual.JobInputs
+					runRequest.Inputs = hre.ExtractData.Manual.Inputs
+					runRequest.Debug = hre.ExtractData.Manual.Debug
 				}
 
 				wr, err := s.Client.WorkflowV2RunFromHook(ctx, wh.ProjectKey, wh.VCSIdentifier, wh.RepositoryIdentifier, wh.WorkflowName,
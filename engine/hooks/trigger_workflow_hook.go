@@ -35,6 +35,10 @@ func (s *Service) triggerGetWorkflowHooks(ctx context.Context, hre *sdk.HookRepo
 		if err := s.handleWorkflowRunHook(ctx, hre); err != nil {
 			return err
 		}
+	case sdk.WorkflowHookEventNameKafka, sdk.WorkflowHookEventNameRabbitMQ:
+		if err := s.handleMessageBusHook(ctx, hre); err != nil {
+			return err
+		}
 	default:
 		if err := s.handleWorkflowHook(ctx, hre); err != nil {
 			return err
@@ -227,6 +231,40 @@ func (s *Service) handleWorkflowHook(ctx context.Context, hre *sdk.HookRepositor
 	return nil
 }
 
+func (s *Service) handleMessageBusHook(ctx context.Context, hre *sdk.HookRepositoryEvent) error {
+	hookType := sdk.WorkflowHookTypeKafka
+	if hre.EventName == sdk.WorkflowHookEventNameRabbitMQ {
+		hookType = sdk.WorkflowHookTypeRabbitMQ
+	}
+	wh := sdk.HookRepositoryEventWorkflow{
+		Type:                 hookType,
+		Status:               sdk.HookEventWorkflowStatusScheduled,
+		ProjectKey:           hre.ExtractData.MessageBus.TargetProject,
+		VCSIdentifier:        hre.VCSServerName,
+		RepositoryIdentifier: hre.RepositoryName,
+		WorkflowName:         hre.ExtractData.MessageBus.TargetWorkflow,
+		Ref:                  hre.ExtractData.Ref,
+		Commit:               hre.ExtractData.Commit,
+		Data: sdk.V2WorkflowHookData{
+			VCSServer:      hre.ExtractData.MessageBus.TargetVCS,
+			RepositoryName: hre.ExtractData.MessageBus.TargetRepo,
+			Integration:    hre.ExtractData.MessageBus.Integration,
+			Topic:          hre.ExtractData.MessageBus.Topic,
+			Queue:          hre.ExtractData.MessageBus.Queue,
+		},
+	}
+	// As for scheduler, retrieve the workflow entity to get userID
+	e, err := s.Client.EntityGet(ctx, hre.ExtractData.MessageBus.TargetProject, hre.VCSServerName, hre.RepositoryName, sdk.EntityTypeWorkflow, hre.ExtractData.MessageBus.TargetWorkflow)
+	if err != nil {
+		return err
+	}
+	if e.UserID != nil {
+		wh.Initiator = &sdk.V2Initiator{UserID: *e.UserID}
+	}
+	hre.WorkflowHooks = []sdk.HookRepositoryEventWorkflow{wh}
+	return nil
+}
+
 func (s *Service) handleWebhookHook(ctx context.Context, hre *sdk.HookRepositoryEvent) error {
 	// Get workflow definition
 	e, err := s.Client.EntityGet(ctx, hre.ExtractData.WebHook.Project, hre.ExtractData.WebHook.VCS, hre.ExtractData.WebHook.Repository, sdk.EntityTypeWorkflow, hre.ExtractData.WebHook.Workflow)
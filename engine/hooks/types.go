@@ -47,6 +47,7 @@ type Service struct {
 	Maintenance             bool
 	WebHooksParsedPublicKey *rsa.PublicKey
 	UIURL                   string
+	messageBus              *messageBusConsumers
 }
 
 // Configuration is the hooks configuration structure
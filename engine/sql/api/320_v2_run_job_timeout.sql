@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE v2_workflow_run_job ADD COLUMN "timeout_minutes" BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE v2_workflow_run_job DROP COLUMN "timeout_minutes";
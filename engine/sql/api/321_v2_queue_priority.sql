@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE v2_workflow_run_job ADD COLUMN "priority" BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE region_project_weight (
+    "id"          uuid PRIMARY KEY,
+    "region_id"   uuid NOT NULL,
+    "project_key" VARCHAR(255) NOT NULL,
+    "weight"      BIGINT NOT NULL DEFAULT 1
+);
+SELECT create_foreign_key_idx_cascade('FK_region_project_weight_region', 'region_project_weight', 'region', 'region_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_region_project_weight_project', 'region_project_weight', 'project', 'project_key', 'projectkey');
+SELECT create_unique_index('region_project_weight', 'IDX_region_project_weight_unq', 'region_id,project_key');
+
+-- +migrate Down
+DROP TABLE region_project_weight;
+ALTER TABLE v2_workflow_run_job DROP COLUMN "priority";
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE project_log_redaction  (
+    "id"                uuid PRIMARY KEY,
+    "project_key"       VARCHAR(255) NOT NULL,
+    "last_modified"     TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    "rules"             JSONB NOT NULL
+);
+SELECT create_foreign_key_idx_cascade('FK_project_log_redaction', 'project_log_redaction', 'project', 'project_key', 'projectkey');
+SELECT create_unique_index('project_log_redaction', 'idx_unq_project_log_redaction', 'project_key');
+
+-- +migrate Down
+DROP TABLE project_log_redaction;
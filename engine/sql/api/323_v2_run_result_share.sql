@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE v2_workflow_run_result_share (
+    "id"                uuid PRIMARY KEY,
+    "project_key"       VARCHAR(255) NOT NULL,
+    "workflow_run_id"   uuid NOT NULL,
+    "run_result_id"     uuid NOT NULL,
+    "created"           TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    "created_by"        VARCHAR(255) NOT NULL,
+    "expires"           TIMESTAMP WITH TIME ZONE NOT NULL,
+    "single_use"        BOOLEAN NOT NULL DEFAULT FALSE,
+    "download_count"    BIGINT NOT NULL DEFAULT 0
+);
+SELECT create_foreign_key_idx_cascade('FK_v2_workflow_run_result_share_run', 'v2_workflow_run_result_share', 'v2_workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_v2_workflow_run_result_share_result', 'v2_workflow_run_result_share', 'v2_workflow_run_result', 'run_result_id', 'id');
+
+CREATE TABLE v2_workflow_run_result_share_download (
+    "id"                uuid PRIMARY KEY,
+    "share_id"          uuid NOT NULL,
+    "downloaded"        TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    "remote_addr"       VARCHAR(255) NOT NULL DEFAULT '',
+    "user_agent"        TEXT NOT NULL DEFAULT ''
+);
+SELECT create_foreign_key_idx_cascade('FK_v2_workflow_run_result_share_download', 'v2_workflow_run_result_share_download', 'v2_workflow_run_result_share', 'share_id', 'id');
+
+-- +migrate Down
+DROP TABLE v2_workflow_run_result_share_download;
+DROP TABLE v2_workflow_run_result_share;
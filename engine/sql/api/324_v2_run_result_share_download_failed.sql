@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE v2_workflow_run_result_share_download ADD COLUMN "failed" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE v2_workflow_run_result_share_download DROP COLUMN "failed";
@@ -0,0 +1,17 @@
+-- +migrate Up
+
+CREATE TABLE IF NOT EXISTS "quota" (
+  id VARCHAR(36) PRIMARY KEY, -- technical ID
+  created TIMESTAMP WITH TIME ZONE, -- creation date
+  last_modified TIMESTAMP WITH TIME ZONE, -- last modified date
+  project_key VARCHAR(256) NOT NULL,
+  item_type VARCHAR(64) NOT NULL DEFAULT '', -- empty for all item types
+  soft_limit BIGINT NOT NULL DEFAULT 0,
+  hard_limit BIGINT NOT NULL DEFAULT 0,
+  sig BYTEA,
+  signer TEXT
+);
+select create_unique_index('quota', 'IDX_QUOTA_UNIQ_PROJECT_TYPE', 'project_key,item_type');
+
+-- +migrate Down
+DROP TABLE IF EXISTS "quota";
@@ -0,0 +1,7 @@
+-- +migrate Up notransaction
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_item_worker_cache_tag_prefix
+ON item (type, (api_ref->>'project_key'), (api_ref->>'cache_tag') text_pattern_ops)
+WHERE to_delete = false;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_item_worker_cache_tag_prefix;
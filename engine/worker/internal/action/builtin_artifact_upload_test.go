@@ -33,6 +33,7 @@ func TestRunArtifactUpload_Absolute(t *testing.T) {
 
 	defer os.Remove("foo")
 
+	gock.New("http://cds-cdn.local").Post("/item/upload/dedup").Reply(404)
 	gock.New("http://cds-cdn.local").Post("/item/upload").Reply(200)
 
 	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
@@ -42,7 +43,7 @@ func TestRunArtifactUpload_Absolute(t *testing.T) {
 		if mock != nil {
 			t.Logf("%s %s - Body: %s", mock.Request().Method, mock.Request().URLStruct.String(), string(bodyContent))
 		}
-		assert.Equal(t, "http://cds-cdn.local/item/upload", mock.Request().URLStruct.String())
+		assert.Contains(t, []string{"http://cds-cdn.local/item/upload/dedup", "http://cds-cdn.local/item/upload"}, mock.Request().URLStruct.String())
 	}
 
 	gock.Observe(checkRequest)
@@ -79,6 +80,7 @@ func TestRunArtifactUpload_Relative(t *testing.T) {
 	fname := filepath.Join(wk.workingDirectory.Name(), "foo")
 	assert.NoError(t, afero.WriteFile(wk.workspace, fname, []byte("something"), os.ModePerm))
 
+	gock.New("http://cds-cdn.local").Post("/item/upload/dedup").Reply(404)
 	gock.New("http://cds-cdn.local").Post("/item/upload").Reply(200)
 
 	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
@@ -88,7 +90,7 @@ func TestRunArtifactUpload_Relative(t *testing.T) {
 		if mock != nil {
 			t.Logf("%s %s - Body: %s", mock.Request().Method, mock.Request().URLStruct.String(), string(bodyContent))
 		}
-		assert.Equal(t, "http://cds-cdn.local/item/upload", mock.Request().URLStruct.String())
+		assert.Contains(t, []string{"http://cds-cdn.local/item/upload/dedup", "http://cds-cdn.local/item/upload"}, mock.Request().URLStruct.String())
 	}
 
 	gock.Observe(checkRequest)
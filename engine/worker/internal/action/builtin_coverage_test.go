@@ -32,6 +32,7 @@ func TestRunCoverage_Absolute(t *testing.T) {
 	fiPath, err := filepath.Abs(fi.Name())
 	require.NoError(t, err)
 
+	gock.New("http://cds-cdn.local").Post("/item/upload/dedup").Reply(404)
 	gock.New("http://cds-cdn.local").Post("/item/upload").Reply(200)
 
 	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
@@ -77,6 +78,7 @@ func TestRunCoverage_Relative(t *testing.T) {
 	fname := filepath.Join(wk.workingDirectory.Name(), "results.xml")
 	require.NoError(t, afero.WriteFile(wk.BaseDir(), fname, []byte(cobertura_result), os.ModePerm))
 
+	gock.New("http://cds-cdn.local").Post("/item/upload/dedup").Reply(404)
 	gock.New("http://cds-cdn.local").Post("/item/upload").Reply(200)
 
 	var checkRequest gock.ObserverFunc = func(request *http.Request, mock gock.Mock) {
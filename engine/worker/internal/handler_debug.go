@@ -0,0 +1,17 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+func debugContinueHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !wk.debugPause.resumeJob() {
+			writeError(w, r, fmt.Errorf("job is not paused"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -71,6 +71,7 @@ func (w *CurrentWorker) Serve(c context.Context) error {
 	r.HandleFunc("/v2/context", LogMiddleware(workerruntime.V2_contextHandler(c, w)))
 	r.HandleFunc("/v2/result", LogMiddleware(workerruntime.V2_runResultHandler(c, w)))
 	r.HandleFunc("/v2/result/synchronize", LogMiddleware(workerruntime.V2_runResultsSynchronizeHandler(c, w)))
+	r.HandleFunc("/v2/debug/continue", LogMiddleware(debugContinueHandler(c, w)))
 
 	srv := &http.Server{
 		Handler: r,
@@ -35,8 +35,9 @@ func (w *CurrentWorker) V2ProcessJob() (res sdk.V2WorkflowRunJobResult) {
 	ctx := w.currentJobV2.context
 	t0 := time.Now()
 
-	// Timeout must be the same as the goroutine which stop jobs in package api/workflow
-	ctx, cancel := context.WithTimeout(ctx, 24*time.Hour)
+	// Timeout must be the same as the goroutine which stop timed out jobs in package api
+	jobTimeout := w.currentJobV2.runJob.Job.GetTimeout() + w.currentJobV2.debug.MaxPauseDuration()
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
 	log.Info(ctx, "Process Job %s (%s)", w.currentJobV2.runJob.JobID, w.currentJobV2.runJob.ID)
 	defer func() {
 		log.Info(ctx, "Process Job Done %s (%s) :%s", w.currentJobV2.runJob.JobID, w.currentJobV2.runJob.ID, sdk.Round(time.Since(t0), time.Second).String())
@@ -62,6 +63,13 @@ func (w *CurrentWorker) V2ProcessJob() (res sdk.V2WorkflowRunJobResult) {
 	ctx = workerruntime.SetWorkingDirectory(ctx, wdFile)
 	log.Debug(ctx, "Setup workspace - %s", wdFile.Name())
 
+	// A job run locally starts with the content of the local working copy
+	if w.currentJobV2.localSource != "" {
+		if err := copyLocalSource(w.currentJobV2.localSource, wdAbs); err != nil {
+			return w.failJob(ctx, fmt.Sprintf("Error: unable to copy %s into the workspace: %v", w.currentJobV2.localSource, err))
+		}
+	}
+
 	// Manage services readiness
 	if result := w.runJobServicesReadiness(ctx); result.Status != sdk.V2WorkflowRunJobStatusSuccess {
 		return w.failJob(ctx, fmt.Sprintf("Error: readiness service command failed: %v", result.Error))
@@ -102,6 +110,9 @@ func (w *CurrentWorker) V2ProcessJob() (res sdk.V2WorkflowRunJobResult) {
 		return w.failJob(ctx, fmt.Sprintf("Error: unable to setup hooks: %v", err))
 	}
 	res = w.runJobAsCode(ctx)
+	if ctx.Err() == context.DeadlineExceeded {
+		res = w.failJob(ctx, fmt.Sprintf("Job timed out after %s", jobTimeout))
+	}
 
 	// Delete hooks directory
 	if err := teardownDirectory(w.basedir, hdFile.Name()); err != nil {
@@ -262,7 +273,7 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 	}
 
 	defer func() {
-		w.gelfLogger.hook.Flush()
+		w.gelfLogger.flush()
 		log.Info(ctx, "runJob> end of job %s (%s)", w.currentJobV2.runJob.JobID, w.currentJobV2.runJob.ID)
 	}()
 
@@ -277,11 +288,27 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 
 	postActionsJob := make([]ActionPostJob, 0)
 
+	// Restore the job cache before the first step, job steps logs are shifted
+	var stepOrderOffset int
+	var cacheState jobCacheState
+	jobCache := w.currentJobV2.runJob.Job.Cache
+	if jobCache != nil {
+		stepOrderOffset = 1
+		if err := w.runJobCacheStep(ctx, &jobResult, 0, jobCacheRestoreStepName, func(ctx context.Context) sdk.V2WorkflowRunJobResult {
+			var res sdk.V2WorkflowRunJobResult
+			cacheState, res = w.restoreJobCache(ctx, *jobCache, w.currentJobV2.runJobContext)
+			return res
+		}); err != nil {
+			return w.failJob(ctx, err.Error())
+		}
+	}
+
+	var pausedOnFailure bool
 	for jobStepIndex, step := range w.currentJobV2.runJob.Job.Steps {
 		// Reset step log line to 0
 		w.stepLogLine = 0
-		w.currentJobV2.currentStepIndexForLog = jobStepIndex
-		ctx = workerruntime.SetStepOrder(ctx, jobStepIndex)
+		w.currentJobV2.currentStepIndexForLog = jobStepIndex + stepOrderOffset
+		ctx = workerruntime.SetStepOrder(ctx, w.currentJobV2.currentStepIndexForLog)
 
 		// Set step in context
 		w.currentJobV2.currentStepNameForLog = sdk.GetJobStepName(step.ID, jobStepIndex)
@@ -301,6 +328,10 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 			return w.failJob(ctx, err.Error())
 		}
 
+		if w.currentJobV2.debug.HasBreakpointBeforeStep(w.currentJobV2.currentStepNameForLog) {
+			w.debugBreakpoint(ctx, w.currentJobV2.currentStepNameForLog, "breakpoint before step")
+		}
+
 		stepRes, pa := w.runActionStep(ctx, step, w.currentJobV2.currentStepNameForLog, *currentStepContext)
 
 		// If job is already failed, display error in job logs
@@ -308,7 +339,7 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 			w.SendLog(ctx, workerruntime.LevelError, stepRes.Error)
 		}
 		w.SendTerminatedStepLog(ctx, workerruntime.LevelInfo, "")
-		w.gelfLogger.hook.Flush()
+		w.gelfLogger.flush()
 		if pa != nil {
 			postActionsJob = append(postActionsJob, *pa)
 		}
@@ -319,6 +350,22 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 			return w.failJob(ctx, fmt.Sprintf("unable to update step context: %v", err))
 		}
 
+		// Pause on the first failure, before the next steps are skipped
+		if w.currentJobV2.debug != nil && w.currentJobV2.debug.OnFailure && !pausedOnFailure &&
+			stepRes.Status == sdk.V2WorkflowRunJobStatusFail && !step.ContinueOnError {
+			pausedOnFailure = true
+			w.debugBreakpoint(ctx, w.currentJobV2.currentStepNameForLog, "step failed")
+		}
+	}
+
+	// Save the job cache after the last step
+	if jobCache != nil && jobResult.Status == sdk.V2WorkflowRunJobStatusSuccess {
+		stepOrderOffset++
+		if err := w.runJobCacheStep(ctx, &jobResult, len(w.currentJobV2.runJob.Job.Steps)+1, jobCacheSaveStepName, func(ctx context.Context) sdk.V2WorkflowRunJobResult {
+			return w.saveJobCache(ctx, *jobCache, cacheState, w.currentJobV2.runJobContext)
+		}); err != nil {
+			return w.failJob(ctx, err.Error())
+		}
 	}
 
 	resolvedOutputs, err := w.computeOutputs(ctx, w.currentJobV2.runJobContext, w.currentJobV2.runJob.Job.Outputs)
@@ -356,7 +403,7 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 		for i := 0; i < len(postActionsJob); i++ {
 			post := postActionsJob[len(postActionsJob)-1-i]
 			w.stepLogLine = 0
-			w.currentJobV2.currentStepIndexForLog = len(w.currentJobV2.runJob.Job.Steps) + i
+			w.currentJobV2.currentStepIndexForLog = len(w.currentJobV2.runJob.Job.Steps) + stepOrderOffset + i
 			ctx = workerruntime.SetStepOrder(ctx, w.currentJobV2.currentStepIndexForLog)
 			w.currentJobV2.currentStepNameForLog = "Post-" + post.StepName
 			ctx = workerruntime.SetStepName(ctx, w.currentJobV2.currentStepNameForLog)
@@ -366,7 +413,7 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 			postActionResult := w.runPostAction(ctx, post, w.currentJobV2.runJobContext)
 			w.updateStepResult(&jobResult, postActionResult, post.ContinueOnError, w.currentJobV2.currentStepNameForLog)
 			w.SendTerminatedStepLog(ctx, workerruntime.LevelInfo, "")
-			w.gelfLogger.hook.Flush()
+			w.gelfLogger.flush()
 			w.currentJobV2.runJobContext.Steps = w.currentJobV2.runJob.StepsStatus.ToStepContext()
 
 			if err := w.ClientV2().V2QueueJobStepUpdate(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, w.currentJobV2.runJob.StepsStatus); err != nil {
@@ -378,6 +425,32 @@ func (w *CurrentWorker) runJobAsCode(ctx context.Context) sdk.V2WorkflowRunJobRe
 	return jobResult
 }
 
+// runJobCacheStep runs a job cache operation as a dedicated step. A job cache failure doesn't fail the job.
+func (w *CurrentWorker) runJobCacheStep(ctx context.Context, jobResult *sdk.V2WorkflowRunJobResult, stepOrder int, stepName string, f func(ctx context.Context) sdk.V2WorkflowRunJobResult) error {
+	w.stepLogLine = 0
+	w.currentJobV2.currentStepIndexForLog = stepOrder
+	ctx = workerruntime.SetStepOrder(ctx, stepOrder)
+	w.currentJobV2.currentStepNameForLog = stepName
+	ctx = workerruntime.SetStepName(ctx, stepName)
+
+	w.createStepStatus(stepName)
+	w.currentJobV2.runJobContext.Steps = w.currentJobV2.runJob.StepsStatus.ToStepContext()
+	if err := w.ClientV2().V2QueueJobStepUpdate(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, w.currentJobV2.runJob.StepsStatus); err != nil {
+		return fmt.Errorf("unable to update step context: %v", err)
+	}
+
+	res := f(ctx)
+	w.SendTerminatedStepLog(ctx, workerruntime.LevelInfo, "")
+	w.gelfLogger.flush()
+	w.updateStepResult(jobResult, res, true, stepName)
+	w.currentJobV2.runJobContext.Steps = w.currentJobV2.runJob.StepsStatus.ToStepContext()
+
+	if err := w.ClientV2().V2QueueJobStepUpdate(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, w.currentJobV2.runJob.StepsStatus); err != nil {
+		return fmt.Errorf("unable to update step context: %v", err)
+	}
+	return nil
+}
+
 func (w *CurrentWorker) runPostAction(ctx context.Context, postAction ActionPostJob, currentContext sdk.WorkflowRunJobsContext) sdk.V2WorkflowRunJobResult {
 	env, err := w.GetEnvVariable(ctx, currentContext)
 	if err != nil {
@@ -495,6 +568,13 @@ func (w *CurrentWorker) runActionStep(ctx context.Context, step sdk.ActionStep,
 		}, nil
 	}
 
+	parentCtx := ctx
+	if step.TimeoutMinutes > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(step.TimeoutMinutes)*time.Minute)
+		defer cancel()
+	}
+
 	var result sdk.V2WorkflowRunJobResult
 	var postActionsJob *ActionPostJob
 	switch {
@@ -505,6 +585,11 @@ func (w *CurrentWorker) runActionStep(ctx context.Context, step sdk.ActionStep,
 	default:
 		return w.failJob(ctx, "invalid action definition. Missing uses or run keys"), nil
 	}
+
+	// Only report the step timeout if the job itself is still alive
+	if ctx.Err() == context.DeadlineExceeded && parentCtx.Err() == nil {
+		return w.failJob(parentCtx, fmt.Sprintf("Step %s timed out after %d minutes", stepName, step.TimeoutMinutes)), nil
+	}
 	return result, postActionsJob
 }
 
@@ -517,6 +602,12 @@ func (w *CurrentWorker) runJobStepAction(ctx context.Context, step sdk.ActionSte
 		Status: sdk.V2WorkflowRunJobStatusSuccess,
 	}
 
+	// The workspace of a job run locally already contains the local working copy
+	if w.currentJobV2.localSource != "" && len(actionPath) == 1 && actionPath[0] == "checkout" {
+		w.SendLog(ctx, workerruntime.LevelInfo, "Checkout skipped: the workspace contains the local working copy")
+		return actionResult, nil
+	}
+
 	// Set action inputs
 	inputs := make(map[string]interface{})
 	if len(actionPath) == 1 {
@@ -0,0 +1,219 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+const (
+	jobCacheRestoreStepName  = "Cache-Restore"
+	jobCacheSaveStepName     = "Post-Cache-Save"
+	jobCacheOutputHit        = "cache-hit"
+	jobCacheOutputMatchedKey = "cache-matched-key"
+)
+
+// jobCacheState contains the job cache resolved when the cache is restored
+type jobCacheState struct {
+	key        string
+	matchedKey string
+}
+
+// hit returns true if the cache restored matches exactly the key of the job cache
+func (s jobCacheState) hit() bool {
+	return s.key != "" && s.key == s.matchedKey
+}
+
+// jobCacheEntryKey returns the key used to store a path of the job cache: each path is stored in a dedicated cache entry
+func jobCacheEntryKey(key string, pathIndex int) string {
+	return key + "." + strconv.Itoa(pathIndex)
+}
+
+// jobCacheKeyFromEntryKey returns the key of the job cache that contains the given cache entry
+func jobCacheKeyFromEntryKey(entryKey string) (string, bool) {
+	i := strings.LastIndex(entryKey, ".")
+	if i <= 0 {
+		return "", false
+	}
+	if _, err := strconv.Atoi(entryKey[i+1:]); err != nil {
+		return "", false
+	}
+	return entryKey[:i], true
+}
+
+func (w *CurrentWorker) interpolateJobCacheKeys(ctx context.Context, cache sdk.V2JobCache, jobContext sdk.WorkflowRunJobsContext) (string, []string, error) {
+	bts, err := json.Marshal(jobContext)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to marshal contexts: %v", err)
+	}
+	var mapContexts map[string]interface{}
+	if err := json.Unmarshal(bts, &mapContexts); err != nil {
+		return "", nil, fmt.Errorf("unable to unmarshal contexts: %v", err)
+	}
+	ap := sdk.NewActionParser(mapContexts, sdk.DefaultFuncs)
+
+	key, err := ap.InterpolateToString(ctx, cache.Key)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to interpolate cache key %s: %v", cache.Key, err)
+	}
+	if key == "" {
+		return "", nil, fmt.Errorf("cache key %s is empty", cache.Key)
+	}
+	restoreKeys := make([]string, 0, len(cache.RestoreKeys))
+	for _, k := range cache.RestoreKeys {
+		restoreKey, err := ap.InterpolateToString(ctx, k)
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to interpolate cache restore key %s: %v", k, err)
+		}
+		if restoreKey != "" {
+			restoreKeys = append(restoreKeys, restoreKey)
+		}
+	}
+	return key, restoreKeys, nil
+}
+
+// findJobCache returns the key of the job cache to restore. The exact key is checked first, then the most recent cache
+// matching each restore key prefix. An empty key is returned if no cache is found.
+func (w *CurrentWorker) findJobCache(ctx context.Context, key string, restoreKeys []string) (string, error) {
+	links, err := w.ClientV2().V2QueueGetCacheLinks(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, jobCacheEntryKey(key, 0))
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return "", err
+	}
+	if err == nil && len(links.Items) > 0 {
+		return key, nil
+	}
+
+	for _, restoreKey := range restoreKeys {
+		links, err := w.ClientV2().V2QueueGetCacheLinks(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, restoreKey, cdsclient.WithQueryParameter("prefix", "true"))
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return "", err
+		}
+		if err != nil || len(links.Items) == 0 {
+			continue
+		}
+		apiRef, ok := links.Items[0].GetCDNWorkerCacheApiRef()
+		if !ok {
+			continue
+		}
+		if matchedKey, ok := jobCacheKeyFromEntryKey(apiRef.CacheTag); ok {
+			return matchedKey, nil
+		}
+	}
+	return "", nil
+}
+
+func hasArtifactManagerIntegration(jobContext sdk.WorkflowRunJobsContext) bool {
+	return jobContext.Integrations != nil && jobContext.Integrations.ArtifactManager.Name != ""
+}
+
+// restoreJobCache restores the job cache before the first step of the job
+func (w *CurrentWorker) restoreJobCache(ctx context.Context, cache sdk.V2JobCache, jobContext sdk.WorkflowRunJobsContext) (jobCacheState, sdk.V2WorkflowRunJobResult) {
+	var state jobCacheState
+	success := sdk.V2WorkflowRunJobResult{Status: sdk.V2WorkflowRunJobStatusSuccess, Time: time.Now()}
+
+	key, restoreKeys, err := w.interpolateJobCacheKeys(ctx, cache, jobContext)
+	if err != nil {
+		// Files used by hashFiles may be created by the steps, the key will be computed again to save the cache
+		w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("unable to compute cache key, cache is not restored: %v", err))
+		w.AddStepOutput(ctx, jobCacheOutputHit, "false")
+		return state, success
+	}
+	state.key = key
+
+	if hasArtifactManagerIntegration(jobContext) {
+		// Caches stored on the artifact manager cannot be listed, only the exact key is restored
+		state.matchedKey = key
+	} else {
+		state.matchedKey, err = w.findJobCache(ctx, key, restoreKeys)
+		if err != nil {
+			return state, w.failJob(ctx, fmt.Sprintf("unable to find cache %s: %v", key, err))
+		}
+	}
+
+	if state.matchedKey == "" {
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("No cache found for key %s", key))
+		w.AddStepOutput(ctx, jobCacheOutputHit, "false")
+		return state, success
+	}
+
+	env, err := w.GetEnvVariable(ctx, jobContext)
+	if err != nil {
+		return state, w.failJob(ctx, err.Error())
+	}
+	for i, p := range cache.Paths {
+		res, _ := w.runPlugin(ctx, "cacheRestore", map[string]interface{}{
+			"key":  jobCacheEntryKey(state.matchedKey, i),
+			"path": filepath.Dir(p),
+		}, env)
+		if res.Status != sdk.V2WorkflowRunJobStatusSuccess {
+			return state, res
+		}
+	}
+
+	if hasArtifactManagerIntegration(jobContext) && w.GetCurrentStepsStatus()[jobCacheRestoreStepName].Outputs[jobCacheOutputHit] != "true" {
+		state.matchedKey = ""
+	}
+	w.AddStepOutput(ctx, jobCacheOutputHit, strconv.FormatBool(state.hit()))
+	w.AddStepOutput(ctx, jobCacheOutputMatchedKey, state.matchedKey)
+	return state, success
+}
+
+// saveJobCache saves the job cache after the last step of the job. Nothing is saved if the key already exists.
+// The key is computed again because the files used by hashFiles may have been created or updated by the steps.
+func (w *CurrentWorker) saveJobCache(ctx context.Context, cache sdk.V2JobCache, state jobCacheState, jobContext sdk.WorkflowRunJobsContext) sdk.V2WorkflowRunJobResult {
+	if w.currentJobV2.localRun {
+		w.SendLog(ctx, workerruntime.LevelInfo, "Cache is not saved on a local run")
+		return sdk.V2WorkflowRunJobResult{Status: sdk.V2WorkflowRunJobStatusSkipped, Time: time.Now()}
+	}
+
+	key, _, err := w.interpolateJobCacheKeys(ctx, cache, jobContext)
+	if err != nil {
+		if state.key == "" {
+			return w.failJob(ctx, err.Error())
+		}
+		w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("unable to compute cache key again, key %s is used: %v", state.key, err))
+		key = state.key
+	}
+	if state.key != "" && key != state.key {
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache key changed from %s to %s during the job", state.key, key))
+	}
+
+	if key == state.matchedKey {
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache hit on key %s, cache is not saved", key))
+		return sdk.V2WorkflowRunJobResult{Status: sdk.V2WorkflowRunJobStatusSkipped, Time: time.Now()}
+	}
+
+	if !hasArtifactManagerIntegration(jobContext) {
+		existingKey, err := w.findJobCache(ctx, key, nil)
+		if err != nil {
+			return w.failJob(ctx, fmt.Sprintf("unable to check cache %s: %v", key, err))
+		}
+		if existingKey != "" {
+			w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache %s already exists, cache is not saved", key))
+			return sdk.V2WorkflowRunJobResult{Status: sdk.V2WorkflowRunJobStatusSkipped, Time: time.Now()}
+		}
+	}
+
+	env, err := w.GetEnvVariable(ctx, jobContext)
+	if err != nil {
+		return w.failJob(ctx, err.Error())
+	}
+	for i, p := range cache.Paths {
+		res, _ := w.runPlugin(ctx, "cacheSave", map[string]interface{}{
+			"key":  jobCacheEntryKey(key, i),
+			"path": p,
+		}, env)
+		if res.Status != sdk.V2WorkflowRunJobStatusSuccess {
+			return res
+		}
+	}
+	return sdk.V2WorkflowRunJobResult{Status: sdk.V2WorkflowRunJobStatusSuccess, Time: time.Now()}
+}
@@ -0,0 +1,206 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+// debugPause allows to resume a paused job from a debug session or from the worker HTTP server
+type debugPause struct {
+	mutex  sync.Mutex
+	resume context.CancelFunc
+}
+
+func (p *debugPause) set(resume context.CancelFunc) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.resume = resume
+}
+
+// resumeJob ends the current pause, it returns false if the job is not paused
+func (p *debugPause) resumeJob() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.resume == nil {
+		return false
+	}
+	p.resume()
+	p.resume = nil
+	return true
+}
+
+// debugShellSession is a shell opened on the worker by a user during a pause
+type debugShellSession struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+func (s *debugShellSession) close() {
+	_ = s.stdin.Close()
+	if s.cmd.Process != nil {
+		_ = s.cmd.Process.Kill()
+	}
+}
+
+// debugShellWriter sends the output of a shell to the debug session
+type debugShellWriter struct {
+	sessionID string
+	send      func(sdk.V2DebugShellMessage)
+}
+
+func (w debugShellWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	w.send(sdk.V2DebugShellMessage{SessionID: w.sessionID, Type: sdk.V2DebugShellMessageTypeOutput, Data: data})
+	return len(p), nil
+}
+
+func debugShellCommand() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd.exe"}
+	}
+	return []string{"/bin/sh", "-i"}
+}
+
+// debugBreakpoint pauses the job if debug mode is enabled. It returns at the end of the pause, when the job is resumed
+// by a user or when the job is canceled. Users can open debug shells on the worker during the pause.
+func (w *CurrentWorker) debugBreakpoint(ctx context.Context, stepName, reason string) {
+	debug := w.currentJobV2.debug
+	if debug == nil {
+		return
+	}
+	runJob := w.currentJobV2.runJob
+
+	until := time.Now().Add(debug.PauseDuration())
+	if err := w.ClientV2().V2QueueJobDebugBreakpoint(ctx, runJob.Region, runJob.ID, sdk.V2WorkflowRunJobBreakpoint{
+		StepName: stepName,
+		Reason:   reason,
+		Until:    until,
+	}); err != nil {
+		w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("unable to pause the job: %v", err))
+		return
+	}
+	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Job paused (%s) until %s. Resume the job from a debug session with 'worker debug continue'", reason, until.Format(time.RFC3339)))
+
+	pauseCtx, cancel := context.WithDeadline(ctx, until)
+	defer cancel()
+	w.debugPause.set(cancel)
+	defer w.debugPause.resumeJob()
+
+	env, err := w.GetEnvVariable(ctx, w.currentJobV2.runJobContext)
+	if err != nil {
+		log.Error(ctx, "unable to compute environment variables for debug shell: %v", err)
+	}
+
+	msgToSend := make(chan json.RawMessage)
+	msgReceived := make(chan json.RawMessage)
+	errs := make(chan error)
+	send := func(msg sdk.V2DebugShellMessage) {
+		bts, _ := json.Marshal(msg)
+		select {
+		case msgToSend <- bts:
+		case <-pauseCtx.Done():
+		}
+	}
+
+	goRoutines := sdk.NewGoRoutines(pauseCtx)
+	websocketDone := make(chan struct{})
+	goRoutines.Exec(pauseCtx, "debug-websocket-"+runJob.ID, func(ctx context.Context) {
+		defer close(websocketDone)
+		for ctx.Err() == nil {
+			if err := w.ClientV2().V2QueueJobDebugWebsocket(ctx, goRoutines, runJob.Region, runJob.ID, msgToSend, msgReceived, errs); err != nil && ctx.Err() == nil {
+				log.Warn(ctx, "debug websocket error: %v", err)
+				time.Sleep(time.Second)
+			}
+		}
+	})
+
+	sessions := make(map[string]*debugShellSession)
+	defer func() {
+		for _, s := range sessions {
+			s.close()
+		}
+	}()
+
+	for {
+		select {
+		case <-websocketDone:
+			w.SendLog(ctx, workerruntime.LevelInfo, "Job resumed")
+			if err := w.ClientV2().V2QueueJobDebugResume(ctx, runJob.Region, runJob.ID); err != nil {
+				log.Error(ctx, "unable to resume the job: %v", err)
+			}
+			return
+		case err := <-errs:
+			log.Warn(ctx, "debug websocket error: %v", err)
+		case m := <-msgReceived:
+			var msg sdk.V2DebugShellMessage
+			if err := sdk.JSONUnmarshal(m, &msg); err != nil {
+				log.Warn(ctx, "invalid debug shell message: %v", err)
+				continue
+			}
+			switch msg.Type {
+			case sdk.V2DebugShellMessageTypeOpen:
+				s, err := w.openDebugShell(pauseCtx, msg.SessionID, env, send)
+				if err != nil {
+					send(sdk.V2DebugShellMessage{SessionID: msg.SessionID, Type: sdk.V2DebugShellMessageTypeOutput, Data: []byte(err.Error())})
+					send(sdk.V2DebugShellMessage{SessionID: msg.SessionID, Type: sdk.V2DebugShellMessageTypeClose})
+					continue
+				}
+				sessions[msg.SessionID] = s
+				w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Debug session %s opened by %s", msg.SessionID, msg.Username))
+			case sdk.V2DebugShellMessageTypeInput:
+				if s, has := sessions[msg.SessionID]; has {
+					_, _ = s.stdin.Write(msg.Data)
+				}
+			case sdk.V2DebugShellMessageTypeClose:
+				if s, has := sessions[msg.SessionID]; has {
+					s.close()
+					delete(sessions, msg.SessionID)
+					w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Debug session %s closed", msg.SessionID))
+				}
+			case sdk.V2DebugShellMessageTypeContinue:
+				w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Job resumed by %s", msg.Username))
+				cancel()
+			}
+		}
+	}
+}
+
+// openDebugShell starts a shell in the job workspace with the job environment variables
+func (w *CurrentWorker) openDebugShell(ctx context.Context, sessionID string, env map[string]string, send func(sdk.V2DebugShellMessage)) (*debugShellSession, error) {
+	shell := debugShellCommand()
+	cmd := exec.CommandContext(ctx, shell[0], shell[1:]...)
+	cmd.Dir = w.workingDirAbs
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	output := debugShellWriter{sessionID: sessionID, send: send}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open debug shell: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("unable to open debug shell: %v", err)
+	}
+	go func() {
+		_ = cmd.Wait()
+		send(sdk.V2DebugShellMessage{SessionID: sessionID, Type: sdk.V2DebugShellMessageTypeClose})
+	}()
+	return &debugShellSession{cmd: cmd, stdin: stdin}, nil
+}
@@ -0,0 +1,337 @@
+package internal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/jws"
+)
+
+// V2LocalRunOptions contains the options of a job run on the local host
+type V2LocalRunOptions struct {
+	// Basedir is the directory where the job workspace is created
+	Basedir string
+	// Source is copied into the job workspace, checkout steps are skipped
+	Source string
+	// Plugins is used to download the action plugins from the CDS API, plugins are not available if nil
+	Plugins cdsclient.GRPCPluginsClient
+	// Output receives the job logs
+	Output io.Writer
+}
+
+// V2LocalRun runs a job on the local host, without any worker registered on the CDS API. Steps status, job infos
+// and run results are kept in memory, the CDS API is only used to download the action plugins.
+func V2LocalRun(ctx context.Context, job sdk.V2TakeJobResponse, opts V2LocalRunOptions) (sdk.V2WorkflowRunJobResult, []sdk.V2WorkflowRunResult, error) {
+	osFs := afero.NewOsFs()
+	if err := osFs.MkdirAll(opts.Basedir, os.FileMode(0755)); err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, fmt.Errorf("unable to setup basedir %q: %v", opts.Basedir, err)
+	}
+
+	w := new(CurrentWorker)
+	cfg := &workerruntime.WorkerConfig{
+		Name:     "local-" + job.RunJob.JobID,
+		Basedir:  opts.Basedir,
+		RunJobID: job.RunJob.ID,
+		Region:   job.RunJob.Region,
+	}
+	if err := w.Init(cfg, afero.NewBasePathFs(osFs, opts.Basedir)); err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, err
+	}
+	client := newLocalClientV2(opts.Plugins, opts.Output, job.RunJob.JobID)
+	w.clientV2 = client
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w.currentJobV2.context = ctx
+	w.currentJobV2.runJob = &job.RunJob
+	w.currentJobV2.sensitiveDatas = job.SensitiveDatas
+	w.currentJobV2.logRedactionRules = job.LogRedactionRules
+	w.currentJobV2.integrations = make(map[string]sdk.ProjectIntegration)
+	w.currentJobV2.runJobContext = job.Contexts
+	w.currentJobV2.localRun = true
+	w.currentJobV2.localSource = opts.Source
+	w.actions = job.AsCodeActions
+	w.actionPlugin = make(map[string]*sdk.GRPCPlugin)
+	w.checkedPluginBinaries = make(map[string]*sdk.GRPCPluginBinary)
+
+	var err error
+	w.blur, err = sdk.NewBlur(w.currentJobV2.sensitiveDatas, w.currentJobV2.logRedactionRules...)
+	if err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, err
+	}
+
+	// Logs are not sent to CDN, but the worker still signs them
+	secretKey := make([]byte, 32)
+	if _, err := rand.Read(secretKey); err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, sdk.WithStack(err)
+	}
+	w.signer, err = jws.NewHMacSigner(secretKey)
+	if err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, sdk.WithStack(err)
+	}
+
+	l := logrus.New()
+	l.SetOutput(opts.Output)
+	l.SetLevel(logrus.DebugLevel)
+	l.SetFormatter(localLogFormatter{prefix: client.prefix})
+	w.SetGelfLogger(nil, l)
+
+	// Plugins and worker commands use the worker HTTP server
+	if err := w.Serve(ctx); err != nil {
+		return sdk.V2WorkflowRunJobResult{}, nil, err
+	}
+
+	res := w.V2ProcessJob()
+	res.Time = time.Now()
+	return res, client.runResults(), nil
+}
+
+// localLogFormatter writes the job logs on the output of a job run locally
+type localLogFormatter struct {
+	prefix string
+}
+
+func (f localLogFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	msg := strings.TrimRight(entry.Message, "\n")
+	if msg == "" {
+		return nil, nil
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(msg, "\n") {
+		b.WriteString(f.prefix + line + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// copyLocalSource copies the content of a directory into the workspace of a job run locally
+func copyLocalSource(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			return nil
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close() // nolint
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close() // nolint
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// localClientV2 replaces the CDS API for a job run locally. Steps status and job infos are written on the output,
+// run results are kept in memory.
+type localClientV2 struct {
+	cdsclient.GRPCPluginsClient
+	out          io.Writer
+	prefix       string
+	mutex        sync.Mutex
+	results      []sdk.V2WorkflowRunResult
+	stepsStarted map[string]bool
+	stepsEnded   map[string]bool
+}
+
+var _ cdsclient.V2WorkerInterface = new(localClientV2)
+
+func newLocalClientV2(plugins cdsclient.GRPCPluginsClient, out io.Writer, jobID string) *localClientV2 {
+	return &localClientV2{
+		GRPCPluginsClient: plugins,
+		out:               out,
+		prefix:            "[" + jobID + "] ",
+		stepsStarted:      make(map[string]bool),
+		stepsEnded:        make(map[string]bool),
+	}
+}
+
+func (c *localClientV2) printf(format string, args ...interface{}) {
+	fmt.Fprintf(c.out, c.prefix+format+"\n", args...)
+}
+
+func (c *localClientV2) runResults() []sdk.V2WorkflowRunResult {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]sdk.V2WorkflowRunResult{}, c.results...)
+}
+
+func (c *localClientV2) PluginsGet(name string) (*sdk.GRPCPlugin, error) {
+	if c.GRPCPluginsClient == nil {
+		return nil, localRunNotImplemented("plugin " + name)
+	}
+	return c.GRPCPluginsClient.PluginsGet(name)
+}
+
+func (c *localClientV2) PluginGetBinary(name, os, arch string, w io.Writer) error {
+	if c.GRPCPluginsClient == nil {
+		return localRunNotImplemented("plugin " + name)
+	}
+	return c.GRPCPluginsClient.PluginGetBinary(name, os, arch, w)
+}
+
+func (c *localClientV2) V2QueueJobStepUpdate(_ context.Context, _ string, _ string, stepsStatus sdk.JobStepsStatus) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for name, s := range stepsStatus {
+		if !c.stepsStarted[name] {
+			c.stepsStarted[name] = true
+			c.printf("Starting step %s", name)
+		}
+		if !s.Ended.IsZero() && !c.stepsEnded[name] {
+			c.stepsEnded[name] = true
+			c.printf("Step %s: %s", name, s.Conclusion)
+		}
+	}
+	return nil
+}
+
+func (c *localClientV2) V2QueuePushJobInfo(_ context.Context, _ string, _ string, msg sdk.V2SendJobRunInfo) error {
+	c.printf("%s: %s", msg.Level, msg.Message)
+	return nil
+}
+
+func (c *localClientV2) V2QueuePushRunInfo(_ context.Context, _ string, _ string, msg sdk.V2WorkflowRunInfo) error {
+	c.printf("%s: %s", msg.Level, msg.Message)
+	return nil
+}
+
+func (c *localClientV2) V2QueueJobRunResultCreate(_ context.Context, _ string, jobRunID string, result *sdk.V2WorkflowRunResult) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if result.ID == "" {
+		result.ID = sdk.UUID()
+	}
+	result.WorkflowRunJobID = jobRunID
+	c.results = append(c.results, *result)
+	return nil
+}
+
+func (c *localClientV2) V2QueueJobRunResultUpdate(_ context.Context, _ string, _ string, result *sdk.V2WorkflowRunResult) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := range c.results {
+		if c.results[i].ID == result.ID {
+			c.results[i] = *result
+			return nil
+		}
+	}
+	return sdk.WithStack(sdk.ErrNotFound)
+}
+
+func (c *localClientV2) V2QueueJobRunResultGet(_ context.Context, _ string, _ string, runResultID string) (*sdk.V2WorkflowRunResult, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := range c.results {
+		if c.results[i].ID == runResultID {
+			r := c.results[i]
+			return &r, nil
+		}
+	}
+	return nil, sdk.WithStack(sdk.ErrNotFound)
+}
+
+func (c *localClientV2) V2QueueJobRunResultsGet(_ context.Context, _ string, _ string) ([]sdk.V2WorkflowRunResult, error) {
+	return c.runResults(), nil
+}
+
+func (c *localClientV2) V2QueueJobRunResultsSynchronize(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func (c *localClientV2) V2QueueJobResult(_ context.Context, _ string, _ string, _ sdk.V2WorkflowRunJobResult) error {
+	return nil
+}
+
+func (c *localClientV2) V2QueueGetJobRun(_ context.Context, _ string, _ string) (*sdk.V2QueueJobInfo, error) {
+	return nil, localRunNotImplemented("job run")
+}
+
+func (c *localClientV2) V2QueuePolling(_ context.Context, _ string, _ []string, _ *sdk.GoRoutines, _ *sdk.HatcheryMetrics, _ *sdk.HatcheryPendingWorkerCreation, _ chan<- string, _ chan<- error, _ time.Duration, _ ...cdsclient.RequestModifier) error {
+	return localRunNotImplemented("queue")
+}
+
+func (c *localClientV2) V2QueueWorkerTakeJob(_ context.Context, _ string, _ string) (*sdk.V2TakeJobResponse, error) {
+	return nil, localRunNotImplemented("queue")
+}
+
+func (c *localClientV2) V2QueueJobDebugBreakpoint(_ context.Context, _ string, _ string, _ sdk.V2WorkflowRunJobBreakpoint) error {
+	return localRunNotImplemented("debug mode")
+}
+
+func (c *localClientV2) V2QueueJobDebugResume(_ context.Context, _ string, _ string) error {
+	return localRunNotImplemented("debug mode")
+}
+
+func (c *localClientV2) V2QueueJobDebugWebsocket(_ context.Context, _ *sdk.GoRoutines, _ string, _ string, _ <-chan json.RawMessage, _ chan<- json.RawMessage, _ chan<- error) error {
+	return localRunNotImplemented("debug mode")
+}
+
+// V2QueueGetCacheLinks returns no cache, a local run always misses the cache
+func (c *localClientV2) V2QueueGetCacheLinks(_ context.Context, _ string, _ string, _ string, _ ...cdsclient.RequestModifier) (*sdk.CDNItemLinks, error) {
+	return &sdk.CDNItemLinks{}, nil
+}
+
+func (c *localClientV2) V2WorkerRegister(_ context.Context, _ string, _ sdk.WorkerRegistrationForm, _ string, _ string) (*sdk.V2Worker, error) {
+	return nil, localRunNotImplemented("worker registration")
+}
+
+func (c *localClientV2) V2WorkerUnregister(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func (c *localClientV2) V2WorkerRefresh(_ context.Context, _ string, _ string) error {
+	return nil
+}
+
+func (c *localClientV2) V2WorkerProjectGetKey(_ context.Context, _ string, _ string, _ string, _ bool) (*sdk.ProjectKey, error) {
+	return nil, localRunNotImplemented("project keys")
+}
+
+func (c *localClientV2) ProjectV2IntegrationWorkerHookGet(_ string, _ string) (*sdk.WorkerHookProjectIntegrationModel, error) {
+	return nil, localRunNotImplemented("integrations")
+}
+
+func localRunNotImplemented(feature string) error {
+	return sdk.NewErrorFrom(sdk.ErrNotImplemented, "%s not available on a local run", feature)
+}
@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/worker/internal/plugin/mock"
+	"github.com/ovh/cds/sdk"
+	cdslog "github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/log/hook/graylog"
+)
+
+func TestCopyLocalSource(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".cds", "workflows"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".cds", "workflows", "build.yml"), []byte("name: build"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "build.sh"), []byte("#!/bin/sh"), 0755))
+	require.NoError(t, os.Symlink("build.sh", filepath.Join(src, "link.sh")))
+
+	dst := t.TempDir()
+	require.NoError(t, copyLocalSource(src, dst))
+
+	content, err := os.ReadFile(filepath.Join(dst, ".cds", "workflows", "build.yml"))
+	require.NoError(t, err)
+	require.Equal(t, "name: build", string(content))
+
+	info, err := os.Stat(filepath.Join(dst, "build.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dst, "link.sh"))
+	require.NoError(t, err)
+	require.Equal(t, "build.sh", link)
+}
+
+func TestLocalClientV2(t *testing.T) {
+	out := new(bytes.Buffer)
+	c := newLocalClientV2(nil, out, "build")
+	ctx := context.TODO()
+
+	r := sdk.V2WorkflowRunResult{Type: sdk.V2WorkflowRunResultTypeVariable}
+	require.NoError(t, c.V2QueueJobRunResultCreate(ctx, "", "job-run-id", &r))
+	require.NotEmpty(t, r.ID)
+	require.Equal(t, "job-run-id", r.WorkflowRunJobID)
+
+	r.Status = sdk.V2WorkflowRunResultStatusCompleted
+	require.NoError(t, c.V2QueueJobRunResultUpdate(ctx, "", "", &r))
+	got, err := c.V2QueueJobRunResultGet(ctx, "", "", r.ID)
+	require.NoError(t, err)
+	require.Equal(t, sdk.V2WorkflowRunResultStatusCompleted, got.Status)
+
+	unknown := sdk.V2WorkflowRunResult{ID: "unknown"}
+	require.True(t, sdk.ErrorIs(c.V2QueueJobRunResultUpdate(ctx, "", "", &unknown), sdk.ErrNotFound))
+
+	results, err := c.V2QueueJobRunResultsGet(ctx, "", "")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	// Plugins are not available without a CDS API
+	_, err = c.PluginsGet("script")
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotImplemented))
+
+	require.NoError(t, c.V2QueueJobStepUpdate(ctx, "", "", sdk.JobStepsStatus{"step-0": {Conclusion: sdk.V2WorkflowRunJobStatusUnknown}}))
+	require.Equal(t, "[build] Starting step step-0\n", out.String())
+}
+
+func TestRunJobWithCacheOnLocalRun(t *testing.T) {
+	var w = new(CurrentWorker)
+	factory := &mock.MockFactory{Result: []string{sdk.StatusSuccess}}
+	w.pluginFactory = factory
+	ctx := context.TODO()
+	w.currentJobV2.runJob = &sdk.V2WorkflowRunJob{
+		ID:     sdk.UUID(),
+		Status: sdk.V2WorkflowRunJobStatusBuilding,
+		JobID:  "myjob",
+		Region: "build",
+		Job: sdk.V2Job{
+			Region: "build",
+			Cache: &sdk.V2JobCache{
+				Key:         "go-abc",
+				Paths:       []string{".cache"},
+				RestoreKeys: []string{"go-"},
+			},
+			Steps: []sdk.ActionStep{
+				{
+					ID:  "step-0",
+					Run: "exit 0",
+				},
+			},
+		},
+	}
+	w.SetContextForTestJobV2(t, ctx)
+	w.currentJobV2.runJobContext = sdk.WorkflowRunJobsContext{}
+	w.currentJobV2.localRun = true
+	w.clientV2 = newLocalClientV2(nil, new(bytes.Buffer), "myjob")
+
+	l, h, err := cdslog.New(ctx, &graylog.Config{Hostname: ""})
+	require.NoError(t, err)
+	w.SetGelfLogger(h, l)
+
+	result := w.runJobAsCode(ctx)
+
+	// The cache is always missed and never saved, only the step runs a plugin
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSuccess, result.Status)
+	require.Equal(t, "false", w.currentJobV2.runJob.StepsStatus[jobCacheRestoreStepName].Outputs[jobCacheOutputHit])
+	require.Equal(t, sdk.V2WorkflowRunJobStatusSkipped, w.currentJobV2.runJob.StepsStatus[jobCacheSaveStepName].Outcome)
+	require.Equal(t, 1, factory.Index)
+}
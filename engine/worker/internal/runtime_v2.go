@@ -130,7 +130,7 @@ func (wk *CurrentWorker) V2GetProjectKey(ctx context.Context, keyName string, cl
 	}
 	if clear {
 		wk.currentJobV2.sensitiveDatas = append(wk.currentJobV2.sensitiveDatas, k.Private)
-		wk.blur, err = sdk.NewBlur(wk.currentJobV2.sensitiveDatas)
+		wk.blur, err = sdk.NewBlur(wk.currentJobV2.sensitiveDatas, wk.currentJobV2.logRedactionRules...)
 		if err != nil {
 			return nil, err
 		}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -32,14 +33,16 @@ func (w *CurrentWorker) V2Take(ctx context.Context, region, jobRunID string) err
 	w.currentJobV2.context = ctx
 	w.currentJobV2.runJob = &info.RunJob
 	w.currentJobV2.sensitiveDatas = info.SensitiveDatas
+	w.currentJobV2.logRedactionRules = info.LogRedactionRules
 	w.currentJobV2.integrations = make(map[string]sdk.ProjectIntegration)
 	w.actions = info.AsCodeActions
 	w.currentJobV2.runJobContext = info.Contexts
+	w.currentJobV2.debug = info.Debug
 	w.actionPlugin = make(map[string]*sdk.GRPCPlugin)
 	w.checkedPluginBinaries = make(map[string]*sdk.GRPCPluginBinary)
 
 	// setup blur
-	w.blur, err = sdk.NewBlur(w.currentJobV2.sensitiveDatas)
+	w.blur, err = sdk.NewBlur(w.currentJobV2.sensitiveDatas, w.currentJobV2.logRedactionRules...)
 	if err != nil {
 		return err
 	}
@@ -151,6 +154,18 @@ func (w *CurrentWorker) V2Take(ctx context.Context, region, jobRunID string) err
 		}
 	}
 
+	// Send the count of values redacted from the job logs
+	if redacted := atomic.LoadInt64(&w.currentJobV2.redactedCount); redacted > 0 {
+		info := sdk.V2SendJobRunInfo{
+			Level:   sdk.WorkflowRunInfoLevelInfo,
+			Message: fmt.Sprintf("%d values were redacted from the job logs", redacted),
+			Time:    time.Now(),
+		}
+		if err := w.ClientV2().V2QueuePushJobInfo(ctx, w.currentJobV2.runJob.Region, w.currentJobV2.runJob.ID, info); err != nil {
+			log.Error(ctx, "processJob> Unable to send redaction info: %v", err)
+		}
+	}
+
 	var lasterr error
 	for try := 1; try <= 10; try++ {
 		log.Info(ctx, "takeWorkflowJob> Sending build result...")
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -39,6 +40,13 @@ type logger struct {
 	logger *logrus.Logger
 }
 
+// flush sends the buffered logs, jobs run locally don't have any hook
+func (l *logger) flush() {
+	if l.hook != nil {
+		l.hook.Flush()
+	}
+}
+
 type CurrentJobV2 struct {
 	runJob                 *sdk.V2WorkflowRunJob
 	runJobContext          sdk.WorkflowRunJobsContext
@@ -48,8 +56,13 @@ type CurrentJobV2 struct {
 	integrations           map[string]sdk.ProjectIntegration // contains integration with clearPassword
 	envFromHooks           map[string]string
 	sensitiveDatas         []string
+	logRedactionRules      []sdk.LogRedactionRule
+	redactedCount          int64 // count of values redacted from the job logs, updated atomically
 	runningStepStatus      sdk.JobStepsStatus
 	subStepName            string
+	debug                  *sdk.V2WorkflowRunDebug
+	localRun               bool   // the job runs on the local host, without any worker registered on the CDS API
+	localSource            string // directory copied into the workspace of a job run locally
 }
 
 type CurrentWorker struct {
@@ -70,6 +83,7 @@ type CurrentWorker struct {
 	actions               map[string]sdk.V2Action
 	pluginFactory         plugin.Factory
 	currentJobV2          CurrentJobV2
+	debugPause            debugPause
 	currentJob            struct {
 		wJob             *sdk.WorkflowNodeJobRun
 		newVariables     []sdk.Variable
@@ -303,7 +317,10 @@ func (wk *CurrentWorker) prepareLog(ctx context.Context, level workerruntime.Lev
 	if wk.blur == nil {
 		return res, "", sdk.WithStack(fmt.Errorf("blur is nil"))
 	}
-	s = wk.blur.String(s)
+	s, redacted := wk.blur.Redact(s)
+	if redacted > 0 {
+		atomic.AddInt64(&wk.currentJobV2.redactedCount, int64(redacted))
+	}
 
 	switch level {
 	case workerruntime.LevelDebug:
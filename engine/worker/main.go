@@ -27,9 +27,11 @@ func main() {
 		} else {
 			cmd.AddCommand(CmdResult())
 			cmd.AddCommand(CmdOutput())
+			cmd.AddCommand(CmdDebug())
 		}
 	} else {
 		cmd.AddCommand(cmdRegister())
+		cmd.AddCommand(cmdExec())
 	}
 	// last command: doc, this command is hidden
 	cmd.AddCommand(cmdDoc(cmd))
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func CmdDebug() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "debug",
+		Short: "worker debug",
+	}
+	c.AddCommand(cmdDebugContinue())
+	return c
+}
+
+func cmdDebugContinue() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "continue",
+		Short: "worker debug continue",
+		Long:  `Inside a debug session, resume the paused job`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req := MustNewWorkerHTTPRequest(http.MethodPost, "/v2/debug/continue", nil)
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := DoHTTPRequest(ctx, req, nil); err != nil {
+				sdk.Exit(err.Error())
+			}
+			return nil
+		},
+	}
+	return c
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/engine/worker/internal"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	cdslog "github.com/ovh/cds/sdk/log"
+)
+
+func cmdExec() *cobra.Command {
+	var source, basedir, logLevel string
+	c := &cobra.Command{
+		Use:   "exec <job_directory>",
+		Short: "worker exec <job_directory>",
+		Long: `Run a job on the local host, without any worker registered on the CDS API. This command is used by cdsctl experimental workflow exec.
+
+The job is read from the file job.json of the job directory, its result is written in the file result.json.
+Action plugins are downloaded from the CDS API given with the environment variables CDS_API_URL and CDS_SESSION_TOKEN.`,
+		Hidden: true,
+		Args:   cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			cdslog.Initialize(ctx, &cdslog.Conf{Level: logLevel})
+
+			jobDir := args[0]
+			bts, err := os.ReadFile(filepath.Join(jobDir, sdk.V2LocalRunJobFile))
+			if err != nil {
+				return err
+			}
+			var job sdk.V2TakeJobResponse
+			if err := json.Unmarshal(bts, &job); err != nil {
+				return err
+			}
+
+			if basedir == "" {
+				basedir = filepath.Join(jobDir, "basedir")
+			}
+			opts := internal.V2LocalRunOptions{
+				Basedir: basedir,
+				Source:  source,
+				Output:  os.Stdout,
+			}
+			if host := os.Getenv("CDS_API_URL"); host != "" {
+				opts.Plugins = cdsclient.New(cdsclient.Config{
+					Host:                  host,
+					SessionToken:          os.Getenv("CDS_SESSION_TOKEN"),
+					InsecureSkipVerifyTLS: os.Getenv("CDS_INSECURE") == "true",
+				})
+			}
+
+			res, runResults, err := internal.V2LocalRun(ctx, job, opts)
+			if err != nil {
+				return err
+			}
+			bts, err = json.Marshal(sdk.V2LocalRunJobResult{Result: res, RunResults: runResults})
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(jobDir, sdk.V2LocalRunResultFile), bts, os.FileMode(0600))
+		},
+	}
+	c.Flags().StringVar(&source, "source", ".", "Directory copied in the workspace of the job")
+	c.Flags().StringVar(&basedir, "basedir", "", "Directory where the job workspace is created (default: <job_directory>/basedir)")
+	c.Flags().StringVar(&logLevel, "log-level", "error", "Log level of the worker engine: debug, info, warning, error")
+	return c
+}
@@ -11,7 +11,8 @@ import (
 	"strings"
 )
 
-func NewBlur(secrets []string) (*Blur, error) {
+// NewBlur returns a Blur that replaces the given secrets and the values matched by the given redaction rules.
+func NewBlur(secrets []string, rules ...LogRedactionRule) (*Blur, error) {
 	alternativeFuncs := func(v string) ([]string, error) {
 		jsonAlternative, err := json.Marshal(v)
 		if err != nil {
@@ -24,6 +25,9 @@ func NewBlur(secrets []string) (*Blur, error) {
 			strings.Trim(string(jsonAlternative), "\""),
 			url.QueryEscape(v),
 			base64.StdEncoding.EncodeToString([]byte(v)),
+			base64.RawStdEncoding.EncodeToString([]byte(v)),
+			base64.URLEncoding.EncodeToString([]byte(v)),
+			base64.RawURLEncoding.EncodeToString([]byte(v)),
 
 			// Useful to match secreet json variable, that are print through toJson function + an env variable
 			jsonAlt,
@@ -37,6 +41,14 @@ func NewBlur(secrets []string) (*Blur, error) {
 			return nil, err
 		}
 		alternatives = append(alternatives, as...)
+		// Multi-line secrets can be printed line by line
+		if lines := strings.Split(secrets[i], "\n"); len(lines) > 1 {
+			for _, l := range lines {
+				if l = strings.TrimSpace(l); isSecretLine(l) {
+					alternatives = append(alternatives, l)
+				}
+			}
+		}
 	}
 	sort.Slice(alternatives, func(i, j int) bool { return len(alternatives[i]) > len(alternatives[j]) })
 
@@ -47,17 +59,64 @@ func NewBlur(secrets []string) (*Blur, error) {
 		}
 	}
 
+	redactors := make([]logRedactor, 0, len(rules))
+	for _, r := range rules {
+		redactor, err := newLogRedactor(r)
+		if err != nil {
+			return nil, err
+		}
+		redactors = append(redactors, redactor)
+	}
+
 	return &Blur{
-		replacer: strings.NewReplacer(oldNew...),
+		replacer:  strings.NewReplacer(oldNew...),
+		redactors: redactors,
 	}, nil
 }
 
+const (
+	// secretLineMinLength is the minimum length of a line of a multi-line secret to be blurred
+	secretLineMinLength = 16
+	// secretLineMinDistinctChars is the minimum count of distinct characters of a line of a multi-line secret to be blurred
+	secretLineMinDistinctChars = 8
+)
+
+// isSecretLine returns false for the lines of a multi-line secret that are too common to be blurred,
+// as short lines, key delimiters (-----BEGIN ... KEY-----) or low entropy lines.
+func isSecretLine(l string) bool {
+	if len(l) < secretLineMinLength {
+		return false
+	}
+	if strings.HasPrefix(l, "-----BEGIN ") || strings.HasPrefix(l, "-----END ") {
+		return false
+	}
+	distinct := make(map[rune]struct{})
+	for _, r := range l {
+		distinct[r] = struct{}{}
+	}
+	return len(distinct) >= secretLineMinDistinctChars
+}
+
 type Blur struct {
-	replacer *strings.Replacer
+	replacer  *strings.Replacer
+	redactors []logRedactor
 }
 
 func (b *Blur) String(s string) string {
-	return b.replacer.Replace(s)
+	res, _ := b.Redact(s)
+	return res
+}
+
+// Redact returns the given string without secrets, and the count of redacted values
+func (b *Blur) Redact(s string) (string, int) {
+	res := b.replacer.Replace(s)
+	count := strings.Count(res, PasswordPlaceholder) - strings.Count(s, PasswordPlaceholder)
+	for _, r := range b.redactors {
+		var n int
+		res, n = r.redact(res)
+		count += n
+	}
+	return res, count
 }
 
 func (b *Blur) Interface(i interface{}) error {
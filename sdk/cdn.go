@@ -116,6 +116,39 @@ type CDNLogsLines struct {
 	LinesCount int64  `json:"lines_count"`
 }
 
+// CDNLogSearch is the result of a search on v2 job logs
+type CDNLogSearch struct {
+	Lines []CDNLogSearchLine `json:"lines"`
+	// Truncated is true if the search stopped on its limits, older lines can match the search
+	Truncated bool `json:"truncated"`
+}
+
+// CDNLogSearchLine is a log line matching a search on v2 job logs, it is also sent when following the logs of a run
+type CDNLogSearchLine struct {
+	APIRefHash string         `json:"api_ref_hash"`
+	APIRef     CDNLogAPIRefV2 `json:"api_ref"`
+	Number     int64          `json:"number"`
+	Value      string         `json:"value"`
+}
+
+// Server-sent events sent when following the logs of a run
+const (
+	CDNLogFollowEventLine   = "line"
+	CDNLogFollowEventStatus = "status"
+)
+
+// CDNLogFollowStatus is the count of log items of a followed run that are still receiving lines or completed
+type CDNLogFollowStatus struct {
+	Incoming  int `json:"incoming"`
+	Completed int `json:"completed"`
+}
+
+// CDNLogFollowEvent is an event received when following the logs of a run, either a line or a status
+type CDNLogFollowEvent struct {
+	Line   *CDNLogSearchLine
+	Status *CDNLogFollowStatus
+}
+
 type CDNLogLinks struct {
 	CDNURL string       `json:"cdn_url,omitempty"`
 	Data   []CDNLogLink `json:"datas"`
@@ -313,6 +346,68 @@ func NewCDNLogApiRef(signature cdn.Signature) CDNApiRef {
 	return &apiRef
 }
 
+// CDNProjectSize is the size used by a project, in total and on each storage unit
+type CDNProjectSize struct {
+	Size  int64                `json:"size"`
+	Units []CDNProjectUnitSize `json:"units"`
+}
+
+type CDNProjectUnitSize struct {
+	Unit string `json:"unit" cli:"unit"`
+	Size int64  `json:"size" cli:"size"`
+}
+
+// CDNQuota limits the size used by a project on the CDN, for all item types or for a given one.
+// A zero limit means no limit.
+type CDNQuota struct {
+	ID           string      `json:"id" db:"id" cli:"-"`
+	Created      time.Time   `json:"created" db:"created" cli:"-"`
+	LastModified time.Time   `json:"last_modified" db:"last_modified" cli:"-"`
+	ProjectKey   string      `json:"project_key" db:"project_key" cli:"project_key,key"`
+	ItemType     CDNItemType `json:"item_type,omitempty" db:"item_type" cli:"item_type"`
+	SoftLimit    int64       `json:"soft_limit" db:"soft_limit" cli:"soft_limit"`
+	HardLimit    int64       `json:"hard_limit" db:"hard_limit" cli:"hard_limit"`
+}
+
+func (q CDNQuota) IsValid() error {
+	if q.ProjectKey == "" {
+		return NewErrorFrom(ErrWrongRequest, "missing project key")
+	}
+	if q.ItemType != "" {
+		if err := q.ItemType.Validate(); err != nil {
+			return err
+		}
+	}
+	if q.SoftLimit < 0 || q.HardLimit < 0 {
+		return NewErrorFrom(ErrWrongRequest, "quota limits must be positive")
+	}
+	if q.SoftLimit == 0 && q.HardLimit == 0 {
+		return NewErrorFrom(ErrWrongRequest, "at least one quota limit must be set")
+	}
+	if q.SoftLimit > 0 && q.HardLimit > 0 && q.SoftLimit > q.HardLimit {
+		return NewErrorFrom(ErrWrongRequest, "soft limit must be lower than hard limit")
+	}
+	return nil
+}
+
+// Match returns true if the quota applies to the given item type
+func (q CDNQuota) Match(itemType CDNItemType) bool {
+	return q.ItemType == "" || q.ItemType == itemType
+}
+
+// CDNQuotaUsage is a quota with the size currently used by the project
+type CDNQuotaUsage struct {
+	CDNQuota
+	Size int64 `json:"size" cli:"size"`
+}
+
+// CDNQuotaEvent is sent by the CDN to the API when a project reaches one of its quota limits
+type CDNQuotaEvent struct {
+	Quota     CDNQuota `json:"quota"`
+	Size      int64    `json:"size"`
+	HardLimit bool     `json:"hard_limit_reached"`
+}
+
 type CDNItemResume struct {
 	CDNItem  CDNItem                `json:"item"` // Here we can't use nested struct because of the custom CDNItem marshaller
 	Location map[string]CDNItemUnit `json:"item_units"`
@@ -527,6 +622,40 @@ type CDNUnitHandlerRequest struct {
 	NbItems int64  `json:"nb_items" cli:"nb_items"`
 }
 
+type CDNItemUnitVerifyStatus string
+
+const (
+	CDNItemUnitVerifyStatusMissing   CDNItemUnitVerifyStatus = "missing"
+	CDNItemUnitVerifyStatusCorrupted CDNItemUnitVerifyStatus = "corrupted"
+)
+
+// CDNUnitVerifyReport is the result of an integrity verification of the items stored on a unit
+type CDNUnitVerifyReport struct {
+	UnitID    string                    `json:"unit_id" cli:"unit_id"`
+	UnitName  string                    `json:"unit_name" cli:"unit_name"`
+	Started   time.Time                 `json:"started" cli:"started"`
+	Ended     *time.Time                `json:"ended,omitempty" cli:"ended"`
+	Sample    int64                     `json:"sample" cli:"sample"`
+	Repair    bool                      `json:"repair" cli:"repair"`
+	Checked   int64                     `json:"checked" cli:"checked"`
+	Missing   int64                     `json:"missing" cli:"missing"`
+	Corrupted int64                     `json:"corrupted" cli:"corrupted"`
+	Repaired  int64                     `json:"repaired" cli:"repaired"`
+	Error     string                    `json:"error,omitempty" cli:"error"`
+	Items     []CDNUnitVerifyReportItem `json:"items,omitempty" cli:"-"`
+}
+
+// CDNUnitVerifyReportItem is an item whose copy on the verified unit is damaged
+type CDNUnitVerifyReportItem struct {
+	ItemID     string                  `json:"item_id" cli:"item_id"`
+	ItemUnitID string                  `json:"item_unit_id" cli:"item_unit_id"`
+	APIRefHash string                  `json:"api_ref_hash" cli:"api_ref_hash"`
+	Type       CDNItemType             `json:"type" cli:"type"`
+	Status     CDNItemUnitVerifyStatus `json:"status" cli:"status"`
+	Error      string                  `json:"error,omitempty" cli:"error"`
+	Repaired   bool                    `json:"repaired" cli:"repaired"`
+}
+
 type CDNDuplicateItemRequest struct {
 	FromJob string `json:"from_job"`
 	ToJob   string `json:"to_job"`
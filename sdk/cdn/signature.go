@@ -18,6 +18,7 @@ type Signature struct {
 	NodeRunName     string
 	NodeRunID       int64
 	Timestamp       int64
+	RunResultShare  *SignatureRunResultShare // V2 public download link of a run result
 }
 
 type SignatureWorker struct {
@@ -33,6 +34,12 @@ type SignatureWorker struct {
 	RunResultType string // V2Runresult required
 }
 
+type SignatureRunResultShare struct {
+	ShareID     string
+	RunResultID string
+	APIRefHash  string
+}
+
 type SignatureHatcheryService struct {
 	HatcheryID   string
 	HatcheryName string
@@ -95,3 +95,16 @@ func TestCDNItemWorkerCacheJSON(t *testing.T) {
 	require.True(t, workerCacheApiRef.ExpireAt.After(time.Now()))
 	require.Equal(t, "mycache", itemU.APIRef.ToFilename())
 }
+
+func TestCDNQuotaIsValid(t *testing.T) {
+	require.Error(t, CDNQuota{SoftLimit: 10}.IsValid())
+	require.Error(t, CDNQuota{ProjectKey: "PRJ"}.IsValid())
+	require.Error(t, CDNQuota{ProjectKey: "PRJ", ItemType: "unknown", HardLimit: 10}.IsValid())
+	require.Error(t, CDNQuota{ProjectKey: "PRJ", SoftLimit: -1, HardLimit: 10}.IsValid())
+	require.Error(t, CDNQuota{ProjectKey: "PRJ", SoftLimit: 20, HardLimit: 10}.IsValid())
+	require.NoError(t, CDNQuota{ProjectKey: "PRJ", SoftLimit: 20}.IsValid())
+	require.NoError(t, CDNQuota{ProjectKey: "PRJ", ItemType: CDNTypeItemRunResultV2, SoftLimit: 5, HardLimit: 10}.IsValid())
+
+	require.True(t, CDNQuota{ProjectKey: "PRJ"}.Match(CDNTypeItemWorkerCacheV2))
+	require.False(t, CDNQuota{ProjectKey: "PRJ", ItemType: CDNTypeItemRunResultV2}.Match(CDNTypeItemWorkerCacheV2))
+}
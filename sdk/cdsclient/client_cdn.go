@@ -3,6 +3,7 @@ package cdsclient
 import (
 	"context"
 	"crypto/md5"
+	"crypto/sha512"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -85,6 +86,11 @@ func (c *client) CDNItemStream(ctx context.Context, cdnAddr string, hash string,
 func (c *client) CDNItemUpload(ctx context.Context, cdnAddr string, signature string, fs afero.Fs, path string) (time.Duration, error) {
 	t0 := time.Now()
 
+	// Skip the upload if the CDN already stores the same content
+	if c.cdnItemUploadDedup(ctx, cdnAddr, signature, fs, path) {
+		return time.Since(t0), nil
+	}
+
 	var savedError error
 	// as *File implement io.ReadSeeker, retry in c.Stream will be skipped
 	for i := 0; i < c.config.Retry; i++ {
@@ -113,3 +119,29 @@ func (c *client) CDNItemUpload(ctx context.Context, cdnAddr string, signature st
 	}
 	return time.Since(t0), savedError
 }
+
+// cdnItemUploadDedup sends the sha512 of the file to the CDN, it returns true if the CDN already knew the content and created the item
+func (c *client) cdnItemUploadDedup(ctx context.Context, cdnAddr string, signature string, fs afero.Fs, path string) bool {
+	f, err := fs.Open(path)
+	if err != nil {
+		return false
+	}
+	sha512Hash := sha512.New()
+	_, err = io.Copy(sha512Hash, f)
+	_ = f.Close()
+	if err != nil {
+		return false
+	}
+
+	body, _, code, err := c.StreamNoRetry(ctx, c.HTTPClient(), http.MethodPost, fmt.Sprintf("%s/item/upload/dedup", cdnAddr), http.NoBody,
+		SetHeader("X-CDS-WORKER-SIGNATURE", signature),
+		SetHeader("X-CDS-ITEM-HASH", hex.EncodeToString(sha512Hash.Sum(nil))))
+	if body != nil {
+		_ = body.Close()
+	}
+	if err != nil || code >= 300 {
+		log.Debug(ctx, "content of %s is unknown by the CDN, uploading it", path)
+		return false
+	}
+	return true
+}
@@ -82,3 +82,11 @@ func (c *client) HookListAllSchedulerHooks(ctx context.Context) ([]sdk.V2Workflo
 	}
 	return hooks, nil
 }
+
+func (c *client) HookListAllMessageBusHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error) {
+	var hooks []sdk.V2WorkflowHook
+	if _, err := c.GetJSON(ctx, "/v2/hooks/workflows/hooks/messagebus", &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
@@ -78,6 +78,65 @@ func (c *client) ProjectV2Access(ctx context.Context, projectKey, sessionID stri
 	return nil
 }
 
+func (c *client) ProjectV2CDNQuotaEvent(ctx context.Context, projectKey string, e sdk.CDNQuotaEvent) error {
+	url := fmt.Sprintf("/v2/project/%s/cdn/quota/event", projectKey)
+	if _, err := c.PostJSON(ctx, url, e, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) ProjectV2CDNLogRedactionRules(ctx context.Context, projectKey string) (sdk.LogRedactionRules, error) {
+	var rules sdk.LogRedactionRules
+	url := fmt.Sprintf("/v2/project/%s/cdn/log/redaction", projectKey)
+	if _, err := c.GetJSON(ctx, url, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (c *client) ProjectV2CDNLogRedactionReport(ctx context.Context, projectKey string, r sdk.CDNLogRedactionReport) error {
+	url := fmt.Sprintf("/v2/project/%s/cdn/log/redaction", projectKey)
+	if _, err := c.PostJSON(ctx, url, r, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) ProjectV2CDNRunResultShareDownload(ctx context.Context, projectKey, shareID string, d sdk.V2WorkflowRunResultShareDownload) (*sdk.V2WorkflowRunResultShareDownload, error) {
+	url := fmt.Sprintf("/v2/project/%s/cdn/share/%s/download", projectKey, shareID)
+	var res sdk.V2WorkflowRunResultShareDownload
+	if _, err := c.PostJSON(ctx, url, d, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *client) ProjectV2CDNRunResultShareDownloadFailed(ctx context.Context, projectKey, shareID, downloadID string) error {
+	url := fmt.Sprintf("/v2/project/%s/cdn/share/%s/download/%s/failed", projectKey, shareID, downloadID)
+	if _, err := c.PostJSON(ctx, url, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) ProjectLogRedactionImport(ctx context.Context, projectKey string, plr sdk.ProjectLogRedaction) error {
+	url := fmt.Sprintf("/v2/project/%s/log/redaction", projectKey)
+	if _, err := c.PutJSON(ctx, url, plr, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) ProjectLogRedactionGet(ctx context.Context, projectKey string) (*sdk.ProjectLogRedaction, error) {
+	var plr sdk.ProjectLogRedaction
+	url := fmt.Sprintf("/v2/project/%s/log/redaction", projectKey)
+	if _, err := c.GetJSON(ctx, url, &plr); err != nil {
+		return nil, err
+	}
+	return &plr, nil
+}
+
 func (c *client) ProjectRunPurge(ctx context.Context, projectKey string) error {
 	url := fmt.Sprintf("/v2/project/%s/run/retention/start", projectKey)
 	if _, err := c.PostJSON(ctx, url, nil, nil); err != nil {
@@ -13,15 +13,36 @@ import (
 	"github.com/rockbears/log"
 )
 
-func (c *client) V2QueueGetCacheLinks(ctx context.Context, regionName string, id string, cacheKey string) (*sdk.CDNItemLinks, error) {
+func (c *client) V2QueueGetCacheLinks(ctx context.Context, regionName string, id string, cacheKey string, mods ...RequestModifier) (*sdk.CDNItemLinks, error) {
 	path := fmt.Sprintf("/v2/queue/%s/job/%s/cache/%s/link", regionName, id, url.PathEscape(cacheKey))
 	var result sdk.CDNItemLinks
-	if _, err := c.GetJSON(ctx, path, &result); err != nil {
+	if _, err := c.GetJSON(ctx, path, &result, mods...); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+func (c *client) V2QueueJobDebugBreakpoint(ctx context.Context, regionName string, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error {
+	path := fmt.Sprintf("/v2/queue/%s/job/%s/debug", regionName, jobRunID)
+	if _, err := c.PostJSON(ctx, path, breakpoint, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) V2QueueJobDebugResume(ctx context.Context, regionName string, jobRunID string) error {
+	path := fmt.Sprintf("/v2/queue/%s/job/%s/debug", regionName, jobRunID)
+	if _, err := c.DeleteJSON(ctx, path, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName string, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	path := fmt.Sprintf("/v2/queue/%s/job/%s/debug/ws", regionName, jobRunID)
+	return c.RequestWebsocket(ctx, goRoutines, path, msgToSend, msgReceived, errorReceived)
+}
+
 func (c *client) V2QueueJobStepUpdate(ctx context.Context, regionName string, jobRunID string, stepsStatus sdk.JobStepsStatus) error {
 	path := fmt.Sprintf("/v2/queue/%s/job/%s/step", regionName, jobRunID)
 	if _, err := c.PostJSON(ctx, path, stepsStatus, nil); err != nil {
@@ -35,3 +35,25 @@ func (c *client) RegionDelete(ctx context.Context, regionIdentifier string) erro
 	}
 	return nil
 }
+
+func (c *client) RegionProjectWeightList(ctx context.Context, regionIdentifier string) ([]sdk.RegionProjectWeight, error) {
+	var weights []sdk.RegionProjectWeight
+	if _, err := c.GetJSON(ctx, "/v2/region/"+regionIdentifier+"/weight", &weights, nil); err != nil {
+		return nil, err
+	}
+	return weights, nil
+}
+
+func (c *client) RegionProjectWeightSet(ctx context.Context, regionIdentifier string, weight sdk.RegionProjectWeight) error {
+	if _, err := c.PostJSON(ctx, "/v2/region/"+regionIdentifier+"/weight", &weight, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) RegionProjectWeightDelete(ctx context.Context, regionIdentifier string, projectKey string) error {
+	if _, err := c.DeleteJSON(ctx, "/v2/region/"+regionIdentifier+"/weight/"+projectKey, nil, nil); err != nil {
+		return err
+	}
+	return nil
+}
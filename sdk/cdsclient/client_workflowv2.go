@@ -1,11 +1,15 @@
 package cdsclient
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -149,6 +153,26 @@ func (c *client) WorkflowV2RunResultList(ctx context.Context, projKey, runIdenti
 	return results, nil
 }
 
+func (c *client) WorkflowV2RunResultShare(ctx context.Context, projKey, runIdentifier, resultID string, r sdk.V2WorkflowRunResultShareRequest) (*sdk.V2WorkflowRunResultShare, error) {
+	var share sdk.V2WorkflowRunResultShare
+	path := fmt.Sprintf("/v2/project/%s/run/%s/result/%s/share", projKey, runIdentifier, resultID)
+	_, _, _, err := c.RequestJSON(ctx, "POST", path, r, &share)
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (c *client) WorkflowV2RunResultShareList(ctx context.Context, projKey, runIdentifier, resultID string) ([]sdk.V2WorkflowRunResultShare, error) {
+	var shares []sdk.V2WorkflowRunResultShare
+	path := fmt.Sprintf("/v2/project/%s/run/%s/result/%s/share", projKey, runIdentifier, resultID)
+	_, _, _, err := c.RequestJSON(ctx, "GET", path, nil, &shares)
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
 func (c *client) WorkflowV2RunJobLogLinks(ctx context.Context, projKey, workflowRunID, jobRunID string) (sdk.CDNLogLinks, error) {
 	var logsLinks sdk.CDNLogLinks
 	path := fmt.Sprintf("/v2/project/%s/run/%s/job/%s/logs/links", projKey, workflowRunID, jobRunID)
@@ -159,6 +183,92 @@ func (c *client) WorkflowV2RunJobLogLinks(ctx context.Context, projKey, workflow
 	return logsLinks, nil
 }
 
+func (c *client) WorkflowV2LogsSearch(ctx context.Context, projKey, pattern string, mods ...RequestModifier) (*sdk.CDNLogSearch, error) {
+	cdnURL, err := c.CDNURL()
+	if err != nil {
+		return nil, err
+	}
+	searchURL := fmt.Sprintf("%s/item/%s/search", cdnURL, sdk.CDNTypeItemJobStepLog)
+	mods = append(mods, WithQueryParameter("project", projKey), WithQueryParameter("pattern", pattern), func(req *http.Request) {
+		req.Header.Add("Authorization", "Bearer "+c.config.SessionToken)
+	})
+	var res sdk.CDNLogSearch
+	if _, _, _, err := c.RequestJSON(ctx, http.MethodGet, searchURL, nil, &res, mods...); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// WorkflowV2LogsFollow streams the job step logs of a run from the CDN, events are sent on the given channel until the stream ends or the context is done
+func (c *client) WorkflowV2LogsFollow(ctx context.Context, projKey, workflowRunID string, events chan<- sdk.CDNLogFollowEvent, mods ...RequestModifier) error {
+	cdnURL, err := c.CDNURL()
+	if err != nil {
+		return err
+	}
+	followURL := fmt.Sprintf("%s/item/%s/follow", cdnURL, sdk.CDNTypeItemJobStepLog)
+	mods = append(mods, WithQueryParameter("project", projKey), WithQueryParameter("run", workflowRunID), func(req *http.Request) {
+		req.Header.Add("Authorization", "Bearer "+c.config.SessionToken)
+		req.Header.Set("Accept", "text/event-stream")
+	})
+	body, _, code, err := c.Stream(ctx, c.HTTPNoTimeoutClient(), http.MethodGet, followURL, nil, mods...)
+	if err != nil {
+		return err
+	}
+	defer body.Close() // nolint
+	if code >= 400 {
+		bts, err := io.ReadAll(body)
+		if err != nil {
+			return newTransportError(err)
+		}
+		if err := sdk.DecodeError(bts); err != nil {
+			return err
+		}
+		return newAPIError(fmt.Errorf("HTTP %d", code))
+	}
+
+	reader := bufio.NewReader(body)
+	var eventName string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return newTransportError(err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "event: ") {
+			eventName = strings.TrimPrefix(line, "event: ")
+			continue
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := []byte(strings.TrimPrefix(line, "data: "))
+
+		var e sdk.CDNLogFollowEvent
+		switch eventName {
+		case sdk.CDNLogFollowEventLine:
+			e.Line = new(sdk.CDNLogSearchLine)
+			if err := sdk.JSONUnmarshal(data, e.Line); err != nil {
+				return err
+			}
+		case sdk.CDNLogFollowEventStatus:
+			e.Status = new(sdk.CDNLogFollowStatus)
+			if err := sdk.JSONUnmarshal(data, e.Status); err != nil {
+				return err
+			}
+		default:
+			continue
+		}
+		select {
+		case events <- e:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func (c *client) WorkflowV2Stop(ctx context.Context, projKey, workflowRunID string) error {
 	path := fmt.Sprintf("/v2/project/%s/run/%s/stop", projKey, workflowRunID)
 	if _, _, _, err := c.RequestJSON(ctx, http.MethodPost, path, nil, nil); err != nil {
@@ -184,6 +294,11 @@ func (c *client) WorkflowV2RunJobInfoList(ctx context.Context, projKey, workflow
 	return infos, nil
 }
 
+func (c *client) WorkflowV2RunJobDebugShell(ctx context.Context, goRoutines *sdk.GoRoutines, projKey, workflowRunID, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	path := fmt.Sprintf("/v2/project/%s/run/%s/job/%s/debug", projKey, workflowRunID, jobRunID)
+	return c.RequestWebsocket(ctx, goRoutines, path, msgToSend, msgReceived, errorReceived)
+}
+
 func (c *client) WorkflowV2VersionList(ctx context.Context, projKey, vcsIdentifier, repoIdentifier, wkfName string) ([]sdk.V2WorkflowVersion, error) {
 	var versions []sdk.V2WorkflowVersion
 	path := fmt.Sprintf("/v2/project/%s/vcs/%s/repository/%s/workflow/%s/version", projKey, url.PathEscape(vcsIdentifier), url.PathEscape(repoIdentifier), wkfName)
@@ -0,0 +1,43 @@
+package cdsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowV2LogsFollow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/item/job-step-log/follow", r.URL.Path)
+		require.Equal(t, "PROJ", r.URL.Query().Get("project"))
+		require.Equal(t, "run-id", r.URL.Query().Get("run"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: line\ndata: {\"api_ref\":{\"run_job_name\":\"build\",\"step_name\":\"step-0\"},\"number\":0,\"value\":\"hello\"}\n\n")
+		fmt.Fprint(w, ": heartbeat\n\n")
+		fmt.Fprint(w, "event: status\ndata: {\"incoming\":0,\"completed\":1}\n\n")
+	}))
+	defer srv.Close()
+
+	c := New(Config{Host: srv.URL, CDNHost: srv.URL})
+
+	events := make(chan sdk.CDNLogFollowEvent, 10)
+	require.NoError(t, c.WorkflowV2LogsFollow(context.TODO(), "PROJ", "run-id", events))
+	close(events)
+
+	var res []sdk.CDNLogFollowEvent
+	for e := range events {
+		res = append(res, e)
+	}
+	require.Len(t, res, 2)
+	require.NotNil(t, res[0].Line)
+	require.Equal(t, "build", res[0].Line.APIRef.RunJobName)
+	require.Equal(t, "hello", res[0].Line.Value)
+	require.NotNil(t, res[1].Status)
+	require.Equal(t, 1, res[1].Status.Completed)
+}
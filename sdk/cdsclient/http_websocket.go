@@ -61,6 +61,12 @@ func (c *client) RequestWebsocket(ctx context.Context, goRoutines *sdk.GoRoutine
 	}
 	defer con.Close() // nolint
 
+	// Close the connection when the context is canceled to unblock the read
+	go func() {
+		<-wsContext.Done()
+		_ = con.Close()
+	}()
+
 	// Message to send
 	goRoutines.Exec(wsContext, fmt.Sprintf("RequestWebsocket-%s-%s", c.config.User, sdk.UUID()), func(ctx context.Context) {
 		for {
@@ -81,6 +87,9 @@ func (c *client) RequestWebsocket(ctx context.Context, goRoutines *sdk.GoRoutine
 		}
 		_, message, err := con.ReadMessage()
 		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			if websocket.IsCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				return err
 			}
@@ -223,6 +223,9 @@ type RegionClient interface {
 	RegionGet(ctx context.Context, regionIdentifier string) (sdk.Region, error)
 	RegionList(ctx context.Context) ([]sdk.Region, error)
 	RegionDelete(ctx context.Context, regionIdentifier string) error
+	RegionProjectWeightList(ctx context.Context, regionIdentifier string) ([]sdk.RegionProjectWeight, error)
+	RegionProjectWeightSet(ctx context.Context, regionIdentifier string, weight sdk.RegionProjectWeight) error
+	RegionProjectWeightDelete(ctx context.Context, regionIdentifier string, projectKey string) error
 }
 
 type HatcheryClient interface {
@@ -276,6 +279,11 @@ type ProjectClientV2 interface {
 	ProjectConcurrencyListRuns(ctx context.Context, pKey string, name string) ([]sdk.ProjectConcurrencyRunObject, error)
 
 	ProjectV2Access(ctx context.Context, projectKey, sessionID string, itemType sdk.CDNItemType) error
+	ProjectV2CDNQuotaEvent(ctx context.Context, projectKey string, e sdk.CDNQuotaEvent) error
+	ProjectV2CDNLogRedactionRules(ctx context.Context, projectKey string) (sdk.LogRedactionRules, error)
+	ProjectV2CDNLogRedactionReport(ctx context.Context, projectKey string, r sdk.CDNLogRedactionReport) error
+	ProjectV2CDNRunResultShareDownload(ctx context.Context, projectKey, shareID string, d sdk.V2WorkflowRunResultShareDownload) (*sdk.V2WorkflowRunResultShareDownload, error)
+	ProjectV2CDNRunResultShareDownloadFailed(ctx context.Context, projectKey, shareID, downloadID string) error
 
 	ProjectWebHookAdd(ctx context.Context, projectKey string, r sdk.PostProjectWebHook) (*sdk.HookAccessData, error)
 	ProjectWebHookList(ctx context.Context, projectKey string) ([]sdk.ProjectWebHook, error)
@@ -286,6 +294,9 @@ type ProjectClientV2 interface {
 	ProjectRunRetentionImport(ctx context.Context, projectKey string, prr sdk.ProjectRunRetention) error
 	ProjectRunRetentionGet(ctx context.Context, projectKey string) (*sdk.ProjectRunRetention, error)
 
+	ProjectLogRedactionImport(ctx context.Context, projectKey string, plr sdk.ProjectLogRedaction) error
+	ProjectLogRedactionGet(ctx context.Context, projectKey string) (*sdk.ProjectLogRedaction, error)
+
 	ProjectV2List(ctx context.Context) ([]sdk.Project, error)
 }
 
@@ -364,7 +375,10 @@ type V2QueueClient interface {
 	V2QueuePushJobInfo(ctx context.Context, regionName string, jobRunID string, msg sdk.V2SendJobRunInfo) error
 	V2QueueWorkerTakeJob(ctx context.Context, region, runJobID string) (*sdk.V2TakeJobResponse, error)
 	V2QueueJobStepUpdate(ctx context.Context, regionName string, id string, stepsStatus sdk.JobStepsStatus) error
-	V2QueueGetCacheLinks(ctx context.Context, regionName string, id string, cacheKey string) (*sdk.CDNItemLinks, error)
+	V2QueueJobDebugBreakpoint(ctx context.Context, regionName string, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error
+	V2QueueJobDebugResume(ctx context.Context, regionName string, jobRunID string) error
+	V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName string, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error
+	V2QueueGetCacheLinks(ctx context.Context, regionName string, id string, cacheKey string, mods ...RequestModifier) (*sdk.CDNItemLinks, error)
 }
 
 // QueueClient exposes queue related functions
@@ -451,6 +465,7 @@ type HookClient interface {
 
 	HookGetWorkflowHook(ctx context.Context, hookID string) (*sdk.V2WorkflowHook, error)
 	HookListAllSchedulerHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error)
+	HookListAllMessageBusHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error)
 	HookRepositoriesList(ctx context.Context, vcsServer, repoName string) ([]sdk.ProjectRepository, error)
 	ListWorkflowToTrigger(ctx context.Context, req sdk.HookListWorkflowRequest) ([]sdk.V2WorkflowHook, error)
 	RetrieveHookEventSigningKey(ctx context.Context, req sdk.HookRetrieveSignKeyRequest) (sdk.Operation, error)
@@ -480,9 +495,14 @@ type WorkflowV2Client interface {
 	WorkflowV2RunJob(ctx context.Context, projKey, workflowRunID, jobRunID string) (*sdk.V2WorkflowRunJob, error)
 	WorkflowV2RunJobInfoList(ctx context.Context, projKey, workflowRunID, jobRunID string) ([]sdk.V2WorkflowRunJobInfo, error)
 	WorkflowV2RunJobLogLinks(ctx context.Context, projKey, workflowRunID, jobRunID string) (sdk.CDNLogLinks, error)
+	WorkflowV2LogsFollow(ctx context.Context, projKey, workflowRunID string, events chan<- sdk.CDNLogFollowEvent, mods ...RequestModifier) error
+	WorkflowV2LogsSearch(ctx context.Context, projKey, pattern string, mods ...RequestModifier) (*sdk.CDNLogSearch, error)
+	WorkflowV2RunJobDebugShell(ctx context.Context, goRoutines *sdk.GoRoutines, projKey, workflowRunID, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error
 	WorkflowV2Stop(ctx context.Context, projKey, workflowRunID string) error
 	WorkflowV2StopJob(ctx context.Context, projKey, workflowRunID, jobIdentifier string) error
 	WorkflowV2RunResultList(ctx context.Context, projKey, runIdentifier string) ([]sdk.V2WorkflowRunResult, error)
+	WorkflowV2RunResultShare(ctx context.Context, projKey, runIdentifier, resultID string, r sdk.V2WorkflowRunResultShareRequest) (*sdk.V2WorkflowRunResultShare, error)
+	WorkflowV2RunResultShareList(ctx context.Context, projKey, runIdentifier, resultID string) ([]sdk.V2WorkflowRunResultShare, error)
 	WorkflowV2VersionList(ctx context.Context, projKey, vcsIdentifier, repoIdentifier, wkfName string) ([]sdk.V2WorkflowVersion, error)
 	WorkflowV2VersionGet(ctx context.Context, projKey, vcsIdentifier, repoIdentifier, wkfName, version string) (*sdk.V2WorkflowVersion, error)
 	WorkflowV2VersionDelete(ctx context.Context, projKey, vcsIdentifier, repoIdentifier, wkfName, version string) error
@@ -2663,6 +2663,49 @@ func (mr *MockRegionClientMockRecorder) RegionList(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionList", reflect.TypeOf((*MockRegionClient)(nil).RegionList), ctx)
 }
 
+// RegionProjectWeightDelete mocks base method.
+func (m *MockRegionClient) RegionProjectWeightDelete(ctx context.Context, regionIdentifier, projectKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightDelete", ctx, regionIdentifier, projectKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegionProjectWeightDelete indicates an expected call of RegionProjectWeightDelete.
+func (mr *MockRegionClientMockRecorder) RegionProjectWeightDelete(ctx, regionIdentifier, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightDelete", reflect.TypeOf((*MockRegionClient)(nil).RegionProjectWeightDelete), ctx, regionIdentifier, projectKey)
+}
+
+// RegionProjectWeightList mocks base method.
+func (m *MockRegionClient) RegionProjectWeightList(ctx context.Context, regionIdentifier string) ([]sdk.RegionProjectWeight, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightList", ctx, regionIdentifier)
+	ret0, _ := ret[0].([]sdk.RegionProjectWeight)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegionProjectWeightList indicates an expected call of RegionProjectWeightList.
+func (mr *MockRegionClientMockRecorder) RegionProjectWeightList(ctx, regionIdentifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightList", reflect.TypeOf((*MockRegionClient)(nil).RegionProjectWeightList), ctx, regionIdentifier)
+}
+
+// RegionProjectWeightSet mocks base method.
+func (m *MockRegionClient) RegionProjectWeightSet(ctx context.Context, regionIdentifier string, weight sdk.RegionProjectWeight) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightSet", ctx, regionIdentifier, weight)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegionProjectWeightSet indicates an expected call of RegionProjectWeightSet.
+func (mr *MockRegionClientMockRecorder) RegionProjectWeightSet(ctx, regionIdentifier, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightSet", reflect.TypeOf((*MockRegionClient)(nil).RegionProjectWeightSet), ctx, regionIdentifier, weight)
+}
+
 // MockHatcheryClient is a mock of HatcheryClient interface.
 type MockHatcheryClient struct {
 	ctrl     *gomock.Controller
@@ -2884,18 +2927,23 @@ func (mr *MockHatcheryServiceClientMockRecorder) V2HatcheryTakeJob(ctx, regionNa
 }
 
 // V2QueueGetCacheLinks mocks base method.
-func (m *MockHatcheryServiceClient) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string) (*sdk.CDNItemLinks, error) {
+func (m *MockHatcheryServiceClient) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string, mods ...cdsclient.RequestModifier) (*sdk.CDNItemLinks, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", ctx, regionName, id, cacheKey)
+	varargs := []any{ctx, regionName, id, cacheKey}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", varargs...)
 	ret0, _ := ret[0].(*sdk.CDNItemLinks)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // V2QueueGetCacheLinks indicates an expected call of V2QueueGetCacheLinks.
-func (mr *MockHatcheryServiceClientMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any) *gomock.Call {
+func (mr *MockHatcheryServiceClientMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any, mods ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueGetCacheLinks), ctx, regionName, id, cacheKey)
+	varargs := append([]any{ctx, regionName, id, cacheKey}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueGetCacheLinks), varargs...)
 }
 
 // V2QueueGetJobRun mocks base method.
@@ -2913,6 +2961,48 @@ func (mr *MockHatcheryServiceClientMockRecorder) V2QueueGetJobRun(ctx, regionNam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetJobRun", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueGetJobRun), ctx, regionName, id)
 }
 
+// V2QueueJobDebugBreakpoint mocks base method.
+func (m *MockHatcheryServiceClient) V2QueueJobDebugBreakpoint(ctx context.Context, regionName, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugBreakpoint", ctx, regionName, jobRunID, breakpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugBreakpoint indicates an expected call of V2QueueJobDebugBreakpoint.
+func (mr *MockHatcheryServiceClientMockRecorder) V2QueueJobDebugBreakpoint(ctx, regionName, jobRunID, breakpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugBreakpoint", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueJobDebugBreakpoint), ctx, regionName, jobRunID, breakpoint)
+}
+
+// V2QueueJobDebugResume mocks base method.
+func (m *MockHatcheryServiceClient) V2QueueJobDebugResume(ctx context.Context, regionName, jobRunID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugResume", ctx, regionName, jobRunID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugResume indicates an expected call of V2QueueJobDebugResume.
+func (mr *MockHatcheryServiceClientMockRecorder) V2QueueJobDebugResume(ctx, regionName, jobRunID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugResume", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueJobDebugResume), ctx, regionName, jobRunID)
+}
+
+// V2QueueJobDebugWebsocket mocks base method.
+func (m *MockHatcheryServiceClient) V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugWebsocket", ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugWebsocket indicates an expected call of V2QueueJobDebugWebsocket.
+func (mr *MockHatcheryServiceClientMockRecorder) V2QueueJobDebugWebsocket(ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugWebsocket", reflect.TypeOf((*MockHatcheryServiceClient)(nil).V2QueueJobDebugWebsocket), ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // V2QueueJobResult mocks base method.
 func (m *MockHatcheryServiceClient) V2QueueJobResult(ctx context.Context, region, jobRunID string, result sdk.V2WorkflowRunJobResult) error {
 	m.ctrl.T.Helper()
@@ -3201,6 +3291,35 @@ func (mr *MockProjectClientV2MockRecorder) ProjectConcurrencyUpdate(ctx, pKey, c
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectConcurrencyUpdate", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectConcurrencyUpdate), ctx, pKey, c)
 }
 
+// ProjectLogRedactionGet mocks base method.
+func (m *MockProjectClientV2) ProjectLogRedactionGet(ctx context.Context, projectKey string) (*sdk.ProjectLogRedaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLogRedactionGet", ctx, projectKey)
+	ret0, _ := ret[0].(*sdk.ProjectLogRedaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectLogRedactionGet indicates an expected call of ProjectLogRedactionGet.
+func (mr *MockProjectClientV2MockRecorder) ProjectLogRedactionGet(ctx, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLogRedactionGet", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectLogRedactionGet), ctx, projectKey)
+}
+
+// ProjectLogRedactionImport mocks base method.
+func (m *MockProjectClientV2) ProjectLogRedactionImport(ctx context.Context, projectKey string, plr sdk.ProjectLogRedaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLogRedactionImport", ctx, projectKey, plr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectLogRedactionImport indicates an expected call of ProjectLogRedactionImport.
+func (mr *MockProjectClientV2MockRecorder) ProjectLogRedactionImport(ctx, projectKey, plr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLogRedactionImport", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectLogRedactionImport), ctx, projectKey, plr)
+}
+
 // ProjectNotificationCreate mocks base method.
 func (m *MockProjectClientV2) ProjectNotificationCreate(ctx context.Context, pKey string, notif *sdk.ProjectNotification) error {
 	m.ctrl.T.Helper()
@@ -3330,6 +3449,78 @@ func (mr *MockProjectClientV2MockRecorder) ProjectV2Access(ctx, projectKey, sess
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2Access", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2Access), ctx, projectKey, sessionID, itemType)
 }
 
+// ProjectV2CDNLogRedactionReport mocks base method.
+func (m *MockProjectClientV2) ProjectV2CDNLogRedactionReport(ctx context.Context, projectKey string, r sdk.CDNLogRedactionReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNLogRedactionReport", ctx, projectKey, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNLogRedactionReport indicates an expected call of ProjectV2CDNLogRedactionReport.
+func (mr *MockProjectClientV2MockRecorder) ProjectV2CDNLogRedactionReport(ctx, projectKey, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNLogRedactionReport", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2CDNLogRedactionReport), ctx, projectKey, r)
+}
+
+// ProjectV2CDNLogRedactionRules mocks base method.
+func (m *MockProjectClientV2) ProjectV2CDNLogRedactionRules(ctx context.Context, projectKey string) (sdk.LogRedactionRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNLogRedactionRules", ctx, projectKey)
+	ret0, _ := ret[0].(sdk.LogRedactionRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectV2CDNLogRedactionRules indicates an expected call of ProjectV2CDNLogRedactionRules.
+func (mr *MockProjectClientV2MockRecorder) ProjectV2CDNLogRedactionRules(ctx, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNLogRedactionRules", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2CDNLogRedactionRules), ctx, projectKey)
+}
+
+// ProjectV2CDNQuotaEvent mocks base method.
+func (m *MockProjectClientV2) ProjectV2CDNQuotaEvent(ctx context.Context, projectKey string, e sdk.CDNQuotaEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNQuotaEvent", ctx, projectKey, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNQuotaEvent indicates an expected call of ProjectV2CDNQuotaEvent.
+func (mr *MockProjectClientV2MockRecorder) ProjectV2CDNQuotaEvent(ctx, projectKey, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNQuotaEvent", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2CDNQuotaEvent), ctx, projectKey, e)
+}
+
+// ProjectV2CDNRunResultShareDownload mocks base method.
+func (m *MockProjectClientV2) ProjectV2CDNRunResultShareDownload(ctx context.Context, projectKey, shareID string, d sdk.V2WorkflowRunResultShareDownload) (*sdk.V2WorkflowRunResultShareDownload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNRunResultShareDownload", ctx, projectKey, shareID, d)
+	ret0, _ := ret[0].(*sdk.V2WorkflowRunResultShareDownload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectV2CDNRunResultShareDownload indicates an expected call of ProjectV2CDNRunResultShareDownload.
+func (mr *MockProjectClientV2MockRecorder) ProjectV2CDNRunResultShareDownload(ctx, projectKey, shareID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNRunResultShareDownload", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2CDNRunResultShareDownload), ctx, projectKey, shareID, d)
+}
+
+// ProjectV2CDNRunResultShareDownloadFailed mocks base method.
+func (m *MockProjectClientV2) ProjectV2CDNRunResultShareDownloadFailed(ctx context.Context, projectKey, shareID, downloadID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNRunResultShareDownloadFailed", ctx, projectKey, shareID, downloadID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNRunResultShareDownloadFailed indicates an expected call of ProjectV2CDNRunResultShareDownloadFailed.
+func (mr *MockProjectClientV2MockRecorder) ProjectV2CDNRunResultShareDownloadFailed(ctx, projectKey, shareID, downloadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNRunResultShareDownloadFailed", reflect.TypeOf((*MockProjectClientV2)(nil).ProjectV2CDNRunResultShareDownloadFailed), ctx, projectKey, shareID, downloadID)
+}
+
 // ProjectV2List mocks base method.
 func (m *MockProjectClientV2) ProjectV2List(ctx context.Context) ([]sdk.Project, error) {
 	m.ctrl.T.Helper()
@@ -4597,18 +4788,23 @@ func (m *MockV2QueueClient) EXPECT() *MockV2QueueClientMockRecorder {
 }
 
 // V2QueueGetCacheLinks mocks base method.
-func (m *MockV2QueueClient) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string) (*sdk.CDNItemLinks, error) {
+func (m *MockV2QueueClient) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string, mods ...cdsclient.RequestModifier) (*sdk.CDNItemLinks, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", ctx, regionName, id, cacheKey)
+	varargs := []any{ctx, regionName, id, cacheKey}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", varargs...)
 	ret0, _ := ret[0].(*sdk.CDNItemLinks)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // V2QueueGetCacheLinks indicates an expected call of V2QueueGetCacheLinks.
-func (mr *MockV2QueueClientMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any) *gomock.Call {
+func (mr *MockV2QueueClientMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any, mods ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueGetCacheLinks), ctx, regionName, id, cacheKey)
+	varargs := append([]any{ctx, regionName, id, cacheKey}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueGetCacheLinks), varargs...)
 }
 
 // V2QueueGetJobRun mocks base method.
@@ -4626,6 +4822,48 @@ func (mr *MockV2QueueClientMockRecorder) V2QueueGetJobRun(ctx, regionName, id an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetJobRun", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueGetJobRun), ctx, regionName, id)
 }
 
+// V2QueueJobDebugBreakpoint mocks base method.
+func (m *MockV2QueueClient) V2QueueJobDebugBreakpoint(ctx context.Context, regionName, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugBreakpoint", ctx, regionName, jobRunID, breakpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugBreakpoint indicates an expected call of V2QueueJobDebugBreakpoint.
+func (mr *MockV2QueueClientMockRecorder) V2QueueJobDebugBreakpoint(ctx, regionName, jobRunID, breakpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugBreakpoint", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueJobDebugBreakpoint), ctx, regionName, jobRunID, breakpoint)
+}
+
+// V2QueueJobDebugResume mocks base method.
+func (m *MockV2QueueClient) V2QueueJobDebugResume(ctx context.Context, regionName, jobRunID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugResume", ctx, regionName, jobRunID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugResume indicates an expected call of V2QueueJobDebugResume.
+func (mr *MockV2QueueClientMockRecorder) V2QueueJobDebugResume(ctx, regionName, jobRunID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugResume", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueJobDebugResume), ctx, regionName, jobRunID)
+}
+
+// V2QueueJobDebugWebsocket mocks base method.
+func (m *MockV2QueueClient) V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugWebsocket", ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugWebsocket indicates an expected call of V2QueueJobDebugWebsocket.
+func (mr *MockV2QueueClientMockRecorder) V2QueueJobDebugWebsocket(ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugWebsocket", reflect.TypeOf((*MockV2QueueClient)(nil).V2QueueJobDebugWebsocket), ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // V2QueueJobResult mocks base method.
 func (m *MockV2QueueClient) V2QueueJobResult(ctx context.Context, region, jobRunID string, result sdk.V2WorkflowRunJobResult) error {
 	m.ctrl.T.Helper()
@@ -5876,6 +6114,21 @@ func (mr *MockHookClientMockRecorder) HookGetWorkflowHook(ctx, hookID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookGetWorkflowHook", reflect.TypeOf((*MockHookClient)(nil).HookGetWorkflowHook), ctx, hookID)
 }
 
+// HookListAllMessageBusHooks mocks base method.
+func (m *MockHookClient) HookListAllMessageBusHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookListAllMessageBusHooks", ctx)
+	ret0, _ := ret[0].([]sdk.V2WorkflowHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookListAllMessageBusHooks indicates an expected call of HookListAllMessageBusHooks.
+func (mr *MockHookClientMockRecorder) HookListAllMessageBusHooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookListAllMessageBusHooks", reflect.TypeOf((*MockHookClient)(nil).HookListAllMessageBusHooks), ctx)
+}
+
 // HookListAllSchedulerHooks mocks base method.
 func (m *MockHookClient) HookListAllSchedulerHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error) {
 	m.ctrl.T.Helper()
@@ -6100,6 +6353,45 @@ func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2JobsStart(ctx, projectKey,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2JobsStart", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2JobsStart), varargs...)
 }
 
+// WorkflowV2LogsFollow mocks base method.
+func (m *MockWorkflowV2Client) WorkflowV2LogsFollow(ctx context.Context, projKey, workflowRunID string, events chan<- sdk.CDNLogFollowEvent, mods ...cdsclient.RequestModifier) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projKey, workflowRunID, events}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowV2LogsFollow", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowV2LogsFollow indicates an expected call of WorkflowV2LogsFollow.
+func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2LogsFollow(ctx, projKey, workflowRunID, events any, mods ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projKey, workflowRunID, events}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2LogsFollow", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2LogsFollow), varargs...)
+}
+
+// WorkflowV2LogsSearch mocks base method.
+func (m *MockWorkflowV2Client) WorkflowV2LogsSearch(ctx context.Context, projKey, pattern string, mods ...cdsclient.RequestModifier) (*sdk.CDNLogSearch, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projKey, pattern}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowV2LogsSearch", varargs...)
+	ret0, _ := ret[0].(*sdk.CDNLogSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2LogsSearch indicates an expected call of WorkflowV2LogsSearch.
+func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2LogsSearch(ctx, projKey, pattern any, mods ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projKey, pattern}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2LogsSearch", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2LogsSearch), varargs...)
+}
+
 // WorkflowV2Restart mocks base method.
 func (m *MockWorkflowV2Client) WorkflowV2Restart(ctx context.Context, projectKey, workflowRunID string, mods ...cdsclient.RequestModifier) (*sdk.V2WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -6209,6 +6501,20 @@ func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2RunJob(ctx, projKey, workf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunJob", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2RunJob), ctx, projKey, workflowRunID, jobRunID)
 }
 
+// WorkflowV2RunJobDebugShell mocks base method.
+func (m *MockWorkflowV2Client) WorkflowV2RunJobDebugShell(ctx context.Context, goRoutines *sdk.GoRoutines, projKey, workflowRunID, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunJobDebugShell", ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowV2RunJobDebugShell indicates an expected call of WorkflowV2RunJobDebugShell.
+func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2RunJobDebugShell(ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunJobDebugShell", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2RunJobDebugShell), ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // WorkflowV2RunJobInfoList mocks base method.
 func (m *MockWorkflowV2Client) WorkflowV2RunJobInfoList(ctx context.Context, projKey, workflowRunID, jobRunID string) ([]sdk.V2WorkflowRunJobInfo, error) {
 	m.ctrl.T.Helper()
@@ -6284,6 +6590,36 @@ func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2RunResultList(ctx, projKey
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultList", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2RunResultList), ctx, projKey, runIdentifier)
 }
 
+// WorkflowV2RunResultShare mocks base method.
+func (m *MockWorkflowV2Client) WorkflowV2RunResultShare(ctx context.Context, projKey, runIdentifier, resultID string, r sdk.V2WorkflowRunResultShareRequest) (*sdk.V2WorkflowRunResultShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunResultShare", ctx, projKey, runIdentifier, resultID, r)
+	ret0, _ := ret[0].(*sdk.V2WorkflowRunResultShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2RunResultShare indicates an expected call of WorkflowV2RunResultShare.
+func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2RunResultShare(ctx, projKey, runIdentifier, resultID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultShare", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2RunResultShare), ctx, projKey, runIdentifier, resultID, r)
+}
+
+// WorkflowV2RunResultShareList mocks base method.
+func (m *MockWorkflowV2Client) WorkflowV2RunResultShareList(ctx context.Context, projKey, runIdentifier, resultID string) ([]sdk.V2WorkflowRunResultShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunResultShareList", ctx, projKey, runIdentifier, resultID)
+	ret0, _ := ret[0].([]sdk.V2WorkflowRunResultShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2RunResultShareList indicates an expected call of WorkflowV2RunResultShareList.
+func (mr *MockWorkflowV2ClientMockRecorder) WorkflowV2RunResultShareList(ctx, projKey, runIdentifier, resultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultShareList", reflect.TypeOf((*MockWorkflowV2Client)(nil).WorkflowV2RunResultShareList), ctx, projKey, runIdentifier, resultID)
+}
+
 // WorkflowV2RunSearch mocks base method.
 func (m *MockWorkflowV2Client) WorkflowV2RunSearch(ctx context.Context, projectKey string, mods ...cdsclient.RequestModifier) ([]sdk.V2WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -8890,6 +9226,21 @@ func (mr *MockInterfaceMockRecorder) HookGetWorkflowHook(ctx, hookID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookGetWorkflowHook", reflect.TypeOf((*MockInterface)(nil).HookGetWorkflowHook), ctx, hookID)
 }
 
+// HookListAllMessageBusHooks mocks base method.
+func (m *MockInterface) HookListAllMessageBusHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HookListAllMessageBusHooks", ctx)
+	ret0, _ := ret[0].([]sdk.V2WorkflowHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HookListAllMessageBusHooks indicates an expected call of HookListAllMessageBusHooks.
+func (mr *MockInterfaceMockRecorder) HookListAllMessageBusHooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HookListAllMessageBusHooks", reflect.TypeOf((*MockInterface)(nil).HookListAllMessageBusHooks), ctx)
+}
+
 // HookListAllSchedulerHooks mocks base method.
 func (m *MockInterface) HookListAllSchedulerHooks(ctx context.Context) ([]sdk.V2WorkflowHook, error) {
 	m.ctrl.T.Helper()
@@ -9772,6 +10123,35 @@ func (mr *MockInterfaceMockRecorder) ProjectList(withApplications, withWorkflow
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectList", reflect.TypeOf((*MockInterface)(nil).ProjectList), varargs...)
 }
 
+// ProjectLogRedactionGet mocks base method.
+func (m *MockInterface) ProjectLogRedactionGet(ctx context.Context, projectKey string) (*sdk.ProjectLogRedaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLogRedactionGet", ctx, projectKey)
+	ret0, _ := ret[0].(*sdk.ProjectLogRedaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectLogRedactionGet indicates an expected call of ProjectLogRedactionGet.
+func (mr *MockInterfaceMockRecorder) ProjectLogRedactionGet(ctx, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLogRedactionGet", reflect.TypeOf((*MockInterface)(nil).ProjectLogRedactionGet), ctx, projectKey)
+}
+
+// ProjectLogRedactionImport mocks base method.
+func (m *MockInterface) ProjectLogRedactionImport(ctx context.Context, projectKey string, plr sdk.ProjectLogRedaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectLogRedactionImport", ctx, projectKey, plr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectLogRedactionImport indicates an expected call of ProjectLogRedactionImport.
+func (mr *MockInterfaceMockRecorder) ProjectLogRedactionImport(ctx, projectKey, plr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectLogRedactionImport", reflect.TypeOf((*MockInterface)(nil).ProjectLogRedactionImport), ctx, projectKey, plr)
+}
+
 // ProjectNotificationCreate mocks base method.
 func (m *MockInterface) ProjectNotificationCreate(ctx context.Context, pKey string, notif *sdk.ProjectNotification) error {
 	m.ctrl.T.Helper()
@@ -10004,6 +10384,78 @@ func (mr *MockInterfaceMockRecorder) ProjectV2Access(ctx, projectKey, sessionID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2Access", reflect.TypeOf((*MockInterface)(nil).ProjectV2Access), ctx, projectKey, sessionID, itemType)
 }
 
+// ProjectV2CDNLogRedactionReport mocks base method.
+func (m *MockInterface) ProjectV2CDNLogRedactionReport(ctx context.Context, projectKey string, r sdk.CDNLogRedactionReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNLogRedactionReport", ctx, projectKey, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNLogRedactionReport indicates an expected call of ProjectV2CDNLogRedactionReport.
+func (mr *MockInterfaceMockRecorder) ProjectV2CDNLogRedactionReport(ctx, projectKey, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNLogRedactionReport", reflect.TypeOf((*MockInterface)(nil).ProjectV2CDNLogRedactionReport), ctx, projectKey, r)
+}
+
+// ProjectV2CDNLogRedactionRules mocks base method.
+func (m *MockInterface) ProjectV2CDNLogRedactionRules(ctx context.Context, projectKey string) (sdk.LogRedactionRules, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNLogRedactionRules", ctx, projectKey)
+	ret0, _ := ret[0].(sdk.LogRedactionRules)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectV2CDNLogRedactionRules indicates an expected call of ProjectV2CDNLogRedactionRules.
+func (mr *MockInterfaceMockRecorder) ProjectV2CDNLogRedactionRules(ctx, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNLogRedactionRules", reflect.TypeOf((*MockInterface)(nil).ProjectV2CDNLogRedactionRules), ctx, projectKey)
+}
+
+// ProjectV2CDNQuotaEvent mocks base method.
+func (m *MockInterface) ProjectV2CDNQuotaEvent(ctx context.Context, projectKey string, e sdk.CDNQuotaEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNQuotaEvent", ctx, projectKey, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNQuotaEvent indicates an expected call of ProjectV2CDNQuotaEvent.
+func (mr *MockInterfaceMockRecorder) ProjectV2CDNQuotaEvent(ctx, projectKey, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNQuotaEvent", reflect.TypeOf((*MockInterface)(nil).ProjectV2CDNQuotaEvent), ctx, projectKey, e)
+}
+
+// ProjectV2CDNRunResultShareDownload mocks base method.
+func (m *MockInterface) ProjectV2CDNRunResultShareDownload(ctx context.Context, projectKey, shareID string, d sdk.V2WorkflowRunResultShareDownload) (*sdk.V2WorkflowRunResultShareDownload, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNRunResultShareDownload", ctx, projectKey, shareID, d)
+	ret0, _ := ret[0].(*sdk.V2WorkflowRunResultShareDownload)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectV2CDNRunResultShareDownload indicates an expected call of ProjectV2CDNRunResultShareDownload.
+func (mr *MockInterfaceMockRecorder) ProjectV2CDNRunResultShareDownload(ctx, projectKey, shareID, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNRunResultShareDownload", reflect.TypeOf((*MockInterface)(nil).ProjectV2CDNRunResultShareDownload), ctx, projectKey, shareID, d)
+}
+
+// ProjectV2CDNRunResultShareDownloadFailed mocks base method.
+func (m *MockInterface) ProjectV2CDNRunResultShareDownloadFailed(ctx context.Context, projectKey, shareID, downloadID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectV2CDNRunResultShareDownloadFailed", ctx, projectKey, shareID, downloadID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectV2CDNRunResultShareDownloadFailed indicates an expected call of ProjectV2CDNRunResultShareDownloadFailed.
+func (mr *MockInterfaceMockRecorder) ProjectV2CDNRunResultShareDownloadFailed(ctx, projectKey, shareID, downloadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectV2CDNRunResultShareDownloadFailed", reflect.TypeOf((*MockInterface)(nil).ProjectV2CDNRunResultShareDownloadFailed), ctx, projectKey, shareID, downloadID)
+}
+
 // ProjectV2List mocks base method.
 func (m *MockInterface) ProjectV2List(ctx context.Context) ([]sdk.Project, error) {
 	m.ctrl.T.Helper()
@@ -10888,6 +11340,49 @@ func (mr *MockInterfaceMockRecorder) RegionList(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionList", reflect.TypeOf((*MockInterface)(nil).RegionList), ctx)
 }
 
+// RegionProjectWeightDelete mocks base method.
+func (m *MockInterface) RegionProjectWeightDelete(ctx context.Context, regionIdentifier, projectKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightDelete", ctx, regionIdentifier, projectKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegionProjectWeightDelete indicates an expected call of RegionProjectWeightDelete.
+func (mr *MockInterfaceMockRecorder) RegionProjectWeightDelete(ctx, regionIdentifier, projectKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightDelete", reflect.TypeOf((*MockInterface)(nil).RegionProjectWeightDelete), ctx, regionIdentifier, projectKey)
+}
+
+// RegionProjectWeightList mocks base method.
+func (m *MockInterface) RegionProjectWeightList(ctx context.Context, regionIdentifier string) ([]sdk.RegionProjectWeight, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightList", ctx, regionIdentifier)
+	ret0, _ := ret[0].([]sdk.RegionProjectWeight)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegionProjectWeightList indicates an expected call of RegionProjectWeightList.
+func (mr *MockInterfaceMockRecorder) RegionProjectWeightList(ctx, regionIdentifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightList", reflect.TypeOf((*MockInterface)(nil).RegionProjectWeightList), ctx, regionIdentifier)
+}
+
+// RegionProjectWeightSet mocks base method.
+func (m *MockInterface) RegionProjectWeightSet(ctx context.Context, regionIdentifier string, weight sdk.RegionProjectWeight) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegionProjectWeightSet", ctx, regionIdentifier, weight)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegionProjectWeightSet indicates an expected call of RegionProjectWeightSet.
+func (mr *MockInterfaceMockRecorder) RegionProjectWeightSet(ctx, regionIdentifier, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegionProjectWeightSet", reflect.TypeOf((*MockInterface)(nil).RegionProjectWeightSet), ctx, regionIdentifier, weight)
+}
+
 // RepositoriesList mocks base method.
 func (m *MockInterface) RepositoriesList(projectKey, repoManager string, resync bool) ([]sdk.VCSRepo, error) {
 	m.ctrl.T.Helper()
@@ -11597,18 +12092,23 @@ func (mr *MockInterfaceMockRecorder) V2ConfigCDN() *gomock.Call {
 }
 
 // V2QueueGetCacheLinks mocks base method.
-func (m *MockInterface) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string) (*sdk.CDNItemLinks, error) {
+func (m *MockInterface) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string, mods ...cdsclient.RequestModifier) (*sdk.CDNItemLinks, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", ctx, regionName, id, cacheKey)
+	varargs := []any{ctx, regionName, id, cacheKey}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", varargs...)
 	ret0, _ := ret[0].(*sdk.CDNItemLinks)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // V2QueueGetCacheLinks indicates an expected call of V2QueueGetCacheLinks.
-func (mr *MockInterfaceMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any) *gomock.Call {
+func (mr *MockInterfaceMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any, mods ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockInterface)(nil).V2QueueGetCacheLinks), ctx, regionName, id, cacheKey)
+	varargs := append([]any{ctx, regionName, id, cacheKey}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockInterface)(nil).V2QueueGetCacheLinks), varargs...)
 }
 
 // V2QueueGetJobRun mocks base method.
@@ -11626,6 +12126,48 @@ func (mr *MockInterfaceMockRecorder) V2QueueGetJobRun(ctx, regionName, id any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetJobRun", reflect.TypeOf((*MockInterface)(nil).V2QueueGetJobRun), ctx, regionName, id)
 }
 
+// V2QueueJobDebugBreakpoint mocks base method.
+func (m *MockInterface) V2QueueJobDebugBreakpoint(ctx context.Context, regionName, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugBreakpoint", ctx, regionName, jobRunID, breakpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugBreakpoint indicates an expected call of V2QueueJobDebugBreakpoint.
+func (mr *MockInterfaceMockRecorder) V2QueueJobDebugBreakpoint(ctx, regionName, jobRunID, breakpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugBreakpoint", reflect.TypeOf((*MockInterface)(nil).V2QueueJobDebugBreakpoint), ctx, regionName, jobRunID, breakpoint)
+}
+
+// V2QueueJobDebugResume mocks base method.
+func (m *MockInterface) V2QueueJobDebugResume(ctx context.Context, regionName, jobRunID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugResume", ctx, regionName, jobRunID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugResume indicates an expected call of V2QueueJobDebugResume.
+func (mr *MockInterfaceMockRecorder) V2QueueJobDebugResume(ctx, regionName, jobRunID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugResume", reflect.TypeOf((*MockInterface)(nil).V2QueueJobDebugResume), ctx, regionName, jobRunID)
+}
+
+// V2QueueJobDebugWebsocket mocks base method.
+func (m *MockInterface) V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugWebsocket", ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugWebsocket indicates an expected call of V2QueueJobDebugWebsocket.
+func (mr *MockInterfaceMockRecorder) V2QueueJobDebugWebsocket(ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugWebsocket", reflect.TypeOf((*MockInterface)(nil).V2QueueJobDebugWebsocket), ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // V2QueueJobResult mocks base method.
 func (m *MockInterface) V2QueueJobResult(ctx context.Context, region, jobRunID string, result sdk.V2WorkflowRunJobResult) error {
 	m.ctrl.T.Helper()
@@ -12877,6 +13419,45 @@ func (mr *MockInterfaceMockRecorder) WorkflowV2JobsStart(ctx, projectKey, workfl
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2JobsStart", reflect.TypeOf((*MockInterface)(nil).WorkflowV2JobsStart), varargs...)
 }
 
+// WorkflowV2LogsFollow mocks base method.
+func (m *MockInterface) WorkflowV2LogsFollow(ctx context.Context, projKey, workflowRunID string, events chan<- sdk.CDNLogFollowEvent, mods ...cdsclient.RequestModifier) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projKey, workflowRunID, events}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowV2LogsFollow", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowV2LogsFollow indicates an expected call of WorkflowV2LogsFollow.
+func (mr *MockInterfaceMockRecorder) WorkflowV2LogsFollow(ctx, projKey, workflowRunID, events any, mods ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projKey, workflowRunID, events}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2LogsFollow", reflect.TypeOf((*MockInterface)(nil).WorkflowV2LogsFollow), varargs...)
+}
+
+// WorkflowV2LogsSearch mocks base method.
+func (m *MockInterface) WorkflowV2LogsSearch(ctx context.Context, projKey, pattern string, mods ...cdsclient.RequestModifier) (*sdk.CDNLogSearch, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, projKey, pattern}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WorkflowV2LogsSearch", varargs...)
+	ret0, _ := ret[0].(*sdk.CDNLogSearch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2LogsSearch indicates an expected call of WorkflowV2LogsSearch.
+func (mr *MockInterfaceMockRecorder) WorkflowV2LogsSearch(ctx, projKey, pattern any, mods ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, projKey, pattern}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2LogsSearch", reflect.TypeOf((*MockInterface)(nil).WorkflowV2LogsSearch), varargs...)
+}
+
 // WorkflowV2Restart mocks base method.
 func (m *MockInterface) WorkflowV2Restart(ctx context.Context, projectKey, workflowRunID string, mods ...cdsclient.RequestModifier) (*sdk.V2WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -12986,6 +13567,20 @@ func (mr *MockInterfaceMockRecorder) WorkflowV2RunJob(ctx, projKey, workflowRunI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunJob", reflect.TypeOf((*MockInterface)(nil).WorkflowV2RunJob), ctx, projKey, workflowRunID, jobRunID)
 }
 
+// WorkflowV2RunJobDebugShell mocks base method.
+func (m *MockInterface) WorkflowV2RunJobDebugShell(ctx context.Context, goRoutines *sdk.GoRoutines, projKey, workflowRunID, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunJobDebugShell", ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WorkflowV2RunJobDebugShell indicates an expected call of WorkflowV2RunJobDebugShell.
+func (mr *MockInterfaceMockRecorder) WorkflowV2RunJobDebugShell(ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunJobDebugShell", reflect.TypeOf((*MockInterface)(nil).WorkflowV2RunJobDebugShell), ctx, goRoutines, projKey, workflowRunID, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // WorkflowV2RunJobInfoList mocks base method.
 func (m *MockInterface) WorkflowV2RunJobInfoList(ctx context.Context, projKey, workflowRunID, jobRunID string) ([]sdk.V2WorkflowRunJobInfo, error) {
 	m.ctrl.T.Helper()
@@ -13061,6 +13656,36 @@ func (mr *MockInterfaceMockRecorder) WorkflowV2RunResultList(ctx, projKey, runId
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultList", reflect.TypeOf((*MockInterface)(nil).WorkflowV2RunResultList), ctx, projKey, runIdentifier)
 }
 
+// WorkflowV2RunResultShare mocks base method.
+func (m *MockInterface) WorkflowV2RunResultShare(ctx context.Context, projKey, runIdentifier, resultID string, r sdk.V2WorkflowRunResultShareRequest) (*sdk.V2WorkflowRunResultShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunResultShare", ctx, projKey, runIdentifier, resultID, r)
+	ret0, _ := ret[0].(*sdk.V2WorkflowRunResultShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2RunResultShare indicates an expected call of WorkflowV2RunResultShare.
+func (mr *MockInterfaceMockRecorder) WorkflowV2RunResultShare(ctx, projKey, runIdentifier, resultID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultShare", reflect.TypeOf((*MockInterface)(nil).WorkflowV2RunResultShare), ctx, projKey, runIdentifier, resultID, r)
+}
+
+// WorkflowV2RunResultShareList mocks base method.
+func (m *MockInterface) WorkflowV2RunResultShareList(ctx context.Context, projKey, runIdentifier, resultID string) ([]sdk.V2WorkflowRunResultShare, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowV2RunResultShareList", ctx, projKey, runIdentifier, resultID)
+	ret0, _ := ret[0].([]sdk.V2WorkflowRunResultShare)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowV2RunResultShareList indicates an expected call of WorkflowV2RunResultShareList.
+func (mr *MockInterfaceMockRecorder) WorkflowV2RunResultShareList(ctx, projKey, runIdentifier, resultID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowV2RunResultShareList", reflect.TypeOf((*MockInterface)(nil).WorkflowV2RunResultShareList), ctx, projKey, runIdentifier, resultID)
+}
+
 // WorkflowV2RunSearch mocks base method.
 func (m *MockInterface) WorkflowV2RunSearch(ctx context.Context, projectKey string, mods ...cdsclient.RequestModifier) ([]sdk.V2WorkflowRun, error) {
 	m.ctrl.T.Helper()
@@ -13357,18 +13982,23 @@ func (mr *MockV2WorkerInterfaceMockRecorder) ProjectV2IntegrationWorkerHookGet(p
 }
 
 // V2QueueGetCacheLinks mocks base method.
-func (m *MockV2WorkerInterface) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string) (*sdk.CDNItemLinks, error) {
+func (m *MockV2WorkerInterface) V2QueueGetCacheLinks(ctx context.Context, regionName, id, cacheKey string, mods ...cdsclient.RequestModifier) (*sdk.CDNItemLinks, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", ctx, regionName, id, cacheKey)
+	varargs := []any{ctx, regionName, id, cacheKey}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "V2QueueGetCacheLinks", varargs...)
 	ret0, _ := ret[0].(*sdk.CDNItemLinks)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // V2QueueGetCacheLinks indicates an expected call of V2QueueGetCacheLinks.
-func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any) *gomock.Call {
+func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueGetCacheLinks(ctx, regionName, id, cacheKey any, mods ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueGetCacheLinks), ctx, regionName, id, cacheKey)
+	varargs := append([]any{ctx, regionName, id, cacheKey}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetCacheLinks", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueGetCacheLinks), varargs...)
 }
 
 // V2QueueGetJobRun mocks base method.
@@ -13386,6 +14016,48 @@ func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueGetJobRun(ctx, regionName, i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueGetJobRun", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueGetJobRun), ctx, regionName, id)
 }
 
+// V2QueueJobDebugBreakpoint mocks base method.
+func (m *MockV2WorkerInterface) V2QueueJobDebugBreakpoint(ctx context.Context, regionName, jobRunID string, breakpoint sdk.V2WorkflowRunJobBreakpoint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugBreakpoint", ctx, regionName, jobRunID, breakpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugBreakpoint indicates an expected call of V2QueueJobDebugBreakpoint.
+func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueJobDebugBreakpoint(ctx, regionName, jobRunID, breakpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugBreakpoint", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueJobDebugBreakpoint), ctx, regionName, jobRunID, breakpoint)
+}
+
+// V2QueueJobDebugResume mocks base method.
+func (m *MockV2WorkerInterface) V2QueueJobDebugResume(ctx context.Context, regionName, jobRunID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugResume", ctx, regionName, jobRunID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugResume indicates an expected call of V2QueueJobDebugResume.
+func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueJobDebugResume(ctx, regionName, jobRunID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugResume", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueJobDebugResume), ctx, regionName, jobRunID)
+}
+
+// V2QueueJobDebugWebsocket mocks base method.
+func (m *MockV2WorkerInterface) V2QueueJobDebugWebsocket(ctx context.Context, goRoutines *sdk.GoRoutines, regionName, jobRunID string, msgToSend <-chan json.RawMessage, msgReceived chan<- json.RawMessage, errorReceived chan<- error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "V2QueueJobDebugWebsocket", ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// V2QueueJobDebugWebsocket indicates an expected call of V2QueueJobDebugWebsocket.
+func (mr *MockV2WorkerInterfaceMockRecorder) V2QueueJobDebugWebsocket(ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "V2QueueJobDebugWebsocket", reflect.TypeOf((*MockV2WorkerInterface)(nil).V2QueueJobDebugWebsocket), ctx, goRoutines, regionName, jobRunID, msgToSend, msgReceived, errorReceived)
+}
+
 // V2QueueJobResult mocks base method.
 func (m *MockV2WorkerInterface) V2QueueJobResult(ctx context.Context, region, jobRunID string, result sdk.V2WorkflowRunJobResult) error {
 	m.ctrl.T.Helper()
@@ -109,6 +109,19 @@ func (e *EntityWithObject) Interpolate(ctx context.Context) error {
 		if err != nil {
 			return WrapError(err, "unable to marshal vsphere spec")
 		}
+	case WorkerModelTypeFirecracker:
+		var firecrackerSpec V2WorkerModelFirecrackerSpec
+		if err := json.Unmarshal(e.Model.Spec, &firecrackerSpec); err != nil {
+			return WrapError(err, "unable to unmarshal firecracker spec")
+		}
+		firecrackerSpec.Image, err = ap.InterpolateToString(ctx, firecrackerSpec.Image)
+		if err != nil {
+			return WithStack(err)
+		}
+		e.Model.Spec, err = json.Marshal(firecrackerSpec)
+		if err != nil {
+			return WrapError(err, "unable to marshal firecracker spec")
+		}
 	case WorkerModelTypeOpenstack:
 		var openstackSpec V2WorkerModelOpenstackSpec
 		if err := json.Unmarshal(e.Model.Spec, &openstackSpec); err != nil {
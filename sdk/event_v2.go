@@ -23,11 +23,12 @@ const (
 	EventRunJobRunResultUpdated EventType = "RunJobRunResultUpdated"
 	EventRunJobEnded            EventType = "RunJobEnded"
 
-	EventRunCrafted  EventType = "RunCrafted"
-	EventRunBuilding EventType = "RunBuilding"
-	EventRunEnded    EventType = "RunEnded"
-	EventRunRestart  EventType = "RunRestart"
-	EventRunDeleted  EventType = "RunDeleted"
+	EventRunCrafted        EventType = "RunCrafted"
+	EventRunBuilding       EventType = "RunBuilding"
+	EventRunEnded          EventType = "RunEnded"
+	EventRunRestart        EventType = "RunRestart"
+	EventRunPartialRestart EventType = "RunPartialRestart"
+	EventRunDeleted        EventType = "RunDeleted"
 
 	EventEntityCreated EventType = "EntityCreated"
 	EventEntityUpdated EventType = "EntityUpdated"
@@ -79,6 +80,9 @@ const (
 
 	EventProjectPurge EventType = "EventProjectPurge"
 
+	EventProjectCDNQuotaSoftLimitReached EventType = "ProjectCDNQuotaSoftLimitReached"
+	EventProjectCDNQuotaHardLimitReached EventType = "ProjectCDNQuotaHardLimitReached"
+
 	EventNotificationCreated EventType = "NotificationCreated"
 	EventNotificationUpdated EventType = "NotificationUpdated"
 	EventNotificationDeleted EventType = "NotificationDeleted"
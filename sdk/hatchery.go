@@ -70,6 +70,9 @@ type HatcheryMetrics struct {
 	WaitingWorkers                *stats.Int64Measure
 	BuildingWorkers               *stats.Int64Measure
 	DisabledWorkers               *stats.Int64Measure
+	WarmPoolHits                  *stats.Int64Measure
+	WarmPoolMisses                *stats.Int64Measure
+	WarmPoolWorkers               *stats.Int64Measure
 }
 
 type HatcheryPendingWorkerCreation struct {
@@ -132,17 +135,18 @@ type WorkerStarterWorkerModel struct {
 	ModelV1 *Model
 
 	// Worker model v2
-	ModelV2       *V2WorkerModel
-	PreCmd        string
-	Cmd           string
-	Shell         string
-	PostCmd       string
-	DockerSpec    V2WorkerModelDockerSpec
-	OpenstackSpec V2WorkerModelOpenstackSpec
-	VSphereSpec   V2WorkerModelVSphereSpec
-	Commit        string
-	Flavor        string
-	Memory        int64
+	ModelV2         *V2WorkerModel
+	PreCmd          string
+	Cmd             string
+	Shell           string
+	PostCmd         string
+	DockerSpec      V2WorkerModelDockerSpec
+	OpenstackSpec   V2WorkerModelOpenstackSpec
+	VSphereSpec     V2WorkerModelVSphereSpec
+	FirecrackerSpec V2WorkerModelFirecrackerSpec
+	Commit          string
+	Flavor          string
+	Memory          int64
 }
 
 func (w WorkerStarterWorkerModel) GetName() string {
@@ -169,6 +173,9 @@ func (w WorkerStarterWorkerModel) GetFlavor(reqs RequirementList, defaultFlavor
 		if w.VSphereSpec.Flavor != "" {
 			return w.VSphereSpec.Flavor
 		}
+		if w.FirecrackerSpec.Flavor != "" {
+			return w.FirecrackerSpec.Flavor
+		}
 		for _, r := range reqs {
 			if r.Type == FlavorRequirement && r.Value != "" {
 				return r.Value
@@ -283,6 +290,14 @@ func (w WorkerStarterWorkerModel) GetDockerPassword() string {
 	return ""
 }
 
+// GetKubernetesSpec returns the pod settings of a worker model v2, nil if not set
+func (w WorkerStarterWorkerModel) GetKubernetesSpec() *V2WorkerModelKubernetesSpec {
+	if w.ModelV2 != nil {
+		return w.DockerSpec.Kubernetes
+	}
+	return nil
+}
+
 func (w WorkerStarterWorkerModel) GetPath() string {
 	switch {
 	case w.ModelV1 != nil:
@@ -333,6 +348,20 @@ func (w WorkerStarterWorkerModel) GetVSpherePassword() string {
 	return ""
 }
 
+func (w WorkerStarterWorkerModel) GetFirecrackerImage() string {
+	if w.ModelV2 != nil {
+		return w.FirecrackerSpec.Image
+	}
+	return ""
+}
+
+func (w WorkerStarterWorkerModel) GetFirecrackerKernel() string {
+	if w.ModelV2 != nil {
+		return w.FirecrackerSpec.Kernel
+	}
+	return ""
+}
+
 func (w WorkerStarterWorkerModel) GetLastModified() string {
 	switch {
 	case w.ModelV1 != nil:
@@ -551,6 +551,18 @@ func handleJobV2(ctx context.Context, h Interface, jobRunID string, cacheAttempt
 			endTrace("cannot allocate resource", jobInfo.RunJob.ID)
 			return nil
 		}
+	} else {
+		// Hatcheries without worker models can still size the worker from the job
+		if jobInfo.RunJob.Job.RunsOn.Memory != "" {
+			mem, err := strconv.ParseInt(jobInfo.RunJob.Job.RunsOn.Memory, 10, 64)
+			if err != nil {
+				cacheAttempts.NewAttempt(jobInfo.RunJob.ID)
+				endTrace(fmt.Sprintf("%v", err.Error()), jobInfo.RunJob.ID)
+				return sdk.NewErrorFrom(sdk.ErrInvalidData, "%s is not an integer", jobInfo.RunJob.Job.RunsOn.Memory)
+			}
+			workerRequest.model.Memory = mem
+		}
+		workerRequest.model.Flavor = jobInfo.RunJob.Job.RunsOn.Flavor
 	}
 
 	cacheAttempts.NewAttempt(jobInfo.RunJob.ID)
@@ -758,6 +770,16 @@ func getWorkerModelV2(ctx context.Context, h InterfaceWithModels, jobInf sdk.V2Q
 			return nil, sdk.WrapError(err, "unable to get vsphere spec")
 		}
 		workerStarterModel.VSphereSpec = vsphereSpec
+	case sdk.WorkerModelTypeFirecracker:
+		workerStarterModel.Cmd = "PATH=$PATH worker"
+		workerStarterModel.PreCmd = preCmd
+		// Firecracker does not emulate power off, the VM exits when the guest reboots
+		workerStarterModel.PostCmd = "reboot"
+		var firecrackerSpec sdk.V2WorkerModelFirecrackerSpec
+		if err := json.Unmarshal(jobInf.Model.Spec, &firecrackerSpec); err != nil {
+			return nil, sdk.WrapError(err, "unable to get firecracker spec")
+		}
+		workerStarterModel.FirecrackerSpec = firecrackerSpec
 	case sdk.WorkerModelTypeOpenstack:
 		workerStarterModel.Cmd = "worker"
 		workerStarterModel.PreCmd = preCmd
@@ -43,6 +43,9 @@ func InitMetrics(ctx context.Context) error {
 		metrics.CheckingWorkers = stats.Int64("cds/checking_workers", "number of checking workers", stats.UnitDimensionless)
 		metrics.BuildingWorkers = stats.Int64("cds/building_workers", "number of building workers", stats.UnitDimensionless)
 		metrics.DisabledWorkers = stats.Int64("cds/disabled_workers", "number of disabled workers", stats.UnitDimensionless)
+		metrics.WarmPoolHits = stats.Int64("cds/warm_pool_hits", "number of workers taken from a warm pool", stats.UnitDimensionless)
+		metrics.WarmPoolMisses = stats.Int64("cds/warm_pool_misses", "number of workers started without warm pool worker available", stats.UnitDimensionless)
+		metrics.WarmPoolWorkers = stats.Int64("cds/warm_pool_workers", "number of idle warm pool workers", stats.UnitDimensionless)
 
 		tags := []tag.Key{telemetry.MustNewKey(telemetry.TagServiceType), telemetry.MustNewKey(telemetry.TagServiceName)}
 		err = telemetry.RegisterView(ctx,
@@ -63,6 +66,9 @@ func InitMetrics(ctx context.Context) error {
 			telemetry.NewViewLast("cds/hatchery/checking_workers", metrics.CheckingWorkers, tags),
 			telemetry.NewViewLast("cds/hatchery/building_workers", metrics.BuildingWorkers, tags),
 			telemetry.NewViewLast("cds/hatchery/disabled_workers", metrics.DisabledWorkers, tags),
+			telemetry.NewViewCount("cds/hatchery/warm_pool_hits_count", metrics.WarmPoolHits, tags),
+			telemetry.NewViewCount("cds/hatchery/warm_pool_misses_count", metrics.WarmPoolMisses, tags),
+			telemetry.NewViewLast("cds/hatchery/warm_pool_workers", metrics.WarmPoolWorkers, tags),
 		)
 	})
 	return err
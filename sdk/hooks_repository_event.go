@@ -47,6 +47,8 @@ const (
 	WorkflowHookEventNameWebHook        WorkflowHookEventName = "webhook"
 	WorkflowHookEventNameWorkflowRun    WorkflowHookEventName = "workflow-run"
 	WorkflowHookEventNameScheduler      WorkflowHookEventName = "scheduler"
+	WorkflowHookEventNameKafka          WorkflowHookEventName = "kafka"
+	WorkflowHookEventNameRabbitMQ       WorkflowHookEventName = "rabbitmq"
 
 	WorkflowHookEventNamePullRequest         WorkflowHookEventName = "pull-request"
 	WorkflowHookEventTypePullRequestOpened   WorkflowHookEventType = "opened"
@@ -324,6 +326,7 @@ type HookRepositoryEventExtractData struct {
 	Scheduler          *HookRepositoryEventExtractedDataScheduler   `json:"scheduler,omitempty"`
 	WorkflowRun        *HookRepositoryEventExtractedDataWorkflowRun `json:"workflow_run,omitempty"`
 	WebHook            *HookRepositoryEventExtractedDataWebHook     `json:"workflow_hook,omitempty"`
+	MessageBus         *HookRepositoryEventExtractedDataMessageBus  `json:"message_bus,omitempty"`
 	HookProjectKey     string                                       `json:"hook_project_key,omitempty"` // force the hook to only trigger from the given CDS project
 	CommitVerified     bool                                         `json:"commit_verified,omitempty"`
 	CommitGpgKeyID     string                                       `json:"commit_gpg_key_id,omitempty"`
@@ -345,6 +348,8 @@ type HookRepositoryEventExtractedDataManual struct {
 	TargetTag        string                 `json:"target_tag,omitempty"`
 	TargetRepository string                 `json:"target_repository,omitempty"`
 	JobInputs        V2WorkflowRunJobInputs `json:"job_inputs,omitempty"`
+	Inputs           map[string]interface{} `json:"inputs,omitempty"`
+	Debug            *V2WorkflowRunDebug    `json:"debug,omitempty"`
 	IsInMaintenance  bool                   `json:"is_in_maintenance,omitempty"`
 }
 
@@ -366,6 +371,17 @@ type HookRepositoryEventExtractedDataScheduler struct {
 	Timezone       string `json:"timezone"`
 }
 
+type HookRepositoryEventExtractedDataMessageBus struct {
+	HookID         string `json:"hook_id"`
+	TargetVCS      string `json:"target_vcs"`
+	TargetRepo     string `json:"target_repo"`
+	TargetWorkflow string `json:"target_workflow"`
+	TargetProject  string `json:"target_project"`
+	Integration    string `json:"integration"`
+	Topic          string `json:"topic,omitempty"`
+	Queue          string `json:"queue,omitempty"`
+}
+
 type GeneratedWebhook struct {
 	Key           string `json:"key"`
 	UUID          string `json:"uuid"`
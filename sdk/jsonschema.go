@@ -14,6 +14,7 @@ func GetWorkerModelJsonSchema() *jsonschema.Schema {
 	wmDocker := reflector.Reflect(&V2WorkerModelDockerSpec{})
 	wmOpenstack := reflector.Reflect(&V2WorkerModelOpenstackSpec{})
 	wmVSphere := reflector.Reflect(&V2WorkerModelVSphereSpec{})
+	wmFirecracker := reflector.Reflect(&V2WorkerModelFirecrackerSpec{})
 
 	if wmSchema.Definitions == nil {
 		wmSchema.Definitions = make(map[string]*jsonschema.Schema)
@@ -21,6 +22,7 @@ func GetWorkerModelJsonSchema() *jsonschema.Schema {
 	wmSchema.Definitions["V2WorkerModelVSphereSpec"] = wmVSphere
 	wmSchema.Definitions["V2WorkerModelOpenstackSpec"] = wmOpenstack
 	wmSchema.Definitions["V2WorkerModelDockerSpec"] = wmDocker
+	wmSchema.Definitions["V2WorkerModelFirecrackerSpec"] = wmFirecracker
 
 	propName, _ := wmSchema.Definitions["V2WorkerModel"].Properties.Get("name")
 	name := propName.(*jsonschema.Schema)
@@ -137,6 +139,8 @@ func GetWorkflowJsonSchema(publicActionNames, regionNames, workerModelNames []st
 		PullRequestComment: &WorkflowOnPullRequestComment{},
 		Push:               &WorkflowOnPush{},
 		WorkflowUpdate:     &WorkflowOnWorkflowUpdate{},
+		WorkflowCall:       &WorkflowOnWorkflowCall{},
+		Manual:             &WorkflowOnManual{},
 	})
 
 	jobSchema := GetJobJsonSchema(publicActionNames, regionNames, workerModelNames)
@@ -167,6 +171,11 @@ func GetWorkflowJsonSchema(publicActionNames, regionNames, workerModelNames []st
 	workflowSchema.Definitions["WorkflowOnWorkflowUpdate"] = workflowOn.Definitions["WorkflowOnWorkflowUpdate"]
 	workflowSchema.Definitions["WorkflowOnSchedule"] = workflowOn.Definitions["WorkflowOnSchedule"]
 	workflowSchema.Definitions["WorkflowOnRun"] = workflowOn.Definitions["WorkflowOnRun"]
+	workflowSchema.Definitions["WorkflowOnWorkflowCall"] = workflowOn.Definitions["WorkflowOnWorkflowCall"]
+	workflowSchema.Definitions["WorkflowCallOutput"] = workflowOn.Definitions["WorkflowCallOutput"]
+	workflowSchema.Definitions["WorkflowOnManual"] = workflowOn.Definitions["WorkflowOnManual"]
+	workflowSchema.Definitions["WorkflowOnKafka"] = workflowOn.Definitions["WorkflowOnKafka"]
+	workflowSchema.Definitions["WorkflowOnRabbitMQ"] = workflowOn.Definitions["WorkflowOnRabbitMQ"]
 
 	// Prop On - Get existing schema to preserve description and order from jsonschema_extras
 	existingOn, _ := workflowSchema.Definitions["V2Workflow"].Properties.Get("on")
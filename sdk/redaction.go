@@ -0,0 +1,174 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LogRedactionDefaultMinLength is the minimal size of a value detected by an entropy rule without pattern
+const LogRedactionDefaultMinLength = 20
+
+// logRedactionTokenPattern matches the values checked by an entropy rule without pattern
+const logRedactionTokenPattern = `[A-Za-z0-9+/=_\-\.]{%d,}`
+
+// ProjectLogRedaction contains the log redaction rules of a project
+type ProjectLogRedaction struct {
+	ID           string            `json:"id" db:"id"`
+	ProjectKey   string            `json:"project_key" db:"project_key"`
+	LastModified time.Time         `json:"last_modified" db:"last_modified"`
+	Rules        LogRedactionRules `json:"rules" db:"rules"`
+}
+
+// CDNLogRedactionReport is the count of values redacted by the CDN from the logs of a job step or service
+type CDNLogRedactionReport struct {
+	RunJobID    string `json:"run_job_id"`
+	StepName    string `json:"step_name,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Count       int    `json:"count"`
+}
+
+// LogRedactionRule describes values to redact from job logs, in addition to the known secrets.
+// Values are matched by a regular expression, by their entropy or by both.
+type LogRedactionRule struct {
+	Name       string  `json:"name" yaml:"name" toml:"name" jsonschema_description:"Name of the rule"`
+	Pattern    string  `json:"pattern,omitempty" yaml:"pattern,omitempty" toml:"pattern" jsonschema_description:"Regular expression of the values to redact. If it contains a group, only the first group is redacted"`
+	MinEntropy float64 `json:"min_entropy,omitempty" yaml:"min_entropy,omitempty" toml:"minEntropy" jsonschema_description:"Minimal Shannon entropy (in bits per character) of the values to redact"`
+	MinLength  int     `json:"min_length,omitempty" yaml:"min_length,omitempty" toml:"minLength" jsonschema_description:"Minimal size of the values to redact"`
+}
+
+// IsValid returns an error if the rule is invalid
+func (r LogRedactionRule) IsValid() error {
+	if r.Name == "" {
+		return NewErrorFrom(ErrInvalidData, "missing log redaction rule name")
+	}
+	if r.Pattern == "" && r.MinEntropy <= 0 {
+		return NewErrorFrom(ErrInvalidData, "log redaction rule %q must have a pattern or a minimal entropy", r.Name)
+	}
+	if r.MinEntropy < 0 || r.MinEntropy > 8 {
+		return NewErrorFrom(ErrInvalidData, "invalid minimal entropy for log redaction rule %q: must be between 0 and 8", r.Name)
+	}
+	if r.MinLength < 0 {
+		return NewErrorFrom(ErrInvalidData, "invalid minimal length for log redaction rule %q", r.Name)
+	}
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return NewErrorFrom(ErrInvalidData, "invalid pattern for log redaction rule %q: %v", r.Name, err)
+		}
+	}
+	return nil
+}
+
+type LogRedactionRules []LogRedactionRule
+
+// IsValid returns an error if one of the rules is invalid
+func (r LogRedactionRules) IsValid() error {
+	names := make(map[string]struct{}, len(r))
+	for i := range r {
+		if err := r[i].IsValid(); err != nil {
+			return err
+		}
+		if _, has := names[r[i].Name]; has {
+			return NewErrorFrom(ErrInvalidData, "duplicated log redaction rule %q", r[i].Name)
+		}
+		names[r[i].Name] = struct{}{}
+	}
+	return nil
+}
+
+func (r LogRedactionRules) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal LogRedactionRules")
+}
+
+func (r *LogRedactionRules) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(json.Unmarshal(source, r), "cannot unmarshal LogRedactionRules")
+}
+
+type logRedactor struct {
+	regexp     *regexp.Regexp
+	minEntropy float64
+	minLength  int
+}
+
+func newLogRedactor(r LogRedactionRule) (logRedactor, error) {
+	minLength := r.MinLength
+	pattern := r.Pattern
+	if pattern == "" {
+		if minLength <= 0 {
+			minLength = LogRedactionDefaultMinLength
+		}
+		pattern = fmt.Sprintf(logRedactionTokenPattern, minLength)
+	}
+	reg, err := regexp.Compile(pattern)
+	if err != nil {
+		return logRedactor{}, NewErrorFrom(ErrInvalidData, "invalid pattern for log redaction rule %q: %v", r.Name, err)
+	}
+	return logRedactor{regexp: reg, minEntropy: r.MinEntropy, minLength: minLength}, nil
+}
+
+// redact replaces the matching values with the password placeholder, and returns the count of replaced values
+func (r logRedactor) redact(s string) (string, int) {
+	matches := r.regexp.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, 0
+	}
+	var b strings.Builder
+	var last, count int
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		// Only redact the first group if any
+		if len(m) >= 4 {
+			if m[2] < 0 {
+				continue
+			}
+			start, end = m[2], m[3]
+		}
+		value := s[start:end]
+		if value == "" || value == PasswordPlaceholder || len(value) < r.minLength {
+			continue
+		}
+		if r.minEntropy > 0 && ShannonEntropy(value) < r.minEntropy {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(PasswordPlaceholder)
+		last = end
+		count++
+	}
+	if count == 0 {
+		return s, 0
+	}
+	b.WriteString(s[last:])
+	return b.String(), count
+}
+
+// ShannonEntropy returns the Shannon entropy of the given value, in bits per character
+func ShannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	freq := make(map[rune]int)
+	var total int
+	for _, c := range s {
+		freq[c]++
+		total++
+	}
+	var entropy float64
+	for _, n := range freq {
+		p := float64(n) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
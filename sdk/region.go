@@ -4,3 +4,13 @@ type Region struct {
 	ID   string `json:"id" db:"id" cli:"id"`
 	Name string `json:"name" db:"name" cli:"name"`
 }
+
+// RegionProjectWeight defines the share of a region allocated to a project in the queue
+type RegionProjectWeight struct {
+	ID         string `json:"id" db:"id"`
+	RegionID   string `json:"region_id" db:"region_id"`
+	ProjectKey string `json:"project_key" db:"project_key" cli:"project_key,key"`
+	Weight     int64  `json:"weight" db:"weight" cli:"weight"`
+}
+
+const RegionProjectDefaultWeight = 1
@@ -50,6 +50,7 @@ type ActionStep struct {
 	If              string                 `json:"if,omitempty" jsonschema:"example=${{ git.branch == 'main' }}" jsonschema_extras:"order=1,textarea=true" jsonschema_description:"Condition to execute/skip the step"`
 	ContinueOnError bool                   `json:"continue-on-error,omitempty" jsonschema:"example=false" jsonschema_extras:"order=2"  jsonschema_description:"Allow a job to continue when this step fails"`
 	Env             map[string]string      `json:"env,omitempty" jsonschema_extras:"order=3,mode=edit" jsonschema_description:"Environment variable available in the step"`
+	TimeoutMinutes  int64                  `json:"timeout-minutes,omitempty" jsonschema:"example=10" jsonschema_extras:"order=6" jsonschema_description:"Maximum number of minutes the step can run before being stopped"`
 }
 
 type ActionStepUsesWith map[string]string
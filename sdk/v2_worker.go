@@ -26,9 +26,11 @@ func (w V2Worker) GetStatus() string { return w.Status }
 func (w V2Worker) GetID() string     { return w.ID }
 
 type V2TakeJobResponse struct {
-	RunJob         V2WorkflowRunJob       `json:"run_job"`
-	AsCodeActions  map[string]V2Action    `json:"actions"`
-	SigningKey     string                 `json:"signing_key"`
-	Contexts       WorkflowRunJobsContext `json:"contexts"`
-	SensitiveDatas []string               `json:"sensitive_datas"`
+	RunJob            V2WorkflowRunJob       `json:"run_job"`
+	AsCodeActions     map[string]V2Action    `json:"actions"`
+	SigningKey        string                 `json:"signing_key"`
+	Contexts          WorkflowRunJobsContext `json:"contexts"`
+	SensitiveDatas    []string               `json:"sensitive_datas"`
+	LogRedactionRules []LogRedactionRule     `json:"log_redaction_rules,omitempty"`
+	Debug             *V2WorkflowRunDebug    `json:"debug,omitempty"`
 }
@@ -2,29 +2,146 @@ package sdk
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
 const (
-	WorkerModelTypeOpenstack = "openstack"
-	WorkerModelTypeDocker    = "docker"
-	WorkerModelTypeVSphere   = "vsphere"
+	WorkerModelTypeOpenstack   = "openstack"
+	WorkerModelTypeDocker      = "docker"
+	WorkerModelTypeVSphere     = "vsphere"
+	WorkerModelTypeFirecracker = "firecracker"
 )
 
 type V2WorkerModel struct {
 	Name        string          `json:"name" cli:"name" jsonschema:"minLength=1,example=my-worker-model" jsonschema_extras:"order=1" jsonschema_description:"Name of the worker model"`
 	Description string          `json:"description,omitempty" jsonschema:"example=Worker model for building Go applications" jsonschema_extras:"order=2" jsonschema_description:"Description of the worker model"`
 	OSArch      string          `json:"osarch" jsonschema:"example=linux/amd64" jsonschema_extras:"order=3" jsonschema_description:"OS/Arch of the worker model"`
-	Type        string          `json:"type" cli:"type" jsonschema:"enum=docker,enum=openstack,enum=vsphere,example=docker" jsonschema_extras:"order=4" jsonschema_description:"Type of worker model: docker, openstack, vsphere"`
-	Spec        json.RawMessage `json:"spec" jsonschema_allof_type:"type=docker:#/$defs/V2WorkerModelDockerSpec,type=openstack:#/$defs/V2WorkerModelOpenstackSpec,type=vsphere:#/$defs/V2WorkerModelVSphereSpec" jsonschema_extras:"order=5" jsonschema_description:"Specification of the worker model"`
+	Type        string          `json:"type" cli:"type" jsonschema:"enum=docker,enum=openstack,enum=vsphere,enum=firecracker,example=docker" jsonschema_extras:"order=4" jsonschema_description:"Type of worker model: docker, openstack, vsphere, firecracker"`
+	Spec        json.RawMessage `json:"spec" jsonschema_allof_type:"type=docker:#/$defs/V2WorkerModelDockerSpec,type=openstack:#/$defs/V2WorkerModelOpenstackSpec,type=vsphere:#/$defs/V2WorkerModelVSphereSpec,type=firecracker:#/$defs/V2WorkerModelFirecrackerSpec" jsonschema_extras:"order=5" jsonschema_description:"Specification of the worker model"`
 }
 
 type V2WorkerModelDockerSpec struct {
-	Image    string            `json:"image" jsonschema:"minLength=1,example=golang:1.21" jsonschema_extras:"order=1" jsonschema_description:"Docker image name"`
-	Username string            `json:"username,omitempty" jsonschema:"example=myuser" jsonschema_extras:"order=2" jsonschema_description:"Username to login to the registry"`
-	Password string            `json:"password,omitempty" jsonschema:"example=${{ secrets.DOCKER_PASSWORD }}" jsonschema_extras:"order=3" jsonschema_description:"User password to login to the registry"`
-	Envs     map[string]string `json:"envs,omitempty" jsonschema_extras:"order=4" jsonschema_description:"Additional environment variables to inject into the worker"`
+	Image      string                       `json:"image" jsonschema:"minLength=1,example=golang:1.21" jsonschema_extras:"order=1" jsonschema_description:"Docker image name"`
+	Username   string                       `json:"username,omitempty" jsonschema:"example=myuser" jsonschema_extras:"order=2" jsonschema_description:"Username to login to the registry"`
+	Password   string                       `json:"password,omitempty" jsonschema:"example=${{ secrets.DOCKER_PASSWORD }}" jsonschema_extras:"order=3" jsonschema_description:"User password to login to the registry"`
+	Envs       map[string]string            `json:"envs,omitempty" jsonschema_extras:"order=4" jsonschema_description:"Additional environment variables to inject into the worker"`
+	Kubernetes *V2WorkerModelKubernetesSpec `json:"kubernetes,omitempty" jsonschema_extras:"order=5" jsonschema_description:"Pod settings used by the kubernetes hatchery, ignored by the other hatcheries"`
+}
+
+// V2WorkerModelKubernetesSpec is a partial pod spec merged into the pod of the worker by the kubernetes hatchery.
+// Fields are named as in the kubernetes API.
+type V2WorkerModelKubernetesSpec struct {
+	NodeSelector       map[string]string                       `json:"nodeSelector,omitempty" jsonschema_extras:"order=1" jsonschema_description:"Labels of the nodes on which the worker can be scheduled"`
+	Tolerations        []V2WorkerModelKubernetesToleration     `json:"tolerations,omitempty" jsonschema_extras:"order=2" jsonschema_description:"Tolerations of the worker pod"`
+	Affinity           *V2WorkerModelKubernetesAffinity        `json:"affinity,omitempty" jsonschema_extras:"order=3" jsonschema_description:"Node affinity of the worker pod"`
+	ServiceAccountName string                                  `json:"serviceAccountName,omitempty" jsonschema:"example=cds-worker" jsonschema_extras:"order=4" jsonschema_description:"Service account of the worker pod, must be allowed by the hatchery"`
+	RuntimeClassName   string                                  `json:"runtimeClassName,omitempty" jsonschema:"example=gvisor" jsonschema_extras:"order=5" jsonschema_description:"Runtime class of the worker pod, must be allowed by the hatchery"`
+	SecurityContext    *V2WorkerModelKubernetesSecurityContext `json:"securityContext,omitempty" jsonschema_extras:"order=6" jsonschema_description:"Security context of the worker pod"`
+	Resources          *V2WorkerModelKubernetesResources       `json:"resources,omitempty" jsonschema_extras:"order=7" jsonschema_description:"Extended resources of the worker container, cpu and memory are set by the hatchery"`
+	Volumes            []V2WorkerModelKubernetesVolume         `json:"volumes,omitempty" jsonschema_extras:"order=8" jsonschema_description:"Volumes of the worker pod"`
+	VolumeMounts       []V2WorkerModelKubernetesVolumeMount    `json:"volumeMounts,omitempty" jsonschema_extras:"order=9" jsonschema_description:"Volumes mounted in the worker container"`
+	Sidecars           []V2WorkerModelKubernetesSidecar        `json:"sidecars,omitempty" jsonschema_extras:"order=10" jsonschema_description:"Containers started next to the worker, they must run as long as the worker"`
+}
+
+type V2WorkerModelKubernetesToleration struct {
+	Key               string `json:"key,omitempty" jsonschema:"example=nvidia.com/gpu" jsonschema_description:"Taint key, empty to match all keys with the Exists operator"`
+	Operator          string `json:"operator,omitempty" jsonschema:"enum=Exists,enum=Equal" jsonschema_description:"Exists or Equal (default)"`
+	Value             string `json:"value,omitempty" jsonschema_description:"Taint value for the Equal operator"`
+	Effect            string `json:"effect,omitempty" jsonschema:"enum=NoSchedule,enum=PreferNoSchedule,enum=NoExecute" jsonschema_description:"Taint effect to match, empty to match all effects"`
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty" jsonschema_description:"Time the pod stays bound to a node with a NoExecute taint"`
+}
+
+type V2WorkerModelKubernetesAffinity struct {
+	NodeAffinity *V2WorkerModelKubernetesNodeAffinity `json:"nodeAffinity,omitempty" jsonschema_description:"Node affinity scheduling rules"`
+}
+
+type V2WorkerModelKubernetesNodeAffinity struct {
+	Required  *V2WorkerModelKubernetesNodeSelector   `json:"requiredDuringSchedulingIgnoredDuringExecution,omitempty" jsonschema_description:"Rules that must be met to schedule the pod on a node"`
+	Preferred []V2WorkerModelKubernetesPreferredTerm `json:"preferredDuringSchedulingIgnoredDuringExecution,omitempty" jsonschema_description:"Rules the scheduler prefers to meet"`
+}
+
+type V2WorkerModelKubernetesNodeSelector struct {
+	NodeSelectorTerms []V2WorkerModelKubernetesNodeSelectorTerm `json:"nodeSelectorTerms" jsonschema:"minItems=1" jsonschema_description:"One of the terms must match"`
+}
+
+type V2WorkerModelKubernetesNodeSelectorTerm struct {
+	MatchExpressions []V2WorkerModelKubernetesNodeSelectorRequirement `json:"matchExpressions" jsonschema:"minItems=1" jsonschema_description:"All the expressions must match"`
+}
+
+type V2WorkerModelKubernetesNodeSelectorRequirement struct {
+	Key      string   `json:"key" jsonschema:"minLength=1,example=kubernetes.io/arch" jsonschema_description:"Node label key"`
+	Operator string   `json:"operator" jsonschema:"enum=In,enum=NotIn,enum=Exists,enum=DoesNotExist,enum=Gt,enum=Lt" jsonschema_description:"Operator applied to the values"`
+	Values   []string `json:"values,omitempty" jsonschema_description:"Node label values"`
+}
+
+type V2WorkerModelKubernetesPreferredTerm struct {
+	Weight     int32                                   `json:"weight" jsonschema:"minimum=1,maximum=100" jsonschema_description:"Weight of the term, between 1 and 100"`
+	Preference V2WorkerModelKubernetesNodeSelectorTerm `json:"preference" jsonschema_description:"Node selector term"`
+}
+
+type V2WorkerModelKubernetesSecurityContext struct {
+	RunAsUser          *int64  `json:"runAsUser,omitempty" jsonschema_description:"UID of the containers processes"`
+	RunAsGroup         *int64  `json:"runAsGroup,omitempty" jsonschema_description:"GID of the containers processes"`
+	RunAsNonRoot       *bool   `json:"runAsNonRoot,omitempty" jsonschema_description:"Containers must not run as root"`
+	FSGroup            *int64  `json:"fsGroup,omitempty" jsonschema_description:"Group owning the volumes"`
+	SupplementalGroups []int64 `json:"supplementalGroups,omitempty" jsonschema_description:"Additional groups of the containers processes"`
+}
+
+type V2WorkerModelKubernetesResources struct {
+	Requests map[string]string `json:"requests,omitempty" jsonschema_description:"Requested resources, ex: nvidia.com/gpu: 1"`
+	Limits   map[string]string `json:"limits,omitempty" jsonschema_description:"Resource limits, ex: nvidia.com/gpu: 1"`
+}
+
+type V2WorkerModelKubernetesVolume struct {
+	Name                  string                                   `json:"name" jsonschema:"minLength=1" jsonschema_description:"Name of the volume"`
+	EmptyDir              *V2WorkerModelKubernetesEmptyDir         `json:"emptyDir,omitempty" jsonschema_description:"Empty directory living as long as the pod"`
+	ConfigMap             *V2WorkerModelKubernetesConfigMap        `json:"configMap,omitempty" jsonschema_description:"Config map of the hatchery namespace"`
+	Secret                *V2WorkerModelKubernetesSecret           `json:"secret,omitempty" jsonschema_description:"Secret of the hatchery namespace, must be allowed by the hatchery"`
+	PersistentVolumeClaim *V2WorkerModelKubernetesPersistentVolume `json:"persistentVolumeClaim,omitempty" jsonschema_description:"Persistent volume claim of the hatchery namespace, must be allowed by the hatchery"`
+}
+
+type V2WorkerModelKubernetesEmptyDir struct {
+	Medium    string `json:"medium,omitempty" jsonschema:"enum=Memory" jsonschema_description:"Memory to use a tmpfs, empty to use the node storage"`
+	SizeLimit string `json:"sizeLimit,omitempty" jsonschema:"example=1Gi" jsonschema_description:"Maximum size of the directory"`
+}
+
+type V2WorkerModelKubernetesConfigMap struct {
+	Name string `json:"name" jsonschema:"minLength=1" jsonschema_description:"Name of the config map"`
+}
+
+type V2WorkerModelKubernetesSecret struct {
+	SecretName string `json:"secretName" jsonschema:"minLength=1" jsonschema_description:"Name of the secret"`
+}
+
+type V2WorkerModelKubernetesPersistentVolume struct {
+	ClaimName string `json:"claimName" jsonschema:"minLength=1" jsonschema_description:"Name of the persistent volume claim"`
+	ReadOnly  bool   `json:"readOnly,omitempty" jsonschema_description:"Mount the volume read-only"`
+}
+
+type V2WorkerModelKubernetesVolumeMount struct {
+	Name      string `json:"name" jsonschema:"minLength=1" jsonschema_description:"Name of the volume"`
+	MountPath string `json:"mountPath" jsonschema:"minLength=1,example=/cache" jsonschema_description:"Path of the volume in the container"`
+	SubPath   string `json:"subPath,omitempty" jsonschema_description:"Path in the volume to mount"`
+	ReadOnly  bool   `json:"readOnly,omitempty" jsonschema_description:"Mount the volume read-only"`
+}
+
+type V2WorkerModelKubernetesSidecar struct {
+	Name         string                               `json:"name" jsonschema:"minLength=1,example=docker" jsonschema_description:"Name of the container"`
+	Image        string                               `json:"image" jsonschema:"minLength=1,example=docker:dind-rootless" jsonschema_description:"Image of the container"`
+	Command      []string                             `json:"command,omitempty" jsonschema_description:"Entrypoint of the container"`
+	Args         []string                             `json:"args,omitempty" jsonschema_description:"Arguments of the entrypoint"`
+	Env          []V2WorkerModelKubernetesEnvVar      `json:"env,omitempty" jsonschema_description:"Environment variables of the container"`
+	Resources    *V2WorkerModelKubernetesResources    `json:"resources,omitempty" jsonschema_description:"Resources of the container"`
+	VolumeMounts []V2WorkerModelKubernetesVolumeMount `json:"volumeMounts,omitempty" jsonschema_description:"Volumes mounted in the container"`
+}
+
+type V2WorkerModelKubernetesEnvVar struct {
+	Name  string `json:"name" jsonschema:"minLength=1" jsonschema_description:"Name of the variable"`
+	Value string `json:"value" jsonschema_description:"Value of the variable"`
 }
 
 type V2WorkerModelOpenstackSpec struct {
@@ -39,6 +156,12 @@ type V2WorkerModelVSphereSpec struct {
 	Password string `json:"password,omitempty" jsonschema:"example=${{ secrets.VSPHERE_PASSWORD }}" jsonschema_description:"Username password to connect to the VM"`
 }
 
+type V2WorkerModelFirecrackerSpec struct {
+	Image  string `json:"image" jsonschema:"minLength=1,example=debian12" jsonschema_description:"Name of the rootfs image available on the hatchery"`
+	Kernel string `json:"kernel,omitempty" jsonschema:"example=vmlinux-6.1" jsonschema_description:"Name of the kernel image available on the hatchery, the hatchery default kernel is used if empty"`
+	Flavor string `json:"flavor,omitempty" jsonschema:"example=small" jsonschema_description:"Default flavor to use for vCPU/memory sizing"`
+}
+
 func (wm V2WorkerModel) GetName() string {
 	return wm.Name
 }
@@ -61,13 +184,154 @@ func (wm V2WorkerModel) Lint() []error {
 	if err != nil {
 		return []error{NewErrorFrom(ErrInvalidData, "worker model %s: unable to validate worker model: %v", wm.Name, err.Error())}
 	}
-	if result.Valid() {
-		return nil
+	if !result.Valid() {
+		errors := make([]error, 0, len(result.Errors()))
+		for _, e := range result.Errors() {
+			errors = append(errors, NewErrorFrom(ErrInvalidData, "worker model %s: yaml validation failed: %s", wm.Name, e.String()))
+		}
+		return errors
+	}
+
+	if wm.Type == WorkerModelTypeDocker {
+		var spec V2WorkerModelDockerSpec
+		if err := json.Unmarshal(wm.Spec, &spec); err != nil {
+			return []error{NewErrorFrom(ErrInvalidData, "worker model %s: unable to read docker spec: %v", wm.Name, err)}
+		}
+		if spec.Kubernetes != nil {
+			var errors []error
+			for _, err := range spec.Kubernetes.Lint() {
+				errors = append(errors, NewErrorFrom(ErrInvalidData, "worker model %s: kubernetes: %v", wm.Name, err))
+			}
+			return errors
+		}
+	}
+	return nil
+}
+
+var (
+	kubernetesNameRegexp     = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	kubernetesQuantityRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+)
+
+// Lint checks the consistency of the pod spec, its structure is checked by the json schema of the worker model
+func (s V2WorkerModelKubernetesSpec) Lint() []error {
+	var errs []error
+
+	volumes := make(map[string]struct{}, len(s.Volumes))
+	for _, v := range s.Volumes {
+		if !kubernetesNameRegexp.MatchString(v.Name) {
+			errs = append(errs, fmt.Errorf("invalid volume name %q", v.Name))
+		}
+		if _, has := volumes[v.Name]; has {
+			errs = append(errs, fmt.Errorf("volume %q is declared twice", v.Name))
+		}
+		volumes[v.Name] = struct{}{}
+
+		var sources int
+		if v.EmptyDir != nil {
+			sources++
+			if v.EmptyDir.SizeLimit != "" && !kubernetesQuantityRegexp.MatchString(v.EmptyDir.SizeLimit) {
+				errs = append(errs, fmt.Errorf("invalid size limit %q for volume %q", v.EmptyDir.SizeLimit, v.Name))
+			}
+		}
+		if v.ConfigMap != nil {
+			sources++
+		}
+		if v.Secret != nil {
+			sources++
+		}
+		if v.PersistentVolumeClaim != nil {
+			sources++
+		}
+		if sources != 1 {
+			errs = append(errs, fmt.Errorf("volume %q must have exactly one of emptyDir, configMap, secret or persistentVolumeClaim", v.Name))
+		}
+	}
+
+	lintMounts := func(container string, mounts []V2WorkerModelKubernetesVolumeMount) {
+		for _, m := range mounts {
+			if _, has := volumes[m.Name]; !has {
+				errs = append(errs, fmt.Errorf("volume %q mounted in %s is not declared", m.Name, container))
+			}
+			if !strings.HasPrefix(m.MountPath, "/") {
+				errs = append(errs, fmt.Errorf("mount path %q of volume %q in %s must be absolute", m.MountPath, m.Name, container))
+			}
+		}
+	}
+	lintMounts("the worker", s.VolumeMounts)
+
+	lintResources := func(container string, r *V2WorkerModelKubernetesResources) {
+		if r == nil {
+			return
+		}
+		for _, list := range []map[string]string{r.Requests, r.Limits} {
+			for name, quantity := range list {
+				if !kubernetesQuantityRegexp.MatchString(quantity) {
+					errs = append(errs, fmt.Errorf("invalid quantity %q for resource %s of %s", quantity, name, container))
+				}
+			}
+		}
+	}
+	lintResources("the worker", s.Resources)
+	if s.Resources != nil {
+		for _, list := range []map[string]string{s.Resources.Requests, s.Resources.Limits} {
+			for _, name := range []string{"cpu", "memory", "ephemeral-storage"} {
+				if _, has := list[name]; has {
+					errs = append(errs, fmt.Errorf("resource %s of the worker is set by the hatchery", name))
+				}
+			}
+		}
+	}
+
+	sidecars := make(map[string]struct{}, len(s.Sidecars))
+	for _, c := range s.Sidecars {
+		if !kubernetesNameRegexp.MatchString(c.Name) || strings.HasPrefix(c.Name, "service-") {
+			errs = append(errs, fmt.Errorf("invalid sidecar name %q", c.Name))
+		}
+		if _, has := sidecars[c.Name]; has {
+			errs = append(errs, fmt.Errorf("sidecar %q is declared twice", c.Name))
+		}
+		sidecars[c.Name] = struct{}{}
+		lintMounts("sidecar "+c.Name, c.VolumeMounts)
+		lintResources("sidecar "+c.Name, c.Resources)
 	}
 
-	errors := make([]error, 0, len(result.Errors()))
-	for _, e := range result.Errors() {
-		errors = append(errors, NewErrorFrom(ErrInvalidData, "worker model %s: yaml validation failed: %s", wm.Name, e.String()))
+	for _, t := range s.Tolerations {
+		if t.Operator == "Exists" && t.Value != "" {
+			errs = append(errs, fmt.Errorf("toleration %q with operator Exists must not have a value", t.Key))
+		}
+		if t.Key == "" && t.Operator != "Exists" {
+			errs = append(errs, fmt.Errorf("toleration without key must have operator Exists"))
+		}
 	}
-	return errors
+
+	if s.Affinity != nil && s.Affinity.NodeAffinity != nil {
+		var terms []V2WorkerModelKubernetesNodeSelectorTerm
+		if s.Affinity.NodeAffinity.Required != nil {
+			terms = append(terms, s.Affinity.NodeAffinity.Required.NodeSelectorTerms...)
+		}
+		for _, p := range s.Affinity.NodeAffinity.Preferred {
+			terms = append(terms, p.Preference)
+		}
+		for _, term := range terms {
+			for _, e := range term.MatchExpressions {
+				switch e.Operator {
+				case "In", "NotIn":
+					if len(e.Values) == 0 {
+						errs = append(errs, fmt.Errorf("node affinity on %q with operator %s must have values", e.Key, e.Operator))
+					}
+				case "Exists", "DoesNotExist":
+					if len(e.Values) > 0 {
+						errs = append(errs, fmt.Errorf("node affinity on %q with operator %s must not have values", e.Key, e.Operator))
+					}
+				case "Gt", "Lt":
+					if len(e.Values) != 1 {
+						errs = append(errs, fmt.Errorf("node affinity on %q with operator %s must have one value", e.Key, e.Operator))
+					}
+				}
+			}
+		}
+	}
+
+	return errs
 }
@@ -65,3 +65,130 @@ func TestWorkerDockerModelOK(t *testing.T) {
 
 	require.Nil(t, dockerModel.Lint())
 }
+
+func TestWorkerFirecrackerModel(t *testing.T) {
+	firecrackerWM := `
+    name: debian12
+    description: "my debian microVM worker model"
+    osarch: linux/amd64
+    type: firecracker
+    spec:
+      image: debian12
+      flavor: small
+  `
+
+	var firecrackerModel V2WorkerModel
+	require.NoError(t, yaml.Unmarshal([]byte(firecrackerWM), &firecrackerModel))
+	require.Nil(t, firecrackerModel.Lint())
+
+	firecrackerWM = `
+    name: debian12
+    osarch: linux/amd64
+    type: firecracker
+    spec:
+      kernel: vmlinux
+  `
+	firecrackerModel = V2WorkerModel{}
+	require.NoError(t, yaml.Unmarshal([]byte(firecrackerWM), &firecrackerModel))
+	err := firecrackerModel.Lint()
+	require.NotEqual(t, 0, len(err))
+	require.Contains(t, fmt.Sprintf("%v", err), "image is required")
+}
+
+func TestWorkerDockerModelKubernetes(t *testing.T) {
+	dockerWM := `
+    name: cuda
+    osarch: linux/amd64
+    type: docker
+    spec:
+      image: nvidia/cuda:12.3.1-base-ubuntu22.04
+      kubernetes:
+        nodeSelector:
+          cloud.google.com/gke-accelerator: nvidia-tesla-t4
+        tolerations:
+        - key: nvidia.com/gpu
+          operator: Exists
+          effect: NoSchedule
+        affinity:
+          nodeAffinity:
+            requiredDuringSchedulingIgnoredDuringExecution:
+              nodeSelectorTerms:
+              - matchExpressions:
+                - key: kubernetes.io/arch
+                  operator: In
+                  values: [amd64]
+        runtimeClassName: nvidia
+        securityContext:
+          runAsNonRoot: true
+          fsGroup: 1000
+        resources:
+          limits:
+            nvidia.com/gpu: "1"
+        volumes:
+        - name: cache
+          emptyDir:
+            sizeLimit: 10Gi
+        volumeMounts:
+        - name: cache
+          mountPath: /cache
+        sidecars:
+        - name: docker
+          image: docker:dind-rootless
+          volumeMounts:
+          - name: cache
+            mountPath: /var/lib/docker
+  `
+
+	var dockerModel V2WorkerModel
+	require.NoError(t, yaml.Unmarshal([]byte(dockerWM), &dockerModel))
+	require.Nil(t, dockerModel.Lint())
+
+	dockerWM = `
+    name: cuda
+    osarch: linux/amd64
+    type: docker
+    spec:
+      image: nvidia/cuda:12.3.1-base-ubuntu22.04
+      kubernetes:
+        tolerations:
+        - key: nvidia.com/gpu
+          operator: Always
+  `
+	dockerModel = V2WorkerModel{}
+	require.NoError(t, yaml.Unmarshal([]byte(dockerWM), &dockerModel))
+	err := dockerModel.Lint()
+	require.NotEqual(t, 0, len(err))
+	require.Contains(t, fmt.Sprintf("%v", err), "operator must be one of the following")
+
+	dockerWM = `
+    name: cuda
+    osarch: linux/amd64
+    type: docker
+    spec:
+      image: nvidia/cuda:12.3.1-base-ubuntu22.04
+      kubernetes:
+        resources:
+          limits:
+            memory: 4Gi
+            nvidia.com/gpu: one
+        volumes:
+        - name: cache
+        volumeMounts:
+        - name: data
+          mountPath: data
+        sidecars:
+        - name: service-db
+          image: postgres
+  `
+	dockerModel = V2WorkerModel{}
+	require.NoError(t, yaml.Unmarshal([]byte(dockerWM), &dockerModel))
+	err = dockerModel.Lint()
+	require.Len(t, err, 6)
+	errs := fmt.Sprintf("%v", err)
+	require.Contains(t, errs, `volume "cache" must have exactly one of emptyDir, configMap, secret or persistentVolumeClaim`)
+	require.Contains(t, errs, `volume "data" mounted in the worker is not declared`)
+	require.Contains(t, errs, `mount path "data" of volume "data" in the worker must be absolute`)
+	require.Contains(t, errs, `invalid quantity "one" for resource nvidia.com/gpu of the worker`)
+	require.Contains(t, errs, "resource memory of the worker is set by the hatchery")
+	require.Contains(t, errs, `invalid sidecar name "service-db"`)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,6 +23,8 @@ const (
 	WorkflowHookTypeWebhook     = "Webhook"
 	WorkflowHookTypeScheduler   = "Scheduler"
 	WorkflowHookTypeWorkflowRun = "WorkflowRun"
+	WorkflowHookTypeKafka       = "Kafka"
+	WorkflowHookTypeRabbitMQ    = "RabbitMQ"
 )
 
 type WorkflowSemverType string
@@ -41,6 +44,9 @@ const (
 	DefaultVersionPattern = "${{%s.version}}-${{cds.run_number}}.sha.g${{git.sha_short}}"
 )
 
+// V2JobDefaultTimeoutMinutes is the max duration of a job without timeout-minutes (24h)
+const V2JobDefaultTimeoutMinutes = 24 * 60
+
 type V2Workflow struct {
 	Name          string                   `json:"name" jsonschema:"example=my-workflow" jsonschema_description:"Workflow name" jsonschema_extras:"order=1"`
 	Repository    *WorkflowRepository      `json:"repository,omitempty" jsonschema_description:"Repository that will be use in the git context ( used by the action for example )" jsonschema_extras:"order=99"`
@@ -107,6 +113,117 @@ type WorkflowOn struct {
 	WorkflowUpdate     *WorkflowOnWorkflowUpdate     `json:"workflow-update,omitempty" jsonschema_description:"Trigger the workflow when updated (for distant workflow only)"`
 	Schedule           []WorkflowOnSchedule          `json:"schedule,omitempty" jsonschema_description:"Trigger the workflow regarding a cron scheduler"`
 	WorkflowRun        []WorkflowOnRun               `json:"workflow-run,omitempty" jsonschema_description:"Trigger the workflow at the end of another workflow run"`
+	WorkflowCall       *WorkflowOnWorkflowCall       `json:"workflow-call,omitempty" jsonschema_description:"Allow the workflow to be called by a job of another workflow"`
+	Manual             *WorkflowOnManual             `json:"manual,omitempty" jsonschema_description:"Inputs to fill when the workflow is manually triggered"`
+	Kafka              []WorkflowOnKafka             `json:"kafka,omitempty" jsonschema_description:"Trigger the workflow on messages consumed from a kafka topic"`
+	RabbitMQ           []WorkflowOnRabbitMQ          `json:"rabbitmq,omitempty" jsonschema_description:"Trigger the workflow on messages consumed from a rabbitMQ queue"`
+}
+
+type WorkflowOnKafka struct {
+	Integration string `json:"integration" jsonschema_description:"Name of the kafka project integration"`
+	Topic       string `json:"topic" jsonschema_description:"Topic to consume"`
+	Filter      string `json:"filter,omitempty" jsonschema:"example=${{ cds.event.action == 'deploy' }}" jsonschema_description:"Condition on the message available in cds.event. The workflow is triggered only if it is true"`
+}
+
+type WorkflowOnRabbitMQ struct {
+	Integration string `json:"integration" jsonschema_description:"Name of the rabbitMQ project integration"`
+	Queue       string `json:"queue" jsonschema_description:"Queue to consume"`
+	Filter      string `json:"filter,omitempty" jsonschema:"example=${{ cds.event.action == 'deploy' }}" jsonschema_description:"Condition on the message available in cds.event. The workflow is triggered only if it is true"`
+}
+
+type WorkflowOnManual struct {
+	Inputs map[string]V2JobGateInput `json:"inputs,omitempty" jsonschema_description:"Inputs given when starting the workflow. An input without default value is required"`
+}
+
+// ComputeInputs checks the inputs given by the user against the workflow definition.
+// Default values are set for missing inputs, and values are converted regarding the input type.
+func (m WorkflowOnManual) ComputeInputs(values map[string]interface{}) (map[string]interface{}, error) {
+	return computeV2Inputs(m.Inputs, values)
+}
+
+type WorkflowOnWorkflowCall struct {
+	Inputs  map[string]V2JobGateInput     `json:"inputs,omitempty" jsonschema_description:"Inputs given by the calling job. An input without default value is required"`
+	Outputs map[string]WorkflowCallOutput `json:"outputs,omitempty" jsonschema_description:"Outputs exported to the calling job"`
+}
+
+type WorkflowCallOutput struct {
+	Description string `json:"description,omitempty" jsonschema_description:"Description of the output"`
+	Value       string `json:"value" jsonschema:"example=${{ jobs.build.outputs.version }}" jsonschema_description:"Value of the output"`
+}
+
+// ComputeInputs checks the inputs given by the calling job against the reusable workflow definition.
+// Missing inputs are set with their default value, string values are converted regarding the input type.
+func (c WorkflowOnWorkflowCall) ComputeInputs(with map[string]interface{}) (map[string]interface{}, error) {
+	return computeV2Inputs(c.Inputs, with)
+}
+
+func computeV2Inputs(defs map[string]V2JobGateInput, with map[string]interface{}) (map[string]interface{}, error) {
+	for k := range with {
+		if _, has := defs[k]; !has {
+			return nil, NewErrorFrom(ErrInvalidData, "input %q is not defined by the workflow", k)
+		}
+	}
+
+	inputs := make(map[string]interface{}, len(defs))
+	for k, def := range defs {
+		v, has := with[k]
+		if !has || v == nil {
+			if def.Default == nil {
+				return nil, NewErrorFrom(ErrInvalidData, "input %q is required", k)
+			}
+			v = def.Default
+		}
+
+		switch def.Type {
+		case "boolean":
+			if s, ok := v.(string); ok {
+				b, err := strconv.ParseBool(s)
+				if err != nil {
+					return nil, NewErrorFrom(ErrInvalidData, "input %q must be a boolean, got %q", k, s)
+				}
+				v = b
+			}
+			if _, ok := v.(bool); !ok {
+				return nil, NewErrorFrom(ErrInvalidData, "input %q must be a boolean, got %T", k, v)
+			}
+		case "number":
+			switch x := v.(type) {
+			case string:
+				f, err := strconv.ParseFloat(x, 64)
+				if err != nil {
+					return nil, NewErrorFrom(ErrInvalidData, "input %q must be a number, got %q", k, x)
+				}
+				v = f
+			case json.Number:
+				f, err := x.Float64()
+				if err != nil {
+					return nil, NewErrorFrom(ErrInvalidData, "input %q must be a number, got %q", k, x)
+				}
+				v = f
+			case float64, float32, int, int64:
+			default:
+				return nil, NewErrorFrom(ErrInvalidData, "input %q must be a number, got %T", k, v)
+			}
+		}
+
+		if def.Options != nil && len(def.Options.Values) > 0 {
+			values := []interface{}{v}
+			if def.Options.Multiple {
+				multipleValues, ok := v.([]interface{})
+				if !ok {
+					return nil, NewErrorFrom(ErrInvalidData, "input %q must be an array, got %T", k, v)
+				}
+				values = multipleValues
+			}
+			for _, value := range values {
+				if !slices.Contains(def.Options.Values, value) {
+					return nil, NewErrorFrom(ErrInvalidData, "input %q with value %v doesn't match %v", k, value, def.Options.Values)
+				}
+			}
+		}
+		inputs[k] = v
+	}
+	return inputs, nil
 }
 
 type WorkflowOnRun struct {
@@ -218,6 +335,16 @@ func IsDefaultHooks(on *WorkflowOn) []WorkflowHookEventName {
 	if len(on.WorkflowRun) > 0 {
 		return nil
 	}
+	// Manual runs are always allowed, on.manual only declares their inputs
+	if on.Manual != nil && len(on.Manual.Inputs) > 0 {
+		return nil
+	}
+	if on.WorkflowCall != nil {
+		return nil
+	}
+	if len(on.Kafka) > 0 || len(on.RabbitMQ) > 0 {
+		return nil
+	}
 	return hookKeys
 }
 
@@ -290,6 +417,7 @@ type WorkflowConcurrency struct {
 	Pool             int64            `json:"pool,omitempty" jsonschema:"example=1" jsonschema_description:"Number of concurrent executions allowed for this concurrency rule"`
 	CancelInProgress bool             `json:"cancel-in-progress" jsonschema:"example=false" jsonschema_description:"If true, when a new execution is triggered, and oldest in-progress executions are canceled"`
 	If               string           `json:"if" jsonschema:"example=${{ git.branch == 'main' }}" jsonschema_description:"Condition to apply the concurrency rule"`
+	Priority         int64            `json:"priority,omitempty" jsonschema:"example=10" jsonschema_description:"Queue priority of the jobs using this concurrency rule. Jobs with the highest priority are started first"`
 }
 
 type V2Job struct {
@@ -313,6 +441,23 @@ type V2Job struct {
 	Parameters      map[string]string       `json:"parameters,omitempty" jsonschema:"oneof=from" jsonschema_description:"Job template parameters"`
 	Concurrency     string                  `json:"concurrency,omitempty" jsonschema_description:"Concurrency rule to apply to the job"`
 	Retry           int64                   `json:"retry,omitempty" jsonschema_description:"The job retry in case of error"`
+	TimeoutMinutes  int64                   `json:"timeout-minutes,omitempty" jsonschema:"example=60" jsonschema_description:"Maximum number of minutes the job can run before being stopped"`
+	Priority        int64                   `json:"priority,omitempty" jsonschema:"example=10" jsonschema_description:"Queue priority of the job. Jobs with the highest priority are started first"`
+	Uses            string                  `json:"uses,omitempty" jsonschema:"oneof=uses,example=my-vcs/my-org/my-repo/my-workflow" jsonschema_description:"Reusable workflow called by the job"`
+	With            map[string]interface{}  `json:"with,omitempty" jsonschema:"oneof=uses" jsonschema_description:"Inputs given to the reusable workflow"`
+	Inputs          map[string]interface{}  `json:"inputs,omitempty" jsonschema_description:"Inputs of the job. Inputs of the reusable workflow are added on its jobs"`
+	Cache           *V2JobCache             `json:"cache,omitempty" jsonschema_description:"Cache restored before the first step and saved after the last step of the job"`
+
+	// Computed by CDS on jobs coming from a reusable workflow
+	Group string `json:"group,omitempty" jsonschema_description:"Computed by CDS: job that called the reusable workflow"`
+}
+
+// GetTimeout returns the maximum duration of the job, the default timeout is used if not set
+func (j V2Job) GetTimeout() time.Duration {
+	if j.TimeoutMinutes <= 0 {
+		return V2JobDefaultTimeoutMinutes * time.Minute
+	}
+	return time.Duration(j.TimeoutMinutes) * time.Minute
 }
 
 func (j V2Job) Copy() V2Job {
@@ -340,6 +485,22 @@ func (j V2Job) Copy() V2Job {
 	new.VariableSets = make([]string, 0, len(j.VariableSets))
 	new.VariableSets = append(new.VariableSets, j.VariableSets...)
 
+	new.With = make(map[string]interface{})
+	for k, v := range j.With {
+		new.With[k] = v
+	}
+	new.Inputs = make(map[string]interface{})
+	for k, v := range j.Inputs {
+		new.Inputs[k] = v
+	}
+
+	if j.Cache != nil {
+		newCache := *j.Cache
+		newCache.Paths = append([]string{}, j.Cache.Paths...)
+		newCache.RestoreKeys = append([]string{}, j.Cache.RestoreKeys...)
+		new.Cache = &newCache
+	}
+
 	new.Steps = make([]ActionStep, 0, len(j.Steps))
 	for _, v := range j.Steps {
 		as := v
@@ -459,6 +620,12 @@ func (job *V2Job) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+type V2JobCache struct {
+	Key         string   `json:"key" jsonschema:"example=go-${{ hashFiles('**/go.sum') }}" jsonschema_extras:"order=1,required" jsonschema_description:"Key of the cache, can use the hashFiles function"`
+	Paths       []string `json:"paths" jsonschema_extras:"order=2,required" jsonschema_description:"Files and directories to cache, relative to the job working directory"`
+	RestoreKeys []string `json:"restore-keys,omitempty" jsonschema_extras:"order=3" jsonschema_description:"Ordered list of key prefixes used to restore a cache when no cache matches the key"`
+}
+
 type V2JobService struct {
 	Image     string                `json:"image" jsonschema_extras:"order=1,required" jsonschema_description:"Docker Image"`
 	Env       map[string]string     `json:"env,omitempty" jsonschema_extras:"order=2" jsonschema_description:"Environment variables"`
@@ -515,6 +682,10 @@ type V2WorkflowHookData struct {
 	WorkflowRunName             string                  `json:"workflow_run_name"`
 	WorkflowRunStatus           []string                `json:"workflow_run_status"`
 	InsecureSkipSignatureVerify bool                    `json:"insecure_skip_signature_verify"`
+	Integration                 string                  `json:"integration,omitempty"`
+	Topic                       string                  `json:"topic,omitempty"`
+	Queue                       string                  `json:"queue,omitempty"`
+	MessageFilter               string                  `json:"message_filter,omitempty"`
 }
 
 func (d V2WorkflowHookData) ValidateRef(ctx context.Context, ref string) bool {
@@ -553,8 +724,92 @@ func (w *V2WorkflowHookData) Scan(src interface{}) error {
 	return WrapError(JSONUnmarshal(source, w), "cannot unmarshal V2WorkflowHookData")
 }
 
+const (
+	V2JobStrategyMatrixInclude = "include"
+	V2JobStrategyMatrixExclude = "exclude"
+)
+
 type V2JobStrategy struct {
-	Matrix map[string]interface{} `json:"matrix" jsonschema_description:"Matrix values for the job"`
+	Matrix           map[string]interface{} `json:"matrix" jsonschema:"oneof_type=object;string" jsonschema_description:"Matrix values for the job, or an expression computing them at runtime"`
+	MatrixExpression string                 `json:"-"`
+	MaxParallel      int64                  `json:"max-parallel,omitempty" jsonschema:"example=2" jsonschema_description:"Maximum number of matrix permutations running at the same time"`
+}
+
+// HasMatrix returns true if the strategy defines a static or a dynamic matrix.
+func (s *V2JobStrategy) HasMatrix() bool {
+	return s != nil && (len(s.Matrix) > 0 || s.MatrixExpression != "")
+}
+
+func (s V2JobStrategy) MarshalJSON() ([]byte, error) {
+	type Alias V2JobStrategy // prevent recursion
+	strategy := struct {
+		Alias
+		Matrix interface{} `json:"matrix"`
+	}{
+		Alias:  Alias(s),
+		Matrix: s.Matrix,
+	}
+	if s.MatrixExpression != "" {
+		strategy.Matrix = s.MatrixExpression
+	}
+	j, err := json.Marshal(strategy)
+	return j, WrapError(err, "cannot marshal V2JobStrategy")
+}
+
+func (s *V2JobStrategy) UnmarshalJSON(data []byte) error {
+	type Alias V2JobStrategy // prevent recursion
+	var strategy struct {
+		Alias
+		Matrix interface{} `json:"matrix"`
+	}
+	if err := JSONUnmarshal(data, &strategy); err != nil {
+		return WrapError(err, "unable to unmarshal V2JobStrategy")
+	}
+	*s = V2JobStrategy(strategy.Alias)
+	switch m := strategy.Matrix.(type) {
+	case nil:
+	case string:
+		s.MatrixExpression = m
+	case map[string]interface{}:
+		s.Matrix = m
+	default:
+		return NewErrorFrom(ErrInvalidData, "matrix must be an object or an expression, got %T", m)
+	}
+	return nil
+}
+
+// GetMatrixEntries returns the entries of the include or exclude key of a matrix.
+func GetMatrixEntries(key string, value interface{}) ([]map[string]string, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		items = v
+	case []map[string]interface{}:
+		for _, i := range v {
+			items = append(items, i)
+		}
+	default:
+		return nil, fmt.Errorf("matrix %s must be a list of objects, got %T", key, value)
+	}
+	entries := make([]map[string]string, 0, len(items))
+	for _, i := range items {
+		item, ok := i.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("matrix %s must be a list of objects, got an item of type %T", key, i)
+		}
+		entry := make(map[string]string, len(item))
+		for k, v := range item {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				return nil, fmt.Errorf("matrix %s value %s must be a scalar", key, k)
+			}
+			entry[k] = fmt.Sprintf("%v", v)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
 }
 
 type V2JobConcurrency struct{}
@@ -587,6 +842,51 @@ func (w V2Workflow) Lint() []error {
 		if j.Retry < 0 || j.Retry > 2 {
 			errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: retry must be 0, 1 or 2", w.Name, j.Name))
 		}
+		if j.TimeoutMinutes < 0 || j.TimeoutMinutes > V2JobDefaultTimeoutMinutes {
+			errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: timeout-minutes must be between 0 and %d", w.Name, j.Name, V2JobDefaultTimeoutMinutes))
+		}
+		if j.Uses != "" {
+			if len(j.Steps) > 0 || j.From != "" {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: uses cannot be combined with steps or from", w.Name, j.Name))
+			}
+			if j.Strategy != nil {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: uses cannot be combined with a strategy", w.Name, j.Name))
+			}
+		} else if len(j.With) > 0 {
+			errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: with can only be used with uses", w.Name, j.Name))
+		}
+		if j.Strategy != nil {
+			if j.Strategy.MaxParallel < 0 {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: max-parallel must be positive", w.Name, j.Name))
+			}
+			if j.Strategy.MatrixExpression != "" {
+				expr := strings.TrimSpace(j.Strategy.MatrixExpression)
+				if !strings.HasPrefix(expr, "${{") || !strings.HasSuffix(expr, "}}") {
+					errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: matrix must be an object or an expression like ${{ fromJSON(needs.job.outputs.matrix) }}", w.Name, j.Name))
+				}
+			}
+			for _, k := range []string{V2JobStrategyMatrixInclude, V2JobStrategyMatrixExclude} {
+				if _, err := GetMatrixEntries(k, j.Strategy.Matrix[k]); err != nil {
+					errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: %v", w.Name, j.Name, err))
+				}
+			}
+		}
+		if j.Cache != nil {
+			if strings.TrimSpace(j.Cache.Key) == "" {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: cache key is mandatory", w.Name, j.Name))
+			}
+			if len(j.Cache.Paths) == 0 {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: cache must define at least one path", w.Name, j.Name))
+			}
+			if j.Uses != "" {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s: uses cannot be combined with a cache", w.Name, j.Name))
+			}
+		}
+		for i, s := range j.Steps {
+			if s.TimeoutMinutes < 0 {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s job %s step %s: timeout-minutes must be positive", w.Name, j.Name, GetJobStepName(s.ID, i)))
+			}
+		}
 	}
 
 	if err := w.CheckSemver(); err != nil {
@@ -617,6 +917,22 @@ func (w V2Workflow) Lint() []error {
 				errs = append(errs, NewErrorFrom(err, "workflow %s: unable to parse cron expression: %s", w.Name, s.Cron))
 			}
 		}
+		for _, k := range w.On.Kafka {
+			if k.Integration == "" || k.Topic == "" {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s: kafka hook must define an integration and a topic", w.Name))
+			}
+			if k.Filter != "" && !strings.HasPrefix(strings.TrimSpace(k.Filter), "${{") {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s: kafka hook filter must be an expression like ${{ cds.event.key == 'value' }}", w.Name))
+			}
+		}
+		for _, r := range w.On.RabbitMQ {
+			if r.Integration == "" || r.Queue == "" {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s: rabbitmq hook must define an integration and a queue", w.Name))
+			}
+			if r.Filter != "" && !strings.HasPrefix(strings.TrimSpace(r.Filter), "${{") {
+				errs = append(errs, NewErrorFrom(ErrInvalidData, "workflow %s: rabbitmq hook filter must be an expression like ${{ cds.event.key == 'value' }}", w.Name))
+			}
+		}
 	}
 
 	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
@@ -798,6 +1114,8 @@ type V2WorkflowRunManualRequest struct {
 	WorkflowTag      string                 `json:"workflow_tag,omitempty"`
 	TargetRepository string                 `json:"target_repository,omitempty"`
 	JobInputs        V2WorkflowRunJobInputs `json:"job_inputs,omitempty"`
+	Inputs           map[string]interface{} `json:"inputs,omitempty"`
+	Debug            *V2WorkflowRunDebug    `json:"debug,omitempty"`
 }
 
 type V2WorkflowRunTriggerJobsRequest struct {
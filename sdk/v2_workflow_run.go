@@ -52,6 +52,10 @@ type V2WorkflowRunHookRequest struct {
 	Initiator          *V2Initiator           `json:"initiator"`
 	TargetRepository   string                 `json:"target_repository"`
 	JobInputs          map[string]GateInputs  `json:"job_inputs,omitempty"`
+	Inputs             map[string]interface{} `json:"inputs,omitempty"`
+	Integration        string                 `json:"integration,omitempty"`
+	Topic              string                 `json:"topic,omitempty"` // kafka topic or rabbitMQ queue
+	Debug              *V2WorkflowRunDebug    `json:"debug,omitempty"`
 }
 
 type V2WorkflowRun struct {
@@ -247,6 +251,10 @@ type V2WorkflowRunEvent struct {
 	WorkflowRunID      string                 `json:"workflow_run_id"`
 	WebHookID          string                 `json:"webhook_id"`
 	HookEventID        string                 `json:"hook_event_id,omitempty"`
+	Inputs             map[string]interface{} `json:"inputs,omitempty"`
+	Integration        string                 `json:"integration,omitempty"`
+	Topic              string                 `json:"topic,omitempty"` // kafka topic or rabbitMQ queue
+	Debug              *V2WorkflowRunDebug    `json:"debug,omitempty"`
 }
 
 func (w V2WorkflowRunEvent) Value() (driver.Value, error) {
@@ -296,6 +304,22 @@ type V2WorkflowRunJob struct {
 	GateInputs         GateInputs             `json:"gate_inputs,omitempty" db:"gate_inputs"`
 	Initiator          V2Initiator            `json:"initiator,omitempty" db:"initiator"`
 	Concurrency        *V2RunConcurrency      `json:"concurrency,omitempty" db:"concurrency"`
+	TimeoutMinutes     int64                  `json:"timeout_minutes,omitempty" db:"timeout_minutes"`
+	Priority           int64                  `json:"priority,omitempty" db:"priority"`
+}
+
+// ComputePriority returns the queue priority of the job: the job priority if set, else the priority of the concurrency rules applied on it
+func (rj V2WorkflowRunJob) ComputePriority(runConcurrency *V2RunConcurrency) int64 {
+	if rj.Job.Priority != 0 {
+		return rj.Job.Priority
+	}
+	if rj.Concurrency != nil && rj.Concurrency.Priority != 0 {
+		return rj.Concurrency.Priority
+	}
+	if runConcurrency != nil {
+		return runConcurrency.Priority
+	}
+	return 0
 }
 
 type V2RunConcurrency struct {
@@ -594,6 +618,19 @@ type V2WorkflowRunJobResult struct {
 	Time   time.Time              `json:"time"`
 }
 
+// V2LocalRunJobResult is the result of a job run on the local host by the worker exec command
+type V2LocalRunJobResult struct {
+	Result     V2WorkflowRunJobResult `json:"result"`
+	RunResults []V2WorkflowRunResult  `json:"run_results"`
+}
+
+const (
+	// V2LocalRunJobFile contains the job given to the worker exec command, in the job directory
+	V2LocalRunJobFile = "job.json"
+	// V2LocalRunResultFile contains the V2LocalRunJobResult written by the worker exec command, in the job directory
+	V2LocalRunResultFile = "result.json"
+)
+
 type V2SendJobRunInfo struct {
 	Level   string    `json:"level" db:"level"`
 	Message string    `json:"message" db:"message"`
@@ -0,0 +1,93 @@
+package sdk
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	V2WorkflowRunDebugDefaultTimeoutMinutes = 15
+	V2WorkflowRunDebugMaxTimeoutMinutes     = 60
+)
+
+// V2WorkflowRunDebug contains the debug mode requested on a manual run or a restart.
+// The worker pauses on a step failure and/or before the given steps, and a debug shell can be opened during the pause.
+type V2WorkflowRunDebug struct {
+	Jobs           []string `json:"jobs,omitempty"`
+	OnFailure      bool     `json:"on_failure,omitempty"`
+	BeforeSteps    []string `json:"before_steps,omitempty"`
+	TimeoutMinutes int64    `json:"timeout_minutes,omitempty"`
+}
+
+// Check the debug request and set the default timeout
+func (d *V2WorkflowRunDebug) Check() error {
+	if !d.OnFailure && len(d.BeforeSteps) == 0 {
+		return NewErrorFrom(ErrInvalidData, "debug mode must pause on failure or before a step")
+	}
+	if d.TimeoutMinutes < 0 || d.TimeoutMinutes > V2WorkflowRunDebugMaxTimeoutMinutes {
+		return NewErrorFrom(ErrInvalidData, "debug timeout must be between 1 and %d minutes, or 0 for the default of %d minutes", V2WorkflowRunDebugMaxTimeoutMinutes, V2WorkflowRunDebugDefaultTimeoutMinutes)
+	}
+	if d.TimeoutMinutes == 0 {
+		d.TimeoutMinutes = V2WorkflowRunDebugDefaultTimeoutMinutes
+	}
+	return nil
+}
+
+// IsEnabledForJob returns true if the debug mode applies on the given job. All jobs are debugged if no job is given.
+func (d *V2WorkflowRunDebug) IsEnabledForJob(jobID string) bool {
+	if d == nil {
+		return false
+	}
+	return len(d.Jobs) == 0 || slices.Contains(d.Jobs, jobID)
+}
+
+// HasBreakpointBeforeStep returns true if the worker has to pause before the given step, identified by its id or name
+func (d *V2WorkflowRunDebug) HasBreakpointBeforeStep(stepName string) bool {
+	return d != nil && slices.Contains(d.BeforeSteps, stepName)
+}
+
+// PauseDuration returns the duration of a pause
+func (d *V2WorkflowRunDebug) PauseDuration() time.Duration {
+	if d == nil {
+		return 0
+	}
+	return time.Duration(d.TimeoutMinutes) * time.Minute
+}
+
+// MaxPauseDuration returns the maximum time a job can be paused: one pause per step breakpoint plus the pause on failure.
+// The job timeout is extended with this duration.
+func (d *V2WorkflowRunDebug) MaxPauseDuration() time.Duration {
+	if d == nil {
+		return 0
+	}
+	nbPauses := len(d.BeforeSteps)
+	if d.OnFailure {
+		nbPauses++
+	}
+	return time.Duration(nbPauses) * d.PauseDuration()
+}
+
+// V2WorkflowRunJobBreakpoint is sent by the worker when it pauses the job
+type V2WorkflowRunJobBreakpoint struct {
+	StepName string    `json:"step_name"`
+	Reason   string    `json:"reason"`
+	Until    time.Time `json:"until"`
+}
+
+type V2DebugShellMessageType string
+
+const (
+	V2DebugShellMessageTypeOpen     V2DebugShellMessageType = "open"
+	V2DebugShellMessageTypeInput    V2DebugShellMessageType = "input"
+	V2DebugShellMessageTypeOutput   V2DebugShellMessageType = "output"
+	V2DebugShellMessageTypeClose    V2DebugShellMessageType = "close"
+	V2DebugShellMessageTypeContinue V2DebugShellMessageType = "continue"
+)
+
+// V2DebugShellMessage is exchanged over websocket between the user, the API and the worker during a debug session
+type V2DebugShellMessage struct {
+	SessionID string                  `json:"session_id"`
+	Type      V2DebugShellMessageType `json:"type"`
+	Data      []byte                  `json:"data,omitempty"`
+	Username  string                  `json:"username,omitempty"`
+}
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"time"
+)
+
+const (
+	// V2WorkflowRunResultShareDefaultTTL is the default validity of a run result download link
+	V2WorkflowRunResultShareDefaultTTL = 24 * time.Hour
+	// V2WorkflowRunResultShareMaxTTL is the maximum validity of a run result download link
+	V2WorkflowRunResultShareMaxTTL = 30 * 24 * time.Hour
+)
+
+// V2WorkflowRunResultShare is a public download link of a run result, that can be used without CDS account
+type V2WorkflowRunResultShare struct {
+	ID            string    `json:"id" db:"id" cli:"id,key"`
+	ProjectKey    string    `json:"project_key" db:"project_key" cli:"-"`
+	WorkflowRunID string    `json:"workflow_run_id" db:"workflow_run_id" cli:"-"`
+	RunResultID   string    `json:"run_result_id" db:"run_result_id" cli:"run_result_id"`
+	Created       time.Time `json:"created" db:"created" cli:"created"`
+	CreatedBy     string    `json:"created_by" db:"created_by" cli:"created_by"`
+	Expires       time.Time `json:"expires" db:"expires" cli:"expires"`
+	SingleUse     bool      `json:"single_use" db:"single_use" cli:"single_use"`
+	DownloadCount int64     `json:"download_count" db:"download_count" cli:"download_count"`
+	// URL is only returned when the link is created
+	URL       string                             `json:"url,omitempty" db:"-" cli:"url"`
+	Downloads []V2WorkflowRunResultShareDownload `json:"downloads,omitempty" db:"-" cli:"-"`
+}
+
+// IsExpired returns true if the link can no more be used
+func (s V2WorkflowRunResultShare) IsExpired() bool {
+	return time.Now().After(s.Expires) || (s.SingleUse && s.DownloadCount > 0)
+}
+
+// V2WorkflowRunResultShareRequest is the request to create a public download link of a run result
+type V2WorkflowRunResultShareRequest struct {
+	TTL       string `json:"ttl,omitempty"`
+	SingleUse bool   `json:"single_use,omitempty"`
+}
+
+// Duration returns the validity of the link, the default one if not set
+func (r V2WorkflowRunResultShareRequest) Duration() (time.Duration, error) {
+	if r.TTL == "" {
+		return V2WorkflowRunResultShareDefaultTTL, nil
+	}
+	d, err := time.ParseDuration(r.TTL)
+	if err != nil {
+		return 0, NewErrorFrom(ErrWrongRequest, "invalid ttl %q: %v", r.TTL, err)
+	}
+	if d <= 0 || d > V2WorkflowRunResultShareMaxTTL {
+		return 0, NewErrorFrom(ErrWrongRequest, "invalid ttl %q: must be between 0 and %s", r.TTL, V2WorkflowRunResultShareMaxTTL)
+	}
+	return d, nil
+}
+
+// V2WorkflowRunResultShareDownload is the audit of a download from a public link
+type V2WorkflowRunResultShareDownload struct {
+	ID         string    `json:"id" db:"id"`
+	ShareID    string    `json:"share_id" db:"share_id"`
+	Downloaded time.Time `json:"downloaded" db:"downloaded"`
+	RemoteAddr string    `json:"remote_addr" db:"remote_addr"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	// Failed is set when the file could not be sent, the download is then not counted on single use links
+	Failed bool `json:"failed" db:"failed"`
+}
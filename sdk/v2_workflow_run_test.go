@@ -121,3 +121,23 @@ func TestJobIntegrationsContext_GetValue(t *testing.T) {
 
 	require.Equal(t, "value_of_token", got)
 }
+
+func TestV2WorkflowRunDebug(t *testing.T) {
+	require.Error(t, (&V2WorkflowRunDebug{}).Check())
+	require.Error(t, (&V2WorkflowRunDebug{OnFailure: true, TimeoutMinutes: V2WorkflowRunDebugMaxTimeoutMinutes + 1}).Check())
+
+	debug := &V2WorkflowRunDebug{OnFailure: true, BeforeSteps: []string{"build", "step-2"}, Jobs: []string{"job1"}}
+	require.NoError(t, debug.Check())
+	require.Equal(t, int64(V2WorkflowRunDebugDefaultTimeoutMinutes), debug.TimeoutMinutes)
+	require.Equal(t, 3*V2WorkflowRunDebugDefaultTimeoutMinutes*time.Minute, debug.MaxPauseDuration())
+
+	require.True(t, debug.IsEnabledForJob("job1"))
+	require.False(t, debug.IsEnabledForJob("job2"))
+	require.True(t, debug.HasBreakpointBeforeStep("step-2"))
+	require.False(t, debug.HasBreakpointBeforeStep("step-1"))
+
+	var nilDebug *V2WorkflowRunDebug
+	require.False(t, nilDebug.IsEnabledForJob("job1"))
+	require.False(t, nilDebug.HasBreakpointBeforeStep("step-2"))
+	require.Equal(t, time.Duration(0), nilDebug.MaxPauseDuration())
+}
@@ -1,8 +1,10 @@
 package sdk
 
 import (
+	"encoding/json"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/rockbears/yaml"
 	"github.com/stretchr/testify/require"
@@ -184,3 +186,251 @@ func TestAncestor(t *testing.T) {
 	require.True(t, slices.Contains(parents, "job333"))
 	require.Len(t, parents, 9)
 }
+
+func TestV2WorkflowLintTimeout(t *testing.T) {
+	src := `name: MyWorkflow
+jobs:
+  myFirstJob:
+    runs-on: docker-debian
+    timeout-minutes: -1
+    steps:
+      - run: echo "Hello"
+        timeout-minutes: -5
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.Equal(t, int64(-1), w.Jobs["myFirstJob"].TimeoutMinutes)
+	require.Equal(t, int64(-5), w.Jobs["myFirstJob"].Steps[0].TimeoutMinutes)
+
+	errs := w.Lint()
+	require.Len(t, errs, 2)
+
+	job := V2Job{}
+	require.Equal(t, 24*time.Hour, job.GetTimeout())
+	job.TimeoutMinutes = 30
+	require.Equal(t, 30*time.Minute, job.GetTimeout())
+}
+
+func TestV2WorkflowReusableWorkflow(t *testing.T) {
+	src := `name: release
+on:
+  workflow-call:
+    inputs:
+      env:
+        type: string
+        options:
+          values: [dev, prod]
+      dry-run:
+        type: boolean
+        default: false
+      replicas:
+        type: number
+        default: 1
+    outputs:
+      version:
+        value: ${{ jobs.build.outputs.version }}
+jobs:
+  build:
+    runs-on: docker-debian
+    steps:
+      - run: echo "Hello"
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.NotNil(t, w.On)
+	require.NotNil(t, w.On.WorkflowCall)
+	require.Len(t, w.Lint(), 0)
+
+	inputs, err := w.On.WorkflowCall.ComputeInputs(map[string]interface{}{"env": "prod", "dry-run": "true"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"env": "prod", "dry-run": true, "replicas": float64(1)}, inputs)
+
+	_, err = w.On.WorkflowCall.ComputeInputs(map[string]interface{}{"dry-run": true})
+	require.Error(t, err)
+	_, err = w.On.WorkflowCall.ComputeInputs(map[string]interface{}{"env": "staging"})
+	require.Error(t, err)
+	_, err = w.On.WorkflowCall.ComputeInputs(map[string]interface{}{"env": "dev", "replicas": "many"})
+	require.Error(t, err)
+	_, err = w.On.WorkflowCall.ComputeInputs(map[string]interface{}{"env": "dev", "unknown": "foo"})
+	require.Error(t, err)
+
+	src = `name: MyWorkflow
+jobs:
+  release:
+    uses: my-vcs/my-org/my-repo/release
+    with:
+      env: prod
+  invalid:
+    uses: my-vcs/my-org/my-repo/release
+    steps:
+      - run: echo "Hello"
+  invalidWith:
+    runs-on: docker-debian
+    with:
+      env: prod
+    steps:
+      - run: echo "Hello"
+`
+	var caller V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &caller))
+	require.Equal(t, "prod", caller.Jobs["release"].With["env"])
+	require.Len(t, caller.Lint(), 2)
+}
+
+func TestV2WorkflowDynamicMatrix(t *testing.T) {
+	src := `name: monorepo
+jobs:
+  discover:
+    runs-on: docker-debian
+    steps:
+      - run: echo "Hello"
+  build:
+    runs-on: docker-debian
+    needs: [discover]
+    strategy:
+      matrix: ${{ fromJSON(needs.discover.outputs.targets) }}
+      max-parallel: 2
+    steps:
+      - run: echo "${{ matrix.service }}"
+  test:
+    runs-on: docker-debian
+    strategy:
+      matrix:
+        os: [linux, windows]
+        exclude:
+          - os: windows
+        include:
+          - os: darwin
+    steps:
+      - run: echo "${{ matrix.os }}"
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.Equal(t, "${{ fromJSON(needs.discover.outputs.targets) }}", w.Jobs["build"].Strategy.MatrixExpression)
+	require.Equal(t, int64(2), w.Jobs["build"].Strategy.MaxParallel)
+	require.True(t, w.Jobs["build"].Strategy.HasMatrix())
+	require.Len(t, w.Jobs["test"].Strategy.Matrix, 3)
+	require.Len(t, w.Lint(), 0)
+
+	// The expression is kept when the workflow is stored
+	bts, err := json.Marshal(w)
+	require.NoError(t, err)
+	var w2 V2Workflow
+	require.NoError(t, JSONUnmarshal(bts, &w2))
+	require.Equal(t, w.Jobs["build"].Strategy.MatrixExpression, w2.Jobs["build"].Strategy.MatrixExpression)
+
+	build := w.Jobs["build"]
+	build.Strategy = &V2JobStrategy{MatrixExpression: "services", MaxParallel: -1}
+	w.Jobs["build"] = build
+	test := w.Jobs["test"]
+	test.Strategy.Matrix["include"] = []interface{}{"darwin"}
+	w.Jobs["test"] = test
+	require.Len(t, w.Lint(), 3)
+}
+
+func TestV2WorkflowManualInputs(t *testing.T) {
+	src := `name: deploy
+on:
+  push: {}
+  manual:
+    inputs:
+      env:
+        type: string
+        options:
+          values: [dev, prod]
+      dry-run:
+        type: boolean
+        default: false
+jobs:
+  deploy:
+    runs-on: docker-debian
+    steps:
+      - run: echo "${{ inputs.env }}"
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.NotNil(t, w.On.Manual)
+	require.NotNil(t, w.On.Push)
+	require.Len(t, w.Lint(), 0)
+
+	inputs, err := w.On.Manual.ComputeInputs(map[string]interface{}{"env": "prod"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"env": "prod", "dry-run": false}, inputs)
+
+	inputs, err = w.On.Manual.ComputeInputs(map[string]interface{}{"env": "dev", "dry-run": "true"})
+	require.NoError(t, err)
+	require.Equal(t, true, inputs["dry-run"])
+
+	_, err = w.On.Manual.ComputeInputs(nil)
+	require.Error(t, err)
+	_, err = w.On.Manual.ComputeInputs(map[string]interface{}{"env": "staging"})
+	require.Error(t, err)
+
+	// With manual inputs, the on section cannot be serialized as a list of event names
+	require.Nil(t, IsDefaultHooks(w.On))
+	w.On.Manual.Inputs = nil
+	require.Equal(t, []WorkflowHookEventName{WorkflowHookEventNamePush}, IsDefaultHooks(w.On))
+}
+
+func TestV2WorkflowMessageBusHooks(t *testing.T) {
+	src := `name: deploy
+on:
+  kafka:
+    - integration: my-kafka
+      topic: deployments
+      filter: ${{ cds.event.action == 'deploy' }}
+  rabbitmq:
+    - integration: my-rabbitmq
+      queue: releases
+jobs:
+  deploy:
+    runs-on: docker-debian
+    steps:
+      - run: echo "${{ cds.event.version }}"
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.Len(t, w.On.Kafka, 1)
+	require.Equal(t, "deployments", w.On.Kafka[0].Topic)
+	require.Len(t, w.On.RabbitMQ, 1)
+	require.Equal(t, "releases", w.On.RabbitMQ[0].Queue)
+	require.Nil(t, IsDefaultHooks(w.On))
+	require.Len(t, w.Lint(), 0)
+
+	w.On.Kafka[0].Topic = ""
+	w.On.RabbitMQ[0].Filter = "cds.event.action == 'deploy'"
+	require.Len(t, w.Lint(), 2)
+}
+
+func TestV2WorkflowJobCache(t *testing.T) {
+	src := `name: build
+jobs:
+  build:
+    runs-on: docker-debian
+    cache:
+      key: go-${{ hashFiles('**/go.sum') }}
+      paths:
+        - .cache/go-build
+        - vendor
+      restore-keys:
+        - go-
+    steps:
+      - run: go build ./...
+`
+	var w V2Workflow
+	require.NoError(t, yaml.Unmarshal([]byte(src), &w))
+	require.NotNil(t, w.Jobs["build"].Cache)
+	require.Equal(t, "go-${{ hashFiles('**/go.sum') }}", w.Jobs["build"].Cache.Key)
+	require.Equal(t, []string{".cache/go-build", "vendor"}, w.Jobs["build"].Cache.Paths)
+	require.Equal(t, []string{"go-"}, w.Jobs["build"].Cache.RestoreKeys)
+	require.Len(t, w.Lint(), 0)
+
+	jobCopy := w.Jobs["build"].Copy()
+	jobCopy.Cache.Paths[0] = "other"
+	require.Equal(t, ".cache/go-build", w.Jobs["build"].Cache.Paths[0])
+
+	job := w.Jobs["build"]
+	job.Cache = &V2JobCache{Paths: []string{}}
+	w.Jobs["build"] = job
+	require.Len(t, w.Lint(), 2)
+}
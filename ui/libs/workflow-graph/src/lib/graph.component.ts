@@ -62,6 +62,23 @@ export class GraphComponent implements AfterViewInit, OnDestroy {
             }));
         }
 
+        // Jobs coming from a reusable workflow are displayed in a group named by the calling job
+        let groups: { [key: string]: GraphNode } = {};
+        if (!this.hasStages && workflow && workflow.jobs) {
+            Object.keys(workflow.jobs).filter(k => workflow.jobs[k]?.group).forEach(k => {
+                const groupName = workflow.jobs[k].group;
+                if (!groups[groupName]) {
+                    groups[groupName] = <GraphNode>{
+                        type: GraphNodeType.Stage,
+                        name: groupName,
+                        depends_on: [],
+                        sub_graph: []
+                    };
+                    this.nodes.push(groups[groupName]);
+                }
+            });
+        }
+
         if (workflow && workflow.jobs) {
             Object.keys(workflow.jobs).forEach(jobName => {
                 const jobSpec = workflow.jobs[jobName];
@@ -83,7 +100,15 @@ export class GraphComponent implements AfterViewInit, OnDestroy {
                             break;
                         }
                     }
-                } else {
+                } else if (jobSpec?.group && groups[jobSpec.group]) {
+                    const group = groups[jobSpec.group];
+                    // Needs outside of the group are carried by the group node
+                    const needs = jobSpec.needs ?? [];
+                    node.depends_on = needs.filter(n => workflow.jobs[n]?.group === jobSpec.group);
+                    needs.filter(n => workflow.jobs[n]?.group !== jobSpec.group && group.depends_on.indexOf(n) === -1)
+                        .forEach(n => group.depends_on.push(n));
+                    group.sub_graph.push(node);
+                } else if (!groups[jobName]) {
                     this.nodes.push(node);
                 }
             });
@@ -448,7 +473,8 @@ export class GraphComponent implements AfterViewInit, OnDestroy {
                 this.graph.nodeMouseEvent(NodeMouseEvent.Out, n.name, options);
                 break;
             case GraphNodeAction.Click:
-                const baseKey = (n.job && n.job.stage) ? `${n.job.stage}-${n.name}` : n.name;
+                const parentKey = n.job ? (n.job.stage || n.job.group) : null;
+                const baseKey = parentKey ? `${parentKey}-${n.name}` : n.name;
                 this.selectedNodeNavigationKey = baseKey
                 if (n.type === GraphNodeType.Matrix) { this.selectedNodeNavigationKey += '-' + options.jobMatrixKey; }
                 this.graph.selectNode(this.selectedNodeNavigationKey);
@@ -25,7 +25,16 @@ export class GraphNode {
     runs: Array<V2WorkflowRunJob>;
     event: V2WorkflowRunJobEvent;
 
-    static generateMatrixOptions(matrix: { [key: string]: Array<string> }): Array<Map<string, string>> {
+    // Dynamic matrices are only known once resolved on run jobs, so use the run job strategy when available
+    static getMatrix(node: GraphNode): { [key: string]: any } | string {
+        return node.runs?.find(r => r.job?.strategy?.matrix)?.job.strategy.matrix ?? node.job.strategy.matrix;
+    }
+
+    static generateMatrixOptions(matrix: { [key: string]: any } | string): Array<Map<string, string>> {
+        let alls = new Array<Map<string, string>>();
+        if (!matrix || typeof matrix === 'string') {
+            return alls;
+        }
         const generateMatrix = (matrix: { [key: string]: string[] }, keys: string[], keyIndex: number, current: Map<string, string>, alls: Array<Map<string, string>>) => {
             if (current.size == keys.length) {
                 let combi = new Map<string, string>();
@@ -43,8 +52,32 @@ export class GraphNode {
                 current.delete(key);
             });
         };
-        let alls = new Array<Map<string, string>>();
-        generateMatrix(matrix, Object.keys(matrix), 0, new Map<string, string>(), alls);
+        const includes: Array<{ [key: string]: any }> = matrix['include'] ?? [];
+        const excludes: Array<{ [key: string]: any }> = matrix['exclude'] ?? [];
+        const keys = Object.keys(matrix).filter(k => k !== 'include' && k !== 'exclude').sort();
+        const values: { [key: string]: string[] } = {};
+        keys.forEach(k => values[k] = (matrix[k] ?? []).map(v => `${v}`));
+        if (keys.length > 0) {
+            generateMatrix(values, keys, 0, new Map<string, string>(), alls);
+        }
+        const match = (combi: Map<string, string>, entry: { [key: string]: any }, onlyMatrixKeys: boolean): boolean => {
+            return Object.keys(entry).filter(k => !onlyMatrixKeys || keys.indexOf(k) !== -1).every(k => combi.get(k) === `${entry[k]}`);
+        };
+        alls = alls.filter(combi => !excludes.some(e => match(combi, e, false)));
+        const nbCombinations = alls.length;
+        includes.forEach(inc => {
+            let found = false;
+            alls.slice(0, nbCombinations).forEach(combi => {
+                if (!match(combi, inc, true)) {
+                    return;
+                }
+                found = true;
+                Object.keys(inc).filter(k => keys.indexOf(k) === -1).forEach(k => combi.set(k, `${inc[k]}`));
+            });
+            if (!found) {
+                alls.push(new Map<string, string>(Object.keys(inc).map(k => [k, `${inc[k]}`])));
+            }
+        });
         return alls;
     }
 }
@@ -104,7 +137,7 @@ export class NavigationGraph {
 
                         switch (sub.type) {
                             case GraphNodeType.Matrix:
-                                const alls = GraphNode.generateMatrixOptions(sub.job.strategy.matrix);
+                                const alls = GraphNode.generateMatrixOptions(GraphNode.getMatrix(sub));
                                 const keys = alls.map(option => Array.from(option.keys()).sort().map(key => `${key}: ${option.get(key)}`).join(', '));
                                 // Build run job ID mapping for stage-nested matrix variants
                                 const stageMatrixRunMap: { [matrixKey: string]: string } = {};
@@ -151,7 +184,7 @@ export class NavigationGraph {
                     });
                     break;
                 case GraphNodeType.Matrix:
-                    const alls = GraphNode.generateMatrixOptions(n.job.strategy.matrix);
+                    const alls = GraphNode.generateMatrixOptions(GraphNode.getMatrix(n));
                     const keys = alls.map(option => Array.from(option.keys()).sort().map(key => `${key}: ${option.get(key)}`).join(', '));
                     // Build run job ID mapping for top-level matrix variants
                     const matrixRunMap: { [matrixKey: string]: string } = {};
@@ -56,7 +56,7 @@ export class GraphMatrixNodeComponent implements OnInit, OnDestroy, InteractiveN
     }
 
     ngOnInit(): void {
-        const alls = GraphNode.generateMatrixOptions(this.node.job.strategy.matrix);
+        const alls = GraphNode.generateMatrixOptions(GraphNode.getMatrix(this.node));
         this.keys = alls.map(option => {
             return Array.from(option.keys()).sort().map(key => {
                 return `${key}: ${option.get(key)}`;
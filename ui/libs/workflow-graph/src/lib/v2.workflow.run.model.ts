@@ -116,6 +116,19 @@ export class WorkflowOn {
     'workflow-update': {
         target_branch: string;
     };
+    manual: {
+        inputs: { [key: string]: V2JobGateInput };
+    };
+    kafka: Array<{
+        integration: string;
+        topic: string;
+        filter: string;
+    }>;
+    rabbitmq: Array<{
+        integration: string;
+        queue: string;
+        filter: string;
+    }>;
 }
 
 export class V2Stage {
@@ -242,6 +255,16 @@ export class V2Job {
     vars: Array<string>;
     env: { [key: string]: string };
     services: { [key: string]: any };
+    uses: string;
+    with: { [key: string]: any };
+    cache: V2JobCache;
+    group: string;
+}
+
+export class V2JobCache {
+    key: string;
+    paths: Array<string>;
+    'restore-keys': Array<string>;
 }
 
 export class ActionStep {
@@ -255,7 +278,8 @@ export class ActionStep {
 }
 
 export class V2JobStrategy {
-    matrix: { [key: string]: Array<string> };
+    matrix: { [key: string]: any } | string;
+    'max-parallel': number;
 }
 
 export class StepStatus {
@@ -317,6 +341,7 @@ export class V2WorkflowRunManualRequest {
     workflow_tag: string;
     payload: any;
     job_inputs: { [key: string]: any };
+    inputs: { [key: string]: any };
 }
 
 export class V2WorkflowRunManualResponse {
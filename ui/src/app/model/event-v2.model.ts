@@ -3,6 +3,7 @@ export enum EventV2Type {
 	EventRunBuilding = "RunBuilding",
 	EventRunEnded = "RunEnded",
 	EventRunRestart = "RunRestart",
+	EventRunPartialRestart = "RunPartialRestart",
 
 	EventRunJobEnqueued = "RunJobEnqueued",
 
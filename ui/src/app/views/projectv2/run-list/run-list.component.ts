@@ -97,7 +97,7 @@ export class ProjectV2RunListComponent implements OnInit, OnDestroy {
 			this.search();
 		});
 		this.eventV2Subscription = this._store.select(EventV2State.last).subscribe((event) => {
-			if (!event || [EventV2Type.EventRunCrafted, EventV2Type.EventRunBuilding, EventV2Type.EventRunEnded, EventV2Type.EventRunRestart].indexOf(event.type) === -1) { return; }
+			if (!event || [EventV2Type.EventRunCrafted, EventV2Type.EventRunBuilding, EventV2Type.EventRunEnded, EventV2Type.EventRunRestart, EventV2Type.EventRunPartialRestart].indexOf(event.type) === -1) { return; }
 			const idx = this.runs.findIndex(run => run.id === event.workflow_run_id);
 			delete (this.animatedRuns[event.payload.id]);
 			this._cd.detectChanges();
@@ -7,7 +7,7 @@ import { VCSProject } from "app/model/vcs.model";
 import { ProjectService } from "app/service/project/project.service";
 import { V2WorkflowRunService } from "app/service/services.module";
 import { lastValueFrom } from "rxjs";
-import { V2Workflow, V2WorkflowRunManualRequest, V2JobGate, V2Job } from "../../../../../libs/workflow-graph/src/lib/v2.workflow.run.model";
+import { V2Workflow, V2WorkflowRunManualRequest, V2JobGate, V2Job, V2JobGateInput } from "../../../../../libs/workflow-graph/src/lib/v2.workflow.run.model";
 import { NzMessageService } from "ng-zorro-antd/message";
 import { NzDrawerRef } from "ng-zorro-antd/drawer";
 import { LoadOptions, load } from "js-yaml";
@@ -65,6 +65,8 @@ export class ProjectV2RunStartComponent implements OnInit {
 
   rootJobsWithGate: { [jobName: string]: V2Job } = {};
   rootGateDefs: { [gateName: string]: V2JobGate } = {};
+  workflowInputDefs: { [inputName: string]: V2JobGateInput } = {};
+  workflowInputs: { [inputName: string]: any } = {};
 
   private _drawerRef = inject<NzDrawerRef<string>>(NzDrawerRef);
   private _messageService = inject(NzMessageService);
@@ -211,6 +213,13 @@ export class ProjectV2RunStartComponent implements OnInit {
       this._cd.markForCheck();
       return
     }
+    // Init workflow inputs with their default values
+    this.workflowInputDefs = wkf.on?.manual?.inputs ?? {};
+    this.workflowInputs = {};
+    Object.keys(this.workflowInputDefs).forEach(k => {
+      this.workflowInputs[k] = this.workflowInputDefs[k].default ?? (this.workflowInputDefs[k].type === 'boolean' ? false : undefined);
+    });
+
     // Reset gate related properties
     this.rootGateDefs = {};
     this.rootJobsWithGate = {};
@@ -334,6 +343,9 @@ export class ProjectV2RunStartComponent implements OnInit {
       req.workflow_branch = this.branches.find(b => b.default).display_id;
     }
     req.job_inputs = this.validateForm.value.jobInputs;
+    if (Object.keys(this.workflowInputDefs).length > 0) {
+      req.inputs = { ...this.workflowInputs };
+    }
     let hookEventUUID: string;
 
     try {
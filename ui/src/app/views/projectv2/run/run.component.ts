@@ -155,7 +155,7 @@ export class ProjectV2RunComponent implements AfterViewInit, OnDestroy {
         this.jobPanelSize = this._store.selectSnapshot(PreferencesState.panelSize(ProjectV2RunComponent.JOB_PANEL_KEY)) ?? '50%';
 
         this.eventV2Subscription = this._store.select(EventV2State.last).subscribe((event) => {
-            if (!event || [EventV2Type.EventRunCrafted, EventV2Type.EventRunBuilding, EventV2Type.EventRunEnded, EventV2Type.EventRunRestart].indexOf(event.type) === -1) { return; }
+            if (!event || [EventV2Type.EventRunCrafted, EventV2Type.EventRunBuilding, EventV2Type.EventRunEnded, EventV2Type.EventRunRestart, EventV2Type.EventRunPartialRestart].indexOf(event.type) === -1) { return; }
             if (!this.runs) { return; }
             const idx = this.runs.findIndex(run => run.id === event.workflow_run_id);
             delete (this.animatedRuns[event.payload.id]);